@@ -0,0 +1,156 @@
+package clientauth
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type Permission int
+
+const (
+	PermNone Permission = iota
+	PermRead
+	PermWrite
+	PermAdmin
+)
+
+var strToPermission = map[string]Permission{
+	"none":  PermNone,
+	"read":  PermRead,
+	"write": PermWrite,
+	"admin": PermAdmin,
+}
+
+var permissionToStr = map[Permission]string{
+	PermNone:  "none",
+	PermRead:  "read",
+	PermWrite: "write",
+	PermAdmin: "admin",
+}
+
+func (p Permission) String() string {
+	return permissionToStr[p]
+}
+
+func (p *Permission) UnmarshalText(text []byte) error {
+	s := string(text)
+	perm, ok := strToPermission[s]
+	if !ok {
+		return fmt.Errorf("Unknown permission %q", s)
+	}
+	*p = perm
+	return nil
+}
+
+// PermissionFromRole returns the permission granted on every path to users without ACL entries.
+func PermissionFromRole(r Role) Permission {
+	switch r {
+	case RoleReadOnly:
+		return PermRead
+	case RoleAdmin:
+		return PermAdmin
+	default:
+		return PermNone
+	}
+}
+
+// ACLEntry grants the user the permission on the files under PathPrefix.
+type ACLEntry struct {
+	// User is the identity from the client certificate, as UserInfo.User.
+	User       string
+	PathPrefix string
+	Permission Permission
+}
+
+// ACL is a set of per-user access control entries. Once a user has any entry,
+// the user can only access the paths under the entries, with the permission of
+// the longest matching prefix, regardless of the role. Users without entries
+// are allowed by their role.
+type ACL struct {
+	entries map[string][]ACLEntry
+}
+
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+func NewACL(es []ACLEntry) (*ACL, error) {
+	acl := &ACL{entries: make(map[string][]ACLEntry)}
+	for _, e := range es {
+		if e.User == "" {
+			return nil, fmt.Errorf("ACL entry for path %q has empty user.", e.PathPrefix)
+		}
+		if e.Permission < PermNone || PermAdmin < e.Permission {
+			return nil, fmt.Errorf("ACL entry for user %q has invalid permission %d.", e.User, e.Permission)
+		}
+		e.PathPrefix = cleanPath(e.PathPrefix)
+		acl.entries[e.User] = append(acl.entries[e.User], e)
+	}
+	return acl, nil
+}
+
+func hasPathPrefix(p, prefix string) bool {
+	return prefix == "/" || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// PermissionFor returns the permission of the user on the path p.
+func (acl *ACL) PermissionFor(ui UserInfo, p string) Permission {
+	if ui.PathScope != "" && !hasPathPrefix(cleanPath(p), cleanPath(ui.PathScope)) {
+		return PermNone
+	}
+	if acl == nil {
+		return PermissionFromRole(ui.Role)
+	}
+	es, ok := acl.entries[ui.User]
+	if !ok {
+		return PermissionFromRole(ui.Role)
+	}
+
+	p = cleanPath(p)
+	perm := PermNone
+	matchlen := -1
+	for _, e := range es {
+		if hasPathPrefix(p, e.PathPrefix) && len(e.PathPrefix) > matchlen {
+			perm = e.Permission
+			matchlen = len(e.PathPrefix)
+		}
+	}
+	return perm
+}
+
+type aclKey struct{}
+
+func ContextWithACL(ctx context.Context, acl *ACL) context.Context {
+	return context.WithValue(ctx, aclKey{}, acl)
+}
+
+// ACLFromContext returns the ACL attached to ctx. nil, which allows users by their role, is returned if none.
+func ACLFromContext(ctx context.Context) *ACL {
+	acl, _ := ctx.Value(aclKey{}).(*ACL)
+	return acl
+}
+
+// CheckPathPermission returns true if the user has the permission req on the path p
+// under the ACL attached to ctx. Denied access is logged.
+func CheckPathPermission(ctx context.Context, ui UserInfo, p string, req Permission) bool {
+	perm := ACLFromContext(ctx).PermissionFor(ui, p)
+	if perm < req {
+		zap.S().Warnf("Denied %v access to %q by %v, who has %v permission.", req, p, ui, perm)
+		return false
+	}
+	return true
+}
+
+func RequirePathPermissionGRPC(ctx context.Context, p string, req Permission) error {
+	ui := UserInfoFromContext(ctx)
+	if !CheckPathPermission(ctx, ui, p, req) {
+		return grpc.Errorf(codes.PermissionDenied, "Action requires %v permission on %q, but you are %v", req, p, ui)
+	}
+	return nil
+}
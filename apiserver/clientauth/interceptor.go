@@ -5,11 +5,13 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"net/http"
 	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
 
@@ -20,6 +22,10 @@ const (
 
 type AuthProvider struct {
 	Disabled bool
+	// ACL restricts the paths users can access. Users are allowed by their role if nil.
+	ACL *ACL
+	// If non-nil, accept bearer tokens in the store in addition to client certs.
+	Tokens *TokenStore
 }
 
 func UserInfoFromClientCert(cert *x509.Certificate) UserInfo {
@@ -51,15 +57,81 @@ func UserInfoFromTLSConnectionState(tcs *tls.ConnectionState) (UserInfo, error)
 	return UserInfoFromClientCert(vc[0]), nil
 }
 
+var ErrTokenAuthDisabled = errors.New("Bearer token authentication is not enabled.")
+
+func authenticateBearer(tokens *TokenStore, secret string) (UserInfo, error) {
+	if tokens == nil {
+		return AnonymousUserInfo, ErrTokenAuthDisabled
+	}
+	return tokens.Authenticate(secret)
+}
+
+func bearerTokenFromAuthorization(v string) (string, bool) {
+	if !strings.HasPrefix(v, BearerPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(v, BearerPrefix), true
+}
+
+func bearerTokenFromMetadata(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	for _, v := range md.Get(AuthorizationKey) {
+		if secret, ok := bearerTokenFromAuthorization(v); ok {
+			return secret, true
+		}
+	}
+	return "", false
+}
+
+type tokenStoreKey struct{}
+
+func ContextWithTokenStore(ctx context.Context, tokens *TokenStore) context.Context {
+	return context.WithValue(ctx, tokenStoreKey{}, tokens)
+}
+
+func TokenStoreFromContext(ctx context.Context) *TokenStore {
+	tokens, _ := ctx.Value(tokenStoreKey{}).(*TokenStore)
+	return tokens
+}
+
+// UserInfoFromHTTPRequest authenticates the HTTP request by its "Authorization: Bearer" header
+// against the TokenStore attached to the request context, or by its client cert.
+func UserInfoFromHTTPRequest(r *http.Request) (UserInfo, error) {
+	if secret, ok := bearerTokenFromAuthorization(r.Header.Get("Authorization")); ok {
+		return authenticateBearer(TokenStoreFromContext(r.Context()), secret)
+	}
+	if r.TLS == nil {
+		return AnonymousUserInfo, ErrZeroVerifiedChains
+	}
+	return UserInfoFromTLSConnectionState(r.TLS)
+}
+
 func (p AuthProvider) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	if p.Disabled {
 		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 			ctx = ContextWithUserInfo(ctx, NoauthUserInfo)
+			ctx = ContextWithACL(ctx, p.ACL)
 			return handler(ctx, req)
 		}
 	}
 
+	acl := p.ACL
+	tokens := p.Tokens
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if secret, ok := bearerTokenFromMetadata(ctx); ok {
+			ui, err := authenticateBearer(tokens, secret)
+			if err != nil {
+				return nil, grpc.Errorf(codes.Unauthenticated, "%v", err)
+			}
+
+			ctx = ContextWithUserInfo(ctx, ui)
+			ctx = ContextWithACL(ctx, acl)
+			return handler(ctx, req)
+		}
+
 		p, ok := peer.FromContext(ctx)
 		if !ok {
 			return nil, grpc.Errorf(codes.Unauthenticated, "AuthProvider requires metadata.")
@@ -78,6 +150,7 @@ func (p AuthProvider) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		}
 
 		ctx = ContextWithUserInfo(ctx, ui)
+		ctx = ContextWithACL(ctx, acl)
 		return handler(ctx, req)
 	}
 }
@@ -1,5 +1,7 @@
 package clientauth
 
+import "fmt"
+
 type Role int
 
 const (
@@ -35,3 +37,16 @@ func RoleFromStr(s string) Role {
 func (r Role) String() string {
 	return roleToStr[r]
 }
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Role) UnmarshalText(text []byte) error {
+	s := string(text)
+	if !IsValidRoleStr(s) {
+		return fmt.Errorf("Unknown role %q", s)
+	}
+	*r = RoleFromStr(s)
+	return nil
+}
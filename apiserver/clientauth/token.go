@@ -0,0 +1,204 @@
+package clientauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidToken = errors.New("Invalid bearer token.")
+	ErrTokenExpired = errors.New("Bearer token expired.")
+	ErrUnknownToken = errors.New("Unknown token id.")
+)
+
+// Token is an API key usable as a bearer token in place of a client certificate.
+// Only the hash of its secret is kept.
+type Token struct {
+	ID   string `json:"id"`
+	Hash string `json:"hash"`
+
+	User string `json:"user"`
+	Role Role   `json:"role"`
+	// If non-empty, the token can only access the files under PathPrefix.
+	PathPrefix  string `json:"path_prefix,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	CreatedT time.Time `json:"created_t"`
+	// The token never expires if zero.
+	ExpiresT time.Time `json:"expires_t,omitempty"`
+}
+
+func (t *Token) IsExpired(now time.Time) bool {
+	return !t.ExpiresT.IsZero() && !now.Before(t.ExpiresT)
+}
+
+func (t *Token) UserInfo() UserInfo {
+	return UserInfo{Role: t.Role, User: t.User, PathScope: t.PathPrefix}
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("Failed to read random bytes: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// TokenStore is the set of tokens persisted to a local json file.
+type TokenStore struct {
+	mu     sync.Mutex
+	path   string
+	tokens map[string]*Token
+}
+
+// NewTokenStore loads the tokens from the file at path, which is created on the first token creation.
+func NewTokenStore(path string) (*TokenStore, error) {
+	s := &TokenStore{path: path, tokens: make(map[string]*Token)}
+
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("Failed to read tokens file: %v", err)
+	}
+	var ts []*Token
+	if err := json.Unmarshal(bs, &ts); err != nil {
+		return nil, fmt.Errorf("Failed to parse tokens file %q: %v", path, err)
+	}
+	for _, t := range ts {
+		s.tokens[t.ID] = t
+	}
+	return s, nil
+}
+
+func (s *TokenStore) saveWithLock() error {
+	ts := s.listWithLock()
+	bs, err := json.MarshalIndent(ts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to encode tokens: %v", err)
+	}
+
+	tmppath := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmppath, bs, 0600); err != nil {
+		return fmt.Errorf("Failed to write tokens file: %v", err)
+	}
+	if err := os.Rename(tmppath, s.path); err != nil {
+		return fmt.Errorf("Failed to rename tokens file: %v", err)
+	}
+	return nil
+}
+
+func (s *TokenStore) listWithLock() []Token {
+	ts := make([]Token, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		ts = append(ts, *t)
+	}
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].CreatedT.Before(ts[j].CreatedT)
+	})
+	return ts
+}
+
+// Create issues a new token, and returns it along with its secret "<id>.<random>".
+// The secret is not recoverable afterwards.
+func (s *TokenStore) Create(user string, role Role, pathPrefix string, expiresT time.Time, description string) (Token, string, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return Token{}, "", err
+	}
+	r, err := randomHex(32)
+	if err != nil {
+		return Token{}, "", err
+	}
+	secret := id + "." + r
+
+	if user == "" {
+		user = "token-" + id
+	}
+	if pathPrefix != "" {
+		pathPrefix = cleanPath(pathPrefix)
+	}
+	t := &Token{
+		ID:          id,
+		Hash:        hashSecret(secret),
+		User:        user,
+		Role:        role,
+		PathPrefix:  pathPrefix,
+		Description: description,
+		CreatedT:    time.Now(),
+		ExpiresT:    expiresT,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[id] = t
+	if err := s.saveWithLock(); err != nil {
+		delete(s.tokens, id)
+		return Token{}, "", err
+	}
+	zap.S().Infof("Created token %s for %v", id, t.UserInfo())
+	return *t, secret, nil
+}
+
+func (s *TokenStore) List() []Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.listWithLock()
+}
+
+func (s *TokenStore) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[id]
+	if !ok {
+		return ErrUnknownToken
+	}
+	delete(s.tokens, id)
+	if err := s.saveWithLock(); err != nil {
+		s.tokens[id] = t
+		return err
+	}
+	zap.S().Infof("Revoked token %s for %v", id, t.UserInfo())
+	return nil
+}
+
+// Authenticate returns the UserInfo of the token with the secret.
+func (s *TokenStore) Authenticate(secret string) (UserInfo, error) {
+	id := strings.SplitN(secret, ".", 2)[0]
+
+	s.mu.Lock()
+	t, ok := s.tokens[id]
+	s.mu.Unlock()
+	if !ok {
+		return AnonymousUserInfo, ErrInvalidToken
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(t.Hash)) != 1 {
+		return AnonymousUserInfo, ErrInvalidToken
+	}
+	if t.IsExpired(time.Now()) {
+		return AnonymousUserInfo, ErrTokenExpired
+	}
+	return t.UserInfo(), nil
+}
@@ -11,9 +11,14 @@ import (
 type UserInfo struct {
 	Role
 	User string
+	// If non-empty, the user can only access the files under PathScope.
+	PathScope string
 }
 
 func (ui UserInfo) String() string {
+	if ui.PathScope != "" {
+		return fmt.Sprintf("%s [role=%v, scope=%s]", ui.User, ui.Role, ui.PathScope)
+	}
 	return fmt.Sprintf("%s [role=%v]", ui.User, ui.Role)
 }
 
@@ -41,6 +46,10 @@ func RequireRoleGRPC(ctx context.Context, req Role) error {
 	if ui.Role < req {
 		return grpc.Errorf(codes.PermissionDenied, "Action requires role %v, but you are %v", req, ui)
 	}
+	// Users scoped to a path are not allowed to administer the whole server.
+	if req == RoleAdmin && ui.PathScope != "" {
+		return grpc.Errorf(codes.PermissionDenied, "Action requires role %v without path scope, but you are %v", req, ui)
+	}
 
 	return nil
 }
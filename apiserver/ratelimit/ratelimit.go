@@ -0,0 +1,265 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/util/bwlimit"
+)
+
+// ConcurrencyRetryAfter is the retry hint for the requests rejected by MaxConcurrent,
+// as when the running requests complete is unknown.
+const ConcurrencyRetryAfter = time.Second
+
+// Rule sets the limits applied to each user it matches. Every user gets its
+// own token buckets. Limits <= 0 are unlimited.
+type Rule struct {
+	// The rule matches the user if User is non-empty. Otherwise, it matches
+	// the users of Role without a rule of their own.
+	User string
+	Role clientauth.Role
+
+	RequestsPerSec   int64
+	ReadBytesPerSec  int64
+	WriteBytesPerSec int64
+	// Max number of the requests of the user processed at the same time.
+	MaxConcurrent int
+}
+
+type userState struct {
+	rule     *Rule
+	reqs     *bwlimit.Limiter
+	reads    *bwlimit.Limiter
+	writes   *bwlimit.Limiter
+	inflight int
+}
+
+// Limiter enforces the rate and concurrency limits of the API users.
+// A nil *Limiter imposes no limit.
+type Limiter struct {
+	byUser map[string]*Rule
+	byRole map[clientauth.Role]*Rule
+
+	mu    sync.Mutex
+	users map[string]*userState
+}
+
+func New(rules []Rule) (*Limiter, error) {
+	l := &Limiter{
+		byUser: make(map[string]*Rule),
+		byRole: make(map[clientauth.Role]*Rule),
+		users:  make(map[string]*userState),
+	}
+	for i := range rules {
+		r := &rules[i]
+		if r.User != "" {
+			if _, ok := l.byUser[r.User]; ok {
+				return nil, fmt.Errorf("Duplicate rate limit rules for user %q.", r.User)
+			}
+			l.byUser[r.User] = r
+			continue
+		}
+		if _, ok := l.byRole[r.Role]; ok {
+			return nil, fmt.Errorf("Duplicate rate limit rules for role %v.", r.Role)
+		}
+		l.byRole[r.Role] = r
+	}
+	return l, nil
+}
+
+func (l *Limiter) ruleFor(ui clientauth.UserInfo) *Rule {
+	if r, ok := l.byUser[ui.User]; ok {
+		return r
+	}
+	return l.byRole[ui.Role]
+}
+
+// stateForWithLock returns the state of the user, or nil if no rule applies. Assumes l.mu is locked.
+func (l *Limiter) stateForWithLock(ui clientauth.UserInfo) *userState {
+	r := l.ruleFor(ui)
+	if r == nil {
+		return nil
+	}
+
+	s, ok := l.users[ui.User]
+	if ok && s.rule == r {
+		return s
+	}
+	s = &userState{
+		rule:   r,
+		reqs:   bwlimit.NewLimiter(r.RequestsPerSec),
+		reads:  bwlimit.NewLimiter(r.ReadBytesPerSec),
+		writes: bwlimit.NewLimiter(r.WriteBytesPerSec),
+	}
+	l.users[ui.User] = s
+	return s
+}
+
+func (l *Limiter) stateFor(ui clientauth.UserInfo) *userState {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stateForWithLock(ui)
+}
+
+type LimitExceededError struct {
+	User string
+	// Limit is the name of the exceeded limit.
+	Limit      string
+	RetryAfter time.Duration
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s exceeded the %s limit. Retry after %v.", e.User, e.Limit, e.RetryAfter)
+}
+
+// RetryAfterSeconds returns RetryAfter rounded up to seconds, as in the http Retry-After header.
+func (e *LimitExceededError) RetryAfterSeconds() int64 {
+	return int64(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// Begin admits a request of the user, and returns the func to be called on its completion.
+// It fails with *LimitExceededError if the user has too many requests running, sent
+// too many requests recently, or transferred more bytes than the limits allow.
+func (l *Limiter) Begin(ui clientauth.UserInfo) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := l.stateForWithLock(ui)
+	if s == nil {
+		return func() {}, nil
+	}
+
+	exceeded := func(limit string, d time.Duration) error {
+		err := &LimitExceededError{User: ui.User, Limit: limit, RetryAfter: d}
+		zap.S().Infof("Rejected request: %v", err)
+		return err
+	}
+	if s.rule.MaxConcurrent > 0 && s.inflight >= s.rule.MaxConcurrent {
+		return nil, exceeded("concurrent requests", ConcurrencyRetryAfter)
+	}
+	// Byte transfers overdraw the buckets, and are paid back before the next request.
+	if d := s.reads.TryReserve(0); d > 0 {
+		return nil, exceeded("read bytes", d)
+	}
+	if d := s.writes.TryReserve(0); d > 0 {
+		return nil, exceeded("write bytes", d)
+	}
+	if d := s.reqs.TryReserve(1); d > 0 {
+		return nil, exceeded("requests", d)
+	}
+
+	s.inflight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			s.inflight--
+			l.mu.Unlock()
+		})
+	}, nil
+}
+
+// ChargeRead accounts n bytes read by the user.
+func (l *Limiter) ChargeRead(ui clientauth.UserInfo, n int) {
+	if s := l.stateFor(ui); s != nil {
+		s.reads.Reserve(n)
+	}
+}
+
+// ChargeWrite accounts n bytes written by the user.
+func (l *Limiter) ChargeWrite(ui clientauth.UserInfo, n int) {
+	if s := l.stateFor(ui); s != nil {
+		s.writes.Reserve(n)
+	}
+}
+
+func noWait(ctx context.Context, n int) error { return nil }
+
+// ReadWaitFunc returns the bwlimit.WaitFunc to throttle the reads by the user in a transfer.
+func (l *Limiter) ReadWaitFunc(ui clientauth.UserInfo) bwlimit.WaitFunc {
+	if s := l.stateFor(ui); s != nil {
+		return s.reads.Wait
+	}
+	return noWait
+}
+
+// WriteWaitFunc returns the bwlimit.WaitFunc to throttle the writes by the user in a transfer.
+func (l *Limiter) WriteWaitFunc(ui clientauth.UserInfo) bwlimit.WaitFunc {
+	if s := l.stateFor(ui); s != nil {
+		return s.writes.Wait
+	}
+	return noWait
+}
+
+// GRPCError converts the error from Begin to a RESOURCE_EXHAUSTED status with a RetryInfo.
+func GRPCError(err error) error {
+	e, ok := err.(*LimitExceededError)
+	if !ok {
+		return grpc.Errorf(codes.Internal, "%v", err)
+	}
+
+	st := status.New(codes.ResourceExhausted, e.Error())
+	if stWithDetails, derr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)}); derr == nil {
+		st = stWithDetails
+	}
+	return st.Err()
+}
+
+// bodyGetter is implemented by the requests and responses carrying file contents.
+type bodyGetter interface {
+	GetBody() []byte
+}
+
+// UnaryServerInterceptor enforces the limits of the user authenticated by the preceding
+// clientauth interceptor. The file contents in the request and the response are
+// accounted as the bytes written and read.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ui := clientauth.UserInfoFromContext(ctx)
+		release, err := l.Begin(ui)
+		if err != nil {
+			return nil, GRPCError(err)
+		}
+		defer release()
+
+		if b, ok := req.(bodyGetter); ok {
+			l.ChargeWrite(ui, len(b.GetBody()))
+		}
+		resp, err := handler(ctx, req)
+		if b, ok := resp.(bodyGetter); ok && err == nil {
+			l.ChargeRead(ui, len(b.GetBody()))
+		}
+		return resp, err
+	}
+}
+
+type limiterKey struct{}
+
+func ContextWithLimiter(ctx context.Context, l *Limiter) context.Context {
+	return context.WithValue(ctx, limiterKey{}, l)
+}
+
+// LimiterFromContext returns the Limiter attached to ctx. nil, which imposes no limit, is returned if none.
+func LimiterFromContext(ctx context.Context) *Limiter {
+	l, _ := ctx.Value(limiterKey{}).(*Limiter)
+	return l
+}
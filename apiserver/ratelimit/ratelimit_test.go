@@ -0,0 +1,172 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/apiserver/ratelimit"
+)
+
+var (
+	alice = clientauth.UserInfo{Role: clientauth.RoleReadOnly, User: "alice"}
+	bob   = clientauth.UserInfo{Role: clientauth.RoleReadOnly, User: "bob"}
+	carol = clientauth.UserInfo{Role: clientauth.RoleAdmin, User: "carol"}
+)
+
+func expectExceeded(t *testing.T, err error, limit string) {
+	t.Helper()
+
+	e, ok := err.(*ratelimit.LimitExceededError)
+	if !ok {
+		t.Fatalf("Expected LimitExceededError, got %v", err)
+	}
+	if e.Limit != limit {
+		t.Errorf("Expected %q limit exceeded, got %q", limit, e.Limit)
+	}
+	if e.RetryAfter <= 0 || e.RetryAfterSeconds() < 1 {
+		t.Errorf("Expected positive retry hint, got %v", e.RetryAfter)
+	}
+}
+
+func TestLimiter_Requests(t *testing.T) {
+	l, err := ratelimit.New([]ratelimit.Rule{
+		{Role: clientauth.RoleReadOnly, RequestsPerSec: 2},
+		{User: "bob", RequestsPerSec: 4},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		ui clientauth.UserInfo
+		n  int
+	}{
+		{alice, 2},
+		// The rule for the user takes precedence over the role.
+		{bob, 4},
+	} {
+		for i := 0; i < tc.n; i++ {
+			release, err := l.Begin(tc.ui)
+			if err != nil {
+				t.Fatalf("Begin #%d of %v failed: %v", i, tc.ui, err)
+			}
+			release()
+		}
+		_, err := l.Begin(tc.ui)
+		expectExceeded(t, err, "requests")
+	}
+
+	// No rule for admins.
+	for i := 0; i < 100; i++ {
+		release, err := l.Begin(carol)
+		if err != nil {
+			t.Fatalf("Begin of %v failed: %v", carol, err)
+		}
+		release()
+	}
+}
+
+func TestLimiter_Concurrent(t *testing.T) {
+	l, err := ratelimit.New([]ratelimit.Rule{{Role: clientauth.RoleReadOnly, MaxConcurrent: 2}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	r1, err := l.Begin(alice)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	r2, err := l.Begin(alice)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	_, err = l.Begin(alice)
+	expectExceeded(t, err, "concurrent requests")
+
+	// Each user has its own limit.
+	if r, err := l.Begin(bob); err != nil {
+		t.Errorf("Begin of other user failed: %v", err)
+	} else {
+		r()
+	}
+
+	r1()
+	r1() // no-op
+	r3, err := l.Begin(alice)
+	if err != nil {
+		t.Fatalf("Begin after release failed: %v", err)
+	}
+	_, err = l.Begin(alice)
+	expectExceeded(t, err, "concurrent requests")
+	r2()
+	r3()
+}
+
+func TestLimiter_Bytes(t *testing.T) {
+	l, err := ratelimit.New([]ratelimit.Rule{{Role: clientauth.RoleReadOnly, ReadBytesPerSec: 1000, WriteBytesPerSec: 1000}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	release, err := l.Begin(alice)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	// A transfer may overdraw the bucket.
+	l.ChargeRead(alice, 3000)
+	release()
+
+	_, err = l.Begin(alice)
+	expectExceeded(t, err, "read bytes")
+	if e := err.(*ratelimit.LimitExceededError); e.RetryAfter < time.Second || e.RetryAfter > 2*time.Second {
+		t.Errorf("Unexpected retry hint: %v", e.RetryAfter)
+	}
+
+	l.ChargeWrite(bob, 2000)
+	_, err = l.Begin(bob)
+	expectExceeded(t, err, "write bytes")
+}
+
+func TestLimiter_Nil(t *testing.T) {
+	var l *ratelimit.Limiter
+	release, err := l.Begin(alice)
+	if err != nil {
+		t.Fatalf("Begin on nil Limiter failed: %v", err)
+	}
+	release()
+	l.ChargeRead(alice, 1024)
+}
+
+func TestNew_Duplicate(t *testing.T) {
+	if _, err := ratelimit.New([]ratelimit.Rule{{User: "alice"}, {User: "alice"}}); err == nil {
+		t.Errorf("New should fail for duplicate user rules")
+	}
+	if _, err := ratelimit.New([]ratelimit.Rule{{Role: clientauth.RoleAdmin}, {Role: clientauth.RoleAdmin}}); err == nil {
+		t.Errorf("New should fail for duplicate role rules")
+	}
+}
+
+func TestGRPCError(t *testing.T) {
+	err := ratelimit.GRPCError(&ratelimit.LimitExceededError{User: "alice", Limit: "requests", RetryAfter: 1500 * time.Millisecond})
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Errorf("Unexpected code: %v", st.Code())
+	}
+	found := false
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			found = true
+			if ri.RetryDelay.AsDuration() != 1500*time.Millisecond {
+				t.Errorf("Unexpected RetryDelay: %v", ri.RetryDelay.AsDuration())
+			}
+		}
+	}
+	if !found {
+		t.Errorf("RetryInfo not found in %v", st.Details())
+	}
+}
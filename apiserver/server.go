@@ -23,6 +23,7 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/apiserver/ratelimit"
 	"github.com/nyaxt/otaru/assets/swaggerui"
 	"github.com/nyaxt/otaru/logger"
 	"github.com/nyaxt/otaru/util/readpem"
@@ -42,6 +43,9 @@ type options struct {
 	key   crypto.PrivateKey
 
 	clientCACert *x509.Certificate
+	acl          *clientauth.ACL
+	tokens       *clientauth.TokenStore
+	limiter      *ratelimit.Limiter
 
 	allowedOrigins []string
 
@@ -77,6 +81,27 @@ func ClientCACert(cert *x509.Certificate) Option {
 	}
 }
 
+// ACL restricts the paths each user can access via the filesystem APIs.
+func ACL(acl *clientauth.ACL) Option {
+	return func(o *options) {
+		o.acl = acl
+	}
+}
+
+// TokenStore enables bearer token authentication with the tokens in the store.
+func TokenStore(tokens *clientauth.TokenStore) Option {
+	return func(o *options) {
+		o.tokens = tokens
+	}
+}
+
+// RateLimiter enforces per-user rate and concurrency limits on the API requests.
+func RateLimiter(l *ratelimit.Limiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
 func ServeApiGateway(b bool) Option {
 	return func(o *options) {
 		o.serveApiGateway = b
@@ -186,6 +211,9 @@ func Serve(ctx context.Context, opt ...Option) error {
 	if opts.clientCACert != nil {
 		s.Infof("Client certificate authentication is enabled.")
 		clientAuthEnabled = true
+	} else if opts.tokens != nil {
+		s.Infof("Client certificate authentication is disabled. Only bearer tokens are accepted.")
+		clientAuthEnabled = true
 	} else {
 		s.Infof("Client certificate authentication is disabled. Any request to the server will treated as if it were from role \"admin\".")
 		clientAuthEnabled = false
@@ -193,12 +221,18 @@ func Serve(ctx context.Context, opt ...Option) error {
 
 	uics := []grpc.UnaryServerInterceptor{
 		grpc_prometheus.UnaryServerInterceptor,
-		clientauth.AuthProvider{Disabled: !clientAuthEnabled}.UnaryServerInterceptor(),
+		clientauth.AuthProvider{Disabled: !clientAuthEnabled, ACL: opts.acl, Tokens: opts.tokens}.UnaryServerInterceptor(),
+	}
+	if opts.limiter != nil {
+		s.Infof("Per-user rate limits are enabled.")
+		uics = append(uics, opts.limiter.UnaryServerInterceptor())
+	}
+	uics = append(uics,
 		grpc_ctxtags.UnaryServerInterceptor(
 			grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.TagBasedRequestFieldExtractor("log_fields")),
 		),
 		grpc_zap.UnaryServerInterceptor(l.Named("grpc")),
-	}
+	)
 	grpcServer := grpc.NewServer(
 		grpc.Creds(grpcCredentials),
 		grpc_middleware.WithUnaryServerChain(uics...),
@@ -232,10 +266,16 @@ func Serve(ctx context.Context, opt ...Option) error {
 		return fmt.Errorf("Failed to listen %q: %v", opts.listenAddr, err)
 	}
 
-	httpHandler := logger.HttpHandler(l, c.Handler(mux))
+	aclHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := clientauth.ContextWithACL(r.Context(), opts.acl)
+		ctx = clientauth.ContextWithTokenStore(ctx, opts.tokens)
+		ctx = ratelimit.ContextWithLimiter(ctx, opts.limiter)
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	})
+	httpHandler := logger.HttpHandler(l, c.Handler(aclHandler))
 	cliauthtype := tls.NoClientCert
 	var clicp *x509.CertPool
-	if clientAuthEnabled {
+	if opts.clientCACert != nil {
 		cliauthtype = tls.VerifyClientCertIfGiven
 
 		clicp = x509.NewCertPool()
@@ -0,0 +1,463 @@
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/btncrypt"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/metadata"
+	"github.com/nyaxt/otaru/metadata/statesnapshot"
+)
+
+const (
+	currentLogFilename = "audit.log"
+	rotatedLogPrefix   = "audit-"
+	rotatedLogSuffix   = ".log"
+	// Rotated log files are named with this suffix until they are archived.
+	unarchivedLogSuffix = ".unarchived" + rotatedLogSuffix
+
+	DefaultMaxSize  = 16 * 1024 * 1024
+	DefaultMaxFiles = 8
+)
+
+// Actor identifies who issued a filesystem mutation.
+type Actor struct {
+	// Source is the interface the request came through, e.g. "fuse", "grpc" or "http".
+	Source string
+	// User is the identity of the requester, e.g. the clientauth.UserInfo or the uid of the fuse caller.
+	User string
+}
+
+func (a Actor) String() string {
+	return fmt.Sprintf("%s:%s", a.Source, a.User)
+}
+
+// Entry is a record of a filesystem mutation.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	User   string    `json:"user"`
+	Op     string    `json:"op"`
+	Path   string    `json:"path"`
+	// Path of the destination on "rename".
+	NewPath string     `json:"new_path,omitempty"`
+	ID      inodedb.ID `json:"id,omitempty"`
+	// TxID of the inodedb transaction applying the mutation. 0 on "write", whose
+	// data is committed over many transactions.
+	TxID  inodedb.TxID `json:"txid,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+// Recorder receives the entries to be logged.
+type Recorder interface {
+	Record(e Entry)
+}
+
+type Config struct {
+	// Dir is the local directory to keep the log files in.
+	Dir string
+	// The log file is rotated once it exceeds MaxSize bytes.
+	MaxSize int64
+	// Up to MaxFiles rotated log files are kept locally.
+	MaxFiles int
+
+	// If ArchiveBS is non-nil, rotated log files are encrypted with Cipher and
+	// stored in ArchiveBS as metadata blobs. A rotated log file is kept
+	// locally, regardless of MaxFiles, until it is archived. Failed archives
+	// are retried on the next rotation or restart.
+	ArchiveBS blobstore.BlobStore
+	Cipher    *btncrypt.Cipher
+}
+
+// Log is an append-only audit log stored as json lines in local files.
+type Log struct {
+	cfg Config
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+
+	// archiving holds the paths of the rotated log files being archived.
+	archiving map[string]struct{}
+	wg        sync.WaitGroup
+}
+
+var _ = Recorder(&Log{})
+
+func New(cfg Config) (*Log, error) {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxSize
+	}
+	if cfg.MaxFiles <= 0 {
+		cfg.MaxFiles = DefaultMaxFiles
+	}
+	if cfg.ArchiveBS != nil && cfg.Cipher == nil {
+		return nil, fmt.Errorf("Cipher must be specified to archive the audit log.")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("Failed to create audit log dir: %v", err)
+	}
+
+	l := &Log{cfg: cfg, archiving: make(map[string]struct{})}
+	if err := l.openWithLock(); err != nil {
+		return nil, err
+	}
+	if cfg.ArchiveBS != nil {
+		// Archive the log files left unarchived by the previous run.
+		l.mu.Lock()
+		err := l.archiveWithLock()
+		l.mu.Unlock()
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+func (l *Log) currentPath() string {
+	return path.Join(l.cfg.Dir, currentLogFilename)
+}
+
+func (l *Log) openWithLock() error {
+	f, err := os.OpenFile(l.currentPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to open audit log: %v", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("Failed to stat audit log: %v", err)
+	}
+	l.f = f
+	l.size = fi.Size()
+	return nil
+}
+
+// Record appends the entry to the log. Failures are logged, but not returned,
+// so that the audit log doesn't fail the mutation already applied.
+func (l *Log) Record(e Entry) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	bs, err := json.Marshal(e)
+	if err != nil {
+		zap.S().Errorf("Failed to encode audit log entry %+v: %v", e, err)
+		return
+	}
+	bs = append(bs, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		zap.S().Errorf("Audit log closed. Dropping entry: %s", bs)
+		return
+	}
+	n, err := l.f.Write(bs)
+	l.size += int64(n)
+	if err != nil {
+		zap.S().Errorf("Failed to write audit log entry: %v", err)
+		return
+	}
+
+	if l.size >= l.cfg.MaxSize {
+		if err := l.rotateWithLock(); err != nil {
+			zap.S().Errorf("Failed to rotate audit log: %v", err)
+		}
+	}
+}
+
+func (l *Log) rotatedLogPaths() ([]string, error) {
+	ps, err := filepath.Glob(path.Join(l.cfg.Dir, rotatedLogPrefix+"*"+rotatedLogSuffix))
+	if err != nil {
+		return nil, err
+	}
+	// The timestamp in the filenames sorts them chronologically.
+	sort.Strings(ps)
+	return ps, nil
+}
+
+// rotatedLogTimestamp returns the timestamp to name the rotated log file, which doesn't exist yet.
+func (l *Log) rotatedLogTimestamp(t time.Time) string {
+	for {
+		ts := t.UTC().Format("20060102.150405.000000000")
+		_, err := os.Stat(l.rotatedLogPath(ts))
+		_, uerr := os.Stat(l.unarchivedLogPath(ts))
+		if os.IsNotExist(err) && os.IsNotExist(uerr) {
+			return ts
+		}
+		t = t.Add(time.Nanosecond)
+	}
+}
+
+func (l *Log) rotatedLogPath(ts string) string {
+	return path.Join(l.cfg.Dir, rotatedLogPrefix+ts+rotatedLogSuffix)
+}
+
+func (l *Log) unarchivedLogPath(ts string) string {
+	return path.Join(l.cfg.Dir, rotatedLogPrefix+ts+unarchivedLogSuffix)
+}
+
+func (l *Log) rotateWithLock() error {
+	if err := l.f.Close(); err != nil {
+		zap.S().Warnf("Failed to close audit log before rotation: %v", err)
+	}
+	l.f = nil
+
+	ts := l.rotatedLogTimestamp(time.Now())
+	rotated := l.rotatedLogPath(ts)
+	if l.cfg.ArchiveBS != nil {
+		rotated = l.unarchivedLogPath(ts)
+	}
+	if err := os.Rename(l.currentPath(), rotated); err != nil {
+		if err := l.openWithLock(); err != nil {
+			return err
+		}
+		return fmt.Errorf("Failed to rename audit log: %v", err)
+	}
+	if err := l.openWithLock(); err != nil {
+		return err
+	}
+
+	if l.cfg.ArchiveBS != nil {
+		if err := l.archiveWithLock(); err != nil {
+			return err
+		}
+	}
+	return l.pruneWithLock()
+}
+
+// pruneWithLock removes the oldest rotated log files in excess of MaxFiles.
+// The ones not yet archived are kept.
+func (l *Log) pruneWithLock() error {
+	ps, err := l.rotatedLogPaths()
+	if err != nil {
+		return fmt.Errorf("Failed to list rotated audit logs: %v", err)
+	}
+	n := len(ps)
+	for _, p := range ps {
+		if n <= l.cfg.MaxFiles {
+			break
+		}
+		if strings.HasSuffix(p, unarchivedLogSuffix) {
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			zap.S().Warnf("Failed to remove old audit log %q: %v", p, err)
+		}
+		n--
+	}
+	return nil
+}
+
+// archiveWithLock starts archiving the rotated log files not yet archived.
+func (l *Log) archiveWithLock() error {
+	ps, err := filepath.Glob(path.Join(l.cfg.Dir, rotatedLogPrefix+"*"+unarchivedLogSuffix))
+	if err != nil {
+		return fmt.Errorf("Failed to list unarchived audit logs: %v", err)
+	}
+	for _, p := range ps {
+		if _, ok := l.archiving[p]; ok {
+			continue
+		}
+		l.archiving[p] = struct{}{}
+
+		l.wg.Add(1)
+		go func(p string) {
+			defer l.wg.Done()
+			err := l.archiveFile(p)
+
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			delete(l.archiving, p)
+			if err != nil {
+				zap.S().Errorf("Failed to archive audit log %q. Keeping it locally to retry later: %v", p, err)
+				return
+			}
+			if err := l.pruneWithLock(); err != nil {
+				zap.S().Warnf("Failed to prune audit logs: %v", err)
+			}
+		}(p)
+	}
+	return nil
+}
+
+// archiveFile archives the unarchived log file p, and then renames it as
+// archived.
+func (l *Log) archiveFile(p string) error {
+	ts := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(p), rotatedLogPrefix), unarchivedLogSuffix)
+	bs, err := ioutil.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("Failed to read rotated audit log: %v", err)
+	}
+	if err := l.archive(metadata.AuditLogBlobpath(ts), bs); err != nil {
+		return err
+	}
+	if err := os.Rename(p, l.rotatedLogPath(ts)); err != nil {
+		return fmt.Errorf("Failed to rename archived audit log: %v", err)
+	}
+	return nil
+}
+
+func (l *Log) archive(bp string, bs []byte) error {
+	w, err := l.cfg.ArchiveBS.OpenWriter(bp)
+	if err != nil {
+		return fmt.Errorf("Failed to open blob %q: %v", bp, err)
+	}
+	if err := statesnapshot.SaveBytes(w, l.cfg.Cipher, bs); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("Failed to close blob %q: %v", bp, err)
+	}
+	zap.S().Infof("Archived audit log as %q", bp)
+	return nil
+}
+
+type Query struct {
+	// Entries in [Since, Until) are returned. Zero value means unbounded.
+	Since time.Time
+	Until time.Time
+
+	// Filters applied if non-empty.
+	User       string
+	Op         string
+	PathPrefix string
+
+	// If Limit > 0, only the latest Limit entries are returned.
+	Limit int
+}
+
+func hasPathPrefix(p, prefix string) bool {
+	prefix = path.Clean("/" + prefix)
+	return prefix == "/" || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+func (q *Query) Match(e *Entry) bool {
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Time.Before(q.Until) {
+		return false
+	}
+	if q.User != "" && e.User != q.User {
+		return false
+	}
+	if q.Op != "" && e.Op != q.Op {
+		return false
+	}
+	if q.PathPrefix != "" && !hasPathPrefix(e.Path, q.PathPrefix) && !hasPathPrefix(e.NewPath, q.PathPrefix) {
+		return false
+	}
+	return true
+}
+
+// openLogFiles opens the locally kept log files, oldest first. The current
+// log file is read only up to its size at the time of the call, so that the
+// files can be scanned without l.mu while entries are being recorded.
+func (l *Log) openLogFiles() ([]*os.File, []io.Reader, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ps, err := l.rotatedLogPaths()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to list rotated audit logs: %v", err)
+	}
+	ps = append(ps, l.currentPath())
+
+	fs := make([]*os.File, 0, len(ps))
+	rs := make([]io.Reader, 0, len(ps))
+	for _, p := range ps {
+		f, err := os.Open(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			for _, f := range fs {
+				f.Close()
+			}
+			return nil, nil, fmt.Errorf("Failed to open audit log %q: %v", p, err)
+		}
+		fs = append(fs, f)
+		rs = append(rs, f)
+	}
+	if len(fs) > 0 && fs[len(fs)-1].Name() == l.currentPath() {
+		rs[len(rs)-1] = io.LimitReader(fs[len(fs)-1], l.size)
+	}
+	return fs, rs, nil
+}
+
+// Query returns the entries matching q from the locally kept log files, oldest first.
+func (l *Log) Query(q Query) ([]Entry, error) {
+	fs, rs, err := l.openLogFiles()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, f := range fs {
+			f.Close()
+		}
+	}()
+
+	es := make([]Entry, 0)
+	for i, r := range rs {
+		if err := scanLogFile(fs[i].Name(), r, func(e Entry) {
+			if !q.Match(&e) {
+				return
+			}
+			es = append(es, e)
+			if q.Limit > 0 && len(es) > q.Limit {
+				es = es[1:]
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return es, nil
+}
+
+func scanLogFile(p string, r io.Reader, cb func(e Entry)) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		var e Entry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			zap.S().Warnf("Skipping malformed audit log line in %q: %v", p, err)
+			continue
+		}
+		cb(e)
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("Failed to read audit log %q: %v", p, err)
+	}
+	return nil
+}
+
+func (l *Log) Close() error {
+	l.mu.Lock()
+	var err error
+	if l.f != nil {
+		err = l.f.Close()
+		l.f = nil
+	}
+	l.mu.Unlock()
+
+	l.wg.Wait()
+	return err
+}
@@ -0,0 +1,243 @@
+package auditlog_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyaxt/otaru/auditlog"
+	"github.com/nyaxt/otaru/chunkstore"
+	"github.com/nyaxt/otaru/metadata"
+	"github.com/nyaxt/otaru/testutils"
+)
+
+func init() { testutils.EnsureLogger() }
+
+func TestLog_RecordQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlogtest")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, err := auditlog.New(auditlog.Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	t0 := time.Now()
+	l.Record(auditlog.Entry{Time: t0, Source: "grpc", User: "alice", Op: "create_file", Path: "/a/foo.txt", TxID: 3})
+	l.Record(auditlog.Entry{Time: t0.Add(time.Second), Source: "fuse", User: "uid=1000", Op: "write", Path: "/b/bar.txt"})
+	l.Record(auditlog.Entry{Time: t0.Add(2 * time.Second), Source: "grpc", User: "alice", Op: "rename", Path: "/b/bar.txt", NewPath: "/a/bar.txt", TxID: 5})
+	l.Record(auditlog.Entry{Time: t0.Add(3 * time.Second), Source: "http", User: "bob", Op: "write", Path: "/a/foo.txt", Error: "permission denied"})
+
+	tcs := []struct {
+		q        auditlog.Query
+		expected []string
+	}{
+		{auditlog.Query{}, []string{"create_file", "write", "rename", "write"}},
+		{auditlog.Query{User: "alice"}, []string{"create_file", "rename"}},
+		{auditlog.Query{Op: "write"}, []string{"write", "write"}},
+		{auditlog.Query{PathPrefix: "/a"}, []string{"create_file", "rename", "write"}},
+		{auditlog.Query{Since: t0.Add(time.Second), Until: t0.Add(3 * time.Second)}, []string{"write", "rename"}},
+		{auditlog.Query{Limit: 1}, []string{"write"}},
+	}
+	for _, tc := range tcs {
+		es, err := l.Query(tc.q)
+		if err != nil {
+			t.Errorf("Query(%+v) failed: %v", tc.q, err)
+			continue
+		}
+		ops := make([]string, 0, len(es))
+		for _, e := range es {
+			ops = append(ops, e.Op)
+		}
+		if fmt.Sprint(ops) != fmt.Sprint(tc.expected) {
+			t.Errorf("Query(%+v): %v, expected %v", tc.q, ops, tc.expected)
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+
+	// Entries are kept across restarts.
+	l, err = auditlog.New(auditlog.Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer l.Close()
+	es, err := l.Query(auditlog.Query{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(es) != 4 {
+		t.Fatalf("len(es): %d", len(es))
+	}
+	if es[3].User != "bob" || es[3].Error != "permission denied" || es[2].TxID != 5 || es[2].NewPath != "/a/bar.txt" {
+		t.Errorf("Unexpected entries: %+v", es)
+	}
+}
+
+func TestLog_RotateArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlogtest")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bs := testutils.TestFileBlobStore()
+	l, err := auditlog.New(auditlog.Config{
+		Dir:       dir,
+		MaxSize:   256,
+		MaxFiles:  2,
+		ArchiveBS: bs,
+		Cipher:    testutils.TestCipher(),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	const n = 32
+	for i := 0; i < n; i++ {
+		l.Record(auditlog.Entry{Source: "grpc", User: "alice", Op: "remove", Path: fmt.Sprintf("/file%d", i)})
+	}
+	// Wait for the archives, as the rotated log files are kept until archived.
+	if err := l.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+
+	es, err := l.Query(auditlog.Query{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(es) == 0 || len(es) >= n {
+		t.Errorf("Expected the old entries to be rotated out, but got %d entries", len(es))
+	} else if es[len(es)-1].Path != fmt.Sprintf("/file%d", n-1) {
+		t.Errorf("Unexpected last entry: %+v", es[len(es)-1])
+	}
+
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(fis) != 3 {
+		t.Errorf("Expected the current log and 2 rotated logs, but got %d files", len(fis))
+	}
+
+	bps, err := bs.ListBlobs()
+	if err != nil {
+		t.Fatalf("ListBlobs failed: %v", err)
+	}
+	narchived := 0
+	var archived string
+	for _, bp := range bps {
+		if !strings.HasPrefix(bp, metadata.AuditLogBlobpathPrefix) {
+			t.Errorf("Unexpected blob %q", bp)
+			continue
+		}
+		if !metadata.IsMetadataBlobpath(bp) {
+			t.Errorf("Archived blob %q is not a metadata blob", bp)
+		}
+
+		r, err := bs.OpenReader(bp)
+		if err != nil {
+			t.Fatalf("OpenReader failed: %v", err)
+		}
+		cr, err := chunkstore.NewChunkReader(r, testutils.TestCipher())
+		if err != nil {
+			t.Fatalf("NewChunkReader failed: %v", err)
+		}
+		p, err := ioutil.ReadAll(io.LimitReader(cr, int64(cr.Length())))
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		r.Close()
+
+		archived += string(p)
+		narchived++
+	}
+	if narchived < len(fis)-1 {
+		t.Errorf("Expected every rotated log to be archived, but got %d blobs", narchived)
+	}
+	for i := 0; i < 2; i++ {
+		if !strings.Contains(archived, fmt.Sprintf("\"/file%d\"", i)) {
+			t.Errorf("Entry %d missing in the archive", i)
+		}
+	}
+}
+
+type unavailableBlobStore struct{}
+
+func (unavailableBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("unavailable")
+}
+
+func (unavailableBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("unavailable")
+}
+
+func TestLog_KeepUnarchived(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlogtest")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := auditlog.Config{
+		Dir:       dir,
+		MaxSize:   256,
+		MaxFiles:  2,
+		ArchiveBS: unavailableBlobStore{},
+		Cipher:    testutils.TestCipher(),
+	}
+	l, err := auditlog.New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	const n = 32
+	for i := 0; i < n; i++ {
+		l.Record(auditlog.Entry{Source: "grpc", User: "alice", Op: "remove", Path: fmt.Sprintf("/file%d", i)})
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+
+	// None of the rotated log files are pruned while the archive fails.
+	es, err := l.Query(auditlog.Query{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(es) != n {
+		t.Errorf("Expected all %d entries to be kept, but got %d", n, len(es))
+	}
+
+	// The next run archives them, and then prunes them.
+	bs := testutils.TestFileBlobStore()
+	cfg.ArchiveBS = bs
+	l, err = auditlog.New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(fis) != 3 {
+		t.Errorf("Expected the current log and 2 rotated logs, but got %d files", len(fis))
+	}
+	bps, err := bs.ListBlobs()
+	if err != nil {
+		t.Fatalf("ListBlobs failed: %v", err)
+	}
+	if len(bps) < len(fis)-1 || len(bps) >= n {
+		t.Errorf("Unexpected number of archived blobs: %d", len(bps))
+	}
+}
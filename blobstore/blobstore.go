@@ -9,6 +9,27 @@ type BlobStore interface {
 	OpenReader(blobpath string) (io.ReadCloser, error)
 }
 
+// WriteAborter is implemented by the blob writers which can discard the blob
+// written so far, instead of committing it on Close.
+type WriteAborter interface {
+	Abort() error
+}
+
+// NoRecallReader is implemented by the blobstores which may move the blob on
+// OpenReader, e.g. recall it from a cold tier. OpenReaderNoRecall reads the
+// blob where it is.
+type NoRecallReader interface {
+	OpenReaderNoRecall(blobpath string) (io.ReadCloser, error)
+}
+
+// OpenReaderNoRecall opens the blob for reading without moving it, if bs supports it.
+func OpenReaderNoRecall(bs BlobStore, blobpath string) (io.ReadCloser, error) {
+	if nrr, ok := bs.(NoRecallReader); ok {
+		return nrr.OpenReaderNoRecall(blobpath)
+	}
+	return bs.OpenReader(blobpath)
+}
+
 type BlobLister interface {
 	ListBlobs() ([]string, error)
 }
@@ -0,0 +1,95 @@
+package cachedblobstore
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nyaxt/otaru/util/bwlimit"
+)
+
+// BandwidthLimitConfig specifies the throughput limit of the transfers
+// between the cache and the backend blobstore.
+type BandwidthLimitConfig struct {
+	// Upload limit in bytes/sec applied to cache writebacks. 0 means unlimited.
+	UploadBytesPerSec int64
+	// Download limit in bytes/sec applied to cache invalidations. 0 means unlimited.
+	DownloadBytesPerSec int64
+	// If non-nil, the limits are lifted while the current time is within the window.
+	FullBandwidthWindow *bwlimit.Window
+}
+
+// BandwidthThrottle is shared among all CacheSyncer workers and cache
+// invalidations of a CachedBlobStore.
+type BandwidthThrottle struct {
+	upload   *bwlimit.Limiter
+	download *bwlimit.Limiter
+
+	mu     sync.Mutex
+	window *bwlimit.Window
+	now    func() time.Time
+}
+
+func NewBandwidthThrottle() *BandwidthThrottle {
+	return &BandwidthThrottle{
+		upload:   bwlimit.NewLimiter(0),
+		download: bwlimit.NewLimiter(0),
+		now:      time.Now,
+	}
+}
+
+func (t *BandwidthThrottle) SetConfig(cfg BandwidthLimitConfig) {
+	t.upload.SetLimit(cfg.UploadBytesPerSec)
+	t.download.SetLimit(cfg.DownloadBytesPerSec)
+
+	t.mu.Lock()
+	t.window = cfg.FullBandwidthWindow
+	t.mu.Unlock()
+}
+
+func (t *BandwidthThrottle) Config() BandwidthLimitConfig {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return BandwidthLimitConfig{
+		UploadBytesPerSec:   t.upload.Limit(),
+		DownloadBytesPerSec: t.download.Limit(),
+		FullBandwidthWindow: t.window,
+	}
+}
+
+// IsFullBandwidth returns true if the limits are currently lifted by the
+// FullBandwidthWindow.
+func (t *BandwidthThrottle) IsFullBandwidth() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.window != nil && t.window.Contains(t.now())
+}
+
+func (t *BandwidthThrottle) waitFunc(l *bwlimit.Limiter) bwlimit.WaitFunc {
+	return func(ctx context.Context, n int) error {
+		if t.IsFullBandwidth() {
+			return nil
+		}
+		return l.Wait(ctx, n)
+	}
+}
+
+// UploadWriter returns w throttled by the upload limit until ctx is done.
+// The writes after that aren't throttled, so that a writeback in progress on
+// shutdown completes without delay instead of failing halfway.
+func (t *BandwidthThrottle) UploadWriter(ctx context.Context, w io.Writer) io.Writer {
+	wait := t.waitFunc(t.upload)
+	return bwlimit.NewWriter(ctx, w, func(ctx context.Context, n int) error {
+		if err := wait(ctx, n); err != nil && ctx.Err() == nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (t *BandwidthThrottle) DownloadReader(ctx context.Context, r io.Reader) io.Reader {
+	return bwlimit.NewReader(ctx, r, t.waitFunc(t.download))
+}
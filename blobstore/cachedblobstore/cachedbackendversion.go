@@ -83,13 +83,13 @@ func (cbv *CachedBackendVersion) Query(blobpath string) (version.Version, error)
 		return ver, nil
 	}
 
-	r, err := cbv.backendbs.OpenReader(blobpath)
+	r, err := blobstore.OpenReaderNoRecall(cbv.backendbs, blobpath)
 	if err != nil {
 		if util.IsNotExist(err) {
 			cbv.cache[blobpath] = 0
 			return 0, nil
 		}
-		return -1, fmt.Errorf("Failed to open backend blob for ver query: %v", err)
+		return -1, fmt.Errorf("Failed to open backend blob for ver query: %w", err)
 	}
 	defer func() {
 		if err := r.Close(); err != nil {
@@ -98,7 +98,7 @@ func (cbv *CachedBackendVersion) Query(blobpath string) (version.Version, error)
 	}()
 	ver, err := cbv.queryVersion(r)
 	if err != nil {
-		return -1, fmt.Errorf("Failed to query backend blob ver: %v", err)
+		return -1, fmt.Errorf("Failed to query backend blob ver: %w", err)
 	}
 
 	queryMissCounter.Inc()
@@ -119,7 +119,7 @@ func (cbv *CachedBackendVersion) decodeCacheFromGob(dec *gob.Decoder) error {
 	defer cbv.mu.Unlock()
 
 	if err := dec.Decode(&cbv.cache); err != nil {
-		return fmt.Errorf("Failed to decode cache map: %v", err)
+		return fmt.Errorf("Failed to decode cache map: %w", err)
 	}
 	cbv.updateNumCacheEntriesGauge()
 	return nil
@@ -146,7 +146,7 @@ func (cbv *CachedBackendVersion) encodeCacheToGob(enc *gob.Encoder) error {
 	defer cbv.mu.Unlock()
 
 	if err := enc.Encode(cbv.cache); err != nil {
-		return fmt.Errorf("Failed to encode cache map: %v", err)
+		return fmt.Errorf("Failed to encode cache map: %w", err)
 	}
 	return nil
 }
@@ -283,6 +283,21 @@ func (mgr *CachedBlobEntriesManager) ListBlobs() (bpaths []string) {
 	return
 }
 
+func (mgr *CachedBlobEntriesManager) ModifiedSince(blobpath string, t time.Time) (modified bool) {
+	ch := make(chan struct{})
+	mgr.reqC <- func() {
+		defer close(ch)
+
+		be, ok := mgr.entries[blobpath]
+		if !ok {
+			return
+		}
+		modified = be.modifiedSince(t)
+	}
+	<-ch
+	return
+}
+
 func (mgr *CachedBlobEntriesManager) RemoveBlob(blobpath string) (err error) {
 	ch := make(chan struct{})
 	mgr.reqC <- func() {
@@ -338,6 +353,29 @@ func (mgr *CachedBlobEntriesManager) tryCloseEntry(be *CachedBlobEntry) {
 	mgr.updateNumCacheEntriesGauge()
 }
 
+// CloseEntryIfUnused closes the entry for blobpath if it has no open handles.
+// Returns false if the entry couldn't be closed.
+func (mgr *CachedBlobEntriesManager) CloseEntryIfUnused(blobpath string) (ok bool) {
+	ch := make(chan struct{})
+	mgr.reqC <- func() {
+		defer close(ch)
+
+		be, exists := mgr.entries[blobpath]
+		if !exists {
+			ok = true
+			return
+		}
+		if len(be.handles) != 0 {
+			return
+		}
+		mgr.tryCloseEntry(be)
+		_, exists = mgr.entries[blobpath]
+		ok = !exists
+	}
+	<-ch
+	return
+}
+
 func (mgr *CachedBlobEntriesManager) CloseEntryForTesting(blobpath string) {
 	ch := make(chan struct{})
 	mgr.reqC <- func() {
@@ -426,6 +464,30 @@ func (mgr *CachedBlobEntriesManager) DropCacheEntry(blobpath string, cbs *Cached
 	return
 }
 
+// RunIfInactive runs fn while blocking any new entry for blobpath from being
+// opened. fn is not run, and ok is false, if there is an active entry for
+// the blobpath. A closed entry for the blobpath is discarded if fn succeeds.
+func (mgr *CachedBlobEntriesManager) RunIfInactive(blobpath string, fn func() error) (ok bool, err error) {
+	ch := make(chan struct{})
+	mgr.reqC <- func() {
+		defer close(ch)
+
+		if be, exists := mgr.entries[blobpath]; exists {
+			if be.state != CacheEntryClosed && be.state != CacheEntryErroredClosed {
+				return
+			}
+		}
+		ok = true
+		if err = fn(); err != nil {
+			return
+		}
+		delete(mgr.entries, blobpath)
+		mgr.updateNumCacheEntriesGauge()
+	}
+	<-ch
+	return
+}
+
 func (mgr *CachedBlobEntriesManager) updateNumCacheEntriesGauge() {
 	numCacheEntriesGauge.Set(float64(len(mgr.entries)))
 }
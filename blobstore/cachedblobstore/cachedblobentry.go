@@ -78,6 +78,15 @@ func (s CacheEntryState) NeedsSync() bool {
 	return s == CacheEntryDirty || s == CacheEntryWriteInProgress
 }
 
+// IsDirty returns true if the backend blob may not have the cached contents yet.
+func (s CacheEntryState) IsDirty() bool {
+	return s == CacheEntryWriteInProgress ||
+		s == CacheEntryWritebackInProgress ||
+		s == CacheEntryStaleWritebackInProgress ||
+		s == CacheEntryDirty ||
+		s == CacheEntryDirtyClosing
+}
+
 func (s CacheEntryState) String() string {
 	switch s {
 	case CacheEntryUninitialized:
@@ -312,7 +321,7 @@ func (be *CachedBlobEntry) invalidateInternal(ctx context.Context) error {
 
 	backendr, err := cbs.backendbs.OpenReader(blobpath)
 	if err != nil {
-		return fmt.Errorf("Failed to open backend blob for cache invalidation: %v", err)
+		return fmt.Errorf("Failed to open backend blob for cache invalidation: %w", err)
 	}
 	defer func() {
 		if err := backendr.Close(); err != nil {
@@ -334,8 +343,9 @@ func (be *CachedBlobEntry) invalidateInternal(ctx context.Context) error {
 
 	buf := make([]byte, invalidateBlockSize)
 	done := false
+	throttledr := cbs.throttle.DownloadReader(ctx, backendr)
 	for !done {
-		nr, er := cancellable.Read(ctx, backendr, buf)
+		nr, er := cancellable.Read(ctx, throttledr, buf)
 		if nr > 0 {
 			nw, ew := cachew.Write(buf[:nr])
 			if nw > 0 {
@@ -352,7 +362,7 @@ func (be *CachedBlobEntry) invalidateInternal(ctx context.Context) error {
 				be.mu.Unlock()
 			}
 			if ew != nil {
-				return fmt.Errorf("Failed to write backend blob content to cache: %v", ew)
+				return fmt.Errorf("Failed to write backend blob content to cache: %w", ew)
 			}
 			if nw != nr {
 				return fmt.Errorf("Failed to write backend blob content to cache: %v", io.ErrShortWrite)
@@ -411,14 +421,14 @@ func (be *CachedBlobEntry) initializeWithLock() error {
 		be.updateState(CacheEntryErrored)
 		initialEntryStateFailedToOpenCache.Inc()
 		go be.CloseWithLogErr(abandonAndClose)
-		return fmt.Errorf("Failed to open cache blob: %v", err)
+		return fmt.Errorf("Failed to open cache blob: %w", err)
 	}
 	cachever, err := cbs.queryVersion(&blobstore.OffsetReader{cachebh, 0})
 	if err != nil {
 		be.updateState(CacheEntryErrored)
 		initialEntryStateFailedToQueryCachedBlobVer.Inc()
 		go be.CloseWithLogErr(abandonAndClose)
-		return fmt.Errorf("Failed to query cached blob ver: %v", err)
+		return fmt.Errorf("Failed to query cached blob ver: %w", err)
 	}
 	backendver, err := cbs.bever.Query(be.blobpath)
 	if err != nil {
@@ -452,7 +462,7 @@ func (be *CachedBlobEntry) initializeWithLock() error {
 			be.updateState(CacheEntryErrored)
 			go be.CloseWithLogErr(abandonAndClose)
 			initialEntryStateFailedToQueryBackendBlobSize.Inc()
-			return fmt.Errorf("Failed to query backend blobsize: %v", err)
+			return fmt.Errorf("Failed to query backend blobsize: %w", err)
 		}
 		if be.bloblen == 0 {
 			be.updateState(CacheEntryClean)
@@ -542,6 +552,13 @@ func (be *CachedBlobEntry) PRead(p []byte, offset int64) error {
 func (be *CachedBlobEntry) LastWrite() time.Time { return be.lastWrite }
 func (be *CachedBlobEntry) LastSync() time.Time  { return be.lastSync }
 
+func (be *CachedBlobEntry) modifiedSince(t time.Time) bool {
+	be.mu.Lock()
+	defer be.mu.Unlock()
+
+	return be.state.IsDirty() || be.lastWrite.After(t)
+}
+
 func (be *CachedBlobEntry) markWriteInProgressWithLock() {
 	now := time.Now()
 	be.lastUsed = now
@@ -662,12 +679,12 @@ func (be *CachedBlobEntry) writeBackWithLock(wbc writeBackCaller) error {
 	// queryVersion is issued while holding be.mu, so it is guaranteed that no racing writes to be.cbs are issued after this query.
 	cachever, err := be.cbs.queryVersion(&blobstore.OffsetReader{be.cachebh, 0})
 	if err != nil {
-		return fmt.Errorf("Failed to query cached blob ver: %v", err)
+		return fmt.Errorf("Failed to query cached blob ver: %w", err)
 	}
 
 	bever, err := be.cbs.bever.Query(be.blobpath)
 	if err != nil {
-		return fmt.Errorf("Failed to query backend blob ver: %v", err)
+		return fmt.Errorf("Failed to query backend blob ver: %w", err)
 	}
 	if bever == cachever {
 		zap.S().Debugf("writeBackWithLock \"%s\" write operations to the cache didn't increment its version: %v", be.blobpath, bever)
@@ -699,7 +716,7 @@ func (be *CachedBlobEntry) writeBackWithLock(wbc writeBackCaller) error {
 	if err != nil {
 		be.mu.Lock()
 		be.updateState(CacheEntryErrored)
-		return fmt.Errorf("Failed to open backend blob writer: %v", err)
+		return fmt.Errorf("Failed to open backend blob writer: %w", err)
 	}
 	defer func() {
 		if err := w.Close(); err != nil {
@@ -707,10 +724,10 @@ func (be *CachedBlobEntry) writeBackWithLock(wbc writeBackCaller) error {
 		}
 	}()
 	r := io.LimitReader(&blobstore.OffsetReader{be.cachebh, 0}, be.cachebh.Size())
-	if _, err := io.Copy(w, r); err != nil {
+	if _, err := io.Copy(be.cbs.throttle.UploadWriter(be.cbs.uploadCtx, w), r); err != nil {
 		be.mu.Lock()
 		be.updateState(CacheEntryErrored)
-		return fmt.Errorf("Failed to copy dirty data to backend blob writer: %v", err)
+		return fmt.Errorf("Failed to copy dirty data to backend blob writer: %w", err)
 	}
 
 	be.mu.Lock()
@@ -725,7 +742,7 @@ func (be *CachedBlobEntry) writeBackWithLock(wbc writeBackCaller) error {
 		if err != nil {
 			zap.S().Errorf("Failed to query cached blob ver: %v", err)
 			be.updateState(CacheEntryErrored)
-			return fmt.Errorf("Failed to query cached blob ver: %v", err)
+			return fmt.Errorf("Failed to query cached blob ver: %w", err)
 		}
 		if cacheverAfterWriteback == cachever {
 			be.updateState(CacheEntryClean)
@@ -797,7 +814,7 @@ Loop:
 
 	go func() {
 		if err := be.writeBackWithLock(callerSync); err != nil {
-			errC <- fmt.Errorf("Failed to writeback dirty: %v", err)
+			errC <- fmt.Errorf("Failed to writeback dirty: %w", err)
 		} else {
 			errC <- nil
 		}
@@ -806,7 +823,7 @@ Loop:
 	go func() {
 		if cs, ok := be.cachebh.(util.Syncer); ok {
 			if err := cs.Sync(); err != nil {
-				errC <- fmt.Errorf("Failed to sync cache blob: %v", err)
+				errC <- fmt.Errorf("Failed to sync cache blob: %w", err)
 			} else {
 				errC <- nil
 			}
@@ -918,7 +935,7 @@ Loop:
 
 	if mode == writebackAndClose {
 		if err := be.writeBackWithLock(callerClose); err != nil {
-			return fmt.Errorf("Failed to writeback dirty: %v", err)
+			return fmt.Errorf("Failed to writeback dirty: %w", err)
 		}
 		be.syncCount++
 		be.lastSync = time.Now()
@@ -926,7 +943,7 @@ Loop:
 
 	if be.cachebh != nil {
 		if err := be.cachebh.Close(); err != nil {
-			return fmt.Errorf("Failed to close cache bh: %v", err)
+			return fmt.Errorf("Failed to close cache bh: %w", err)
 		}
 	}
 
@@ -3,6 +3,7 @@ package cachedblobstore
 import (
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"context"
@@ -10,6 +11,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/nyaxt/otaru/blobstore"
@@ -56,6 +58,15 @@ type CachedBlobStore struct {
 	entriesmgr *CachedBlobEntriesManager
 	usagestats *CacheUsageStats
 	syncer     *CacheSyncer
+	throttle   *BandwidthThrottle
+
+	// uploadCtx is cancelled on Quit, which lifts the upload throttle so
+	// that the writebacks on shutdown aren't delayed.
+	uploadCtx    context.Context
+	cancelUpload context.CancelFunc
+
+	mu             sync.Mutex
+	evictionPolicy EvictionPolicy
 }
 
 func New(backendbs blobstore.BlobStore, cachebs blobstore.RandomAccessBlobStore, s *scheduler.Scheduler, flags int, queryVersion version.QueryFunc) (*CachedBlobStore, error) {
@@ -70,6 +81,7 @@ func New(backendbs blobstore.BlobStore, cachebs blobstore.RandomAccessBlobStore,
 		return nil, fmt.Errorf("CachedBlobStore requested, but cachebs doesn't allow writes")
 	}
 
+	uploadCtx, cancelUpload := context.WithCancel(context.Background())
 	cbs := &CachedBlobStore{
 		backendbs:    backendbs,
 		cachebs:      cachebs,
@@ -79,6 +91,11 @@ func New(backendbs blobstore.BlobStore, cachebs blobstore.RandomAccessBlobStore,
 		bever:        NewCachedBackendVersion(backendbs, queryVersion),
 		entriesmgr:   NewCachedBlobEntriesManager(),
 		usagestats:   NewCacheUsageStats(),
+		throttle:     NewBandwidthThrottle(),
+		uploadCtx:    uploadCtx,
+		cancelUpload: cancelUpload,
+
+		evictionPolicy: DefaultEvictionPolicy,
 	}
 	if fl.IsWriteAllowed(flags) {
 		cbs.syncer = NewCacheSyncer(cbs.entriesmgr, defaultNumWorkers)
@@ -100,7 +117,14 @@ func New(backendbs blobstore.BlobStore, cachebs blobstore.RandomAccessBlobStore,
 }
 
 func (cbs *CachedBlobStore) RestoreState(c *btncrypt.Cipher) error {
-	return cbs.bever.RestoreStateFromBlobstore(c, cbs.cachebs)
+	var me error
+	if err := cbs.bever.RestoreStateFromBlobstore(c, cbs.cachebs); err != nil {
+		me = multierr.Append(me, fmt.Errorf("Failed to restore CachedBackendVersion: %v", err))
+	}
+	if err := cbs.usagestats.RestoreStateFromBlobstore(c, cbs.cachebs); err != nil {
+		me = multierr.Append(me, fmt.Errorf("Failed to restore CacheUsageStats: %v", err))
+	}
+	return me
 }
 
 type SaveStateTask struct {
@@ -118,7 +142,14 @@ func (t SaveStateTask) String() string {
 }
 
 func (cbs *CachedBlobStore) SaveState(c *btncrypt.Cipher) error {
-	return cbs.bever.SaveStateToBlobstore(c, cbs.cachebs)
+	var me error
+	if err := cbs.bever.SaveStateToBlobstore(c, cbs.cachebs); err != nil {
+		me = multierr.Append(me, fmt.Errorf("Failed to save CachedBackendVersion: %v", err))
+	}
+	if err := cbs.usagestats.SaveStateToBlobstore(c, cbs.cachebs); err != nil {
+		me = multierr.Append(me, fmt.Errorf("Failed to save CacheUsageStats: %v", err))
+	}
+	return me
 }
 
 func (cbs *CachedBlobStore) Sync() error {
@@ -130,6 +161,7 @@ func (cbs *CachedBlobStore) Sync() error {
 }
 
 func (cbs *CachedBlobStore) Quit() error {
+	cbs.cancelUpload()
 	err := cbs.Sync()
 	if cbs.syncer != nil {
 		cbs.syncer.Quit()
@@ -195,10 +227,39 @@ func (cbs *CachedBlobStore) Open(blobpath string, flags int) (blobstore.BlobHand
 	return be.OpenHandle(flags)
 }
 
+func (cbs *CachedBlobStore) SetBandwidthLimit(cfg BandwidthLimitConfig) {
+	cbs.throttle.SetConfig(cfg)
+}
+
+func (cbs *CachedBlobStore) BandwidthLimit() BandwidthLimitConfig {
+	return cbs.throttle.Config()
+}
+
+func (cbs *CachedBlobStore) IsFullBandwidth() bool {
+	return cbs.throttle.IsFullBandwidth()
+}
+
+func (cbs *CachedBlobStore) SetEvictionPolicy(p EvictionPolicy) {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+	cbs.evictionPolicy = p
+}
+
+func (cbs *CachedBlobStore) EvictionPolicy() EvictionPolicy {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+	return cbs.evictionPolicy
+}
+
 func (cbs *CachedBlobStore) DumpEntriesInfo() []*CachedBlobEntryInfo {
 	return cbs.entriesmgr.DumpEntriesInfo()
 }
 
+// UsageStats returns the access history of the cached blobs.
+func (cbs *CachedBlobStore) UsageStats() []UsageStat {
+	return cbs.usagestats.Snapshot()
+}
+
 func (*CachedBlobStore) ImplName() string { return "CachedBlobStore" }
 
 var _ = blobstore.BlobLister(&CachedBlobStore{})
@@ -236,6 +297,12 @@ func (cbs *CachedBlobStore) ListBlobs() ([]string, error) {
 
 var _ = blobstore.BlobRemover(&CachedBlobStore{})
 
+// ModifiedSince returns true if the cached blob has changes not yet written
+// back to the backend, or was written after t.
+func (cbs *CachedBlobStore) ModifiedSince(blobpath string, t time.Time) bool {
+	return cbs.entriesmgr.ModifiedSince(blobpath, t)
+}
+
 func (cbs *CachedBlobStore) RemoveBlob(blobpath string) error {
 	issuedRemoveBlob.Inc()
 
@@ -266,6 +333,17 @@ func (cbs *CachedBlobStore) RemoveBlob(blobpath string) error {
 	return nil
 }
 
+// InvalidateBackendVersion discards the cached backend version of the blob, so
+// that the cache is checked against the backend blob on next open. This lets
+// read-only instances observe blob updates made by the writer instance.
+// Returns false if the blob is in use, in which case its open handles keep
+// reading the cached content.
+func (cbs *CachedBlobStore) InvalidateBackendVersion(blobpath string) bool {
+	ok := cbs.entriesmgr.CloseEntryIfUnused(blobpath)
+	cbs.bever.Delete(blobpath)
+	return ok
+}
+
 func (cbs *CachedBlobStore) ReduceCache(ctx context.Context, desiredSize int64, dryrun bool) error {
 	issuedReduceCache.Inc()
 
@@ -299,7 +377,15 @@ func (cbs *CachedBlobStore) ReduceCache(ctx context.Context, desiredSize int64,
 	zap.S().Infof("ReduceCache: Current cache bs total size: %s. Desired size: %s. Needs to reduce %s.",
 		humanize.IBytes(uint64(totalSizeBefore)), humanize.IBytes(uint64(desiredSize)), humanize.IBytes(uint64(needsReduce)))
 
-	bps := cbs.usagestats.FindLeastUsed()
+	policy := cbs.EvictionPolicy()
+	bps := cbs.usagestats.FindEvictionCandidates(policy, func(bp string) int64 {
+		size, err := blobsizer.BlobSize(bp)
+		if err != nil {
+			return 0
+		}
+		return size
+	})
+	zap.S().Infof("ReduceCache: Using eviction policy %q.", policy.Name())
 	for _, bp := range bps {
 		size, err := blobsizer.BlobSize(bp)
 		if err != nil {
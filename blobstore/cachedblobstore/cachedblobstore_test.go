@@ -1,8 +1,10 @@
 package cachedblobstore_test
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"reflect"
 	"sort"
 	"sync"
@@ -12,6 +14,7 @@ import (
 	"context"
 
 	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/blobstore/version"
 	"github.com/nyaxt/otaru/flags"
 	"github.com/nyaxt/otaru/scheduler"
 	tu "github.com/nyaxt/otaru/testutils"
@@ -823,3 +826,193 @@ func TestCachedBlobStore_ReadOnly(t *testing.T) {
 		return
 	}
 }
+
+func TestCachedBlobStore_VerifyCache(t *testing.T) {
+	cachedblobstore.DisableAutoSyncForTesting = true
+	defer func() { cachedblobstore.DisableAutoSyncForTesting = false }()
+
+	backendbs := tu.TestFileBlobStoreOfName("backend")
+	cachebs := tu.TestFileBlobStoreOfName("cache")
+	quarantinebs := tu.TestFileBlobStoreOfName("quarantine")
+	s := scheduler.NewScheduler()
+
+	// A valid test blob consists only of its version byte.
+	verify := func(r io.Reader) (version.Version, error) {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return 0, err
+		}
+		if len(b) > 1 {
+			return 0, fmt.Errorf("trailing garbage")
+		}
+		return tu.TestQueryVersion(bytes.NewReader(b))
+	}
+	// The header of a blob starting with badHeader can't be read.
+	const badHeader = 0xee
+	queryVersion := func(r io.Reader) (version.Version, error) {
+		v, err := tu.TestQueryVersion(r)
+		if err == nil && v == badHeader {
+			return -1, fmt.Errorf("bad header")
+		}
+		return v, err
+	}
+	writeCorrupted := func(bp string, ver byte) {
+		w, err := cachebs.OpenWriter(bp)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		w.Write([]byte{ver, 0xff})
+		w.Close()
+	}
+
+	for _, bp := range []string{"healthy", "stale", "corrupted"} {
+		if err := tu.WriteVersionedBlob(backendbs, bp, 3); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	if err := tu.WriteVersionedBlob(cachebs, "healthy", 3); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := tu.WriteVersionedBlob(cachebs, "stale", 2); err != nil {
+		t.Fatalf("%v", err)
+	}
+	writeCorrupted("corrupted", 3)
+	writeCorrupted("notwrittenback", 4)
+	if err := tu.WriteVersionedBlob(backendbs, "badheader", 3); err != nil {
+		t.Fatalf("%v", err)
+	}
+	writeCorrupted("badheader", badHeader)
+
+	bs, err := cachedblobstore.New(backendbs, cachebs, s, flags.O_RDWRCREATE, queryVersion)
+	if err != nil {
+		t.Fatalf("Failed to create CachedBlobStore: %v", err)
+	}
+	defer bs.Quit()
+
+	report, err := bs.VerifyCache(context.Background(), verify, quarantinebs, true)
+	if err != nil {
+		t.Fatalf("VerifyCache(dryrun) failed: %v", err)
+	}
+	if report.NumVerified != 5 || report.NumStale != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if !reflect.DeepEqual(report.Quarantined, []string{"corrupted"}) {
+		t.Errorf("Unexpected Quarantined: %v", report.Quarantined)
+	}
+	sort.Strings(report.DataLoss)
+	if !reflect.DeepEqual(report.DataLoss, []string{"badheader", "notwrittenback"}) {
+		t.Errorf("Unexpected DataLoss: %v", report.DataLoss)
+	}
+	if _, err := quarantinebs.BlobSize("corrupted"); !util.IsNotExist(err) {
+		t.Errorf("dryrun should not quarantine blobs")
+	}
+
+	report, err = bs.VerifyCache(context.Background(), verify, quarantinebs, false)
+	if err != nil {
+		t.Fatalf("VerifyCache failed: %v", err)
+	}
+	if !reflect.DeepEqual(report.Quarantined, []string{"corrupted"}) {
+		t.Errorf("Unexpected Quarantined: %v", report.Quarantined)
+	}
+	for _, bp := range []string{"corrupted", "notwrittenback", "badheader"} {
+		if size, err := quarantinebs.BlobSize(bp); err != nil || size != 2 {
+			t.Errorf("Corrupted blob \"%s\" should be in quarantine. size: %d, err: %v", bp, size, err)
+		}
+	}
+	// A blob which may have had changes not yet written back should not be
+	// replaced with the backend copy.
+	if _, err := cachebs.BlobSize("badheader"); !util.IsNotExist(err) {
+		t.Errorf("Blob with unreadable header should not be re-fetched. err: %v", err)
+	}
+	// The quarantined blob should be re-fetched from the backend.
+	if size, err := cachebs.BlobSize("corrupted"); err != nil || size != 1 {
+		t.Errorf("Corrupted blob should have been re-fetched. size: %d, err: %v", size, err)
+	}
+	if err := tu.AssertBlobVersionRA(bs, "corrupted", 3); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestCachedBlobStore_InvalidateBackendVersion(t *testing.T) {
+	backendbs := tu.TestFileBlobStoreOfName("backend")
+	cachebs := tu.TestFileBlobStoreOfName("cache")
+	s := scheduler.NewScheduler()
+
+	if err := tu.WriteVersionedBlob(backendbs, "hoge", 1); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	bs, err := cachedblobstore.New(backendbs, cachebs, s, flags.O_RDONLY, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("Failed to create CachedBlobStore: %v", err)
+	}
+	defer bs.Quit()
+
+	if err := tu.AssertBlobVersionRA(bs, "hoge", 1); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// Update by another instance is not observed until invalidated.
+	if err := tu.WriteVersionedBlob(backendbs, "hoge", 2); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := tu.AssertBlobVersionRA(bs, "hoge", 1); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	h, err := bs.Open("hoge", flags.O_RDONLY)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if bs.InvalidateBackendVersion("hoge") {
+		t.Errorf("InvalidateBackendVersion should fail while the blob is open.")
+	}
+	h.Close()
+
+	if !bs.InvalidateBackendVersion("hoge") {
+		t.Errorf("InvalidateBackendVersion failed.")
+	}
+	if err := tu.AssertBlobVersionRA(bs, "hoge", 2); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestCachedBlobStore_QuitLiftsUploadThrottle(t *testing.T) {
+	cachedblobstore.DisableAutoSyncForTesting = true
+	defer func() { cachedblobstore.DisableAutoSyncForTesting = false }()
+
+	backendbs := tu.TestFileBlobStoreOfName("backend")
+	cachebs := tu.TestFileBlobStoreOfName("cache")
+	s := scheduler.NewScheduler()
+
+	bs, err := cachedblobstore.New(backendbs, cachebs, s, flags.O_RDWRCREATE, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("Failed to create CachedBlobStore: %v", err)
+	}
+	bs.SetBandwidthLimit(cachedblobstore.BandwidthLimitConfig{UploadBytesPerSec: 1})
+
+	w, err := bs.OpenWriter("hoge")
+	if err != nil {
+		t.Fatalf("OpenWriter failed: %v", err)
+	}
+	data := make([]byte, 64)
+	data[0] = 3
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// The writeback would take a minute at the limit.
+	start := time.Now()
+	if err := bs.Quit(); err != nil {
+		t.Errorf("Quit failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Quit took %v while throttled", elapsed)
+	}
+	if err := tu.AssertBlobVersion(backendbs, "hoge", 3); err != nil {
+		t.Errorf("%v", err)
+	}
+}
@@ -1,17 +1,32 @@
 package cachedblobstore
 
 import (
-	"sort"
+	"encoding/gob"
+	"fmt"
 	"sync"
 	"time"
 
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/btncrypt"
 	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/metadata"
+	"github.com/nyaxt/otaru/metadata/statesnapshot"
 )
 
+// Opens of a blob within this period are considered to be a part of a single
+// reference, e.g. a sequential read of a file spanning multiple chunks.
+const correlatedReferencePeriod = 1 * time.Minute
+
+// usageStatEntry fields are exported for gob serialization.
 type usageStatEntry struct {
-	lastUsed   time.Time
-	readCount  int
-	writeCount int
+	LastUsed   time.Time
+	ReadCount  int
+	WriteCount int
+	RefCount   int
+	// Size of the cached blob when the stats were last saved. 0 if unknown.
+	Size int64
 }
 
 type CacheUsageStats struct {
@@ -27,14 +42,19 @@ func (s *CacheUsageStats) ObserveOpen(blobpath string, flags int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	now := time.Now()
+
 	e := s.entries[blobpath]
 	if fl.IsReadAllowed(flags) {
-		e.readCount++
+		e.ReadCount++
 	}
 	if fl.IsWriteAllowed(flags) {
-		e.writeCount++
+		e.WriteCount++
 	}
-	e.lastUsed = time.Now()
+	if now.Sub(e.LastUsed) > correlatedReferencePeriod {
+		e.RefCount++
+	}
+	e.LastUsed = now
 
 	s.entries[blobpath] = e
 }
@@ -53,39 +73,193 @@ func (s *CacheUsageStats) ImportBlobList(blobpaths []string) {
 	var tzero time.Time
 
 	for _, bp := range blobpaths {
-		s.entries[bp] = usageStatEntry{lastUsed: tzero}
+		if metadata.IsCacheStateBlobpath(bp) {
+			continue
+		}
+		s.entries[bp] = usageStatEntry{LastUsed: tzero}
 	}
 }
 
-type lastUsedSorter struct {
-	bps     []string
-	entries map[string]usageStatEntry
+// Snapshot returns UsageStat of all tracked blobs.
+func (s *CacheUsageStats) Snapshot() []UsageStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]UsageStat, 0, len(s.entries))
+	for bp, e := range s.entries {
+		stats = append(stats, UsageStat{
+			BlobPath:   bp,
+			LastUsed:   e.LastUsed,
+			ReadCount:  e.ReadCount,
+			WriteCount: e.WriteCount,
+			RefCount:   e.RefCount,
+		})
+	}
+	return stats
 }
 
-func (s lastUsedSorter) Len() int { return len(s.bps) }
+// FindEvictionCandidates returns blobpaths in the order they should be
+// evicted according to the policy. If the policy UsesSize(), sizeOf is used
+// to query blob sizes.
+func (s *CacheUsageStats) FindEvictionCandidates(policy EvictionPolicy, sizeOf func(blobpath string) int64) []string {
+	stats := s.Snapshot()
+	if policy.UsesSize() {
+		for i := range stats {
+			stats[i].Size = sizeOf(stats[i].BlobPath)
+		}
+	}
+	// Start from a deterministic order, as policies sort stably.
+	sortByBlobPath(stats)
+	policy.Order(stats, time.Now())
+
+	bps := make([]string, 0, len(stats))
+	for _, st := range stats {
+		bps = append(bps, st.BlobPath)
+	}
+	return bps
+}
 
-func (s lastUsedSorter) Swap(i, j int) {
-	s.bps[i], s.bps[j] = s.bps[j], s.bps[i]
+func (s *CacheUsageStats) FindLeastUsed() []string {
+	return s.FindEvictionCandidates(LRUPolicy{}, nil)
 }
 
-func (s lastUsedSorter) Less(i, j int) bool {
-	a := s.entries[s.bps[i]].lastUsed
-	b := s.entries[s.bps[j]].lastUsed
+// verifyRestoredEntries drops restored entries which don't match the blobs
+// actually in the cache blobstore.
+func verifyRestoredEntries(es map[string]usageStatEntry, bs blobstore.RandomAccessBlobStore) (int, error) {
+	ndropped := 0
+
+	if lister, ok := bs.(blobstore.BlobLister); ok {
+		bps, err := lister.ListBlobs()
+		if err != nil {
+			return 0, fmt.Errorf("Failed to list cache blobs: %v", err)
+		}
+		exists := make(map[string]struct{}, len(bps))
+		for _, bp := range bps {
+			exists[bp] = struct{}{}
+		}
+		for bp := range es {
+			if _, ok := exists[bp]; !ok {
+				delete(es, bp)
+				ndropped++
+			}
+		}
+	}
+
+	if sizer, ok := bs.(blobstore.BlobSizer); ok {
+		for bp, e := range es {
+			if e.Size == 0 {
+				continue
+			}
+			size, err := sizer.BlobSize(bp)
+			if err != nil || size != e.Size {
+				zap.S().Infof("Discarding restored usage stats for \"%s\" as its size changed %d -> %d (err: %v)", bp, e.Size, size, err)
+				delete(es, bp)
+				ndropped++
+			}
+		}
+	}
 
-	return a.Before(b)
+	return ndropped, nil
 }
 
-func (s *CacheUsageStats) FindLeastUsed() []string {
+func (s *CacheUsageStats) RestoreStateFromBlobstore(c *btncrypt.Cipher, bs blobstore.RandomAccessBlobStore) error {
+	bp := metadata.CacheUsageStatsBlobpath
+	h, err := bs.Open(bp, fl.O_RDONLY)
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	es := make(map[string]usageStatEntry)
+	if err := statesnapshot.Restore(
+		&blobstore.OffsetReader{h, 0}, c,
+		func(dec *gob.Decoder) error {
+			if err := dec.Decode(&es); err != nil {
+				return fmt.Errorf("Failed to decode usage stats map: %v", err)
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	ndropped, err := verifyRestoredEntries(es, bs)
+	if err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	for bp, e := range es {
+		if metadata.IsCacheStateBlobpath(bp) {
+			continue
+		}
+		// Don't override stats observed before restore.
+		if cur, ok := s.entries[bp]; ok && cur.LastUsed.After(e.LastUsed) {
+			continue
+		}
+		s.entries[bp] = e
+	}
+	zap.S().Infof("Restored cache usage stats of %d blobs. Dropped %d stale entries.", len(es), ndropped)
+	return nil
+}
+
+// updateSizes records the current size of the cached blobs, so that the
+// restored stats can be verified against the blobs on disk.
+func (s *CacheUsageStats) updateSizes(sizer blobstore.BlobSizer) {
+	s.mu.Lock()
 	bps := make([]string, 0, len(s.entries))
-	for bp, _ := range s.entries {
+	for bp := range s.entries {
 		bps = append(bps, bp)
 	}
-	sort.Sort(lastUsedSorter{bps, s.entries})
+	s.mu.Unlock()
 
-	return bps
+	sizes := make(map[string]int64, len(bps))
+	for _, bp := range bps {
+		size, err := sizer.BlobSize(bp)
+		if err != nil {
+			continue
+		}
+		sizes[bp] = size
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for bp, size := range sizes {
+		if e, ok := s.entries[bp]; ok {
+			e.Size = size
+			s.entries[bp] = e
+		}
+	}
+}
+
+func (s *CacheUsageStats) encodeToGob(enc *gob.Encoder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := enc.Encode(s.entries); err != nil {
+		return fmt.Errorf("Failed to encode usage stats map: %v", err)
+	}
+	return nil
+}
+
+func (s *CacheUsageStats) SaveStateToBlobstore(c *btncrypt.Cipher, bs blobstore.RandomAccessBlobStore) error {
+	if sizer, ok := bs.(blobstore.BlobSizer); ok {
+		s.updateSizes(sizer)
+	}
+
+	bp := metadata.CacheUsageStatsBlobpath
+	h, err := bs.Open(bp, fl.O_RDWRCREATE)
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	return statesnapshot.Save(
+		&blobstore.OffsetWriter{h, 0}, c,
+		func(enc *gob.Encoder) error { return s.encodeToGob(enc) },
+	)
 }
 
 type CacheUsageStatsView struct {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
 	fl "github.com/nyaxt/otaru/flags"
+	tu "github.com/nyaxt/otaru/testutils"
 )
 
 func TestCacheUsageStats_FindLeastUsed(t *testing.T) {
@@ -20,3 +21,64 @@ func TestCacheUsageStats_FindLeastUsed(t *testing.T) {
 		t.Errorf("Unexpected result: %v", leastUsed)
 	}
 }
+
+func TestCacheUsageStats_SaveRestore(t *testing.T) {
+	bs := tu.TestFileBlobStore()
+	for _, bp := range []string{"cold", "hot", "removed", "modified"} {
+		if err := tu.WriteVersionedBlobRA(bs, bp, 1); err != nil {
+			t.Fatalf("WriteVersionedBlobRA failed: %v", err)
+		}
+	}
+
+	s := cachedblobstore.NewCacheUsageStats()
+	s.ImportBlobList([]string{"cold"})
+	s.ObserveOpen("hot", fl.O_RDONLY)
+	s.ObserveOpen("hot", fl.O_RDWR)
+	s.ObserveOpen("removed", fl.O_RDONLY)
+	s.ObserveOpen("modified", fl.O_RDONLY)
+	if err := s.SaveStateToBlobstore(tu.TestCipher(), bs); err != nil {
+		t.Fatalf("SaveStateToBlobstore failed: %v", err)
+	}
+
+	// Mutate the cache dir behind the saved stats.
+	if err := bs.RemoveBlob("removed"); err != nil {
+		t.Fatalf("RemoveBlob failed: %v", err)
+	}
+	{
+		bh, err := bs.Open("modified", fl.O_RDWR)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if err := bh.PWrite([]byte{2, 2}, 0); err != nil {
+			t.Fatalf("PWrite failed: %v", err)
+		}
+		bh.Close()
+	}
+
+	bps, err := bs.ListBlobs()
+	if err != nil {
+		t.Fatalf("ListBlobs failed: %v", err)
+	}
+	s2 := cachedblobstore.NewCacheUsageStats()
+	s2.ImportBlobList(bps)
+	if err := s2.RestoreStateFromBlobstore(tu.TestCipher(), bs); err != nil {
+		t.Fatalf("RestoreStateFromBlobstore failed: %v", err)
+	}
+
+	leastUsed := s2.FindLeastUsed()
+	if !reflect.DeepEqual([]string{"cold", "modified", "hot"}, leastUsed) {
+		t.Errorf("Unexpected result: %v", leastUsed)
+	}
+	for _, st := range s2.Snapshot() {
+		switch st.BlobPath {
+		case "hot":
+			if st.ReadCount != 2 || st.WriteCount != 1 {
+				t.Errorf("Unexpected restored stat: %+v", st)
+			}
+		case "modified":
+			if st.ReadCount != 0 {
+				t.Errorf("Stats of modified blob should be discarded: %+v", st)
+			}
+		}
+	}
+}
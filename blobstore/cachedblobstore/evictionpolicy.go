@@ -0,0 +1,132 @@
+package cachedblobstore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UsageStat is a snapshot of CacheUsageStats entry for a cached blob.
+type UsageStat struct {
+	BlobPath   string
+	LastUsed   time.Time
+	ReadCount  int
+	WriteCount int
+	// RefCount counts uncorrelated references, i.e. opens separated by more
+	// than correlatedReferencePeriod.
+	RefCount int
+	// Size of the cached blob. Only filled if EvictionPolicy.UsesSize().
+	Size int64
+}
+
+// EvictionPolicy decides which cached blobs are dropped first on ReduceCache.
+type EvictionPolicy interface {
+	Name() string
+	// UsesSize returns true if Order() requires UsageStat.Size to be filled.
+	UsesSize() bool
+	// Order sorts stats so that the blobs to be evicted first come first.
+	Order(stats []UsageStat, now time.Time)
+}
+
+// LRUPolicy evicts the least recently used blob first.
+type LRUPolicy struct{}
+
+func (LRUPolicy) Name() string   { return "lru" }
+func (LRUPolicy) UsesSize() bool { return false }
+
+func (LRUPolicy) Order(stats []UsageStat, now time.Time) {
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].LastUsed.Before(stats[j].LastUsed)
+	})
+}
+
+// LFUPolicy evicts the least frequently opened blob first, breaking ties by
+// last use.
+type LFUPolicy struct{}
+
+func (LFUPolicy) Name() string   { return "lfu" }
+func (LFUPolicy) UsesSize() bool { return false }
+
+func (LFUPolicy) Order(stats []UsageStat, now time.Time) {
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		ac, bc := a.ReadCount+a.WriteCount, b.ReadCount+b.WriteCount
+		if ac != bc {
+			return ac < bc
+		}
+		return a.LastUsed.Before(b.LastUsed)
+	})
+}
+
+// SizeWeightedPolicy evicts large blobs which haven't been used for a long
+// time first, so that a single eviction releases as much space as possible.
+type SizeWeightedPolicy struct{}
+
+func (SizeWeightedPolicy) Name() string   { return "size" }
+func (SizeWeightedPolicy) UsesSize() bool { return true }
+
+func (SizeWeightedPolicy) Order(stats []UsageStat, now time.Time) {
+	score := func(s UsageStat) float64 {
+		age := now.Sub(s.LastUsed).Seconds()
+		if age < 1 {
+			age = 1
+		}
+		return age * float64(s.Size)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return score(stats[i]) > score(stats[j])
+	})
+}
+
+// ScanResistantPolicy is a 2Q-style policy. Blobs referenced only once
+// (e.g. read by a large sequential scan) are kept in a probationary queue,
+// and are evicted in LRU order before any blob which was referenced
+// multiple times.
+type ScanResistantPolicy struct{}
+
+func (ScanResistantPolicy) Name() string   { return "2q" }
+func (ScanResistantPolicy) UsesSize() bool { return false }
+
+func (ScanResistantPolicy) Order(stats []UsageStat, now time.Time) {
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		ap, bp := a.RefCount <= 1, b.RefCount <= 1
+		if ap != bp {
+			return ap
+		}
+		return a.LastUsed.Before(b.LastUsed)
+	})
+}
+
+var DefaultEvictionPolicy EvictionPolicy = LRUPolicy{}
+
+var evictionPolicies = []EvictionPolicy{
+	LRUPolicy{},
+	LFUPolicy{},
+	SizeWeightedPolicy{},
+	ScanResistantPolicy{},
+}
+
+// EvictionPolicyFromName returns the EvictionPolicy of the given name.
+// An empty name selects DefaultEvictionPolicy.
+func EvictionPolicyFromName(name string) (EvictionPolicy, error) {
+	if name == "" {
+		return DefaultEvictionPolicy, nil
+	}
+
+	var names []string
+	for _, p := range evictionPolicies {
+		if p.Name() == strings.ToLower(name) {
+			return p, nil
+		}
+		names = append(names, p.Name())
+	}
+	return nil, fmt.Errorf("Unknown cache eviction policy %q. Must be one of %v", name, names)
+}
+
+func sortByBlobPath(stats []UsageStat) {
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].BlobPath < stats[j].BlobPath
+	})
+}
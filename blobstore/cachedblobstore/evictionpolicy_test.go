@@ -0,0 +1,65 @@
+package cachedblobstore_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+)
+
+func blobpathsOf(stats []cachedblobstore.UsageStat) []string {
+	bps := make([]string, 0, len(stats))
+	for _, s := range stats {
+		bps = append(bps, s.BlobPath)
+	}
+	return bps
+}
+
+func TestEvictionPolicy_Order(t *testing.T) {
+	now := time.Now()
+	stats := func() []cachedblobstore.UsageStat {
+		return []cachedblobstore.UsageStat{
+			// hot: accessed many times, recently.
+			{BlobPath: "hot", LastUsed: now.Add(-1 * time.Minute), ReadCount: 10, RefCount: 5, Size: 100},
+			// scanned: accessed once, most recently.
+			{BlobPath: "scanned", LastUsed: now, ReadCount: 1, RefCount: 1, Size: 100},
+			// old: accessed a few times long ago.
+			{BlobPath: "old", LastUsed: now.Add(-1 * time.Hour), ReadCount: 3, RefCount: 3, Size: 10},
+			// huge: accessed a few times rather recently, but large.
+			{BlobPath: "huge", LastUsed: now.Add(-10 * time.Minute), ReadCount: 2, RefCount: 2, Size: 1000},
+		}
+	}
+
+	testcases := []struct {
+		policy   cachedblobstore.EvictionPolicy
+		expected []string
+	}{
+		{cachedblobstore.LRUPolicy{}, []string{"old", "huge", "hot", "scanned"}},
+		{cachedblobstore.LFUPolicy{}, []string{"scanned", "huge", "old", "hot"}},
+		{cachedblobstore.SizeWeightedPolicy{}, []string{"huge", "old", "hot", "scanned"}},
+		{cachedblobstore.ScanResistantPolicy{}, []string{"scanned", "old", "huge", "hot"}},
+	}
+	for _, tc := range testcases {
+		ss := stats()
+		tc.policy.Order(ss, now)
+		if actual := blobpathsOf(ss); !reflect.DeepEqual(tc.expected, actual) {
+			t.Errorf("policy %s: expected %v, got %v", tc.policy.Name(), tc.expected, actual)
+		}
+	}
+}
+
+func TestEvictionPolicyFromName(t *testing.T) {
+	p, err := cachedblobstore.EvictionPolicyFromName("")
+	if err != nil || p.Name() != "lru" {
+		t.Errorf("Empty name should default to lru: %v, %v", p, err)
+	}
+	for _, name := range []string{"lru", "lfu", "size", "2q", "LFU"} {
+		if _, err := cachedblobstore.EvictionPolicyFromName(name); err != nil {
+			t.Errorf("EvictionPolicyFromName(%q) failed: %v", name, err)
+		}
+	}
+	if _, err := cachedblobstore.EvictionPolicyFromName("random"); err == nil {
+		t.Errorf("EvictionPolicyFromName should fail on unknown policy")
+	}
+}
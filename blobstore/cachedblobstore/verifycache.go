@@ -0,0 +1,266 @@
+package cachedblobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/version"
+	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/metadata"
+	oprometheus "github.com/nyaxt/otaru/prometheus"
+	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/util"
+)
+
+var (
+	verifyCacheResult = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "verify_cache_result",
+		Help:      "Counts results of cache blob verification.",
+	}, []string{"result"})
+	verifyCacheResultHealthy     = verifyCacheResult.WithLabelValues("healthy")
+	verifyCacheResultStale       = verifyCacheResult.WithLabelValues("stale")
+	verifyCacheResultQuarantined = verifyCacheResult.WithLabelValues("quarantined")
+	verifyCacheResultDataLoss    = verifyCacheResult.WithLabelValues("dataLoss")
+)
+
+type VerifyCacheReport struct {
+	// Number of cache blobs verified.
+	NumVerified int
+	// Number of healthy cache blobs older than the backend.
+	NumStale int
+	// Corrupted cache blobs, which are re-fetched from the backend.
+	Quarantined []string
+	// Corrupted cache blobs, which had or may have had changes not yet
+	// written back to the backend. These are not re-fetched.
+	DataLoss []string
+	// Cache blobs which were not verified as they were in use.
+	Skipped []string
+}
+
+type verifyResult int
+
+const (
+	verifyHealthy verifyResult = iota
+	verifyStale
+	verifyQuarantine
+	verifyDataLoss
+)
+
+func (cbs *CachedBlobStore) verifyCacheBlob(blobpath string, verify version.VerifyFunc) (verifyResult, error) {
+	h, err := cbs.cachebs.Open(blobpath, fl.O_RDONLY)
+	if err != nil {
+		return verifyHealthy, fmt.Errorf("Failed to open cache blob: %v", err)
+	}
+	defer h.Close()
+
+	backendver, err := cbs.bever.Query(blobpath)
+	if err != nil {
+		return verifyHealthy, err
+	}
+
+	cachever, verr := verify(io.LimitReader(&blobstore.OffsetReader{h, 0}, h.Size()))
+	if verr == nil {
+		if cachever < backendver {
+			return verifyStale, nil
+		}
+		return verifyHealthy, nil
+	}
+	zap.S().Warnf("VerifyCache: cache blob \"%s\" is corrupted: %v", blobpath, verr)
+
+	// The content is corrupted, but the header may still tell if the blob had
+	// changes not yet written back.
+	headerver, herr := cbs.queryVersion(&blobstore.OffsetReader{h, 0})
+	if herr != nil {
+		// Whether the blob was dirty is unknown, so the backend copy may be
+		// older than the lost changes. Don't overwrite the cache with it.
+		zap.S().Warnf("VerifyCache: failed to read the header of corrupted cache blob \"%s\": %v", blobpath, herr)
+		return verifyDataLoss, nil
+	}
+	if headerver > backendver {
+		return verifyDataLoss, nil
+	}
+	if backendver == 0 {
+		// The blob never reached the backend.
+		return verifyDataLoss, nil
+	}
+	return verifyQuarantine, nil
+}
+
+func copyBlob(dst blobstore.BlobStore, src blobstore.RandomAccessBlobStore, blobpath string) error {
+	h, err := src.Open(blobpath, fl.O_RDONLY)
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	w, err := dst.OpenWriter(blobpath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, io.LimitReader(&blobstore.OffsetReader{h, 0}, h.Size())); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (cbs *CachedBlobStore) quarantine(blobpath string, verify version.VerifyFunc, quarantinebs blobstore.BlobStore) (bool, error) {
+	var result verifyResult
+	ok, err := cbs.entriesmgr.RunIfInactive(blobpath, func() error {
+		// Re-verify, as the blob may have been modified since the first verification.
+		var err error
+		result, err = cbs.verifyCacheBlob(blobpath, verify)
+		if err != nil {
+			return err
+		}
+		if result != verifyQuarantine && result != verifyDataLoss {
+			return nil
+		}
+
+		if quarantinebs != nil {
+			if err := copyBlob(quarantinebs, cbs.cachebs, blobpath); err != nil {
+				return fmt.Errorf("Failed to copy corrupted cache blob to quarantine: %v", err)
+			}
+		}
+		if err := cbs.cachebs.(blobstore.BlobRemover).RemoveBlob(blobpath); err != nil {
+			return fmt.Errorf("Failed to remove corrupted cache blob: %v", err)
+		}
+		cbs.usagestats.ObserveRemoveBlob(blobpath)
+		return nil
+	})
+	if err != nil || !ok {
+		return ok, err
+	}
+	if result != verifyQuarantine && result != verifyDataLoss {
+		return true, nil
+	}
+
+	if result == verifyQuarantine {
+		// Re-fetch the blob from the backend.
+		bh, err := cbs.Open(blobpath, fl.O_RDONLY)
+		if err != nil {
+			return true, fmt.Errorf("Failed to re-fetch blob from backend: %v", err)
+		}
+		defer bh.Close()
+
+		// Wait for the fetch to complete by reading its last byte.
+		if size := bh.Size(); size > 0 {
+			if err := bh.PRead(make([]byte, 1), size-1); err != nil {
+				return true, fmt.Errorf("Failed to re-fetch blob from backend: %v", err)
+			}
+		}
+	}
+	return true, nil
+}
+
+// VerifyCache verifies all blobs in the cache using verify. Corrupted blobs
+// are moved to quarantinebs, if non-nil, and are re-fetched from the
+// backend, unless they may have had changes not yet written back. If dryrun,
+// the corrupted blobs are only reported.
+func (cbs *CachedBlobStore) VerifyCache(ctx context.Context, verify version.VerifyFunc, quarantinebs blobstore.BlobStore, dryrun bool) (*VerifyCacheReport, error) {
+	start := time.Now()
+
+	lister, ok := cbs.cachebs.(blobstore.BlobLister)
+	if !ok {
+		return nil, fmt.Errorf("Cache backend \"%s\" doesn't support ListBlobs() method, required to VerifyCache(). aborting.", util.TryGetImplName(cbs.cachebs))
+	}
+	if _, ok := cbs.cachebs.(blobstore.BlobRemover); !ok && !dryrun {
+		return nil, fmt.Errorf("Cache backend \"%s\" doesn't support RemoveBlob() method, required to VerifyCache(). aborting.", util.TryGetImplName(cbs.cachebs))
+	}
+	bps, err := lister.ListBlobs()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list cache blobs: %v", err)
+	}
+
+	active := make(map[string]struct{})
+	for _, bp := range cbs.entriesmgr.ListBlobs() {
+		active[bp] = struct{}{}
+	}
+
+	report := &VerifyCacheReport{}
+	for _, bp := range bps {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if metadata.IsCacheStateBlobpath(bp) {
+			continue
+		}
+		if _, ok := active[bp]; ok {
+			report.Skipped = append(report.Skipped, bp)
+			continue
+		}
+
+		result, err := cbs.verifyCacheBlob(bp, verify)
+		if err != nil {
+			return report, fmt.Errorf("Failed to verify cache blob \"%s\": %v", bp, err)
+		}
+		report.NumVerified++
+
+		switch result {
+		case verifyHealthy:
+			verifyCacheResultHealthy.Inc()
+			continue
+		case verifyStale:
+			verifyCacheResultStale.Inc()
+			report.NumStale++
+			continue
+		}
+
+		if !dryrun {
+			ok, err := cbs.quarantine(bp, verify, quarantinebs)
+			if err != nil {
+				return report, fmt.Errorf("Failed to quarantine cache blob \"%s\": %v", bp, err)
+			}
+			if !ok {
+				report.Skipped = append(report.Skipped, bp)
+				continue
+			}
+		}
+		switch result {
+		case verifyQuarantine:
+			verifyCacheResultQuarantined.Inc()
+			report.Quarantined = append(report.Quarantined, bp)
+		case verifyDataLoss:
+			verifyCacheResultDataLoss.Inc()
+			report.DataLoss = append(report.DataLoss, bp)
+			zap.S().Errorf("VerifyCache: cache blob \"%s\" was corrupted before its changes were written back. Data loss!", bp)
+		}
+	}
+
+	zap.S().Infof("VerifyCache done. Verified: %d, Stale: %d, Quarantined: %d, DataLoss: %d, Skipped: %d. Dryrun: %t. Took: %s",
+		report.NumVerified, report.NumStale, len(report.Quarantined), len(report.DataLoss), len(report.Skipped),
+		dryrun, time.Since(start))
+	return report, nil
+}
+
+type VerifyCacheTask struct {
+	CBS          *CachedBlobStore
+	Verify       version.VerifyFunc
+	QuarantineBS blobstore.BlobStore
+	DryRun       bool
+}
+
+type VerifyCacheResult struct {
+	Report *VerifyCacheReport
+	Error  error
+}
+
+func (r VerifyCacheResult) Err() error { return r.Error }
+
+func (t *VerifyCacheTask) Run(ctx context.Context) scheduler.Result {
+	report, err := t.CBS.VerifyCache(ctx, t.Verify, t.QuarantineBS, t.DryRun)
+	return VerifyCacheResult{report, err}
+}
+
+func (t *VerifyCacheTask) String() string {
+	return fmt.Sprintf("VerifyCacheTask{dryrun: %t}", t.DryRun)
+}
@@ -0,0 +1,564 @@
+package erasurecodedblobstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"sort"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/util"
+	"github.com/nyaxt/otaru/util/reedsolomon"
+)
+
+const DefaultStripeShardSize = 256 * 1024
+
+var ErrTooFewShards = errors.New("Too few shards available to reconstruct the blob.")
+
+// ErasureCodedBlobStore splits each blob into k data shards and m parity
+// shards using Reed-Solomon coding, and stores shard i on backends[i].
+// Blobs can be read as long as at most m backends fail.
+type ErasureCodedBlobStore struct {
+	backends        []blobstore.BlobStore
+	enc             *reedsolomon.Encoder
+	stripeShardSize int
+}
+
+var _ = blobstore.BlobStore(&ErasureCodedBlobStore{})
+
+// New creates ErasureCodedBlobStore. The first k backends store data shards,
+// and the rest len(backends)-k store parity shards.
+func New(backends []blobstore.BlobStore, k int) (*ErasureCodedBlobStore, error) {
+	if k <= 0 || k > len(backends) {
+		return nil, fmt.Errorf("Invalid number of data shards %d for %d backends.", k, len(backends))
+	}
+	if len(backends) > 255 {
+		return nil, fmt.Errorf("Too many backends: %d", len(backends))
+	}
+	enc, err := reedsolomon.New(k, len(backends)-k)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ErasureCodedBlobStore{
+		backends:        backends,
+		enc:             enc,
+		stripeShardSize: DefaultStripeShardSize,
+	}, nil
+}
+
+// SetStripeShardSize changes the size of shard pieces of newly written blobs.
+func (ecbs *ErasureCodedBlobStore) SetStripeShardSize(n int) { ecbs.stripeShardSize = n }
+
+func (ecbs *ErasureCodedBlobStore) DataShards() int   { return ecbs.enc.DataShards() }
+func (ecbs *ErasureCodedBlobStore) ParityShards() int { return ecbs.enc.ParityShards() }
+
+type shardWriter struct {
+	ecbs     *ErasureCodedBlobStore
+	blobpath string
+	meta     blobMeta
+
+	ws     []io.WriteCloser
+	buf    []byte
+	shards [][]byte
+	err    error
+}
+
+func (w *shardWriter) flushStripe() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	k := len(w.shards) - w.ecbs.ParityShards()
+	plen := (len(w.buf) + k - 1) / k
+	for i := range w.shards {
+		s := w.shards[i][:plen]
+		if i < k {
+			n := copy(s, w.buf[util.IntMin(i*plen, len(w.buf)):])
+			for j := n; j < plen; j++ {
+				s[j] = 0
+			}
+		}
+		w.shards[i] = s
+	}
+	if err := w.ecbs.enc.Encode(w.shards); err != nil {
+		return err
+	}
+	for i, s := range w.shards {
+		if _, err := w.ws[i].Write(s); err != nil {
+			return fmt.Errorf("Failed to write shard %d: %v", i, err)
+		}
+		w.shards[i] = w.shards[i][:cap(w.shards[i])]
+	}
+	w.meta.Size += int64(len(w.buf))
+	w.buf = w.buf[:0]
+	return nil
+}
+
+func (w *shardWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n := 0
+	for len(p) > 0 {
+		m := util.IntMin(cap(w.buf)-len(w.buf), len(p))
+		w.buf = append(w.buf, p[:m]...)
+		p = p[m:]
+		n += m
+
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flushStripe(); err != nil {
+				w.err = err
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// abort discards the shards written so far. The meta still points to the
+// shards of the previous write, so the blob keeps its old content.
+func (w *shardWriter) abort() {
+	sbp := shardBlobpath(w.blobpath, w.meta.WriteID)
+	for i, sw := range w.ws {
+		if aborter, ok := sw.(blobstore.WriteAborter); ok {
+			if err := aborter.Abort(); err != nil {
+				zap.S().Warnf("Failed to abort write of shard %d of blob \"%s\": %v", i, w.blobpath, err)
+			}
+			continue
+		}
+		sw.Close()
+		w.ecbs.removeShard(i, sbp)
+	}
+	w.ws = nil
+}
+
+func (w *shardWriter) Close() error {
+	if w.err == nil {
+		w.err = w.flushStripe()
+	}
+	if w.err != nil {
+		w.abort()
+		return fmt.Errorf("Failed to write blob \"%s\": %v", w.blobpath, w.err)
+	}
+
+	var errs []error
+	for i, sw := range w.ws {
+		if err := sw.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("Failed to close shard %d: %v", i, err))
+		}
+	}
+	if err := multierr.Combine(errs...); err != nil {
+		sbp := shardBlobpath(w.blobpath, w.meta.WriteID)
+		for i := range w.ecbs.backends {
+			w.ecbs.removeShard(i, sbp)
+		}
+		return fmt.Errorf("Failed to write blob \"%s\": %v", w.blobpath, err)
+	}
+
+	// Switch the meta only after all shards are complete. The shards of the
+	// previous write are kept until the meta on every backend is switched,
+	// as a backend failing here still has the old meta pointing to them.
+	oldIDs := w.ecbs.writeIDsOf(w.blobpath)
+	delete(oldIDs, w.meta.WriteID)
+	for i, bs := range w.ecbs.backends {
+		mw, err := bs.OpenWriter(metaBlobpath(w.blobpath))
+		if err != nil {
+			return fmt.Errorf("Failed to open meta writer of blob \"%s\" on backend %d: %v", w.blobpath, i, err)
+		}
+		if err := writeMeta(mw, w.meta); err != nil {
+			mw.Close()
+			return fmt.Errorf("Failed to write meta of blob \"%s\" on backend %d: %v", w.blobpath, i, err)
+		}
+		if err := mw.Close(); err != nil {
+			return fmt.Errorf("Failed to close meta writer of blob \"%s\" on backend %d: %v", w.blobpath, i, err)
+		}
+	}
+
+	for id := range oldIDs {
+		sbp := shardBlobpath(w.blobpath, id)
+		for i := range w.ecbs.backends {
+			if err := w.ecbs.removeShard(i, sbp); err != nil {
+				zap.S().Warnf("Failed to remove shard %d of the previous write of blob \"%s\": %v", i, w.blobpath, err)
+			}
+		}
+	}
+	return nil
+}
+
+// removeShard removes the shard blob sbp from the backend i, if it exists.
+func (ecbs *ErasureCodedBlobStore) removeShard(i int, sbp string) error {
+	remover, ok := ecbs.backends[i].(blobstore.BlobRemover)
+	if !ok {
+		return fmt.Errorf("Backend blobstore \"%s\" don't support RemoveBlob()", util.TryGetImplName(ecbs.backends[i]))
+	}
+	if err := remover.RemoveBlob(sbp); err != nil && !util.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// OpenWriter writes the blob to all backends. The write fails if any of the
+// backends fail, so that a successfully written blob always has full redundancy.
+// The shards are written under a new WriteID, so a failed overwrite leaves
+// the previous content readable.
+func (ecbs *ErasureCodedBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	k := ecbs.DataShards()
+	w := &shardWriter{
+		ecbs:     ecbs,
+		blobpath: blobpath,
+		meta: blobMeta{
+			DataShards:      uint8(k),
+			ParityShards:    uint8(ecbs.ParityShards()),
+			StripeShardSize: uint32(ecbs.stripeShardSize),
+			WriteID:         rand.Uint64(),
+		},
+		buf:    make([]byte, 0, k*ecbs.stripeShardSize),
+		shards: make([][]byte, len(ecbs.backends)),
+	}
+	for i := range w.shards {
+		w.shards[i] = make([]byte, ecbs.stripeShardSize)
+	}
+
+	sbp := shardBlobpath(blobpath, w.meta.WriteID)
+	for i, bs := range ecbs.backends {
+		sw, err := bs.OpenWriter(sbp)
+		if err != nil {
+			w.abort()
+			return nil, fmt.Errorf("Failed to open shard writer of blob \"%s\" on backend %d: %v", blobpath, i, err)
+		}
+		w.ws = append(w.ws, sw)
+		if err := writeShardHeader(sw, i, w.meta.WriteID); err != nil {
+			w.abort()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// readMetas reads the meta of the blob from each backend. ok[i] tells if the
+// meta on the backend i was read.
+func (ecbs *ErasureCodedBlobStore) readMetas(blobpath string) (metas []blobMeta, ok []bool, errs []error) {
+	metas = make([]blobMeta, len(ecbs.backends))
+	ok = make([]bool, len(ecbs.backends))
+	for i, bs := range ecbs.backends {
+		r, err := bs.OpenReader(metaBlobpath(blobpath))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		m, err := readMeta(r)
+		r.Close()
+		if err != nil {
+			zap.S().Warnf("Failed to read meta of blob \"%s\" on backend %d: %v", blobpath, i, err)
+			errs = append(errs, err)
+			continue
+		}
+		metas[i], ok[i] = m, true
+	}
+	return
+}
+
+// writeIDsOf returns the WriteIDs the metas of the blob point to.
+func (ecbs *ErasureCodedBlobStore) writeIDsOf(blobpath string) map[uint64]struct{} {
+	metas, ok, _ := ecbs.readMetas(blobpath)
+	ids := make(map[uint64]struct{})
+	for i, m := range metas {
+		if ok[i] {
+			ids[m.WriteID] = struct{}{}
+		}
+	}
+	return ids
+}
+
+// queryMeta reads the meta of the blob from all backends, and returns the
+// one agreed by the most backends.
+func (ecbs *ErasureCodedBlobStore) queryMeta(blobpath string) (blobMeta, []bool, error) {
+	metas, ok, errs := ecbs.readMetas(blobpath)
+	for i, m := range metas {
+		if ok[i] && m.numShards() != len(ecbs.backends) {
+			zap.S().Warnf("Meta of blob \"%s\" on backend %d has unexpected number of shards: %d", blobpath, i, m.numShards())
+			ok[i] = false
+		}
+	}
+
+	count := make(map[uint64]int)
+	best := -1
+	for i, m := range metas {
+		if !ok[i] {
+			continue
+		}
+		count[m.WriteID]++
+		if best < 0 || count[m.WriteID] > count[metas[best].WriteID] {
+			best = i
+		}
+	}
+	if best < 0 {
+		if allNotExist(errs) {
+			return blobMeta{}, nil, util.ENOENT
+		}
+		return blobMeta{}, nil, fmt.Errorf("No valid meta found for blob \"%s\": %v", blobpath, multierr.Combine(errs...))
+	}
+
+	m := metas[best]
+	agreed := make([]bool, len(ecbs.backends))
+	for i := range metas {
+		agreed[i] = ok[i] && metas[i] == m
+	}
+	return m, agreed, nil
+}
+
+func allNotExist(errs []error) bool {
+	if len(errs) == 0 {
+		return false
+	}
+	for _, err := range errs {
+		if !util.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+type shardReader struct {
+	blobpath string
+	meta     blobMeta
+	enc      *reedsolomon.Encoder
+
+	rs []io.ReadCloser
+	// pos[i] is the offset of rs[i] within the shard payload.
+	pos []int64
+	// shardOff is the offset within the shard payload of the current stripe.
+	shardOff int64
+	shards   [][]byte
+	off      int64
+	out      []byte
+}
+
+func (r *shardReader) drop(i int, err error) {
+	zap.S().Warnf("Shard %d of blob \"%s\" is unavailable: %v", i, r.blobpath, err)
+	r.rs[i].Close()
+	r.rs[i] = nil
+}
+
+func (r *shardReader) readStripe() error {
+	k := int(r.meta.DataShards)
+	plen := r.meta.pieceLen(r.off)
+
+	navail := 0
+	for i, sr := range r.rs {
+		r.shards[i] = nil
+		if sr == nil {
+			continue
+		}
+		// Reconstruct needs no more than k shards. The data shards come
+		// first, so the parity shards are only read on failures.
+		if navail >= k {
+			continue
+		}
+		if skip := r.shardOff - r.pos[i]; skip > 0 {
+			if _, err := io.CopyN(ioutil.Discard, sr, skip); err != nil {
+				r.drop(i, err)
+				continue
+			}
+			r.pos[i] += skip
+		}
+		s := make([]byte, plen)
+		if _, err := io.ReadFull(sr, s); err != nil {
+			r.drop(i, err)
+			continue
+		}
+		r.pos[i] += int64(plen)
+		r.shards[i] = s
+		navail++
+	}
+	if navail < k {
+		return ErrTooFewShards
+	}
+	r.shardOff += int64(plen)
+	if err := r.enc.Reconstruct(r.shards); err != nil {
+		return err
+	}
+
+	n := util.Int64Min(r.meta.Size-r.off, int64(plen*k))
+	r.out = r.out[:0]
+	for i := 0; i < k && int64(len(r.out)) < n; i++ {
+		r.out = append(r.out, r.shards[i]...)
+	}
+	r.out = r.out[:n]
+	r.off += n
+	return nil
+}
+
+func (r *shardReader) Read(p []byte) (int, error) {
+	if len(r.out) == 0 {
+		if r.off >= r.meta.Size {
+			return 0, io.EOF
+		}
+		if err := r.readStripe(); err != nil {
+			return 0, fmt.Errorf("Failed to read blob \"%s\" at offset %d: %v", r.blobpath, r.off, err)
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *shardReader) Close() error {
+	for _, sr := range r.rs {
+		if sr != nil {
+			sr.Close()
+		}
+	}
+	return nil
+}
+
+func (ecbs *ErasureCodedBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	m, agreed, err := ecbs.queryMeta(blobpath)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &shardReader{
+		blobpath: blobpath,
+		meta:     m,
+		enc:      ecbs.enc,
+		rs:       make([]io.ReadCloser, len(ecbs.backends)),
+		pos:      make([]int64, len(ecbs.backends)),
+		shards:   make([][]byte, len(ecbs.backends)),
+		out:      make([]byte, 0, m.stripeSize()),
+	}
+	navail := 0
+	sbp := shardBlobpath(blobpath, m.WriteID)
+	for i, bs := range ecbs.backends {
+		if !agreed[i] {
+			continue
+		}
+		sr, err := bs.OpenReader(sbp)
+		if err != nil {
+			zap.S().Warnf("Failed to open shard %d of blob \"%s\": %v", i, blobpath, err)
+			continue
+		}
+		h, err := readShardHeader(sr)
+		if err != nil || int(h.Index) != i || h.WriteID != m.WriteID {
+			zap.S().Warnf("Shard %d of blob \"%s\" doesn't match its meta. header: %+v, err: %v", i, blobpath, h, err)
+			sr.Close()
+			continue
+		}
+		r.rs[i] = sr
+		navail++
+	}
+	if navail < int(m.DataShards) {
+		r.Close()
+		return nil, fmt.Errorf("Failed to open blob \"%s\": %v", blobpath, ErrTooFewShards)
+	}
+	return r, nil
+}
+
+var _ = fl.FlagsReader(&ErasureCodedBlobStore{})
+
+func (ecbs *ErasureCodedBlobStore) Flags() int {
+	flags := fl.O_RDWRCREATE
+
+	for _, bs := range ecbs.backends {
+		if flagsreader, ok := bs.(fl.FlagsReader); ok {
+			flags = fl.Mask(flags, flagsreader.Flags())
+		}
+	}
+
+	return flags
+}
+
+var _ = blobstore.BlobLister(&ErasureCodedBlobStore{})
+
+// ListBlobs returns the blobs which have meta on any of the backends. Up to m
+// backends may fail to list.
+func (ecbs *ErasureCodedBlobStore) ListBlobs() ([]string, error) {
+	set := make(map[string]struct{})
+	var errs []error
+	for _, bs := range ecbs.backends {
+		lister, ok := bs.(blobstore.BlobLister)
+		if !ok {
+			return nil, fmt.Errorf("Backend blobstore \"%s\" don't support ListBlobs()", util.TryGetImplName(bs))
+		}
+		bps, err := lister.ListBlobs()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Backend blobstore \"%s\" failed to ListBlobs: %v", util.TryGetImplName(bs), err))
+			continue
+		}
+		for _, bp := range bps {
+			if IsMetaBlobpath(bp) {
+				set[bp[:len(bp)-len(MetaSuffix)]] = struct{}{}
+			}
+		}
+	}
+	if len(errs) > ecbs.ParityShards() {
+		return nil, multierr.Combine(errs...)
+	}
+
+	ret := make([]string, 0, len(set))
+	for bp := range set {
+		ret = append(ret, bp)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+var _ = blobstore.BlobSizer(&ErasureCodedBlobStore{})
+
+func (ecbs *ErasureCodedBlobStore) BlobSize(blobpath string) (int64, error) {
+	m, _, err := ecbs.queryMeta(blobpath)
+	if err != nil {
+		return -1, err
+	}
+	return m.Size, nil
+}
+
+var _ = blobstore.BlobRemover(&ErasureCodedBlobStore{})
+
+// RemoveBlob removes the meta and the shards of the blob from all backends.
+func (ecbs *ErasureCodedBlobStore) RemoveBlob(blobpath string) error {
+	ids := ecbs.writeIDsOf(blobpath)
+
+	var errs []error
+	found := false
+	// Remove meta first, so that a partially removed blob is never read.
+	for _, bs := range ecbs.backends {
+		remover, ok := bs.(blobstore.BlobRemover)
+		if !ok {
+			return fmt.Errorf("Backend blobstore \"%s\" don't support RemoveBlob()", util.TryGetImplName(bs))
+		}
+		err := remover.RemoveBlob(metaBlobpath(blobpath))
+		if err == nil {
+			found = true
+		} else if !util.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	for id := range ids {
+		sbp := shardBlobpath(blobpath, id)
+		for i := range ecbs.backends {
+			if err := ecbs.removeShard(i, sbp); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := multierr.Combine(errs...); err != nil {
+		return err
+	}
+	if !found {
+		return util.ENOENT
+	}
+	return nil
+}
+
+var _ = util.ImplNamed(&ErasureCodedBlobStore{})
+
+func (*ErasureCodedBlobStore) ImplName() string { return "ErasureCodedBlobStore" }
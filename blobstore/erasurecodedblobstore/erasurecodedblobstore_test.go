@@ -0,0 +1,295 @@
+package erasurecodedblobstore_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/erasurecodedblobstore"
+	tu "github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/util"
+)
+
+func init() { tu.EnsureLogger() }
+
+const k, m = 3, 2
+
+func testBackends() ([]*blobstore.FileBlobStore, []blobstore.BlobStore) {
+	fbss := make([]*blobstore.FileBlobStore, 0, k+m)
+	bss := make([]blobstore.BlobStore, 0, k+m)
+	for i := 0; i < k+m; i++ {
+		fbs := tu.TestFileBlobStoreOfName("ec")
+		fbss = append(fbss, fbs)
+		bss = append(bss, fbs)
+	}
+	return fbss, bss
+}
+
+func writeBlob(t *testing.T, bs blobstore.BlobStore, bp string, p []byte) {
+	w, err := bs.OpenWriter(bp)
+	if err != nil {
+		t.Fatalf("OpenWriter failed: %v", err)
+	}
+	// Write in odd sized pieces to exercise stripe buffering.
+	for len(p) > 0 {
+		n := util.IntMin(len(p), 13)
+		if _, err := w.Write(p[:n]); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		p = p[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func readBlob(bs blobstore.BlobStore, bp string) ([]byte, error) {
+	r, err := bs.OpenReader(bp)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// shardOf returns the blobpath of the shard of bp stored on fbs.
+func shardOf(t *testing.T, fbs *blobstore.FileBlobStore, bp string) string {
+	bps, err := fbs.ListBlobs()
+	if err != nil {
+		t.Fatalf("ListBlobs failed: %v", err)
+	}
+	var shards []string
+	for _, sbp := range bps {
+		if strings.HasPrefix(sbp, bp+".") && strings.HasSuffix(sbp, erasurecodedblobstore.ShardSuffix) {
+			shards = append(shards, sbp)
+		}
+	}
+	if len(shards) != 1 {
+		t.Fatalf("Expected a shard of %q, found: %v", bp, shards)
+	}
+	return shards[0]
+}
+
+func randomBytes(n int) []byte {
+	p := make([]byte, n)
+	rand.Read(p)
+	return p
+}
+
+func TestErasureCodedBlobStore_WriteRead(t *testing.T) {
+	_, bss := testBackends()
+	ecbs, err := erasurecodedblobstore.New(bss, k)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ecbs.SetStripeShardSize(7)
+
+	for _, size := range []int{0, 1, 20, 21, 22, 1000} {
+		p := randomBytes(size)
+		writeBlob(t, ecbs, "hoge", p)
+
+		q, err := readBlob(ecbs, "hoge")
+		if err != nil {
+			t.Fatalf("size %d: read failed: %v", size, err)
+		}
+		if !bytes.Equal(p, q) {
+			t.Errorf("size %d: content mismatch", size)
+		}
+		if n, err := ecbs.BlobSize("hoge"); err != nil || n != int64(size) {
+			t.Errorf("size %d: unexpected BlobSize %d, err: %v", size, n, err)
+		}
+	}
+
+	bps, err := ecbs.ListBlobs()
+	if err != nil {
+		t.Fatalf("ListBlobs failed: %v", err)
+	}
+	if !reflect.DeepEqual(bps, []string{"hoge"}) {
+		t.Errorf("Unexpected ListBlobs: %v", bps)
+	}
+
+	if err := ecbs.RemoveBlob("hoge"); err != nil {
+		t.Errorf("RemoveBlob failed: %v", err)
+	}
+	if _, err := ecbs.OpenReader("hoge"); !util.IsNotExist(err) {
+		t.Errorf("Expected ENOENT after RemoveBlob, got %v", err)
+	}
+	if _, err := ecbs.BlobSize("hoge"); !util.IsNotExist(err) {
+		t.Errorf("Expected ENOENT after RemoveBlob, got %v", err)
+	}
+}
+
+func TestErasureCodedBlobStore_Reconstruct(t *testing.T) {
+	fbss, bss := testBackends()
+	ecbs, err := erasurecodedblobstore.New(bss, k)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ecbs.SetStripeShardSize(16)
+
+	p := randomBytes(1000)
+	writeBlob(t, ecbs, "hoge", p)
+
+	// Lose a data shard and a parity shard.
+	for _, i := range []int{1, 4} {
+		if err := fbss[i].RemoveBlob(shardOf(t, fbss[i], "hoge")); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	q, err := readBlob(ecbs, "hoge")
+	if err != nil {
+		t.Fatalf("Read with %d lost shards failed: %v", m, err)
+	}
+	if !bytes.Equal(p, q) {
+		t.Errorf("Reconstructed content mismatch")
+	}
+
+	// One more lost shard is beyond what m parity shards can recover.
+	if err := fbss[0].RemoveBlob(shardOf(t, fbss[0], "hoge")); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, err := readBlob(ecbs, "hoge"); err == nil {
+		t.Errorf("Read with %d lost shards should fail", m+1)
+	}
+}
+
+type failAfterReader struct {
+	io.ReadCloser
+	left int
+}
+
+func (r *failAfterReader) Read(p []byte) (int, error) {
+	if r.left <= 0 {
+		return 0, errors.New("injected read failure")
+	}
+	if len(p) > r.left {
+		p = p[:r.left]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.left -= n
+	return n, err
+}
+
+func TestErasureCodedBlobStore_ReadFailureMidStream(t *testing.T) {
+	_, bss := testBackends()
+	// Reads from the first data shard fail after its header and a few stripes.
+	bss[0] = tu.RWInterceptBlobStore{
+		BE:         bss[0],
+		WrapWriter: func(orig io.WriteCloser) (io.WriteCloser, error) { return orig, nil },
+		WrapReader: func(orig io.ReadCloser) (io.ReadCloser, error) {
+			return &failAfterReader{orig, 100}, nil
+		},
+	}
+	ecbs, err := erasurecodedblobstore.New(bss, k)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ecbs.SetStripeShardSize(16)
+
+	p := randomBytes(1000)
+	writeBlob(t, ecbs, "hoge", p)
+
+	q, err := readBlob(ecbs, "hoge")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !bytes.Equal(p, q) {
+		t.Errorf("Content mismatch after mid-stream failure")
+	}
+}
+
+type failCloseWriter struct {
+	io.WriteCloser
+}
+
+func (w failCloseWriter) Close() error {
+	w.WriteCloser.Close()
+	return errors.New("injected close failure")
+}
+
+// failingBlobStore fails to close the shard writers while failShards is set.
+type failingBlobStore struct {
+	*blobstore.FileBlobStore
+	failShards bool
+}
+
+func (bs *failingBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	w, err := bs.FileBlobStore.OpenWriter(blobpath)
+	if err != nil || !bs.failShards || !strings.HasSuffix(blobpath, erasurecodedblobstore.ShardSuffix) {
+		return w, err
+	}
+	return failCloseWriter{w}, nil
+}
+
+func TestErasureCodedBlobStore_FailedOverwrite(t *testing.T) {
+	fbss, bss := testBackends()
+	failing := &failingBlobStore{FileBlobStore: fbss[2]}
+	bss[2] = failing
+	ecbs, err := erasurecodedblobstore.New(bss, k)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ecbs.SetStripeShardSize(16)
+
+	p1 := randomBytes(1000)
+	writeBlob(t, ecbs, "hoge", p1)
+	oldShard := shardOf(t, fbss[0], "hoge")
+
+	failing.failShards = true
+	w, err := ecbs.OpenWriter("hoge")
+	if err != nil {
+		t.Fatalf("OpenWriter failed: %v", err)
+	}
+	if _, err := w.Write(randomBytes(1000)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err == nil {
+		t.Fatalf("Close should fail when a shard fails to close")
+	}
+
+	// The blob keeps its old content, and the shards of the failed write are removed.
+	q, err := readBlob(ecbs, "hoge")
+	if err != nil {
+		t.Fatalf("Read after failed overwrite failed: %v", err)
+	}
+	if !bytes.Equal(p1, q) {
+		t.Errorf("Content changed by the failed overwrite")
+	}
+	for i, fbs := range fbss {
+		if sbp := shardOf(t, fbs, "hoge"); sbp != oldShard {
+			t.Errorf("Unexpected shard on backend %d: %q", i, sbp)
+		}
+	}
+
+	// A successful overwrite removes the shards of the previous write.
+	failing.failShards = false
+	p2 := randomBytes(500)
+	writeBlob(t, ecbs, "hoge", p2)
+	q, err = readBlob(ecbs, "hoge")
+	if err != nil {
+		t.Fatalf("Read after overwrite failed: %v", err)
+	}
+	if !bytes.Equal(p2, q) {
+		t.Errorf("Content mismatch after overwrite")
+	}
+	for i, fbs := range fbss {
+		if sbp := shardOf(t, fbs, "hoge"); sbp == oldShard {
+			t.Errorf("Shard of the previous write is left on backend %d", i)
+		}
+	}
+
+	if err := ecbs.RemoveBlob("hoge"); err != nil {
+		t.Fatalf("RemoveBlob failed: %v", err)
+	}
+	for i, fbs := range fbss {
+		if bps, err := fbs.ListBlobs(); err != nil || len(bps) != 0 {
+			t.Errorf("Blobs left on backend %d after RemoveBlob: %v, err: %v", i, bps, err)
+		}
+	}
+}
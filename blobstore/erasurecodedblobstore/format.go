@@ -0,0 +1,121 @@
+package erasurecodedblobstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Each blob is stored as a shard blob and a meta blob on every backend.
+//
+// The shard blob at blobpath.<WriteID>+ShardSuffix consists of shardHeader
+// followed by the shard pieces of each stripe. A stripe holds k*StripeShardSize bytes of
+// the blob, split into k data pieces and extended by m parity pieces. The
+// pieces of the last partial stripe are shortened to ceil(len/k) bytes,
+// padded with zeros.
+//
+// The meta blob at blobpath+MetaSuffix is written after all shard blobs are
+// complete, and holds the parameters needed to decode them, including the
+// WriteID naming the shard blobs. An overwrite writes its shard blobs under
+// a new WriteID, so the shards the old meta points to stay intact until
+// every meta is switched to the new WriteID.
+
+const (
+	MetaSuffix  = ".ecmeta"
+	ShardSuffix = ".ecshard"
+)
+
+var (
+	metaMagic  = [4]byte{'O', 'T', 'E', 'M'}
+	shardMagic = [4]byte{'O', 'T', 'E', 'S'}
+
+	ErrInvalidMeta  = errors.New("Invalid erasure coded blob meta.")
+	ErrInvalidShard = errors.New("Invalid erasure coded shard header.")
+)
+
+func metaBlobpath(blobpath string) string { return blobpath + MetaSuffix }
+
+func IsMetaBlobpath(blobpath string) bool { return strings.HasSuffix(blobpath, MetaSuffix) }
+
+func shardBlobpath(blobpath string, writeID uint64) string {
+	return fmt.Sprintf("%s.%016x%s", blobpath, writeID, ShardSuffix)
+}
+
+type blobMeta struct {
+	Magic           [4]byte
+	DataShards      uint8
+	ParityShards    uint8
+	_               uint16
+	StripeShardSize uint32
+	Size            int64
+	// WriteID is a random id shared by the shards of a single write. It
+	// tells apart shards left by an older or incomplete write.
+	WriteID uint64
+}
+
+func (m blobMeta) numShards() int { return int(m.DataShards) + int(m.ParityShards) }
+
+func (m blobMeta) stripeSize() int64 { return int64(m.DataShards) * int64(m.StripeShardSize) }
+
+// pieceLen returns the length of the shard pieces of the stripe starting at blob offset off.
+func (m blobMeta) pieceLen(off int64) int {
+	left := m.Size - off
+	if left >= m.stripeSize() {
+		return int(m.StripeShardSize)
+	}
+	k := int64(m.DataShards)
+	return int((left + k - 1) / k)
+}
+
+func readMeta(r io.Reader) (blobMeta, error) {
+	var m blobMeta
+	b, err := ioutil.ReadAll(io.LimitReader(r, int64(binary.Size(m))+1))
+	if err != nil {
+		return m, err
+	}
+	if len(b) != binary.Size(m) {
+		return m, ErrInvalidMeta
+	}
+	if err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &m); err != nil {
+		return m, err
+	}
+	if m.Magic != metaMagic || m.DataShards == 0 || m.StripeShardSize == 0 || m.Size < 0 {
+		return m, ErrInvalidMeta
+	}
+	return m, nil
+}
+
+func writeMeta(w io.Writer, m blobMeta) error {
+	m.Magic = metaMagic
+	return binary.Write(w, binary.LittleEndian, &m)
+}
+
+type shardHeader struct {
+	Magic   [4]byte
+	Index   uint8
+	_       [3]byte
+	WriteID uint64
+}
+
+func readShardHeader(r io.Reader) (shardHeader, error) {
+	var h shardHeader
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return h, err
+	}
+	if h.Magic != shardMagic {
+		return h, ErrInvalidShard
+	}
+	return h, nil
+}
+
+func writeShardHeader(w io.Writer, index int, writeID uint64) error {
+	h := shardHeader{Magic: shardMagic, Index: uint8(index), WriteID: writeID}
+	if err := binary.Write(w, binary.LittleEndian, &h); err != nil {
+		return fmt.Errorf("Failed to write shard header: %v", err)
+	}
+	return nil
+}
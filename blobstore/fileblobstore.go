@@ -179,7 +179,25 @@ func (f *FileBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
 	wc, err := os.Create(realpath)
 	zap.S().Debugf("OpenWriter(fullpath: %q) -> err: %v", realpath, err)
 	issuedOpenWriter.WithLabelValues(f.base).Inc()
-	return wc, err
+	if err != nil {
+		return nil, err
+	}
+	return fileBlobWriter{wc}, nil
+}
+
+type fileBlobWriter struct {
+	*os.File
+}
+
+var _ = WriteAborter(fileBlobWriter{})
+
+// Abort removes the partially written blob.
+func (w fileBlobWriter) Abort() error {
+	w.File.Close()
+	if err := os.Remove(w.Name()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
 
 func (f *FileBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
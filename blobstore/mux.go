@@ -50,6 +50,16 @@ func (m Mux) OpenReader(blobpath string) (io.ReadCloser, error) {
 	return bs.OpenReader(blobpath)
 }
 
+var _ = NoRecallReader(Mux{})
+
+func (m Mux) OpenReaderNoRecall(blobpath string) (io.ReadCloser, error) {
+	bs := m.findBlobStoreFor(blobpath)
+	if bs == nil {
+		return nil, ErrEmptyMux
+	}
+	return OpenReaderNoRecall(bs, blobpath)
+}
+
 var _ = RandomAccessBlobStore(Mux{})
 
 func (m Mux) Open(blobpath string, flags int) (BlobHandle, error) {
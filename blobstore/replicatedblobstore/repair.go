@@ -0,0 +1,244 @@
+package replicatedblobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/metadata"
+	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/util"
+)
+
+type RepairReport struct {
+	// Number of blobs checked.
+	NumBlobs int
+	// Number of blob copies made to missing, stale or torn replicas.
+	NumCopied int
+	// Number of blobs removed from replicas, completing partially failed removals.
+	NumRemoved int
+	// Blobs which failed to be repaired.
+	Failed []string
+}
+
+func (rbs *ReplicatedBlobStore) copyReplica(blobpath string, src io.Reader, dst int) error {
+	w, err := rbs.backends[dst].OpenWriter(blobpath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		rbs.abortReplicaWrite(dst, blobpath, w)
+		return err
+	}
+	return w.Close()
+}
+
+// replicaSize returns the size of the blob on the replica i, or -1 if unknown.
+func (rbs *ReplicatedBlobStore) replicaSize(i int, blobpath string) int64 {
+	sizer, ok := rbs.backends[i].(blobstore.BlobSizer)
+	if !ok {
+		return -1
+	}
+	size, err := sizer.BlobSize(blobpath)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// repairBlob copies the newest version of the blob to the replicas which
+// don't have it. present[i] tells if the replica i listed the blob.
+func (rbs *ReplicatedBlobStore) repairBlob(blobpath string, present []bool, dryrun bool) (int, error) {
+	sts := rbs.queryReplicas(blobpath)
+	defer closeReplicas(sts)
+
+	if len(sts) == 0 || sts[0].r == nil {
+		return 0, fmt.Errorf("%v", ErrNoReadableReplica)
+	}
+	newest := sts[0].ver
+
+	// A torn write leaves a prefix of the blob with a valid header, so the
+	// longest of the replicas of the newest version is the source.
+	sizes := make(map[int]int64)
+	srcj := 0
+	for j, st := range sts {
+		if st.r == nil {
+			continue
+		}
+		sizes[st.i] = rbs.replicaSize(st.i, blobpath)
+		if st.ver == newest && sizes[st.i] > sizes[sts[srcj].i] {
+			srcj = j
+		}
+	}
+	src := sts[srcj]
+	srcSize := sizes[src.i]
+
+	var dsts []int
+	for _, st := range sts {
+		switch {
+		case !present[st.i] || st.r == nil:
+			zap.S().Infof("Repair: blob \"%s\" is missing on replica %d", blobpath, st.i)
+		case newest != unknownVersion && st.ver < newest:
+			zap.S().Infof("Repair: blob \"%s\" on replica %d is stale. version: %d, newest: %d", blobpath, st.i, st.ver, newest)
+		case srcSize >= 0 && sizes[st.i] >= 0 && sizes[st.i] != srcSize:
+			zap.S().Infof("Repair: blob \"%s\" on replica %d is torn. size: %d, expected: %d", blobpath, st.i, sizes[st.i], srcSize)
+		default:
+			continue
+		}
+		dsts = append(dsts, st.i)
+	}
+	if len(dsts) == 0 || dryrun {
+		return len(dsts), nil
+	}
+
+	// Reuse the reader of the source replica for the first copy, and reopen
+	// it for the rest.
+	ncopied := 0
+	for j, dst := range dsts {
+		var r io.Reader = src.r
+		if j > 0 {
+			rc, err := rbs.backends[src.i].OpenReader(blobpath)
+			if err != nil {
+				return ncopied, err
+			}
+			defer rc.Close()
+			r = rc
+		}
+		if err := rbs.copyReplica(blobpath, r, dst); err != nil {
+			return ncopied, fmt.Errorf("Failed to copy blob to replica %d: %v", dst, err)
+		}
+		ncopied++
+	}
+	return ncopied, nil
+}
+
+// removeTombstones removes the tombstones of the blob from all replicas.
+func (rbs *ReplicatedBlobStore) removeTombstones(blobpath string) error {
+	tbp := metadata.ReplicaTombstoneBlobpath(blobpath)
+	var errs []error
+	for i := range rbs.backends {
+		if err := rbs.removeReplica(i, tbp); err != nil && !util.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
+// completeRemoval removes the blob with a tombstone from the replicas it
+// was left on, and then its tombstones.
+func (rbs *ReplicatedBlobStore) completeRemoval(blobpath string, present []bool, dryrun bool) (int, error) {
+	nremoved := 0
+	var errs []error
+	for i, p := range present {
+		if !p {
+			continue
+		}
+		zap.S().Infof("Repair: blob \"%s\" removed on other replicas is left on replica %d", blobpath, i)
+		if dryrun {
+			nremoved++
+			continue
+		}
+		if err := rbs.removeReplica(i, blobpath); err != nil && !util.IsNotExist(err) {
+			errs = append(errs, err)
+			continue
+		}
+		nremoved++
+	}
+	if dryrun || len(errs) > 0 {
+		return nremoved, multierr.Combine(errs...)
+	}
+	return nremoved, rbs.removeTombstones(blobpath)
+}
+
+// Repair re-copies the blobs missing, stale or torn on some of the replicas
+// from the replica with the newest version, and completes the removals which
+// failed on some of the replicas.
+func (rbs *ReplicatedBlobStore) Repair(ctx context.Context, dryrun bool) (*RepairReport, error) {
+	start := time.Now()
+
+	n := len(rbs.backends)
+	presence := make(map[string][]bool)
+	tombstoned := make(map[string]struct{})
+	for i := 0; i < n; i++ {
+		bps, err := rbs.listReplica(i)
+		if err != nil {
+			return nil, err
+		}
+		for _, bp := range bps {
+			if tbp, ok := tombstonedBlobpath(bp); ok {
+				tombstoned[tbp] = struct{}{}
+				continue
+			}
+			p, ok := presence[bp]
+			if !ok {
+				p = make([]bool, n)
+				presence[bp] = p
+			}
+			p[i] = true
+		}
+	}
+
+	report := &RepairReport{}
+	for bp, present := range presence {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		report.NumBlobs++
+		if _, ok := tombstoned[bp]; ok {
+			nremoved, err := rbs.completeRemoval(bp, present, dryrun)
+			report.NumRemoved += nremoved
+			if err != nil {
+				zap.S().Errorf("Repair: failed to remove blob \"%s\": %v", bp, err)
+				report.Failed = append(report.Failed, bp)
+			}
+			continue
+		}
+
+		ncopied, err := rbs.repairBlob(bp, present, dryrun)
+		report.NumCopied += ncopied
+		if err != nil {
+			zap.S().Errorf("Repair: failed to repair blob \"%s\": %v", bp, err)
+			report.Failed = append(report.Failed, bp)
+		}
+	}
+	// Drop the tombstones of the blobs already removed from all replicas.
+	for bp := range tombstoned {
+		if _, ok := presence[bp]; ok || dryrun {
+			continue
+		}
+		if err := rbs.removeTombstones(bp); err != nil {
+			zap.S().Warnf("Repair: failed to remove tombstones of blob \"%s\": %v", bp, err)
+		}
+	}
+
+	zap.S().Infof("Repair done. Checked %d blobs, copied %d replicas, removed %d replicas, %d failed. Dryrun: %t. Took %v.",
+		report.NumBlobs, report.NumCopied, report.NumRemoved, len(report.Failed), dryrun, time.Since(start))
+	return report, nil
+}
+
+type RepairTask struct {
+	RBS    *ReplicatedBlobStore
+	DryRun bool
+}
+
+type RepairResult struct {
+	Report *RepairReport
+	Error  error
+}
+
+func (r RepairResult) Err() error { return r.Error }
+
+func (t *RepairTask) Run(ctx context.Context) scheduler.Result {
+	report, err := t.RBS.Repair(ctx, t.DryRun)
+	return RepairResult{report, err}
+}
+
+func (t *RepairTask) String() string {
+	return fmt.Sprintf("replicatedblobstore.RepairTask{%s, dryrun: %t}", util.TryGetImplName(t.RBS), t.DryRun)
+}
@@ -0,0 +1,495 @@
+package replicatedblobstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/version"
+	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/metadata"
+	oprometheus "github.com/nyaxt/otaru/prometheus"
+	"github.com/nyaxt/otaru/util"
+)
+
+const promSubsystem = "replicatedblobstore"
+
+var (
+	readFallbackCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "read_fallback",
+		Help:      "Number of times a read fell back to the next replica.",
+	})
+	replicaWriteFailureCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "replica_write_failure",
+		Help:      "Number of blob writes which failed on a replica.",
+	})
+)
+
+var ErrNoReadableReplica = errors.New("No readable replica found.")
+
+// unknownVersion is assigned to a replica whose blob version couldn't be
+// queried, e.g. a metadata blob which isn't in the chunk format.
+const unknownVersion version.Version = -1
+
+// ReplicatedBlobStore replicates blobs to multiple backend blobstores.
+// A write succeeds if it succeeded on at least "quorum" replicas. A read is
+// served from the first readable replica, falling back to the next one of the
+// same version on error. Reads don't compare the versions of the replicas, so
+// a replica which missed a write serves the stale blob until Repair.
+type ReplicatedBlobStore struct {
+	backends     []blobstore.BlobStore
+	quorum       int
+	queryVersion version.QueryFunc
+}
+
+var _ = blobstore.BlobStore(&ReplicatedBlobStore{})
+
+// New creates ReplicatedBlobStore over backends. backends[0] is the
+// preferred replica for reads. If quorum is 0, writes need to succeed on all
+// backends.
+func New(backends []blobstore.BlobStore, quorum int, queryVersion version.QueryFunc) (*ReplicatedBlobStore, error) {
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("ReplicatedBlobStore requires at least one backend.")
+	}
+	if quorum == 0 {
+		quorum = len(backends)
+	}
+	if quorum < 0 || quorum > len(backends) {
+		return nil, fmt.Errorf("Invalid write quorum %d for %d backends.", quorum, len(backends))
+	}
+
+	return &ReplicatedBlobStore{
+		backends:     backends,
+		quorum:       quorum,
+		queryVersion: queryVersion,
+	}, nil
+}
+
+func (rbs *ReplicatedBlobStore) Backends() []blobstore.BlobStore { return rbs.backends }
+func (rbs *ReplicatedBlobStore) Quorum() int                     { return rbs.quorum }
+
+type replicaWriter struct {
+	rbs       *ReplicatedBlobStore
+	blobpath  string
+	ws        []io.WriteCloser
+	errs      []error
+	numFailed int
+}
+
+func (w *replicaWriter) fail(i int, err error) {
+	zap.S().Warnf("Write of blob \"%s\" to replica %d failed: %v", w.blobpath, i, err)
+	replicaWriteFailureCounter.Inc()
+	w.errs[i] = err
+	w.numFailed++
+	w.abort(i)
+}
+
+// abort discards the blob partially written to the replica i, so that a torn
+// blob isn't committed on it.
+func (w *replicaWriter) abort(i int) {
+	rw := w.ws[i]
+	if rw == nil {
+		return
+	}
+	w.ws[i] = nil
+	w.rbs.abortReplicaWrite(i, w.blobpath, rw)
+}
+
+func (rbs *ReplicatedBlobStore) abortReplicaWrite(i int, blobpath string, w io.WriteCloser) {
+	if aborter, ok := w.(blobstore.WriteAborter); ok {
+		if err := aborter.Abort(); err != nil {
+			zap.S().Warnf("Failed to abort write of blob \"%s\" to replica %d: %v", blobpath, i, err)
+		}
+		return
+	}
+
+	// The writer can't discard the blob, so remove the torn blob committed on Close.
+	w.Close()
+	if err := rbs.removeReplica(i, blobpath); err != nil && !util.IsNotExist(err) {
+		zap.S().Warnf("Failed to remove torn blob \"%s\" from replica %d: %v", blobpath, i, err)
+	}
+}
+
+func (w *replicaWriter) abortAll() {
+	for i := range w.ws {
+		w.abort(i)
+	}
+}
+
+func (w *replicaWriter) quorumErr() error {
+	if len(w.ws)-w.numFailed >= w.rbs.quorum {
+		return nil
+	}
+	return fmt.Errorf("Write of blob \"%s\" succeeded only on %d replicas, below quorum %d: %v",
+		w.blobpath, len(w.ws)-w.numFailed, w.rbs.quorum, multierr.Combine(w.errs...))
+}
+
+func (w *replicaWriter) Write(p []byte) (int, error) {
+	for i, rw := range w.ws {
+		if rw == nil {
+			continue
+		}
+		if _, err := rw.Write(p); err != nil {
+			w.fail(i, err)
+		}
+	}
+	if err := w.quorumErr(); err != nil {
+		// The blob is incomplete on all replicas.
+		w.abortAll()
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *replicaWriter) Close() error {
+	if err := w.quorumErr(); err != nil {
+		w.abortAll()
+		return err
+	}
+
+	for i, rw := range w.ws {
+		if rw == nil {
+			continue
+		}
+		w.ws[i] = nil
+		if err := rw.Close(); err != nil && w.errs[i] == nil {
+			zap.S().Warnf("Write of blob \"%s\" to replica %d failed on close: %v", w.blobpath, i, err)
+			replicaWriteFailureCounter.Inc()
+			w.errs[i] = err
+			w.numFailed++
+		}
+	}
+	return w.quorumErr()
+}
+
+func (rbs *ReplicatedBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	w := &replicaWriter{
+		rbs:      rbs,
+		blobpath: blobpath,
+		ws:       make([]io.WriteCloser, len(rbs.backends)),
+		errs:     make([]error, len(rbs.backends)),
+	}
+	for i, bs := range rbs.backends {
+		bw, err := bs.OpenWriter(blobpath)
+		if err != nil {
+			w.fail(i, err)
+			continue
+		}
+		w.ws[i] = bw
+	}
+	if err := w.quorumErr(); err != nil {
+		w.abortAll()
+		return nil, err
+	}
+	return w, nil
+}
+
+type replicaState struct {
+	i   int
+	ver version.Version
+	// r is the reader positioned at the beginning of the blob. nil if the
+	// replica isn't readable.
+	r   io.ReadCloser
+	err error
+}
+
+type prefixedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (rbs *ReplicatedBlobStore) queryReplica(i int, blobpath string) replicaState {
+	r, err := rbs.backends[i].OpenReader(blobpath)
+	if err != nil {
+		return replicaState{i: i, err: err}
+	}
+
+	// Keep the bytes consumed by queryVersion, so that the reader can be
+	// handed out without reopening.
+	var buf bytes.Buffer
+	ver, err := rbs.queryVersion(io.TeeReader(r, &buf))
+	if err != nil {
+		zap.S().Infof("Failed to query version of blob \"%s\" on replica %d: %v", blobpath, i, err)
+		ver = unknownVersion
+	}
+	return replicaState{i: i, ver: ver, r: prefixedReadCloser{io.MultiReader(&buf, r), r}}
+}
+
+// queryReplicas returns the states of all replicas of the blob, with the
+// replicas having the newest version first.
+func (rbs *ReplicatedBlobStore) queryReplicas(blobpath string) []replicaState {
+	sts := make([]replicaState, 0, len(rbs.backends))
+	for i := range rbs.backends {
+		sts = append(sts, rbs.queryReplica(i, blobpath))
+	}
+	sort.SliceStable(sts, func(i, j int) bool {
+		a, b := sts[i], sts[j]
+		if (a.r != nil) != (b.r != nil) {
+			return a.r != nil
+		}
+		return a.ver > b.ver
+	})
+	return sts
+}
+
+func allNotExist(errs []error) bool {
+	if len(errs) == 0 {
+		return false
+	}
+	for _, err := range errs {
+		if !util.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+func closeReplicas(sts []replicaState) {
+	for _, st := range sts {
+		if st.r != nil {
+			st.r.Close()
+		}
+	}
+}
+
+// replicaReader reads from a replica, and falls back to the next replica of
+// the same version on a read error.
+type replicaReader struct {
+	rbs      *ReplicatedBlobStore
+	blobpath string
+	st       replicaState
+	offset   int64
+}
+
+func (r *replicaReader) fallback(err error) error {
+	zap.S().Warnf("Read of blob \"%s\" from replica %d failed at offset %d: %v", r.blobpath, r.st.i, r.offset, err)
+	readFallbackCounter.Inc()
+
+	r.st.r.Close()
+	r.st.r = nil
+	for i := r.st.i + 1; i < len(r.rbs.backends); i++ {
+		st := r.rbs.queryReplica(i, r.blobpath)
+		if st.r == nil {
+			zap.S().Warnf("Read of blob \"%s\" failed to open replica %d: %v", r.blobpath, i, st.err)
+			continue
+		}
+		if st.ver != r.st.ver {
+			zap.S().Warnf("Read of blob \"%s\" can't continue on replica %d of version %d, while read version %d", r.blobpath, i, st.ver, r.st.ver)
+			st.r.Close()
+			continue
+		}
+		if _, err := io.CopyN(ioutil.Discard, st.r, r.offset); err != nil {
+			zap.S().Warnf("Read of blob \"%s\" from replica %d failed to skip to offset %d: %v", r.blobpath, i, r.offset, err)
+			st.r.Close()
+			continue
+		}
+		zap.S().Infof("Read of blob \"%s\" continues on replica %d", r.blobpath, i)
+		r.st = st
+		return nil
+	}
+	return fmt.Errorf("%v Last error: %v", ErrNoReadableReplica, err)
+}
+
+func (r *replicaReader) Read(p []byte) (int, error) {
+	for {
+		if r.st.r == nil {
+			return 0, ErrNoReadableReplica
+		}
+		n, err := r.st.r.Read(p)
+		r.offset += int64(n)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+		if ferr := r.fallback(err); ferr != nil {
+			return 0, ferr
+		}
+	}
+}
+
+func (r *replicaReader) Close() error {
+	if r.st.r != nil {
+		r.st.r.Close()
+		r.st.r = nil
+	}
+	return nil
+}
+
+func (rbs *ReplicatedBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	var errs []error
+	for i := range rbs.backends {
+		st := rbs.queryReplica(i, blobpath)
+		if st.r == nil {
+			errs = append(errs, st.err)
+			continue
+		}
+		if i != 0 {
+			zap.S().Infof("Read of blob \"%s\" falls back to replica %d", blobpath, i)
+			readFallbackCounter.Inc()
+		}
+		return &replicaReader{rbs: rbs, blobpath: blobpath, st: st}, nil
+	}
+	if allNotExist(errs) {
+		return nil, util.ENOENT
+	}
+	return nil, fmt.Errorf("%v %v", ErrNoReadableReplica, multierr.Combine(errs...))
+}
+
+var _ = fl.FlagsReader(&ReplicatedBlobStore{})
+
+func (rbs *ReplicatedBlobStore) Flags() int {
+	flags := fl.O_RDWRCREATE
+
+	for _, bs := range rbs.backends {
+		if flagsreader, ok := bs.(fl.FlagsReader); ok {
+			flags = fl.Mask(flags, flagsreader.Flags())
+		}
+	}
+
+	return flags
+}
+
+var _ = blobstore.BlobLister(&ReplicatedBlobStore{})
+
+func (rbs *ReplicatedBlobStore) listReplica(i int) ([]string, error) {
+	bs := rbs.backends[i]
+	lister, ok := bs.(blobstore.BlobLister)
+	if !ok {
+		return nil, fmt.Errorf("Backend blobstore \"%s\" don't support ListBlobs()", util.TryGetImplName(bs))
+	}
+	bps, err := lister.ListBlobs()
+	if err != nil {
+		return nil, fmt.Errorf("Backend blobstore \"%s\" failed to ListBlobs: %v", util.TryGetImplName(bs), err)
+	}
+	return bps, nil
+}
+
+// ListBlobs returns the union of the blobs on all replicas.
+func (rbs *ReplicatedBlobStore) ListBlobs() ([]string, error) {
+	set := make(map[string]struct{})
+	for i := range rbs.backends {
+		bps, err := rbs.listReplica(i)
+		if err != nil {
+			return nil, err
+		}
+		for _, bp := range bps {
+			if _, ok := tombstonedBlobpath(bp); ok {
+				continue
+			}
+			set[bp] = struct{}{}
+		}
+	}
+
+	ret := make([]string, 0, len(set))
+	for bp := range set {
+		ret = append(ret, bp)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+var _ = blobstore.BlobSizer(&ReplicatedBlobStore{})
+
+// BlobSize returns the size of the blob on the first replica which has it.
+func (rbs *ReplicatedBlobStore) BlobSize(blobpath string) (int64, error) {
+	var errs []error
+	for _, bs := range rbs.backends {
+		sizer, ok := bs.(blobstore.BlobSizer)
+		if !ok {
+			errs = append(errs, fmt.Errorf("Backend blobstore \"%s\" don't support BlobSize()", util.TryGetImplName(bs)))
+			continue
+		}
+		size, err := sizer.BlobSize(blobpath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return size, nil
+	}
+	if allNotExist(errs) {
+		return -1, util.ENOENT
+	}
+	return -1, multierr.Combine(errs...)
+}
+
+var _ = blobstore.BlobRemover(&ReplicatedBlobStore{})
+
+func (rbs *ReplicatedBlobStore) removeReplica(i int, blobpath string) error {
+	bs := rbs.backends[i]
+	remover, ok := bs.(blobstore.BlobRemover)
+	if !ok {
+		return fmt.Errorf("Backend blobstore \"%s\" don't support RemoveBlob()", util.TryGetImplName(bs))
+	}
+	return remover.RemoveBlob(blobpath)
+}
+
+// tombstonedBlobpath returns the blobpath whose removal the tombstone blob records.
+func tombstonedBlobpath(blobpath string) (string, bool) {
+	prefix := metadata.ReplicaTombstoneBlobpathPrefix + "."
+	if !strings.HasPrefix(blobpath, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(blobpath, prefix), true
+}
+
+// writeTombstones records the removal of the blob on all replicas, so that
+// Repair removes the blob left on the replicas it failed to be removed from,
+// instead of copying it back. This assumes blobpaths aren't reused after removal.
+func (rbs *ReplicatedBlobStore) writeTombstones(blobpath string) {
+	tbp := metadata.ReplicaTombstoneBlobpath(blobpath)
+	for i, bs := range rbs.backends {
+		w, err := bs.OpenWriter(tbp)
+		if err == nil {
+			err = w.Close()
+		}
+		if err != nil {
+			zap.S().Warnf("Failed to write tombstone of blob \"%s\" to replica %d: %v", blobpath, i, err)
+		}
+	}
+}
+
+// RemoveBlob removes the blob from all replicas. Replicas which don't have
+// the blob are counted as success towards the quorum. If the removal failed
+// on some replicas, tombstones are left for Repair to complete the removal.
+func (rbs *ReplicatedBlobStore) RemoveBlob(blobpath string) error {
+	var errs []error
+	nsucc := 0
+	for i := range rbs.backends {
+		if err := rbs.removeReplica(i, blobpath); err != nil && !util.IsNotExist(err) {
+			errs = append(errs, err)
+			continue
+		}
+		nsucc++
+	}
+	if len(errs) > 0 && nsucc > 0 {
+		rbs.writeTombstones(blobpath)
+	}
+	if nsucc < rbs.quorum {
+		return fmt.Errorf("Removal of blob \"%s\" succeeded only on %d replicas, below quorum %d: %v",
+			blobpath, nsucc, rbs.quorum, multierr.Combine(errs...))
+	}
+	if len(errs) > 0 {
+		zap.S().Warnf("Removal of blob \"%s\" failed on some replicas: %v", blobpath, multierr.Combine(errs...))
+	}
+	return nil
+}
+
+var _ = util.ImplNamed(&ReplicatedBlobStore{})
+
+func (*ReplicatedBlobStore) ImplName() string { return "ReplicatedBlobStore" }
@@ -0,0 +1,439 @@
+package replicatedblobstore_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/blobstore/replicatedblobstore"
+	"github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/scheduler"
+	tu "github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/util"
+)
+
+func init() { tu.EnsureLogger() }
+
+func testBackends(n int) []*blobstore.FileBlobStore {
+	ret := make([]*blobstore.FileBlobStore, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, tu.TestFileBlobStoreOfName("replica"))
+	}
+	return ret
+}
+
+func asBlobStores(fbss []*blobstore.FileBlobStore) []blobstore.BlobStore {
+	ret := make([]blobstore.BlobStore, 0, len(fbss))
+	for _, fbs := range fbss {
+		ret = append(ret, fbs)
+	}
+	return ret
+}
+
+func TestReplicatedBlobStore_WriteRead(t *testing.T) {
+	fbss := testBackends(3)
+	rbs, err := replicatedblobstore.New(asBlobStores(fbss), 0, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if err := tu.WriteVersionedBlob(rbs, "hoge", 3); err != nil {
+		t.Fatalf("%v", err)
+	}
+	for i, fbs := range fbss {
+		if err := tu.AssertBlobVersion(fbs, "hoge", 3); err != nil {
+			t.Errorf("replica %d: %v", i, err)
+		}
+	}
+
+	// The preferred replica is stale. Reads don't compare the replicas, and
+	// are served from it until Repair.
+	if err := tu.WriteVersionedBlob(fbss[0], "hoge", 2); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := tu.AssertBlobVersion(rbs, "hoge", 2); err != nil {
+		t.Errorf("Read should be served from the preferred replica: %v", err)
+	}
+	if _, err := rbs.Repair(context.Background(), false); err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if err := tu.AssertBlobVersion(rbs, "hoge", 3); err != nil {
+		t.Errorf("Read after Repair: %v", err)
+	}
+
+	// The preferred replica is missing the blob.
+	if err := fbss[0].RemoveBlob("hoge"); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := tu.AssertBlobVersion(rbs, "hoge", 3); err != nil {
+		t.Errorf("Read should fall back to the next replica: %v", err)
+	}
+	if size, err := rbs.BlobSize("hoge"); err != nil || size != 1 {
+		t.Errorf("Unexpected BlobSize: %d, err: %v", size, err)
+	}
+
+	if _, err := rbs.OpenReader("nonexistent"); !util.IsNotExist(err) {
+		t.Errorf("Expected ENOENT for nonexistent blob, got %v", err)
+	}
+
+	if err := rbs.RemoveBlob("hoge"); err != nil {
+		t.Errorf("RemoveBlob failed: %v", err)
+	}
+	bps, err := rbs.ListBlobs()
+	if err != nil {
+		t.Fatalf("ListBlobs failed: %v", err)
+	}
+	if len(bps) != 0 {
+		t.Errorf("Blobs left after RemoveBlob: %v", bps)
+	}
+}
+
+func TestReplicatedBlobStore_Quorum(t *testing.T) {
+	fbss := testBackends(2)
+	rodir, err := ioutil.TempDir("", "blobstoretestreadonly")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	robs, err := blobstore.NewFileBlobStore(rodir, flags.O_RDONLY)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	backends := append(asBlobStores(fbss), robs)
+
+	rbs, err := replicatedblobstore.New(backends, 2, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := tu.WriteVersionedBlob(rbs, "hoge", 1); err != nil {
+		t.Errorf("Write should succeed on quorum: %v", err)
+	}
+
+	rbsall, err := replicatedblobstore.New(backends, 0, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := tu.WriteVersionedBlob(rbsall, "fuga", 1); err == nil {
+		t.Errorf("Write should fail below quorum")
+	}
+
+	if _, err := replicatedblobstore.New(backends, 4, tu.TestQueryVersion); err == nil {
+		t.Errorf("New should fail on quorum larger than the number of backends")
+	}
+}
+
+func TestReplicatedBlobStore_Repair(t *testing.T) {
+	fbss := testBackends(3)
+	rbs, err := replicatedblobstore.New(asBlobStores(fbss), 0, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for _, bp := range []string{"ok", "missing", "stale"} {
+		if err := tu.WriteVersionedBlob(rbs, bp, 3); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	if err := fbss[1].RemoveBlob("missing"); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := tu.WriteVersionedBlob(fbss[2], "stale", 2); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	report, err := rbs.Repair(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Repair(dryrun) failed: %v", err)
+	}
+	if report.NumBlobs != 3 || report.NumCopied != 2 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if err := tu.AssertBlobVersion(fbss[2], "stale", 2); err != nil {
+		t.Errorf("dryrun should not modify replicas: %v", err)
+	}
+
+	report, err = rbs.Repair(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if report.NumCopied != 2 || len(report.Failed) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	for i, fbs := range fbss {
+		for _, bp := range []string{"ok", "missing", "stale"} {
+			if err := tu.AssertBlobVersion(fbs, bp, 3); err != nil {
+				t.Errorf("replica %d blob %s: %v", i, bp, err)
+			}
+		}
+	}
+}
+
+func TestReplicatedBlobStore_CachedBlobStoreBackend(t *testing.T) {
+	cachedblobstore.DisableAutoSyncForTesting = true
+	defer func() { cachedblobstore.DisableAutoSyncForTesting = false }()
+
+	fbss := testBackends(2)
+	rbs, err := replicatedblobstore.New(asBlobStores(fbss), 0, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	cachebs := tu.TestFileBlobStoreOfName("cache")
+	s := scheduler.NewScheduler()
+
+	if err := tu.WriteVersionedBlob(fbss[1], "backendonly", 5); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	cbs, err := cachedblobstore.New(rbs, cachebs, s, flags.O_RDWRCREATE, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("Failed to create CachedBlobStore: %v", err)
+	}
+	defer cbs.Quit()
+
+	if err := tu.AssertBlobVersionRA(cbs, "backendonly", 5); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if err := tu.WriteVersionedBlobRA(cbs, "backendonly", 10); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := cbs.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	for i, fbs := range fbss {
+		if err := tu.AssertBlobVersion(fbs, "backendonly", 10); err != nil {
+			t.Errorf("replica %d: %v", i, err)
+		}
+	}
+}
+
+func writeBlobBytewise(bs blobstore.BlobStore, blobpath string, data []byte) error {
+	w, err := bs.OpenWriter(blobpath)
+	if err != nil {
+		return err
+	}
+	for i := range data {
+		if _, err := w.Write(data[i : i+1]); err != nil {
+			w.Close()
+			return err
+		}
+	}
+	return w.Close()
+}
+
+func readBlob(bs blobstore.BlobStore, blobpath string) ([]byte, error) {
+	r, err := bs.OpenReader(blobpath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// failingWriteBlobStore fails the writes after the first one.
+type failingWriteBlobStore struct {
+	*blobstore.FileBlobStore
+}
+
+type failingWriter struct {
+	io.WriteCloser
+	nwrites int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.nwrites++
+	if w.nwrites > 1 {
+		return 0, errors.New("injected write failure")
+	}
+	return w.WriteCloser.Write(p)
+}
+
+func (w *failingWriter) Abort() error {
+	return w.WriteCloser.(blobstore.WriteAborter).Abort()
+}
+
+func (bs failingWriteBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	w, err := bs.FileBlobStore.OpenWriter(blobpath)
+	if err != nil {
+		return nil, err
+	}
+	return &failingWriter{WriteCloser: w}, nil
+}
+
+func TestReplicatedBlobStore_AbortFailedReplica(t *testing.T) {
+	fbss := testBackends(3)
+	backends := asBlobStores(fbss)
+	backends[1] = failingWriteBlobStore{fbss[1]}
+
+	rbs, err := replicatedblobstore.New(backends, 2, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	data := []byte{3, 1, 2, 3}
+	if err := writeBlobBytewise(rbs, "hoge", data); err != nil {
+		t.Fatalf("Write should succeed on quorum: %v", err)
+	}
+
+	// The torn blob must not be committed on the failed replica.
+	if _, err := fbss[1].OpenReader("hoge"); !util.IsNotExist(err) {
+		t.Errorf("Torn blob left on the failed replica. err: %v", err)
+	}
+
+	// Repair once the replica has recovered.
+	rbs, err = replicatedblobstore.New(asBlobStores(fbss), 2, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	report, err := rbs.Repair(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if report.NumCopied != 1 || len(report.Failed) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	for i, fbs := range fbss {
+		if bs, err := readBlob(fbs, "hoge"); err != nil || !bytes.Equal(bs, data) {
+			t.Errorf("replica %d: %v, err: %v", i, bs, err)
+		}
+	}
+}
+
+func TestReplicatedBlobStore_RepairTorn(t *testing.T) {
+	fbss := testBackends(3)
+	rbs, err := replicatedblobstore.New(asBlobStores(fbss), 0, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	data := []byte{3, 1, 2, 3}
+	if err := writeBlobBytewise(rbs, "hoge", data); err != nil {
+		t.Fatalf("%v", err)
+	}
+	// The preferred replica has a prefix of the blob with the same version.
+	if err := writeBlobBytewise(fbss[0], "hoge", data[:2]); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	report, err := rbs.Repair(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if report.NumCopied != 1 || len(report.Failed) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if bs, err := readBlob(fbss[0], "hoge"); err != nil || !bytes.Equal(bs, data) {
+		t.Errorf("Torn replica not repaired: %v, err: %v", bs, err)
+	}
+}
+
+// flakyRemoveBlobStore fails RemoveBlob while fail is set.
+type flakyRemoveBlobStore struct {
+	*blobstore.FileBlobStore
+	fail *bool
+}
+
+func (bs flakyRemoveBlobStore) RemoveBlob(blobpath string) error {
+	if *bs.fail {
+		return errors.New("injected remove failure")
+	}
+	return bs.FileBlobStore.RemoveBlob(blobpath)
+}
+
+func TestReplicatedBlobStore_RepairPartialRemoval(t *testing.T) {
+	fbss := testBackends(3)
+	fail := true
+	backends := asBlobStores(fbss)
+	backends[2] = flakyRemoveBlobStore{fbss[2], &fail}
+
+	rbs, err := replicatedblobstore.New(backends, 2, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := tu.WriteVersionedBlob(rbs, "hoge", 3); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := rbs.RemoveBlob("hoge"); err != nil {
+		t.Fatalf("RemoveBlob should succeed on quorum: %v", err)
+	}
+	if err := tu.AssertBlobVersion(fbss[2], "hoge", 3); err != nil {
+		t.Fatalf("The blob should be left on the failed replica: %v", err)
+	}
+	if bps, err := rbs.ListBlobs(); err != nil || len(bps) != 1 || bps[0] != "hoge" {
+		t.Errorf("Unexpected ListBlobs: %v, err: %v", bps, err)
+	}
+
+	fail = false
+	report, err := rbs.Repair(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if report.NumCopied != 0 || report.NumRemoved != 1 || len(report.Failed) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	for i, fbs := range fbss {
+		bps, err := fbs.ListBlobs()
+		if err != nil {
+			t.Fatalf("ListBlobs failed: %v", err)
+		}
+		if len(bps) != 0 {
+			t.Errorf("replica %d: blobs left after repair: %v", i, bps)
+		}
+	}
+}
+
+// failingReadBlobStore fails the reads after the first byte.
+type failingReadBlobStore struct {
+	*blobstore.FileBlobStore
+}
+
+type failingReader struct {
+	io.ReadCloser
+	nread int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.nread > 0 {
+		return 0, errors.New("injected read failure")
+	}
+	n, err := r.ReadCloser.Read(p[:1])
+	r.nread += n
+	return n, err
+}
+
+func (bs failingReadBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	r, err := bs.FileBlobStore.OpenReader(blobpath)
+	if err != nil {
+		return nil, err
+	}
+	return &failingReader{ReadCloser: r}, nil
+}
+
+func TestReplicatedBlobStore_ReadFallback(t *testing.T) {
+	fbss := testBackends(3)
+	backends := asBlobStores(fbss)
+	backends[0] = failingReadBlobStore{fbss[0]}
+	rbs, err := replicatedblobstore.New(backends, 0, tu.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	data := []byte{3, 1, 2, 3}
+	if err := writeBlobBytewise(rbs, "hoge", data); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if bs, err := readBlob(rbs, "hoge"); err != nil || !bytes.Equal(bs, data) {
+		t.Errorf("Read should fall back to the next replica: %v, err: %v", bs, err)
+	}
+
+	// The read doesn't continue on a replica of another version.
+	if err := writeBlobBytewise(fbss[1], "hoge", []byte{2, 1, 2, 3}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if bs, err := readBlob(rbs, "hoge"); err != nil || !bytes.Equal(bs, data) {
+		t.Errorf("Read should skip the replica of another version: %v, err: %v", bs, err)
+	}
+}
@@ -0,0 +1,135 @@
+package resilientblobstore
+
+import (
+	"sync"
+	"time"
+)
+
+type BreakerState int
+
+const (
+	// BreakerClosed lets all operations through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects all operations until the cooldown passes.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe operation through to test the backend.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "Closed"
+	case BreakerOpen:
+		return "Open"
+	case BreakerHalfOpen:
+		return "HalfOpen"
+	default:
+		return "<unknown>"
+	}
+}
+
+type BreakerStatus struct {
+	State               BreakerState
+	ConsecutiveFailures int
+	// Time the breaker last transitioned to BreakerOpen. Zero if never opened.
+	OpenedAt time.Time
+}
+
+// Breaker is a circuit breaker which opens after "threshold" consecutive
+// failures, and lets a probe operation through after "cooldown" to decide
+// whether to close again.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	onStateChange func(BreakerState)
+
+	mu                  sync.Mutex
+	state               BreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	probeInFlight       bool
+}
+
+// NewBreaker creates a Breaker. The breaker never opens if threshold <= 0.
+func NewBreaker(threshold int, cooldown time.Duration, onStateChange func(BreakerState)) *Breaker {
+	if onStateChange == nil {
+		onStateChange = func(BreakerState) {}
+	}
+	return &Breaker{
+		threshold:     threshold,
+		cooldown:      cooldown,
+		onStateChange: onStateChange,
+	}
+}
+
+func (b *Breaker) setStateWithLock(s BreakerState) {
+	if b.state == s {
+		return
+	}
+	b.state = s
+	if s == BreakerOpen {
+		b.openedAt = time.Now()
+	}
+	b.onStateChange(s)
+}
+
+// Allow returns ErrCircuitOpen if an operation shouldn't be attempted now.
+// Otherwise, the caller must report the operation result via Success or Failure.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.setStateWithLock(BreakerHalfOpen)
+		b.probeInFlight = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probeInFlight {
+			return ErrCircuitOpen
+		}
+		b.probeInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures = 0
+	b.probeInFlight = false
+	b.setStateWithLock(BreakerClosed)
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	b.probeInFlight = false
+	if b.threshold <= 0 {
+		return
+	}
+	if b.state == BreakerHalfOpen || b.consecutiveFailures >= b.threshold {
+		b.setStateWithLock(BreakerOpen)
+	}
+}
+
+func (b *Breaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return BreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		OpenedAt:            b.openedAt,
+	}
+}
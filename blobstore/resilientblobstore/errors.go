@@ -0,0 +1,43 @@
+package resilientblobstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nyaxt/fuse"
+
+	"github.com/nyaxt/otaru/util"
+)
+
+var (
+	ErrCircuitOpen = errors.New("Circuit breaker is open. Backend blobstore is temporarily unavailable.")
+	ErrTimeout     = errors.New("Backend blobstore operation timed out.")
+)
+
+// Error is returned when a backend blobstore operation failed even after
+// retries, or was rejected by the circuit breaker.
+type Error struct {
+	Op       string
+	BlobPath string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	if e.BlobPath == "" {
+		return fmt.Sprintf("%s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("%s \"%s\": %v", e.Op, e.BlobPath, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Temporary returns true if the operation is expected to succeed if retried
+// later, i.e. it was rejected by an open circuit breaker.
+func (e *Error) Temporary() bool { return errors.Is(e.Err, ErrCircuitOpen) }
+
+func (e *Error) Errno() fuse.Errno {
+	if e.Temporary() {
+		return util.EAGAIN.Errno()
+	}
+	return util.EIO.Errno()
+}
@@ -0,0 +1,422 @@
+package resilientblobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	fl "github.com/nyaxt/otaru/flags"
+	oprometheus "github.com/nyaxt/otaru/prometheus"
+	"github.com/nyaxt/otaru/util"
+)
+
+const promSubsystem = "resilientblobstore"
+
+var (
+	retryCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "retry",
+		Help:      "Number of backend blobstore operations retried.",
+	}, []string{"backend", "op"})
+	timeoutCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "timeout",
+		Help:      "Number of backend blobstore operations timed out.",
+	}, []string{"backend", "op"})
+	abandonedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "abandoned",
+		Help:      "Number of timed out backend blobstore operations still running.",
+	}, []string{"backend"})
+	rejectedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "rejected",
+		Help:      "Number of backend blobstore operations rejected by the open circuit breaker.",
+	}, []string{"backend", "op"})
+	breakerStateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "breaker_state",
+		Help:      "Circuit breaker state of the backend blobstore. 0: closed, 1: open, 2: half open.",
+	}, []string{"backend"})
+)
+
+type Config struct {
+	// Number of retries after the first attempt failed.
+	MaxRetries int
+	// Backoff before the first retry. Doubled on each retry up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout of a single attempt of opening a stream, or of a ListBlobs,
+	// BlobSize or RemoveBlob call. Reads, writes and Close on an opened stream
+	// take time in proportion to the bytes transferred, and are not timed
+	// out. No timeout if 0.
+	Timeout time.Duration
+	// Number of consecutive failures to open the circuit breaker. Never opens if 0.
+	BreakerThreshold int
+	// Duration the circuit breaker stays open before letting a probe operation through.
+	BreakerCooldown time.Duration
+	// Decides if a failed operation should be retried, and counted as a
+	// backend failure. If nil, all errors except util.Error (e.g. ENOENT) are retried.
+	IsRetryable func(error) bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxRetries:       3,
+		InitialBackoff:   100 * time.Millisecond,
+		MaxBackoff:       10 * time.Second,
+		Timeout:          60 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+func isRetryableDefault(err error) bool {
+	_, ok := err.(util.Error)
+	return !ok
+}
+
+// ResilientBlobStore wraps a backend blobstore to retry failed operations
+// with exponential backoff, time out stuck operations, and stop calling the
+// backend while it keeps failing.
+//
+// Opening readers/writers, listing, sizing and removing blobs are retried,
+// and time out. Reads, writes and Close on an opened stream are subject to
+// the circuit breaker only.
+type ResilientBlobStore struct {
+	be      blobstore.BlobStore
+	cfg     Config
+	name    string
+	breaker *Breaker
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var _ = blobstore.BlobStore(&ResilientBlobStore{})
+
+func New(be blobstore.BlobStore, cfg Config) *ResilientBlobStore {
+	if cfg.IsRetryable == nil {
+		cfg.IsRetryable = isRetryableDefault
+	}
+
+	name := util.TryGetImplName(be)
+	gauge := breakerStateGauge.WithLabelValues(name)
+	gauge.Set(float64(BreakerClosed))
+	onStateChange := func(s BreakerState) {
+		gauge.Set(float64(s))
+		if s == BreakerOpen {
+			zap.S().Warnf("Circuit breaker for backend blobstore \"%s\" opened.", name)
+		} else {
+			zap.S().Infof("Circuit breaker for backend blobstore \"%s\" is now %v.", name, s)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ResilientBlobStore{
+		be:      be,
+		cfg:     cfg,
+		name:    name,
+		breaker: NewBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown, onStateChange),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Quit interrupts the retry backoffs in progress, so that the shutdown isn't
+// blocked by a failing backend. Failed operations are no longer retried after Quit.
+func (bs *ResilientBlobStore) Quit() { bs.cancel() }
+
+func (bs *ResilientBlobStore) Backend() blobstore.BlobStore { return bs.be }
+func (bs *ResilientBlobStore) Config() Config               { return bs.cfg }
+
+func (bs *ResilientBlobStore) BreakerStatus() BreakerStatus { return bs.breaker.Status() }
+
+type result struct {
+	v   interface{}
+	err error
+}
+
+// callWithTimeout runs f, giving up after cfg.Timeout. The backend calls
+// can't be cancelled, so f keeps running in its goroutine until the backend
+// returns, counted by the "abandoned" gauge. If f returns an io.Closer after
+// timing out, it is closed.
+func (bs *ResilientBlobStore) callWithTimeout(op string, f func() (interface{}, error)) (interface{}, error) {
+	if bs.cfg.Timeout <= 0 {
+		return f()
+	}
+
+	resC := make(chan result, 1)
+	go func() {
+		v, err := f()
+		resC <- result{v, err}
+	}()
+
+	t := time.NewTimer(bs.cfg.Timeout)
+	defer t.Stop()
+	select {
+	case res := <-resC:
+		return res.v, res.err
+	case <-t.C:
+		timeoutCounter.WithLabelValues(bs.name, op).Inc()
+		abandoned := abandonedGauge.WithLabelValues(bs.name)
+		abandoned.Inc()
+		go func() {
+			res := <-resC
+			abandoned.Dec()
+			if c, ok := res.v.(io.Closer); ok && res.err == nil {
+				if err := c.Close(); err != nil {
+					zap.S().Warnf("Failed to close result of timed out %s: %v", op, err)
+				}
+			}
+		}()
+		return nil, ErrTimeout
+	}
+}
+
+// callOnce makes a single attempt of an operation, guarded by the circuit
+// breaker. The attempt times out if timeout is set.
+func (bs *ResilientBlobStore) callOnce(op, blobpath string, timeout bool, f func() (interface{}, error)) (interface{}, error) {
+	if err := bs.breaker.Allow(); err != nil {
+		rejectedCounter.WithLabelValues(bs.name, op).Inc()
+		return nil, &Error{Op: op, BlobPath: blobpath, Err: err}
+	}
+
+	var v interface{}
+	var err error
+	if timeout {
+		v, err = bs.callWithTimeout(op, f)
+	} else {
+		v, err = f()
+	}
+	if err != nil && (err == ErrTimeout || bs.cfg.IsRetryable(err)) {
+		bs.breaker.Failure()
+		return v, err
+	}
+	// Non-retryable errors such as ENOENT are valid responses from a healthy backend.
+	bs.breaker.Success()
+	return v, err
+}
+
+func (bs *ResilientBlobStore) call(op, blobpath string, f func() (interface{}, error)) (interface{}, error) {
+	backoff := bs.cfg.InitialBackoff
+	for i := 0; ; i++ {
+		v, err := bs.callOnce(op, blobpath, true, f)
+		if err == nil {
+			return v, nil
+		}
+		if _, ok := err.(*Error); ok {
+			return nil, err
+		}
+		if err != ErrTimeout && !bs.cfg.IsRetryable(err) {
+			return nil, err
+		}
+		if i >= bs.cfg.MaxRetries {
+			return nil, &Error{Op: op, BlobPath: blobpath, Err: err}
+		}
+
+		zap.S().Infof("Backend blobstore \"%s\" %s \"%s\" failed: %v. Retrying %d / %d in %v...", bs.name, op, blobpath, err, i+1, bs.cfg.MaxRetries, backoff)
+		retryCounter.WithLabelValues(bs.name, op).Inc()
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-bs.ctx.Done():
+			t.Stop()
+			return nil, &Error{Op: op, BlobPath: blobpath, Err: err}
+		}
+		backoff *= 2
+		if backoff > bs.cfg.MaxBackoff {
+			backoff = bs.cfg.MaxBackoff
+		}
+	}
+}
+
+// streamErr converts an error on an opened stream. Stream operations are not retried.
+func (bs *ResilientBlobStore) streamErr(op, blobpath string, err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+	if err != ErrTimeout && !bs.cfg.IsRetryable(err) {
+		return err
+	}
+	return &Error{Op: op, BlobPath: blobpath, Err: err}
+}
+
+type reader struct {
+	bs       *ResilientBlobStore
+	blobpath string
+	r        io.ReadCloser
+	err      error
+}
+
+type readResult struct {
+	n   int
+	eof bool
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	v, err := r.bs.callOnce("Read", r.blobpath, false, func() (interface{}, error) {
+		n, err := r.r.Read(p)
+		if err == io.EOF {
+			// Don't count EOF as a failure.
+			return readResult{n, true}, nil
+		}
+		return readResult{n, false}, err
+	})
+	if err != nil {
+		r.err = r.bs.streamErr("Read", r.blobpath, err)
+		return 0, r.err
+	}
+	res := v.(readResult)
+	if res.eof {
+		r.err = io.EOF
+		return res.n, io.EOF
+	}
+	return res.n, nil
+}
+
+func (r *reader) Close() error {
+	return r.r.Close()
+}
+
+func (bs *ResilientBlobStore) openReader(blobpath string, open func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	v, err := bs.call("OpenReader", blobpath, func() (interface{}, error) {
+		return open()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reader{bs: bs, blobpath: blobpath, r: v.(io.ReadCloser)}, nil
+}
+
+func (bs *ResilientBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	return bs.openReader(blobpath, func() (io.ReadCloser, error) {
+		return bs.be.OpenReader(blobpath)
+	})
+}
+
+var _ = blobstore.NoRecallReader(&ResilientBlobStore{})
+
+func (bs *ResilientBlobStore) OpenReaderNoRecall(blobpath string) (io.ReadCloser, error) {
+	return bs.openReader(blobpath, func() (io.ReadCloser, error) {
+		return blobstore.OpenReaderNoRecall(bs.be, blobpath)
+	})
+}
+
+type writer struct {
+	bs       *ResilientBlobStore
+	blobpath string
+	w        io.WriteCloser
+	err      error
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	v, err := w.bs.callOnce("Write", w.blobpath, false, func() (interface{}, error) {
+		return w.w.Write(p)
+	})
+	if err != nil {
+		w.err = w.bs.streamErr("Write", w.blobpath, err)
+		return 0, w.err
+	}
+	return v.(int), nil
+}
+
+func (w *writer) Close() error {
+	_, err := w.bs.callOnce("Close", w.blobpath, false, func() (interface{}, error) {
+		return nil, w.w.Close()
+	})
+	if w.err != nil {
+		return w.err
+	}
+	return w.bs.streamErr("Close", w.blobpath, err)
+}
+
+func (bs *ResilientBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	v, err := bs.call("OpenWriter", blobpath, func() (interface{}, error) {
+		return bs.be.OpenWriter(blobpath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &writer{bs: bs, blobpath: blobpath, w: v.(io.WriteCloser)}, nil
+}
+
+func (bs *ResilientBlobStore) Flags() int {
+	if fr, ok := bs.be.(fl.FlagsReader); ok {
+		return fr.Flags()
+	}
+	return fl.O_RDWRCREATE
+}
+
+var _ = blobstore.BlobLister(&ResilientBlobStore{})
+
+func (bs *ResilientBlobStore) ListBlobs() ([]string, error) {
+	lister, ok := bs.be.(blobstore.BlobLister)
+	if !ok {
+		return nil, fmt.Errorf("Backend blobstore \"%s\" don't support ListBlobs()", bs.name)
+	}
+	v, err := bs.call("ListBlobs", "", func() (interface{}, error) {
+		return lister.ListBlobs()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+var _ = blobstore.BlobSizer(&ResilientBlobStore{})
+
+func (bs *ResilientBlobStore) BlobSize(blobpath string) (int64, error) {
+	sizer, ok := bs.be.(blobstore.BlobSizer)
+	if !ok {
+		return -1, fmt.Errorf("Backend blobstore \"%s\" don't support BlobSize()", bs.name)
+	}
+	v, err := bs.call("BlobSize", blobpath, func() (interface{}, error) {
+		return sizer.BlobSize(blobpath)
+	})
+	if err != nil {
+		return -1, err
+	}
+	return v.(int64), nil
+}
+
+var _ = blobstore.BlobRemover(&ResilientBlobStore{})
+
+func (bs *ResilientBlobStore) RemoveBlob(blobpath string) error {
+	remover, ok := bs.be.(blobstore.BlobRemover)
+	if !ok {
+		return fmt.Errorf("Backend blobstore \"%s\" don't support RemoveBlob()", bs.name)
+	}
+	_, err := bs.call("RemoveBlob", blobpath, func() (interface{}, error) {
+		return nil, remover.RemoveBlob(blobpath)
+	})
+	return err
+}
+
+var _ = util.ImplNamed(&ResilientBlobStore{})
+
+func (bs *ResilientBlobStore) ImplName() string {
+	return fmt.Sprintf("ResilientBlobStore{%s}", bs.name)
+}
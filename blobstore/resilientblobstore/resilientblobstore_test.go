@@ -0,0 +1,254 @@
+package resilientblobstore_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/resilientblobstore"
+	tu "github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/util"
+)
+
+func init() { tu.EnsureLogger() }
+
+var errFlaky = errors.New("flaky backend failure")
+
+// flakyBlobStore fails OpenReader "failures" times before succeeding, and
+// blocks OpenReader while "stall" is set.
+type flakyBlobStore struct {
+	blobstore.BlobStore
+
+	mu       sync.Mutex
+	failures int
+	calls    int
+	stall    time.Duration
+}
+
+func (bs *flakyBlobStore) setFailures(n int) {
+	bs.mu.Lock()
+	bs.failures = n
+	bs.mu.Unlock()
+}
+
+func (bs *flakyBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	bs.mu.Lock()
+	bs.calls++
+	stall := bs.stall
+	fail := bs.failures > 0
+	if fail {
+		bs.failures--
+	}
+	bs.mu.Unlock()
+
+	time.Sleep(stall)
+	if fail {
+		return nil, errFlaky
+	}
+	return bs.BlobStore.OpenReader(blobpath)
+}
+
+func testConfig() resilientblobstore.Config {
+	return resilientblobstore.Config{
+		MaxRetries:       2,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       time.Millisecond,
+		BreakerThreshold: 3,
+		BreakerCooldown:  50 * time.Millisecond,
+	}
+}
+
+func writeBlob(bs blobstore.BlobStore, blobpath string, p []byte) error {
+	w, err := bs.OpenWriter(blobpath)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(p); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func readAll(bs blobstore.BlobStore, blobpath string) ([]byte, error) {
+	r, err := bs.OpenReader(blobpath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func TestResilientBlobStore_Retry(t *testing.T) {
+	be := &flakyBlobStore{BlobStore: tu.TestFileBlobStore()}
+	bs := resilientblobstore.New(be, testConfig())
+
+	if err := writeBlob(bs, "foo", tu.HelloWorld); err != nil {
+		t.Fatalf("WriteBlob failed: %v", err)
+	}
+
+	be.setFailures(2)
+	b, err := readAll(bs, "foo")
+	if err != nil {
+		t.Fatalf("Read failed despite retries: %v", err)
+	}
+	if string(b) != string(tu.HelloWorld) {
+		t.Errorf("Unexpected content: %q", b)
+	}
+	if be.calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", be.calls)
+	}
+
+	be.calls = 0
+	if _, err := bs.OpenReader("nonexistent"); !util.IsNotExist(err) {
+		t.Errorf("Expected ENOENT, got %v", err)
+	}
+	if be.calls != 1 {
+		t.Errorf("ENOENT shouldn't be retried. calls: %d", be.calls)
+	}
+
+	be.setFailures(3)
+	_, err = bs.OpenReader("foo")
+	var rerr *resilientblobstore.Error
+	if !errors.As(err, &rerr) || !errors.Is(err, errFlaky) {
+		t.Fatalf("Expected resilientblobstore.Error wrapping errFlaky, got %v", err)
+	}
+	if rerr.Temporary() || rerr.Errno() != util.EIO.Errno() {
+		t.Errorf("Retries exhausted error should map to EIO.")
+	}
+}
+
+func TestResilientBlobStore_Breaker(t *testing.T) {
+	be := &flakyBlobStore{BlobStore: tu.TestFileBlobStore()}
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+	bs := resilientblobstore.New(be, cfg)
+
+	if err := writeBlob(bs, "foo", tu.HelloWorld); err != nil {
+		t.Fatalf("WriteBlob failed: %v", err)
+	}
+
+	be.setFailures(3)
+	for i := 0; i < 3; i++ {
+		if _, err := bs.OpenReader("foo"); !errors.Is(err, errFlaky) {
+			t.Fatalf("Expected errFlaky, got %v", err)
+		}
+	}
+	if st := bs.BreakerStatus(); st.State != resilientblobstore.BreakerOpen || st.ConsecutiveFailures != 3 {
+		t.Fatalf("Expected breaker open after 3 failures: %+v", st)
+	}
+
+	be.calls = 0
+	_, err := bs.OpenReader("foo")
+	var rerr *resilientblobstore.Error
+	if !errors.As(err, &rerr) || !errors.Is(err, resilientblobstore.ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if !rerr.Temporary() || rerr.Errno() != util.EAGAIN.Errno() {
+		t.Errorf("Open breaker error should map to EAGAIN.")
+	}
+	if be.calls != 0 {
+		t.Errorf("Backend shouldn't be called while breaker is open. calls: %d", be.calls)
+	}
+
+	time.Sleep(cfg.BreakerCooldown)
+	if _, err := readAll(bs, "foo"); err != nil {
+		t.Fatalf("Probe after cooldown failed: %v", err)
+	}
+	if st := bs.BreakerStatus(); st.State != resilientblobstore.BreakerClosed {
+		t.Errorf("Expected breaker closed after successful probe: %+v", st)
+	}
+}
+
+func TestResilientBlobStore_Timeout(t *testing.T) {
+	be := &flakyBlobStore{BlobStore: tu.TestFileBlobStore()}
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+	cfg.Timeout = 10 * time.Millisecond
+	bs := resilientblobstore.New(be, cfg)
+
+	if err := writeBlob(bs, "foo", tu.HelloWorld); err != nil {
+		t.Fatalf("WriteBlob failed: %v", err)
+	}
+	if _, err := readAll(bs, "foo"); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	be.stall = 100 * time.Millisecond
+	if _, err := bs.OpenReader("foo"); !errors.Is(err, resilientblobstore.ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+}
+
+type slowCloseWriter struct {
+	io.WriteCloser
+	delay time.Duration
+}
+
+func (w slowCloseWriter) Close() error {
+	time.Sleep(w.delay)
+	return w.WriteCloser.Close()
+}
+
+func TestResilientBlobStore_SlowStreamNotTimedOut(t *testing.T) {
+	// A Close uploading the buffered content takes longer than the timeout.
+	be := tu.RWInterceptBlobStore{
+		BE: tu.TestFileBlobStore(),
+		WrapWriter: func(orig io.WriteCloser) (io.WriteCloser, error) {
+			return slowCloseWriter{orig, 50 * time.Millisecond}, nil
+		},
+		WrapReader: func(orig io.ReadCloser) (io.ReadCloser, error) { return orig, nil },
+	}
+	cfg := testConfig()
+	cfg.Timeout = 10 * time.Millisecond
+	bs := resilientblobstore.New(be, cfg)
+
+	if err := writeBlob(bs, "foo", tu.HelloWorld); err != nil {
+		t.Fatalf("WriteBlob failed: %v", err)
+	}
+	p, err := readAll(bs, "foo")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !bytes.Equal(p, tu.HelloWorld) {
+		t.Errorf("Unexpected content: %q", p)
+	}
+	if st := bs.BreakerStatus(); st.ConsecutiveFailures != 0 {
+		t.Errorf("Slow stream counted as a failure: %+v", st)
+	}
+}
+
+func TestResilientBlobStore_QuitInterruptsBackoff(t *testing.T) {
+	be := &flakyBlobStore{BlobStore: tu.TestFileBlobStore()}
+	cfg := testConfig()
+	cfg.InitialBackoff = time.Hour
+	cfg.MaxBackoff = time.Hour
+	bs := resilientblobstore.New(be, cfg)
+
+	if err := writeBlob(bs, "foo", tu.HelloWorld); err != nil {
+		t.Fatalf("WriteBlob failed: %v", err)
+	}
+
+	be.setFailures(1)
+	errC := make(chan error, 1)
+	go func() {
+		_, err := bs.OpenReader("foo")
+		errC <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	bs.Quit()
+
+	select {
+	case err := <-errC:
+		if !errors.Is(err, errFlaky) {
+			t.Errorf("Expected errFlaky, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Quit didn't interrupt the retry backoff.")
+	}
+}
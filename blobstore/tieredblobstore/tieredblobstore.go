@@ -0,0 +1,548 @@
+package tieredblobstore
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/btncrypt"
+	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/metadata"
+	"github.com/nyaxt/otaru/metadata/statesnapshot"
+	oprometheus "github.com/nyaxt/otaru/prometheus"
+	"github.com/nyaxt/otaru/util"
+)
+
+const promSubsystem = "tieredblobstore"
+
+var (
+	movedToColdCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "moved_to_cold",
+		Help:      "Number of blobs moved to the cold tier.",
+	})
+	recallCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "recall",
+		Help:      "Number of reads of blobs in the cold tier.",
+	})
+	recallLatencyHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "recall_latency_seconds",
+		Help:      "Extra latency to read a blob in the cold tier, including the copy back to the hot tier.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14),
+	})
+	numColdBlobsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "num_cold_blobs",
+		Help:      "Number of blobs in the cold tier.",
+	})
+)
+
+type Tier int
+
+const (
+	HotTier Tier = iota
+	ColdTier
+)
+
+func (t Tier) String() string {
+	switch t {
+	case HotTier:
+		return "hot"
+	case ColdTier:
+		return "cold"
+	default:
+		return fmt.Sprintf("Tier(%d)", int(t))
+	}
+}
+
+// TieredBlobStore stores blobs in either of the hot or the cold backend
+// blobstore. Blobs are routed by a blobpath->tier map persisted to the hot
+// tier. Blobs not in the map are in the hot tier.
+// Reading a blob in the cold tier recalls the blob to the hot tier, unless
+// the blobstore is read only.
+type TieredBlobStore struct {
+	hot   blobstore.BlobStore
+	cold  blobstore.BlobStore
+	c     *btncrypt.Cipher
+	flags int
+
+	mu    sync.Mutex
+	tiers map[string]Tier
+	// writers counts the open writers of each blob in the hot tier.
+	writers map[string]int
+	// moving holds the blobs being moved to the cold tier. An entry turns
+	// false once the blob is written, which aborts its move.
+	moving map[string]bool
+
+	mux blobstore.Mux
+}
+
+var _ = blobstore.BlobStore(&TieredBlobStore{})
+
+func New(hot, cold blobstore.BlobStore, c *btncrypt.Cipher, flags int) (*TieredBlobStore, error) {
+	tbs := &TieredBlobStore{
+		hot:     hot,
+		cold:    cold,
+		c:       c,
+		flags:   flags,
+		tiers:   make(map[string]Tier),
+		writers: make(map[string]int),
+		moving:  make(map[string]bool),
+	}
+	tbs.mux = blobstore.Mux{
+		blobstore.MuxEntry{tbs.isCold, cold},
+		blobstore.MuxEntry{nil, hot},
+	}
+	if err := tbs.restoreTierMap(); err != nil {
+		return nil, err
+	}
+	return tbs, nil
+}
+
+func (tbs *TieredBlobStore) restoreTierMap() error {
+	r, err := tbs.hot.OpenReader(metadata.TierMapBlobpath)
+	if err != nil {
+		if util.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("Failed to open tier map: %v", err)
+	}
+	defer r.Close()
+
+	tiers := make(map[string]Tier)
+	if err := statesnapshot.Restore(r, tbs.c, func(dec *gob.Decoder) error {
+		if err := dec.Decode(&tiers); err != nil {
+			return fmt.Errorf("Failed to decode tier map: %v", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	tbs.mu.Lock()
+	tbs.tiers = tiers
+	tbs.mu.Unlock()
+	numColdBlobsGauge.Set(float64(len(tiers)))
+	zap.S().Infof("Restored tier map. %d blobs in the cold tier.", len(tiers))
+	return nil
+}
+
+// saveTierMapWithLock persists the tier map. tbs.mu must be held.
+func (tbs *TieredBlobStore) saveTierMapWithLock() error {
+	w, err := tbs.hot.OpenWriter(metadata.TierMapBlobpath)
+	if err != nil {
+		return fmt.Errorf("Failed to open tier map for writing: %v", err)
+	}
+	if err := statesnapshot.Save(w, tbs.c, func(enc *gob.Encoder) error {
+		return enc.Encode(tbs.tiers)
+	}); err != nil {
+		w.Close()
+		return fmt.Errorf("Failed to save tier map: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("Failed to close tier map: %v", err)
+	}
+	numColdBlobsGauge.Set(float64(len(tbs.tiers)))
+	return nil
+}
+
+func (tbs *TieredBlobStore) setTier(blobpath string, t Tier) error {
+	tbs.mu.Lock()
+	defer tbs.mu.Unlock()
+
+	if tbs.tiers[blobpath] == t {
+		return nil
+	}
+	if t == HotTier {
+		delete(tbs.tiers, blobpath)
+	} else {
+		tbs.tiers[blobpath] = t
+	}
+	return tbs.saveTierMapWithLock()
+}
+
+// beginWrite registers a writer of the blob in the hot tier, and returns
+// the tier of the blob before the write.
+func (tbs *TieredBlobStore) beginWrite(blobpath string) Tier {
+	tbs.mu.Lock()
+	defer tbs.mu.Unlock()
+
+	tbs.writers[blobpath]++
+	if _, ok := tbs.moving[blobpath]; ok {
+		tbs.moving[blobpath] = false
+	}
+	return tbs.tiers[blobpath]
+}
+
+func (tbs *TieredBlobStore) endWrite(blobpath string) {
+	tbs.mu.Lock()
+	defer tbs.mu.Unlock()
+
+	if tbs.writers[blobpath]--; tbs.writers[blobpath] <= 0 {
+		delete(tbs.writers, blobpath)
+	}
+}
+
+func (tbs *TieredBlobStore) TierOf(blobpath string) Tier {
+	tbs.mu.Lock()
+	defer tbs.mu.Unlock()
+	return tbs.tiers[blobpath]
+}
+
+func (tbs *TieredBlobStore) isCold(blobpath string) bool {
+	return tbs.TierOf(blobpath) == ColdTier
+}
+
+func (tbs *TieredBlobStore) backendOf(t Tier) blobstore.BlobStore {
+	if t == ColdTier {
+		return tbs.cold
+	}
+	return tbs.hot
+}
+
+func otherTier(t Tier) Tier {
+	if t == ColdTier {
+		return HotTier
+	}
+	return ColdTier
+}
+
+// openReaderIn opens the blob in tier t. As the tier map may be outdated,
+// e.g. on a read only instance, it falls back to the other tier if the blob
+// doesn't exist in t.
+func (tbs *TieredBlobStore) openReaderIn(blobpath string, t Tier) (io.ReadCloser, Tier, error) {
+	r, err := tbs.backendOf(t).OpenReader(blobpath)
+	if err == nil || !util.IsNotExist(err) {
+		return r, t, err
+	}
+	o := otherTier(t)
+	r, err = tbs.backendOf(o).OpenReader(blobpath)
+	return r, o, err
+}
+
+func (tbs *TieredBlobStore) copyBlob(blobpath string, src io.Reader, dst blobstore.BlobStore) error {
+	w, err := dst.OpenWriter(blobpath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// recall copies the blob in the cold tier back to the hot tier.
+func (tbs *TieredBlobStore) recall(blobpath string) error {
+	tbs.beginWrite(blobpath)
+	defer tbs.endWrite(blobpath)
+
+	r, err := tbs.cold.OpenReader(blobpath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := tbs.copyBlob(blobpath, r, tbs.hot); err != nil {
+		return fmt.Errorf("Failed to copy blob to the hot tier: %v", err)
+	}
+	if err := tbs.setTier(blobpath, HotTier); err != nil {
+		return err
+	}
+	if remover, ok := tbs.cold.(blobstore.BlobRemover); ok {
+		if err := remover.RemoveBlob(blobpath); err != nil {
+			zap.S().Warnf("Failed to remove recalled blob \"%s\" from the cold tier: %v", blobpath, err)
+		}
+	}
+	return nil
+}
+
+func (tbs *TieredBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	t := tbs.TierOf(blobpath)
+	if t == HotTier {
+		r, _, err := tbs.openReaderIn(blobpath, t)
+		return r, err
+	}
+
+	start := time.Now()
+	defer func() {
+		d := time.Since(start)
+		recallCounter.Inc()
+		recallLatencyHistogram.Observe(d.Seconds())
+		zap.S().Infof("Read of blob \"%s\" in the cold tier took extra %v.", blobpath, d)
+	}()
+
+	if fl.IsWriteAllowed(tbs.flags) {
+		if err := tbs.recall(blobpath); err != nil {
+			if !util.IsNotExist(err) {
+				return nil, fmt.Errorf("Failed to recall blob \"%s\": %v", blobpath, err)
+			}
+			zap.S().Warnf("Blob \"%s\" in the tier map was not found in the cold tier.", blobpath)
+		} else {
+			t = HotTier
+		}
+	}
+	r, _, err := tbs.openReaderIn(blobpath, t)
+	return r, err
+}
+
+var _ = blobstore.NoRecallReader(&TieredBlobStore{})
+
+// OpenReaderNoRecall reads the blob from the tier it is in, without recalling
+// it to the hot tier. Used for scrub and version queries.
+func (tbs *TieredBlobStore) OpenReaderNoRecall(blobpath string) (io.ReadCloser, error) {
+	r, _, err := tbs.openReaderIn(blobpath, tbs.TierOf(blobpath))
+	return r, err
+}
+
+type writer struct {
+	io.WriteCloser
+	tbs      *TieredBlobStore
+	blobpath string
+	wasCold  bool
+}
+
+func (w *writer) Close() error {
+	defer w.tbs.endWrite(w.blobpath)
+
+	if err := w.WriteCloser.Close(); err != nil {
+		return err
+	}
+	if !w.wasCold {
+		return nil
+	}
+	// The blob was in the cold tier. The new content is in the hot tier.
+	if err := w.tbs.setTier(w.blobpath, HotTier); err != nil {
+		return err
+	}
+	if remover, ok := w.tbs.cold.(blobstore.BlobRemover); ok {
+		if err := remover.RemoveBlob(w.blobpath); err != nil && !util.IsNotExist(err) {
+			zap.S().Warnf("Failed to remove overwritten blob \"%s\" from the cold tier: %v", w.blobpath, err)
+		}
+	}
+	return nil
+}
+
+// OpenWriter always writes to the hot tier. Opening a writer aborts the move
+// of the blob to the cold tier if it is in flight.
+func (tbs *TieredBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	t := tbs.beginWrite(blobpath)
+	w, err := tbs.hot.OpenWriter(blobpath)
+	if err != nil {
+		tbs.endWrite(blobpath)
+		return nil, err
+	}
+	return &writer{w, tbs, blobpath, t == ColdTier}, nil
+}
+
+// MoveToCold moves the blob in the hot tier to the cold tier.
+func (tbs *TieredBlobStore) MoveToCold(blobpath string) error {
+	_, failed, err := tbs.MoveBlobsToCold(context.Background(), []string{blobpath})
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return failed[0].Err
+	}
+	return nil
+}
+
+// copyToCold copies the blob in the hot tier to the cold tier.
+func (tbs *TieredBlobStore) copyToCold(blobpath string) error {
+	if metadata.IsMetadataBlobpath(blobpath) {
+		return fmt.Errorf("Metadata blob \"%s\" can't be moved to the cold tier.", blobpath)
+	}
+
+	r, err := tbs.hot.OpenReader(blobpath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if err := tbs.copyBlob(blobpath, r, tbs.cold); err != nil {
+		return fmt.Errorf("Failed to copy blob to the cold tier: %v", err)
+	}
+	return nil
+}
+
+// MoveError records a blob which failed to be moved to the cold tier.
+type MoveError struct {
+	BlobPath string
+	Err      error
+}
+
+var ErrWrittenDuringMove = errors.New("Blob was written while being moved to the cold tier.")
+
+// startMove registers the blob as being moved to the cold tier. It returns
+// false if the blob is already cold or being moved.
+func (tbs *TieredBlobStore) startMove(blobpath string) (bool, error) {
+	tbs.mu.Lock()
+	defer tbs.mu.Unlock()
+
+	if tbs.tiers[blobpath] == ColdTier {
+		return false, nil
+	}
+	if _, ok := tbs.moving[blobpath]; ok {
+		return false, nil
+	}
+	if tbs.writers[blobpath] > 0 {
+		return false, ErrWrittenDuringMove
+	}
+	tbs.moving[blobpath] = true
+	return true, nil
+}
+
+// removeCold removes the copy of the blob in the cold tier left by an
+// aborted move.
+func (tbs *TieredBlobStore) removeCold(blobpath string) {
+	remover, ok := tbs.cold.(blobstore.BlobRemover)
+	if !ok {
+		return
+	}
+	if err := remover.RemoveBlob(blobpath); err != nil && !util.IsNotExist(err) {
+		zap.S().Warnf("Failed to remove the cold tier copy of blob \"%s\" of an aborted move: %v", blobpath, err)
+	}
+}
+
+// MoveBlobsToCold moves the blobs in the hot tier to the cold tier, saving
+// the tier map once for all the blobs. The blobs are removed from the hot
+// tier only after the tier map is saved, so that the saved map never points
+// a blob to the tier it is missing from.
+// A blob written while its move is in flight stays in the hot tier, and is
+// reported as failed with ErrWrittenDuringMove.
+func (tbs *TieredBlobStore) MoveBlobsToCold(ctx context.Context, bps []string) (moved []string, failed []MoveError, err error) {
+	if !fl.IsWriteAllowed(tbs.flags) {
+		return nil, nil, util.EACCES
+	}
+
+	copied := make([]string, 0, len(bps))
+	for _, bp := range bps {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		ok, serr := tbs.startMove(bp)
+		if serr != nil {
+			failed = append(failed, MoveError{bp, serr})
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if cerr := tbs.copyToCold(bp); cerr != nil {
+			tbs.mu.Lock()
+			delete(tbs.moving, bp)
+			tbs.mu.Unlock()
+			failed = append(failed, MoveError{bp, cerr})
+			continue
+		}
+		copied = append(copied, bp)
+	}
+	if len(copied) == 0 {
+		return nil, failed, err
+	}
+
+	var aborted []string
+	tbs.mu.Lock()
+	for _, bp := range copied {
+		if !tbs.moving[bp] {
+			delete(tbs.moving, bp)
+			aborted = append(aborted, bp)
+			continue
+		}
+		tbs.tiers[bp] = ColdTier
+		moved = append(moved, bp)
+	}
+	if len(moved) > 0 {
+		if serr := tbs.saveTierMapWithLock(); serr != nil {
+			for _, bp := range moved {
+				delete(tbs.tiers, bp)
+				delete(tbs.moving, bp)
+			}
+			tbs.mu.Unlock()
+			return nil, failed, serr
+		}
+	}
+	tbs.mu.Unlock()
+
+	for _, bp := range aborted {
+		tbs.removeCold(bp)
+		failed = append(failed, MoveError{bp, ErrWrittenDuringMove})
+	}
+
+	remover, ok := tbs.hot.(blobstore.BlobRemover)
+	for _, bp := range moved {
+		movedToColdCounter.Inc()
+
+		// Remove the hot copy with tbs.mu held, so that no writer can open
+		// the blob in between the check and the removal.
+		tbs.mu.Lock()
+		if ok && tbs.moving[bp] && tbs.writers[bp] == 0 && tbs.tiers[bp] == ColdTier {
+			if rerr := remover.RemoveBlob(bp); rerr != nil {
+				zap.S().Warnf("Failed to remove blob \"%s\" moved to the cold tier from the hot tier: %v", bp, rerr)
+			}
+		}
+		delete(tbs.moving, bp)
+		tbs.mu.Unlock()
+	}
+	return moved, failed, err
+}
+
+var _ = fl.FlagsReader(&TieredBlobStore{})
+
+func (tbs *TieredBlobStore) Flags() int {
+	return fl.Mask(tbs.flags, tbs.mux.Flags())
+}
+
+var _ = blobstore.BlobLister(&TieredBlobStore{})
+
+func (tbs *TieredBlobStore) ListBlobs() ([]string, error) {
+	bps, err := tbs.mux.ListBlobs()
+	if err != nil {
+		return nil, err
+	}
+
+	// A blob may exist in both tiers while it is being moved.
+	seen := make(map[string]struct{}, len(bps))
+	ret := make([]string, 0, len(bps))
+	for _, bp := range bps {
+		if _, ok := seen[bp]; ok {
+			continue
+		}
+		seen[bp] = struct{}{}
+		ret = append(ret, bp)
+	}
+	return ret, nil
+}
+
+var _ = blobstore.BlobSizer(&TieredBlobStore{})
+
+func (tbs *TieredBlobStore) BlobSize(blobpath string) (int64, error) {
+	return tbs.mux.BlobSize(blobpath)
+}
+
+var _ = blobstore.BlobRemover(&TieredBlobStore{})
+
+func (tbs *TieredBlobStore) RemoveBlob(blobpath string) error {
+	if err := tbs.mux.RemoveBlob(blobpath); err != nil {
+		return err
+	}
+	return tbs.setTier(blobpath, HotTier)
+}
+
+var _ = util.ImplNamed(&TieredBlobStore{})
+
+func (*TieredBlobStore) ImplName() string { return "TieredBlobStore" }
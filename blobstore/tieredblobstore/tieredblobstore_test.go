@@ -0,0 +1,360 @@
+package tieredblobstore_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/blobstore/tieredblobstore"
+	"github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/metadata"
+	tu "github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/util"
+)
+
+func init() { tu.EnsureLogger() }
+
+func TestTieredBlobStore_MoveToColdRecall(t *testing.T) {
+	hot := tu.TestFileBlobStoreOfName("hot")
+	cold := tu.TestFileBlobStoreOfName("cold")
+
+	tbs, err := tieredblobstore.New(hot, cold, tu.TestCipher(), flags.O_RDWRCREATE)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := tu.WriteVersionedBlob(tbs, "a", 1); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := tu.WriteVersionedBlob(tbs, "b", 2); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := tbs.MoveToCold("a"); err != nil {
+		t.Fatalf("MoveToCold failed: %v", err)
+	}
+	if tier := tbs.TierOf("a"); tier != tieredblobstore.ColdTier {
+		t.Errorf("Unexpected tier: %v", tier)
+	}
+	if _, err := hot.BlobSize("a"); !util.IsNotExist(err) {
+		t.Errorf("Blob moved to the cold tier still exists in the hot tier. err: %v", err)
+	}
+	if err := tu.AssertBlobVersion(cold, "a", 1); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	bps, err := tbs.ListBlobs()
+	if err != nil {
+		t.Fatalf("ListBlobs failed: %v", err)
+	}
+	sort.Strings(bps)
+	if !reflect.DeepEqual(bps, []string{"META_TIER_MAP", "a", "b"}) {
+		t.Errorf("Unexpected ListBlobs: %v", bps)
+	}
+
+	// The tier map is persisted. Read only instances read from the cold tier directly.
+	rotbs, err := tieredblobstore.New(hot, cold, tu.TestCipher(), flags.O_RDONLY)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if tier := rotbs.TierOf("a"); tier != tieredblobstore.ColdTier {
+		t.Errorf("Unexpected tier after restore: %v", tier)
+	}
+	if err := tu.AssertBlobVersion(rotbs, "a", 1); err != nil {
+		t.Errorf("%v", err)
+	}
+	if tier := rotbs.TierOf("a"); tier != tieredblobstore.ColdTier {
+		t.Errorf("Read only instance recalled the blob.")
+	}
+
+	// Reads recall the blob to the hot tier.
+	if err := tu.AssertBlobVersion(tbs, "a", 1); err != nil {
+		t.Errorf("%v", err)
+	}
+	if tier := tbs.TierOf("a"); tier != tieredblobstore.HotTier {
+		t.Errorf("Unexpected tier after recall: %v", tier)
+	}
+	if err := tu.AssertBlobVersion(hot, "a", 1); err != nil {
+		t.Errorf("%v", err)
+	}
+	if _, err := cold.BlobSize("a"); !util.IsNotExist(err) {
+		t.Errorf("Recalled blob still exists in the cold tier. err: %v", err)
+	}
+
+	// The read only instance with the outdated tier map falls back to the hot tier.
+	if err := tu.AssertBlobVersion(rotbs, "a", 1); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestTieredBlobStore_OverwriteCold(t *testing.T) {
+	hot := tu.TestFileBlobStoreOfName("hot")
+	cold := tu.TestFileBlobStoreOfName("cold")
+
+	tbs, err := tieredblobstore.New(hot, cold, tu.TestCipher(), flags.O_RDWRCREATE)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := tu.WriteVersionedBlob(tbs, "a", 1); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := tbs.MoveToCold("a"); err != nil {
+		t.Fatalf("MoveToCold failed: %v", err)
+	}
+
+	if err := tu.WriteVersionedBlob(tbs, "a", 2); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if tier := tbs.TierOf("a"); tier != tieredblobstore.HotTier {
+		t.Errorf("Unexpected tier after overwrite: %v", tier)
+	}
+	if err := tu.AssertBlobVersion(tbs, "a", 2); err != nil {
+		t.Errorf("%v", err)
+	}
+	if _, err := cold.BlobSize("a"); !util.IsNotExist(err) {
+		t.Errorf("Overwritten blob still exists in the cold tier. err: %v", err)
+	}
+}
+
+type mockStats []cachedblobstore.UsageStat
+
+func (s mockStats) UsageStats() []cachedblobstore.UsageStat { return s }
+
+func TestTieredBlobStore_MoveColdBlobs(t *testing.T) {
+	hot := tu.TestFileBlobStoreOfName("hot")
+	cold := tu.TestFileBlobStoreOfName("cold")
+
+	tbs, err := tieredblobstore.New(hot, cold, tu.TestCipher(), flags.O_RDWRCREATE)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for _, bp := range []string{"a", "b", "unreferenced"} {
+		if err := tu.WriteVersionedBlob(tbs, bp, 1); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	db, err := inodedb.NewEmptyDB(inodedb.NewSimpleDBStateSnapshotIO(), inodedb.NewSimpleDBTransactionLogIO())
+	if err != nil {
+		t.Fatalf("Failed to NewEmptyDB: %v", err)
+	}
+	nlock, err := db.LockNode(inodedb.AllocateNewNodeID)
+	if err != nil {
+		t.Fatalf("Failed to LockNode: %v", err)
+	}
+	if _, err := db.ApplyTransaction(inodedb.DBTransaction{Ops: []inodedb.DBOperation{
+		&inodedb.CreateNodeOp{NodeLock: nlock, OrigPath: "/hoge.txt", Type: inodedb.FileNodeT},
+		&inodedb.HardLinkOp{NodeLock: inodedb.NodeLock{inodedb.RootDirID, inodedb.NoTicket}, Name: "hoge.txt", TargetID: nlock.ID},
+		&inodedb.UpdateChunksOp{NodeLock: nlock, Chunks: []inodedb.FileChunk{
+			{Offset: 0, Length: 1, BlobPath: "a"},
+			{Offset: 1, Length: 1, BlobPath: "b"},
+		}},
+		&inodedb.UpdateModifiedTOp{ID: nlock.ID, ModifiedT: time.Now().Add(-48 * time.Hour)},
+	}}); err != nil {
+		t.Fatalf("Failed to apply tx: %v", err)
+	}
+
+	stats := mockStats{{BlobPath: "b", LastUsed: time.Now()}}
+	ctx := context.Background()
+
+	report, err := tbs.MoveColdBlobs(ctx, db, stats, 24*time.Hour, true)
+	if err != nil {
+		t.Fatalf("MoveColdBlobs failed: %v", err)
+	}
+	if report.NumCandidates != 1 || report.NumMoved != 0 {
+		t.Errorf("Unexpected dryrun report: %+v", report)
+	}
+	if tier := tbs.TierOf("a"); tier != tieredblobstore.HotTier {
+		t.Errorf("Dryrun moved the blob.")
+	}
+
+	report, err = tbs.MoveColdBlobs(ctx, db, stats, 24*time.Hour, false)
+	if err != nil {
+		t.Fatalf("MoveColdBlobs failed: %v", err)
+	}
+	if report.NumCandidates != 1 || report.NumMoved != 1 || len(report.Failed) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	for bp, expected := range map[string]tieredblobstore.Tier{
+		"a":            tieredblobstore.ColdTier,
+		"b":            tieredblobstore.HotTier,
+		"unreferenced": tieredblobstore.HotTier,
+	} {
+		if tier := tbs.TierOf(bp); tier != expected {
+			t.Errorf("Unexpected tier of %q: %v", bp, tier)
+		}
+	}
+}
+
+// countingBlobStore counts the writes of the tier map.
+type countingBlobStore struct {
+	*blobstore.FileBlobStore
+	numTierMapWrites int
+}
+
+func (bs *countingBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	if blobpath == metadata.TierMapBlobpath {
+		bs.numTierMapWrites++
+	}
+	return bs.FileBlobStore.OpenWriter(blobpath)
+}
+
+func TestTieredBlobStore_MoveBlobsToCold(t *testing.T) {
+	hot := &countingBlobStore{FileBlobStore: tu.TestFileBlobStoreOfName("hot")}
+	cold := tu.TestFileBlobStoreOfName("cold")
+
+	tbs, err := tieredblobstore.New(hot, cold, tu.TestCipher(), flags.O_RDWRCREATE)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	bps := []string{"a", "b", "c"}
+	for _, bp := range bps {
+		if err := tu.WriteVersionedBlob(tbs, bp, 1); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	moved, failed, err := tbs.MoveBlobsToCold(context.Background(), append(bps, "nonexistent"))
+	if err != nil {
+		t.Fatalf("MoveBlobsToCold failed: %v", err)
+	}
+	if !reflect.DeepEqual(moved, bps) {
+		t.Errorf("Unexpected moved: %v", moved)
+	}
+	if len(failed) != 1 || failed[0].BlobPath != "nonexistent" {
+		t.Errorf("Unexpected failed: %+v", failed)
+	}
+	if hot.numTierMapWrites != 1 {
+		t.Errorf("Expected the tier map to be saved once, saved %d times.", hot.numTierMapWrites)
+	}
+	for _, bp := range bps {
+		if tier := tbs.TierOf(bp); tier != tieredblobstore.ColdTier {
+			t.Errorf("Unexpected tier of %q: %v", bp, tier)
+		}
+		if _, err := hot.BlobSize(bp); !util.IsNotExist(err) {
+			t.Errorf("Blob %q moved to the cold tier still exists in the hot tier. err: %v", bp, err)
+		}
+	}
+
+	rotbs, err := tieredblobstore.New(hot, cold, tu.TestCipher(), flags.O_RDONLY)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if tier := rotbs.TierOf("c"); tier != tieredblobstore.ColdTier {
+		t.Errorf("Unexpected tier after restore: %v", tier)
+	}
+}
+
+func TestTieredBlobStore_OpenReaderNoRecall(t *testing.T) {
+	hot := tu.TestFileBlobStoreOfName("hot")
+	cold := tu.TestFileBlobStoreOfName("cold")
+
+	tbs, err := tieredblobstore.New(hot, cold, tu.TestCipher(), flags.O_RDWRCREATE)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := tu.WriteVersionedBlob(tbs, "a", 1); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := tbs.MoveToCold("a"); err != nil {
+		t.Fatalf("MoveToCold failed: %v", err)
+	}
+
+	// Read through a Mux, as the backend blobstore is muxed with the metadata blobstore.
+	mux := blobstore.Mux{
+		blobstore.MuxEntry{metadata.IsMetadataBlobpath, tu.TestFileBlobStoreOfName("meta")},
+		blobstore.MuxEntry{nil, tbs},
+	}
+	r, err := blobstore.OpenReaderNoRecall(mux, "a")
+	if err != nil {
+		t.Fatalf("OpenReaderNoRecall failed: %v", err)
+	}
+	ver, err := tu.TestQueryVersion(r)
+	r.Close()
+	if err != nil || ver != 1 {
+		t.Errorf("Unexpected version: %d, err: %v", ver, err)
+	}
+	if tier := tbs.TierOf("a"); tier != tieredblobstore.ColdTier {
+		t.Errorf("OpenReaderNoRecall recalled the blob.")
+	}
+	if _, err := hot.BlobSize("a"); !util.IsNotExist(err) {
+		t.Errorf("OpenReaderNoRecall copied the blob to the hot tier. err: %v", err)
+	}
+}
+
+// hookedBlobStore calls onOpenReader before opening a blob for reading.
+type hookedBlobStore struct {
+	*blobstore.FileBlobStore
+	onOpenReader func(blobpath string)
+}
+
+func (bs *hookedBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	if bs.onOpenReader != nil {
+		bs.onOpenReader(blobpath)
+	}
+	return bs.FileBlobStore.OpenReader(blobpath)
+}
+
+func TestTieredBlobStore_MoveBlobsToColdWrittenMidMove(t *testing.T) {
+	hot := &hookedBlobStore{FileBlobStore: tu.TestFileBlobStoreOfName("hot")}
+	cold := tu.TestFileBlobStoreOfName("cold")
+
+	tbs, err := tieredblobstore.New(hot, cold, tu.TestCipher(), flags.O_RDWRCREATE)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for _, bp := range []string{"a", "b"} {
+		if err := tu.WriteVersionedBlob(tbs, bp, 1); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	// Overwrite "a" after it is copied to the cold tier, before the move is committed.
+	hot.onOpenReader = func(blobpath string) {
+		if blobpath != "b" {
+			return
+		}
+		hot.onOpenReader = nil
+		if err := tu.WriteVersionedBlob(tbs, "a", 2); err != nil {
+			t.Errorf("%v", err)
+		}
+	}
+
+	moved, failed, err := tbs.MoveBlobsToCold(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("MoveBlobsToCold failed: %v", err)
+	}
+	if !reflect.DeepEqual(moved, []string{"b"}) {
+		t.Errorf("Unexpected moved: %v", moved)
+	}
+	if len(failed) != 1 || failed[0].BlobPath != "a" || failed[0].Err != tieredblobstore.ErrWrittenDuringMove {
+		t.Errorf("Unexpected failed: %+v", failed)
+	}
+	if tier := tbs.TierOf("a"); tier != tieredblobstore.HotTier {
+		t.Errorf("Unexpected tier of the overwritten blob: %v", tier)
+	}
+	if err := tu.AssertBlobVersion(tbs, "a", 2); err != nil {
+		t.Errorf("%v", err)
+	}
+	if _, err := cold.BlobSize("a"); !util.IsNotExist(err) {
+		t.Errorf("Stale copy of the overwritten blob is left in the cold tier. err: %v", err)
+	}
+	if tier := tbs.TierOf("b"); tier != tieredblobstore.ColdTier {
+		t.Errorf("Unexpected tier of %q: %v", "b", tier)
+	}
+
+	// Blobs with an open writer are not moved.
+	w, err := tbs.OpenWriter("c")
+	if err != nil {
+		t.Fatalf("OpenWriter failed: %v", err)
+	}
+	if _, failed, _ := tbs.MoveBlobsToCold(context.Background(), []string{"c"}); len(failed) != 1 || failed[0].Err != tieredblobstore.ErrWrittenDuringMove {
+		t.Errorf("Unexpected failed: %+v", failed)
+	}
+	w.Close()
+}
@@ -0,0 +1,141 @@
+package tieredblobstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/util"
+)
+
+type DB interface {
+	inodedb.DBHandler
+	inodedb.DBChunkRefLister
+}
+
+type UsageStatsProvider interface {
+	UsageStats() []cachedblobstore.UsageStat
+}
+
+// FindColdBlobs returns the chunk blobs untouched since before "now - minAge".
+// A blob is considered touched when the file referencing it was modified, or
+// when it was last used in the cache.
+func FindColdBlobs(db DB, stats UsageStatsProvider, minAge time.Duration, now time.Time) ([]string, error) {
+	refs, errs := db.ListChunkRefs()
+	if len(errs) > 0 {
+		// Don't move blobs based on a partial view.
+		return nil, fmt.Errorf("Failed to list chunk refs: %v", errs)
+	}
+
+	lastTouched := make(map[string]time.Time)
+	modifiedT := make(map[inodedb.ID]time.Time)
+	for _, ref := range refs {
+		mt, ok := modifiedT[ref.NodeID]
+		if !ok {
+			v, _, err := db.QueryNode(ref.NodeID, false)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to query node %d: %v", ref.NodeID, err)
+			}
+			mt = v.GetModifiedT()
+			modifiedT[ref.NodeID] = mt
+		}
+		if t, ok := lastTouched[ref.BlobPath]; !ok || mt.After(t) {
+			lastTouched[ref.BlobPath] = mt
+		}
+	}
+	if stats != nil {
+		for _, st := range stats.UsageStats() {
+			if t, ok := lastTouched[st.BlobPath]; ok && st.LastUsed.After(t) {
+				lastTouched[st.BlobPath] = st.LastUsed
+			}
+		}
+	}
+
+	threshold := now.Add(-minAge)
+	bps := make([]string, 0)
+	for bp, t := range lastTouched {
+		if t.Before(threshold) {
+			bps = append(bps, bp)
+		}
+	}
+	sort.Strings(bps)
+	return bps, nil
+}
+
+type TierReport struct {
+	// Number of cold blobs found in the hot tier.
+	NumCandidates int
+	// Number of blobs moved to the cold tier.
+	NumMoved int
+	// Blobs which failed to be moved.
+	Failed []string
+}
+
+// MoveColdBlobs moves the chunk blobs untouched for minAge to the cold tier.
+func (tbs *TieredBlobStore) MoveColdBlobs(ctx context.Context, db DB, stats UsageStatsProvider, minAge time.Duration, dryrun bool) (*TierReport, error) {
+	start := time.Now()
+
+	bps, err := FindColdBlobs(db, stats, minAge, start)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TierReport{}
+	candidates := make([]string, 0, len(bps))
+	for _, bp := range bps {
+		if tbs.isCold(bp) {
+			continue
+		}
+		candidates = append(candidates, bp)
+		if dryrun {
+			zap.S().Infof("Dryrun found cold blob: %s", bp)
+		}
+	}
+	report.NumCandidates = len(candidates)
+
+	if !dryrun && len(candidates) > 0 {
+		moved, failed, err := tbs.MoveBlobsToCold(ctx, candidates)
+		report.NumMoved = len(moved)
+		for _, f := range failed {
+			zap.S().Errorf("Failed to move blob \"%s\" to the cold tier: %v", f.BlobPath, f.Err)
+			report.Failed = append(report.Failed, f.BlobPath)
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+
+	zap.S().Infof("Tiering done. Found %d cold blobs in the hot tier, moved %d, %d failed. Dryrun: %t. Took %v.",
+		report.NumCandidates, report.NumMoved, len(report.Failed), dryrun, time.Since(start))
+	return report, nil
+}
+
+type TierTask struct {
+	TBS    *TieredBlobStore
+	DB     DB
+	Stats  UsageStatsProvider
+	MinAge time.Duration
+	DryRun bool
+}
+
+type TierResult struct {
+	Report *TierReport
+	Error  error
+}
+
+func (r TierResult) Err() error { return r.Error }
+
+func (t *TierTask) Run(ctx context.Context) scheduler.Result {
+	report, err := t.TBS.MoveColdBlobs(ctx, t.DB, t.Stats, t.MinAge, t.DryRun)
+	return TierResult{report, err}
+}
+
+func (t *TierTask) String() string {
+	return fmt.Sprintf("tieredblobstore.TierTask{%s, minAge: %v, dryrun: %t}", util.TryGetImplName(t.TBS), t.MinAge, t.DryRun)
+}
@@ -5,3 +5,7 @@ import "io"
 // FIXME: handle overflows
 type Version int64
 type QueryFunc func(r io.Reader) (Version, error)
+
+// VerifyFunc reads through the entire blob to verify its integrity, and
+// returns its version.
+type VerifyFunc func(r io.Reader) (Version, error)
@@ -15,6 +15,11 @@ import (
 
 var ChunkSplitSize int64 = 256 * 1024 * 1024 // 256MB
 
+// Writes leaving a gap of at least MinHoleSize bytes after the existing data
+// start a new chunk, leaving the gap as a hole without any blob. Smaller gaps
+// are zero filled.
+var MinHoleSize int64 = ContentFramePayloadLength
+
 const (
 	NewChunk      = true
 	ExistingChunk = false
@@ -104,13 +109,29 @@ func (cfio *ChunkedFileIO) SetOrigFilename(name string) { cfio.origFilename = na
 func (cfio *ChunkedFileIO) newFileChunk(newo int64) (inodedb.FileChunk, error) {
 	bpath, err := blobstore.GenerateNewBlobPath(cfio.bs)
 	if err != nil {
-		return inodedb.FileChunk{}, fmt.Errorf("Failed to generate new blobpath: %v", err)
+		return inodedb.FileChunk{}, fmt.Errorf("Failed to generate new blobpath: %w", err)
 	}
 	fc := inodedb.FileChunk{Offset: newo, Length: 0, BlobPath: bpath}
 	zap.S().Debugf("new chunk %+v", fc)
 	return fc, nil
 }
 
+// newChunkRange returns the offset and the max length of a new chunk to
+// write at offset remo, placed after the data ending at prevRight.
+// The chunk starts at the ChunkSplitSize boundary if possible, unless the
+// gap to remo is large enough to be left as a hole.
+func newChunkRange(remo, prevRight int64) (int64, int64) {
+	newo := remo / ChunkSplitSize * ChunkSplitSize
+	end := newo + ChunkSplitSize
+	if newo < prevRight {
+		newo = prevRight
+	}
+	if remo-newo >= MinHoleSize {
+		newo = remo
+	}
+	return newo, end - newo
+}
+
 type ChunkLenUpdatedType bool
 
 const (
@@ -123,14 +144,14 @@ func (cfio *ChunkedFileIO) closeCachedChunkIO() error {
 
 	if cfio.cachedCio != nil {
 		if err := cfio.cachedCio.Close(); err != nil {
-			ret = multierr.Append(ret, fmt.Errorf("Failed to close previously cached cio (blobpath: \"%s\"): %v", cfio.cachedCioBlobpath, err))
+			ret = multierr.Append(ret, fmt.Errorf("Failed to close previously cached cio (blobpath: \"%s\"): %w", cfio.cachedCioBlobpath, err))
 		}
 
 		cfio.cachedCio = nil
 	}
 	if cfio.cachedBh != nil {
 		if err := cfio.cachedBh.Close(); err != nil {
-			ret = multierr.Append(ret, fmt.Errorf("Failed to close previously cached bh (blobpath: \"%s\"): %v", cfio.cachedCioBlobpath, err))
+			ret = multierr.Append(ret, fmt.Errorf("Failed to close previously cached bh (blobpath: \"%s\"): %w", cfio.cachedCioBlobpath, err))
 		}
 
 		cfio.cachedBh = nil
@@ -166,7 +187,7 @@ func (cfio *ChunkedFileIO) openChunkIO(blobpath string, isNewChunk bool, offset
 
 	bh, err := cfio.bs.Open(blobpath, f)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to open path \"%s\" (flags: %v, isNewChunk: %t): %v", blobpath, fl.FlagsToString(f), isNewChunk, err)
+		return nil, fmt.Errorf("Failed to open path \"%s\" (flags: %v, isNewChunk: %t): %w", blobpath, fl.FlagsToString(f), isNewChunk, err)
 	}
 
 	cfio.cachedBh = bh
@@ -221,22 +242,13 @@ func (cfio *ChunkedFileIO) PWrite(p []byte, offset int64) error {
 		if c.Left() > remo {
 			// Insert a new chunk @ i
 
-			// try best to align offset at ChunkSplitSize
-			newo := remo / ChunkSplitSize * ChunkSplitSize
-			maxlen := int64(ChunkSplitSize)
+			pright := int64(0)
 			if i > 0 {
-				prev := cfio.cs[i-1]
-				pright := prev.Right()
-				if newo < pright {
-					maxlen -= pright - newo
-					newo = pright
-				}
+				pright = cfio.cs[i-1].Right()
 			}
-			if i < len(cfio.cs)-1 {
-				next := cfio.cs[i+1]
-				if newo+maxlen > next.Left() {
-					maxlen = next.Left() - newo
-				}
+			newo, maxlen := newChunkRange(remo, pright)
+			if newo+maxlen > c.Left() {
+				maxlen = c.Left() - newo
 			}
 
 			newc, err := cfio.newFileChunk(newo)
@@ -267,6 +279,10 @@ func (cfio *ChunkedFileIO) PWrite(p []byte, offset int64) error {
 		if cRight < remo {
 			continue
 		}
+		if i == len(cfio.cs)-1 && remo-c.Right() >= MinHoleSize {
+			// Leave a hole after the last chunk.
+			continue
+		}
 
 		n, updated := cfio.writeToChunk(c, ExistingChunk, maxlen, remp, remo)
 		if updated == ChunkLenUpdated {
@@ -278,17 +294,11 @@ func (cfio *ChunkedFileIO) PWrite(p []byte, offset int64) error {
 
 	for len(remp) > 0 {
 		// Append a new chunk at the end
-		newo := remo / ChunkSplitSize * ChunkSplitSize
-		maxlen := int64(ChunkSplitSize)
-
+		lastRight := int64(0)
 		if len(cfio.cs) > 0 {
-			last := cfio.cs[len(cfio.cs)-1]
-			lastRight := last.Right()
-			if newo < lastRight {
-				maxlen -= lastRight - newo
-				newo = lastRight
-			}
+			lastRight = cfio.cs[len(cfio.cs)-1].Right()
 		}
+		newo, maxlen := newChunkRange(remo, lastRight)
 
 		newc, err := cfio.newFileChunk(newo)
 		if err != nil {
@@ -320,7 +330,7 @@ func (cfio *ChunkedFileIO) readFromChunk(c inodedb.FileChunk, p []byte, coff int
 	//zap.S().Debugf("cio: %p, len(p) = %d, c.Length = %d, coff = %d", cio, len(p), c.Length, coff)
 	n := util.Int64Min(int64(len(p)), c.Length-coff)
 	if err := cio.PRead(p[:n], coff); err != nil {
-		return 0, fmt.Errorf("cio PRead failed: %v", err)
+		return 0, fmt.Errorf("cio PRead failed: %w", err)
 	}
 
 	return n, nil
@@ -356,6 +366,7 @@ func (cfio *ChunkedFileIO) ReadAt(p []byte, offset int64) (int, error) {
 				remp[j] = 0
 			}
 			remo += n
+			remp = remp[n:]
 			coff = 0
 			if len(remp) == 0 {
 				return int(remo - offset), nil
@@ -364,7 +375,7 @@ func (cfio *ChunkedFileIO) ReadAt(p []byte, offset int64) (int, error) {
 
 		n, err := cfio.readFromChunk(c, remp, coff)
 		if err != nil {
-			return int(remo - offset), fmt.Errorf("readFromChunk failed. offset %d chunk offset %d len %d cfio.cs +%v. err: %v", remo, coff, n, cfio.cs, err)
+			return int(remo - offset), fmt.Errorf("readFromChunk failed. offset %d chunk offset %d len %d cfio.cs +%v. err: %w", remo, coff, n, cfio.cs, err)
 		}
 
 		remo += n
@@ -382,6 +393,116 @@ func (cfio *ChunkedFileIO) Size() int64 {
 	return cfio.cs[len(cfio.cs)-1].Right()
 }
 
+// AllocatedSize returns the total length of the chunks, excluding holes.
+func (cfio *ChunkedFileIO) AllocatedSize() int64 {
+	return AllocatedSize(cfio.cs)
+}
+
+// AllocatedSize returns the total length of the chunks cs.
+func AllocatedSize(cs []inodedb.FileChunk) int64 {
+	total := int64(0)
+	for _, c := range cs {
+		total += c.Length
+	}
+	return total
+}
+
+// SeekData returns the first offset at or after offset which is backed by a
+// chunk, or -1 if there is no data after offset.
+func (cfio *ChunkedFileIO) SeekData(offset int64) int64 {
+	for _, c := range cfio.cs {
+		if c.Right() <= offset {
+			continue
+		}
+		return util.Int64Max(offset, c.Left())
+	}
+	return -1
+}
+
+// SeekHole returns the first offset at or after offset which is not backed
+// by a chunk. Offsets past the last chunk are holes.
+func (cfio *ChunkedFileIO) SeekHole(offset int64) int64 {
+	for _, c := range cfio.cs {
+		if c.Right() <= offset {
+			continue
+		}
+		if c.Left() > offset {
+			return offset
+		}
+		// Continue to the adjacent chunk if any.
+		offset = c.Right()
+	}
+	return offset
+}
+
+func (cfio *ChunkedFileIO) zeroFillChunk(c *inodedb.FileChunk, left, right int64) error {
+	cio, err := cfio.openChunkIO(c.BlobPath, false, c.Left())
+	if err != nil {
+		return err
+	}
+
+	zeros := make([]byte, util.Int64Min(right-left, ContentFramePayloadLength))
+	for o := left; o < right; {
+		n := util.Int64Min(int64(len(zeros)), right-o)
+		if err := cio.PWrite(zeros[:n], o-c.Left()); err != nil {
+			return fmt.Errorf("Failed to zero fill chunk \"%s\" at offset %d: %w", c.BlobPath, o, err)
+		}
+		o += n
+	}
+	return nil
+}
+
+// PunchHole deallocates the range [offset, offset+length).
+// Only chunks fully inside the range are freed, and chunks ending inside the
+// range are truncated at offset. Chunks aren't split: a hole starting at or
+// inside a chunk and ending inside it is zero filled in place, which frees
+// nothing and rewrites the overlapped frames of the chunk.
+func (cfio *ChunkedFileIO) PunchHole(offset, length int64) error {
+	if !fl.IsReadWriteAllowed(cfio.bs.Flags()) {
+		return util.EACCES
+	}
+	if offset < 0 || length <= 0 {
+		return util.EINVAL
+	}
+
+	right := offset + length
+	newcs := make([]inodedb.FileChunk, 0, len(cfio.cs))
+	for _, c := range cfio.cs {
+		if c.Right() <= offset || right <= c.Left() {
+			newcs = append(newcs, c)
+			continue
+		}
+		if offset <= c.Left() && c.Right() <= right {
+			// drop the chunk
+			continue
+		}
+		if c.Left() < offset && c.Right() <= right {
+			// trim the chunk
+			cio, err := cfio.openChunkIO(c.BlobPath, false, c.Left())
+			if err != nil {
+				return err
+			}
+			if err := cio.Truncate(offset - c.Left()); err != nil {
+				return err
+			}
+			c.Length = int64(cio.Size())
+			newcs = append(newcs, c)
+			continue
+		}
+
+		if err := cfio.zeroFillChunk(&c, util.Int64Max(offset, c.Left()), util.Int64Min(right, c.Right())); err != nil {
+			return err
+		}
+		newcs = append(newcs, c)
+	}
+	cfio.cs = newcs
+
+	if err := cfio.caio.Write(cfio.cs); err != nil {
+		return fmt.Errorf("Failed to write updated cfio.cs array: %w", err)
+	}
+	return nil
+}
+
 func (cfio *ChunkedFileIO) Close() error {
 	return cfio.closeCachedChunkIO()
 }
@@ -420,7 +541,7 @@ func (cfio *ChunkedFileIO) Truncate(size int64) error {
 
 updateAndExit:
 	if err := cfio.caio.Write(cfio.cs); err != nil {
-		return fmt.Errorf("Failed to write updated cfio.cs array (empty): %v", err)
+		return fmt.Errorf("Failed to write updated cfio.cs array (empty): %w", err)
 	}
 	return nil
 }
@@ -117,3 +117,74 @@ func TestChunkedFileIO_MultiChunk(t *testing.T) {
 		fmt.Printf("? %+v\n", bh.Log[1])
 	}
 }
+
+func TestChunkedFileIO_Holes(t *testing.T) {
+	caio := chunkstore.NewSimpleDBChunksArrayIO()
+	fbs := TestFileBlobStore()
+	cfio := chunkstore.NewChunkedFileIO(fbs, TestCipher(), caio)
+
+	holeo := 4 * chunkstore.MinHoleSize
+	if err := cfio.PWrite(HelloWorld, 0); err != nil {
+		t.Fatalf("PWrite failed: %v", err)
+	}
+	if err := cfio.PWrite(HelloWorld, holeo); err != nil {
+		t.Fatalf("PWrite failed: %v", err)
+	}
+
+	if len(caio.Cs) != 2 {
+		t.Fatalf("len(caio.Cs) %d", len(caio.Cs))
+	}
+	if caio.Cs[1].Offset != holeo {
+		t.Errorf("Chunk past the hole at invalid offset: %d", caio.Cs[1].Offset)
+	}
+	if n := cfio.AllocatedSize(); n != 2*int64(len(HelloWorld)) {
+		t.Errorf("Unexpected AllocatedSize: %d", n)
+	}
+
+	readtgt := make([]byte, 32)
+	n, err := cfio.ReadAt(readtgt, holeo-16)
+	if err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	expected := append(make([]byte, 16), HelloWorld...)
+	if !bytes.Equal(readtgt[:n], expected) {
+		t.Errorf("Unexpected read across the hole: %v", readtgt[:n])
+	}
+
+	if o := cfio.SeekHole(0); o != int64(len(HelloWorld)) {
+		t.Errorf("Unexpected SeekHole(0): %d", o)
+	}
+	if o := cfio.SeekData(int64(len(HelloWorld))); o != holeo {
+		t.Errorf("Unexpected SeekData: %d", o)
+	}
+	if o := cfio.SeekData(cfio.Size()); o != -1 {
+		t.Errorf("Unexpected SeekData past the last chunk: %d", o)
+	}
+
+	// Punch the head of the first chunk, and the whole second chunk.
+	if err := cfio.PunchHole(0, 5); err != nil {
+		t.Fatalf("PunchHole failed: %v", err)
+	}
+	if err := cfio.PunchHole(holeo-1, 100); err != nil {
+		t.Fatalf("PunchHole failed: %v", err)
+	}
+	if len(caio.Cs) != 1 {
+		t.Fatalf("len(caio.Cs) after PunchHole %d", len(caio.Cs))
+	}
+	n, err = cfio.ReadAt(readtgt, 0)
+	if err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	expected = append(make([]byte, 5), HelloWorld[5:]...)
+	if !bytes.Equal(readtgt[:n], expected) {
+		t.Errorf("Unexpected read after PunchHole: %v", readtgt[:n])
+	}
+
+	// Punch the tail of the chunk.
+	if err := cfio.PunchHole(7, 100); err != nil {
+		t.Fatalf("PunchHole failed: %v", err)
+	}
+	if caio.Cs[0].Length != 7 {
+		t.Errorf("Chunk not trimmed: %+v", caio.Cs[0])
+	}
+}
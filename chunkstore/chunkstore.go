@@ -39,7 +39,7 @@ func NewQueryChunkVersion(c *btncrypt.Cipher) version.QueryFunc {
 
 func NewChunkWriter(w io.Writer, c *btncrypt.Cipher, h ChunkHeader) (io.WriteCloser, error) {
 	if err := h.WriteTo(w, c); err != nil {
-		return nil, fmt.Errorf("Failed to write header: %v", err)
+		return nil, fmt.Errorf("Failed to write header: %w", err)
 	}
 
 	return c.NewWriteCloser(w, int(h.PayloadLen))
@@ -58,7 +58,7 @@ func NewChunkReader(r io.Reader, c *btncrypt.Cipher) (*ChunkReader, error) {
 	cr := &ChunkReader{r: r, c: c}
 
 	if err := cr.header.ReadFrom(r, c); err != nil {
-		return nil, fmt.Errorf("Failed to read header: %v", err)
+		return nil, fmt.Errorf("Failed to read header: %w", err)
 	}
 
 	var err error
@@ -141,7 +141,7 @@ func (ch *ChunkIO) ensureHeader() error {
 	}
 
 	if err := ch.header.ReadFrom(&blobstore.OffsetReader{ch.bh, 0}, ch.c); err != nil {
-		return fmt.Errorf("Failed to read header: %v", err)
+		return fmt.Errorf("Failed to read header: %w", err)
 	}
 
 	ch.didReadHeader = true
@@ -159,8 +159,48 @@ func (ch *ChunkIO) Size() int64 {
 	return int64(ch.PayloadLen())
 }
 
+// Truncate shrinks the payload to size. Expanding the payload is not supported.
 func (ch *ChunkIO) Truncate(size int64) error {
-	return fmt.Errorf("FIXME: implement ChunkIO.Truncate!")
+	if err := ch.ensureHeader(); err != nil {
+		return err
+	}
+
+	if size < 0 || int64(ch.PayloadLen()) < size {
+		return fmt.Errorf("Truncate size out of range: %d, payload len: %d", size, ch.PayloadLen())
+	}
+	if size == int64(ch.PayloadLen()) {
+		return nil
+	}
+
+	i := int(size) / ContentFramePayloadLength
+	inframeOffset := int(size) - i*ContentFramePayloadLength
+	blobSize := int64(ch.encryptedFrameOffset(i))
+	if inframeOffset > 0 {
+		// Re-encrypt the new last frame with the shortened payload.
+		f, err := ch.readCachedContentFrame(i)
+		if err != nil {
+			return err
+		}
+		f.P = f.P[:inframeOffset]
+		f.IsLastFrame = true
+		if err := ch.writeContentFrame(i, f); err != nil {
+			return fmt.Errorf("failed to write back the truncated frame: %w", err)
+		}
+		ch.c.PutDecryptedFrameBuf(f.P)
+		blobSize += int64(ch.c.EncryptedFrameSize(inframeOffset))
+	} else {
+		ch.cachedFrame = nil
+		ch.header.PayloadVersion++
+	}
+
+	if err := ch.bh.Truncate(blobSize); err != nil {
+		return fmt.Errorf("Failed to truncate blob: %w", err)
+	}
+	ch.header.PayloadLen = uint32(size)
+	ch.needsHeaderUpdate = true
+	zap.S().Debugf("ChunkIO truncate to %d", size)
+
+	return nil
 }
 
 func (ch *ChunkIO) PayloadLen() int {
@@ -217,13 +257,13 @@ func (ch *ChunkIO) readContentFrame(i int) (*decryptedContentFrame, error) {
 	enc := ch.c.GetEncryptedFrameBuf()[:ch.c.EncryptedFrameSize(framePayloadLen)]
 	defer ch.c.PutEncryptedFrameBuf(enc)
 	if err := ch.bh.PRead(enc, int64(blobOffset)); err != nil {
-		return nil, fmt.Errorf("Failed to read encrypted frame: %v", err)
+		return nil, fmt.Errorf("Failed to read encrypted frame: %w", err)
 	}
 
 	dec := ch.c.GetDecryptedFrameBuf()
 	dec, err := ch.c.DecryptFrame(dec, enc)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to decrypt frame idx: %d, err: %v", i, err)
+		return nil, fmt.Errorf("Failed to decrypt frame idx: %d, err: %w", i, err)
 	}
 
 	zap.S().Debugf("ChunkIO: Read content frame idx: %d", i)
@@ -254,7 +294,7 @@ func (ch *ChunkIO) writeContentFrame(i int, f *decryptedContentFrame) error {
 	enc = ch.c.EncryptFrame(enc, f.P)
 
 	if err := ch.bh.PWrite(enc, int64(blobOffset)); err != nil {
-		return fmt.Errorf("Failed to write encrypted frame: %v", err)
+		return fmt.Errorf("Failed to write encrypted frame: %w", err)
 	}
 
 	ch.header.PayloadVersion++
@@ -357,7 +397,7 @@ func (ch *ChunkIO) PWrite(p []byte, offset int64) error {
 				zap.S().Debugf(" len: %d", n)
 
 				if err := ch.writeContentFrame(i, f); err != nil {
-					return fmt.Errorf("failed to write zero fill frame: %v", err)
+					return fmt.Errorf("failed to write zero fill frame: %w", err)
 				}
 			} else {
 				n := util.IntMin(zflen, ContentFramePayloadLength-inframeOffset)
@@ -381,7 +421,7 @@ func (ch *ChunkIO) PWrite(p []byte, offset int64) error {
 
 				// writeback the frame
 				if err := ch.writeContentFrame(i, f); err != nil {
-					return fmt.Errorf("failed to write back the encrypted frame: %v", err)
+					return fmt.Errorf("failed to write back the encrypted frame: %w", err)
 				}
 				ch.c.PutDecryptedFrameBuf(f.P)
 			}
@@ -447,7 +487,7 @@ func (ch *ChunkIO) PWrite(p []byte, offset int64) error {
 
 		// writeback the updated encrypted frame
 		if err := ch.writeContentFrame(i, f); err != nil {
-			return fmt.Errorf("failed to write back the encrypted frame: %v", err)
+			return fmt.Errorf("failed to write back the encrypted frame: %w", err)
 		}
 		zap.S().Debugf("PWrite: wrote %d bytes for off %d len %d", n, offset, len(remp))
 
@@ -463,7 +503,7 @@ func (ch *ChunkIO) PWrite(p []byte, offset int64) error {
 func (ch *ChunkIO) Sync() error {
 	if ch.needsHeaderUpdate {
 		if err := ch.header.WriteTo(&blobstore.OffsetWriter{ch.bh, 0}, ch.c); err != nil {
-			return fmt.Errorf("Header write failed: %v", err)
+			return fmt.Errorf("Header write failed: %w", err)
 		}
 		zap.S().Debugf("Wrote chunk header: %+v", ch.header)
 		ch.needsHeaderUpdate = false
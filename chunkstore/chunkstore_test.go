@@ -383,6 +383,47 @@ type eofReader struct{}
 
 func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
 
+func TestChunkIO_Truncate(t *testing.T) {
+	td := genTestData(1024*1024 + 123)
+	for _, size := range []int{300000, chunkstore.ContentFramePayloadLength, 0} {
+		b := genFrameByChunkWriter(t, td)
+		if b == nil {
+			return
+		}
+		testbh := &TestBlobHandle{b}
+		cio := chunkstore.NewChunkIO(testbh, TestCipher())
+
+		if err := cio.Truncate(int64(size)); err != nil {
+			t.Errorf("failed to Truncate ChunkIO to %d: %v", size, err)
+			continue
+		}
+		if cio.Size() != int64(size) {
+			t.Errorf("Unexpected size after Truncate: %d != %d", cio.Size(), size)
+		}
+		if err := cio.Close(); err != nil {
+			t.Errorf("failed to Close ChunkIO: %v", err)
+			continue
+		}
+
+		if expected := genFrameByChunkWriter(t, td[:size]); len(testbh.Buf) != len(expected) {
+			t.Errorf("Unexpected blob len after Truncate to %d: %d != %d", size, len(testbh.Buf), len(expected))
+		}
+		cr, err := chunkstore.NewChunkReader(bytes.NewBuffer(testbh.Buf), TestCipher())
+		if err != nil {
+			t.Errorf("failed to create chunk reader: %v", err)
+			continue
+		}
+		readtgt := make([]byte, cr.Length())
+		if _, err := io.ReadFull(cr, readtgt); err != nil {
+			t.Errorf("failed to Read from ChunkReader: %v", err)
+			continue
+		}
+		if !bytes.Equal(readtgt, td[:size]) {
+			t.Errorf("Read content invalid after Truncate to %d", size)
+		}
+	}
+}
+
 func Test_QueryChunkVersion_EOF(t *testing.T) {
 	queryFn := chunkstore.NewQueryChunkVersion(TestCipher())
 	ver, err := queryFn(&eofReader{})
@@ -393,3 +434,34 @@ func Test_QueryChunkVersion_EOF(t *testing.T) {
 		t.Errorf("NewQueryChunkVersion should return 0 on EOF")
 	}
 }
+
+func TestVerifyChunk(t *testing.T) {
+	td := genTestData(1024*1024 + 123)
+	b := genFrameByChunkWriter(t, td)
+	if b == nil {
+		return
+	}
+
+	h, err := chunkstore.VerifyChunk(bytes.NewReader(b), TestCipher())
+	if err != nil {
+		t.Errorf("VerifyChunk failed on valid chunk: %v", err)
+	}
+	if h.PayloadLen != uint32(len(td)) {
+		t.Errorf("Unexpected PayloadLen: %d", h.PayloadLen)
+	}
+
+	if _, err := chunkstore.VerifyChunk(bytes.NewReader(nil), TestCipher()); err != nil {
+		t.Errorf("VerifyChunk should accept empty blob: %v", err)
+	}
+
+	tampered := append([]byte{}, b...)
+	tampered[len(tampered)-100] ^= 0x01
+	if _, err := chunkstore.VerifyChunk(bytes.NewReader(tampered), TestCipher()); err == nil {
+		t.Errorf("VerifyChunk should fail on tampered chunk")
+	}
+
+	truncated := b[:len(b)-100]
+	if _, err := chunkstore.VerifyChunk(bytes.NewReader(truncated), TestCipher()); err == nil {
+		t.Errorf("VerifyChunk should fail on truncated chunk")
+	}
+}
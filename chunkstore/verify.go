@@ -0,0 +1,48 @@
+package chunkstore
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/nyaxt/otaru/blobstore/version"
+	"github.com/nyaxt/otaru/btncrypt"
+)
+
+// VerifyChunk reads through the chunk from r, verifying its header and the
+// authenticity of all its content frames. An empty blob is a valid chunk of
+// version 0.
+func VerifyChunk(r io.Reader, c *btncrypt.Cipher) (ChunkHeader, error) {
+	var h ChunkHeader
+	if err := h.ReadFrom(r, c); err != nil {
+		if err == io.EOF {
+			return h, nil
+		}
+		return h, fmt.Errorf("Failed to read header: %v", err)
+	}
+
+	bdr, err := c.NewReader(r, int(h.PayloadLen))
+	if err != nil {
+		return h, err
+	}
+	defer bdr.Close()
+
+	n, err := io.Copy(ioutil.Discard, bdr)
+	if err != nil {
+		return h, fmt.Errorf("Failed to verify content frame at payload offset %d: %v", n, err)
+	}
+	if n != int64(h.PayloadLen) {
+		return h, fmt.Errorf("Payload length mismatch. header: %d, actual: %d", h.PayloadLen, n)
+	}
+	return h, nil
+}
+
+func NewVerifyChunk(c *btncrypt.Cipher) version.VerifyFunc {
+	return func(r io.Reader) (version.Version, error) {
+		h, err := VerifyChunk(r, c)
+		if err != nil {
+			return 0, err
+		}
+		return version.Version(h.PayloadVersion), nil
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/nyaxt/otaru/pb"
+)
+
+// QueryAuditLog prints the audit log entries of the otaru server at vhost matching req.
+func QueryAuditLog(ctx context.Context, w io.Writer, cfg *CliConfig, vhost string, req *pb.QueryAuditLogRequest, jsonOutput bool) error {
+	cinfo, err := QueryConnectionInfo(cfg, vhost)
+	if err != nil {
+		return err
+	}
+	conn, err := cinfo.DialGrpc(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := pb.NewAuditLogServiceClient(conn).QueryAuditLog(ctx, req)
+	if err != nil {
+		return fmt.Errorf("QueryAuditLog failed: %v", err)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to Write: %v", err)
+		}
+		return nil
+	}
+	for _, e := range resp.Entry {
+		target := e.Path
+		if e.NewPath != "" {
+			target = fmt.Sprintf("%s -> %s", e.Path, e.NewPath)
+		}
+		result := "ok"
+		if e.Error != "" {
+			result = "error: " + e.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\ttx %d\t%s\n",
+			formatUnixTime(e.Time), e.Source, e.User, e.Op, target, e.Txid, result)
+	}
+	return nil
+}
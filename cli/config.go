@@ -41,6 +41,9 @@ type Host struct {
 	CertsFile  string
 	Key        crypto.PrivateKey
 	KeyFile    string
+
+	// If non-empty, authenticate with the bearer token instead of the client cert.
+	Token string
 }
 
 type FeConfig struct {
@@ -6,7 +6,9 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/http"
 
+	"github.com/nyaxt/otaru/apiserver/clientauth"
 	"github.com/nyaxt/otaru/util/readpem"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -18,6 +20,8 @@ var ErrUnknownVhost = errors.New("Unknown vhost.")
 type ConnectionInfo struct {
 	ApiEndpoint string
 	TLSConfig   *tls.Config
+	// Bearer token to authenticate with, if non-empty.
+	Token string
 }
 
 func QueryConnectionInfo(cfg *CliConfig, vhost string) (*ConnectionInfo, error) {
@@ -51,6 +55,22 @@ func ConnectionInfoFromHost(h *Host) *ConnectionInfo {
 	return &ConnectionInfo{
 		ApiEndpoint: h.ApiEndpoint,
 		TLSConfig:   &tc,
+		Token:       h.Token,
+	}
+}
+
+type bearerCredentials string
+
+func (c bearerCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{clientauth.AuthorizationKey: clientauth.BearerPrefix + string(c)}, nil
+}
+
+func (bearerCredentials) RequireTransportSecurity() bool { return true }
+
+// SetAuthHeader sets the "Authorization" header of the HTTP request to the API server, if a token is configured.
+func (ci *ConnectionInfo) SetAuthHeader(h http.Header) {
+	if ci.Token != "" {
+		h.Set("Authorization", clientauth.BearerPrefix+ci.Token)
 	}
 }
 
@@ -60,6 +80,9 @@ func (ci *ConnectionInfo) DialGrpc(ctx context.Context) (*grpc.ClientConn, error
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(credentials.NewTLS(ci.TLSConfig)),
 	}
+	if ci.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerCredentials(ci.Token)))
+	}
 	conn, err := grpc.DialContext(ctx, ci.ApiEndpoint, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to grpc.Dial(%q). err: %v", ci.ApiEndpoint, err)
@@ -50,6 +50,7 @@ func newReaderHttp(cinfo *ConnectionInfo, id uint64) (Reader, error) {
 		},
 		URL: url,
 	}
+	cinfo.SetAuthHeader(req.Header)
 	resp, err := cli.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to issue Http GET request: %v", err)
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	humanize "github.com/dustin/go-humanize"
+
+	"github.com/nyaxt/otaru/pb"
+)
+
+var ErrScrubFoundBadBlobs = errors.New("Scrub found bad blobs.")
+
+// Scrub requests the otaru server at vhost to verify all its backend blobs,
+// and prints the bad blobs along with the file paths referencing them.
+func Scrub(ctx context.Context, w io.Writer, cfg *CliConfig, vhost string, jsonOutput bool) error {
+	cinfo, err := QueryConnectionInfo(cfg, vhost)
+	if err != nil {
+		return err
+	}
+	conn, err := cinfo.DialGrpc(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	bsc := pb.NewBlobstoreServiceClient(conn)
+	resp, err := bsc.Scrub(ctx, &pb.ScrubRequest{})
+	if err != nil {
+		return fmt.Errorf("Scrub failed: %v", err)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to Write: %v", err)
+		}
+	} else {
+		for _, b := range resp.BadBlobs {
+			fmt.Fprintf(w, "BAD %s: %s\n", b.Blobpath, b.Error)
+			for _, ref := range b.Refs {
+				fmt.Fprintf(w, "  %s (id: %d, offset: %d, length: %d)\n", ref.Path, ref.Id, ref.Offset, ref.Length)
+			}
+		}
+		fmt.Fprintf(w, "Verified %d blobs (%s). Skipped %d blobs modified in the cache. Found %d bad blobs.\n",
+			resp.NumVerified, humanize.Bytes(uint64(resp.NumBytes)), resp.NumSkipped, len(resp.BadBlobs))
+	}
+
+	if len(resp.BadBlobs) > 0 {
+		return ErrScrubFoundBadBlobs
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nyaxt/otaru/pb"
+)
+
+func dialTokenService(ctx context.Context, cfg *CliConfig, vhost string) (pb.TokenServiceClient, func(), error) {
+	cinfo, err := QueryConnectionInfo(cfg, vhost)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := cinfo.DialGrpc(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pb.NewTokenServiceClient(conn), func() { conn.Close() }, nil
+}
+
+func formatUnixTime(t int64) string {
+	if t == 0 {
+		return "-"
+	}
+	return time.Unix(t, 0).Format(time.RFC3339)
+}
+
+// CreateToken requests the otaru server at vhost to issue a bearer token, and prints its secret.
+// The token never expires if ttl is 0.
+func CreateToken(ctx context.Context, w io.Writer, cfg *CliConfig, vhost string, req *pb.CreateTokenRequest, ttl time.Duration) error {
+	tsc, closeConn, err := dialTokenService(ctx, cfg, vhost)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	if ttl > 0 {
+		req.ExpiresTime = time.Now().Add(ttl).Unix()
+	}
+	resp, err := tsc.CreateToken(ctx, req)
+	if err != nil {
+		return fmt.Errorf("CreateToken failed: %v", err)
+	}
+
+	t := resp.Token
+	fmt.Fprintf(w, "Created token %s for %s [role=%s] expiring %s.\n", t.Id, t.User, t.Role, formatUnixTime(t.ExpiresTime))
+	fmt.Fprintf(w, "The token can't be shown again:\n%s\n", resp.Secret)
+	return nil
+}
+
+func ListTokens(ctx context.Context, w io.Writer, cfg *CliConfig, vhost string, jsonOutput bool) error {
+	tsc, closeConn, err := dialTokenService(ctx, cfg, vhost)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := tsc.ListTokens(ctx, &pb.ListTokensRequest{})
+	if err != nil {
+		return fmt.Errorf("ListTokens failed: %v", err)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to Write: %v", err)
+		}
+		return nil
+	}
+	for _, t := range resp.Token {
+		scope := t.PathPrefix
+		if scope == "" {
+			scope = "/"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\tcreated %s\texpires %s\t%s\n",
+			t.Id, t.User, t.Role, scope, formatUnixTime(t.CreatedTime), formatUnixTime(t.ExpiresTime), t.Description)
+	}
+	return nil
+}
+
+func RevokeToken(ctx context.Context, cfg *CliConfig, vhost string, id string) error {
+	tsc, closeConn, err := dialTokenService(ctx, cfg, vhost)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	if _, err := tsc.RevokeToken(ctx, &pb.RevokeTokenRequest{Id: id}); err != nil {
+		return fmt.Errorf("RevokeToken failed: %v", err)
+	}
+	return nil
+}
@@ -54,6 +54,7 @@ func newWriterHttp(cinfo *ConnectionInfo, id uint64) (io.WriteCloser, error) {
 			Body:          pr,
 			ContentLength: -1, // FIXME
 		}
+		cinfo.SetAuthHeader(req.Header)
 		resp, err := cli.Do(req)
 		if err != nil {
 			errC <- err
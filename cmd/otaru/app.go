@@ -12,13 +12,17 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/nyaxt/otaru/cmd/otaru/auditlog"
 	"github.com/nyaxt/otaru/cmd/otaru/deleteallblobs"
 	"github.com/nyaxt/otaru/cmd/otaru/dumpblob"
 	"github.com/nyaxt/otaru/cmd/otaru/fe"
 	"github.com/nyaxt/otaru/cmd/otaru/fscli"
 	"github.com/nyaxt/otaru/cmd/otaru/globallock"
+	"github.com/nyaxt/otaru/cmd/otaru/migrate"
 	"github.com/nyaxt/otaru/cmd/otaru/mkfs"
+	"github.com/nyaxt/otaru/cmd/otaru/scrub"
 	"github.com/nyaxt/otaru/cmd/otaru/serve"
+	"github.com/nyaxt/otaru/cmd/otaru/token"
 	"github.com/nyaxt/otaru/cmd/otaru/webdav"
 	"github.com/nyaxt/otaru/facade"
 	"github.com/nyaxt/otaru/version"
@@ -60,12 +64,16 @@ func NewApp() *cli.App {
 		},
 	}
 	app.Commands = []*cli.Command{
+		auditlog.Command,
 		deleteallblobs.Command,
 		dumpblob.Command,
 		fe.Command,
 		globallock.Command,
+		migrate.Command,
 		mkfs.Command,
+		scrub.Command,
 		serve.Command,
+		token.Command,
 		webdav.Command,
 	}
 	app.Commands = append(app.Commands, fscli.Commands...)
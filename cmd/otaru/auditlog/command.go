@@ -0,0 +1,65 @@
+package auditlog
+
+import (
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	ocli "github.com/nyaxt/otaru/cli"
+	"github.com/nyaxt/otaru/pb"
+)
+
+var Command = &cli.Command{
+	Name:  "auditlog",
+	Usage: "Query the audit log of filesystem mutations",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "vhost",
+			Value: "default",
+			Usage: "otaru server to query",
+		},
+		&cli.DurationFlag{
+			Name:  "since",
+			Usage: "show the entries in the last duration, e.g. \"24h\"",
+		},
+		&cli.StringFlag{
+			Name:  "user",
+			Usage: "show the entries by the user",
+		},
+		&cli.StringFlag{
+			Name:  "op",
+			Usage: "show the entries of the op, e.g. \"write\" or \"remove\"",
+		},
+		&cli.StringFlag{
+			Name:  "path",
+			Usage: "show the entries of the files under the path",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Value: 100,
+			Usage: "show only the latest entries up to the number. Unlimited if 0",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "format output using json",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		cfg, err := ocli.NewConfig(c.String("configDir"))
+		if err != nil {
+			return err
+		}
+
+		req := &pb.QueryAuditLogRequest{
+			User:       c.String("user"),
+			Op:         c.String("op"),
+			PathPrefix: c.String("path"),
+			Limit:      int32(c.Int("limit")),
+		}
+		if d := c.Duration("since"); d > 0 {
+			req.SinceTime = time.Now().Add(-d).Unix()
+		}
+		return ocli.QueryAuditLog(c.Context, os.Stdout, cfg, c.String("vhost"), req, c.Bool("json"))
+	},
+}
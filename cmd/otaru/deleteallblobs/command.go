@@ -50,8 +50,8 @@ func clearCache(cacheDir string) error {
 	return clearBlobStore(bs)
 }
 
-func clearGCS(projectName, bucketName string, tsrc oauth2.TokenSource) error {
-	bs, err := gcs.NewGCSBlobStore(projectName, bucketName, tsrc, oflags.O_RDWRCREATE)
+func clearGCS(projectName, bucketName string, tsrc oauth2.TokenSource, endpoint string) error {
+	bs, err := gcs.NewGCSBlobStore(projectName, bucketName, tsrc, endpoint, oflags.O_RDWRCREATE)
 	if err != nil {
 		return fmt.Errorf("Failed to init GCSBlobStore: %v", err)
 	}
@@ -98,12 +98,12 @@ var Command = &cli.Command{
 		}
 		defer l.Unlock(c.Context)
 
-		if err := clearGCS(cfg.ProjectName, cfg.BucketName, tsrc); err != nil {
+		if err := clearGCS(cfg.ProjectName, cfg.BucketName, tsrc, cfg.GCSEndpoint); err != nil {
 			return fmt.Errorf("Failed to clear bucket \"%s\": %w", cfg.BucketName, err)
 		}
 		if cfg.UseSeparateBucketForMetadata {
 			metabucketname := fmt.Sprintf("%s-meta", cfg.BucketName)
-			if err := clearGCS(cfg.ProjectName, metabucketname, tsrc); err != nil {
+			if err := clearGCS(cfg.ProjectName, metabucketname, tsrc, cfg.GCSEndpoint); err != nil {
 				return fmt.Errorf("Failed to clear metadata bucket \"%s\": %w", metabucketname, err)
 			}
 		}
@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/facade"
+	"github.com/nyaxt/otaru/migrate"
+)
+
+const progressInterval = 1 * time.Second
+
+type progressPrinter struct {
+	mu        sync.Mutex
+	lastPrint time.Time
+}
+
+func (pp *progressPrinter) print(p migrate.Progress, force bool) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	if !force && time.Since(pp.lastPrint) < progressInterval {
+		return
+	}
+	pp.lastPrint = time.Now()
+
+	var rate uint64
+	if secs := p.Elapsed.Seconds(); secs > 0 {
+		rate = uint64(float64(p.BytesCopied) / secs)
+	}
+	fmt.Fprintf(os.Stderr, "\r%d/%d blobs (copied: %d, skipped: %d, failed: %d) %s %s/s",
+		p.NumDone(), p.NumBlobs, p.NumCopied, p.NumSkipped, p.NumFailed,
+		humanize.Bytes(uint64(p.BytesCopied)), humanize.Bytes(rate))
+}
+
+var Command = &cli.Command{
+	Name:  "migrate",
+	Usage: "Copy otaru filesystem instance from another backend to the one configured in configDir.",
+	Flags: []cli.Flag{
+		&cli.PathFlag{
+			Name:     "srcConfigDir",
+			Usage:    "Config dirpath of the source otaru instance",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:  "parallelism",
+			Value: migrate.DefaultParallelism,
+			Usage: "Number of blobs copied in parallel",
+		},
+		&cli.BoolFlag{
+			Name:  "dryrun",
+			Usage: "List blobs to be copied without copying",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		srccfg, err := facade.NewConfig(c.Path("srcConfigDir"))
+		if err != nil {
+			return fmt.Errorf("Failed to load source config: %w", err)
+		}
+		dstcfg, err := facade.NewConfig(c.Path("configDir"))
+		if err != nil {
+			return fmt.Errorf("Failed to load destination config: %w", err)
+		}
+
+		pp := &progressPrinter{}
+		opts := migrate.Options{
+			Parallelism: c.Int("parallelism"),
+			DryRun:      c.Bool("dryrun"),
+			Progress:    func(p migrate.Progress) { pp.print(p, false) },
+		}
+		report, err := facade.Migrate(c.Context, srccfg, dstcfg, opts)
+		if report != nil {
+			pp.print(report.Progress, true)
+			fmt.Fprintln(os.Stderr)
+			for _, bp := range report.Failed {
+				fmt.Printf("failed: %s\n", bp)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("facade.Migrate: %w", err)
+		}
+		zap.S().Infof("migrate finished successfully!")
+
+		return nil
+	},
+}
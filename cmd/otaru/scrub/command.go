@@ -0,0 +1,34 @@
+package scrub
+
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	ocli "github.com/nyaxt/otaru/cli"
+)
+
+var Command = &cli.Command{
+	Name:      "scrub",
+	Usage:     "Verify all backend blobs referenced from the filesystem",
+	ArgsUsage: "[vhost]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "format output using json",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		cfg, err := ocli.NewConfig(c.String("configDir"))
+		if err != nil {
+			return err
+		}
+
+		vhost := "default"
+		if c.Args().Present() {
+			vhost = c.Args().First()
+		}
+
+		return ocli.Scrub(c.Context, os.Stdout, cfg, vhost, c.Bool("json"))
+	},
+}
@@ -0,0 +1,102 @@
+package token
+
+import (
+	"errors"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	ocli "github.com/nyaxt/otaru/cli"
+	"github.com/nyaxt/otaru/pb"
+)
+
+var vhostFlag = &cli.StringFlag{
+	Name:  "vhost",
+	Value: "default",
+	Usage: "otaru server to manage tokens of",
+}
+
+var Command = &cli.Command{
+	Name:  "token",
+	Usage: "Manage bearer tokens for the API server",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "create",
+			Usage: "Issue a new token",
+			Flags: []cli.Flag{
+				vhostFlag,
+				&cli.StringFlag{
+					Name:  "user",
+					Usage: "user name of the token. Defaults to \"token-<id>\"",
+				},
+				&cli.StringFlag{
+					Name:  "role",
+					Value: "readonly",
+					Usage: "role of the token: \"readonly\" or \"admin\"",
+				},
+				&cli.StringFlag{
+					Name:  "path",
+					Usage: "restrict the token to the files under the path",
+				},
+				&cli.DurationFlag{
+					Name:  "ttl",
+					Usage: "expire the token after the duration, e.g. \"720h\". Never expires if unspecified",
+				},
+				&cli.StringFlag{
+					Name:  "description",
+					Usage: "note of what the token is for",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				cfg, err := ocli.NewConfig(c.String("configDir"))
+				if err != nil {
+					return err
+				}
+
+				req := &pb.CreateTokenRequest{
+					User:        c.String("user"),
+					Role:        c.String("role"),
+					PathPrefix:  c.String("path"),
+					Description: c.String("description"),
+				}
+				return ocli.CreateToken(c.Context, os.Stdout, cfg, c.String("vhost"), req, c.Duration("ttl"))
+			},
+		},
+		{
+			Name:  "list",
+			Usage: "List the tokens",
+			Flags: []cli.Flag{
+				vhostFlag,
+				&cli.BoolFlag{
+					Name:  "json",
+					Usage: "format output using json",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				cfg, err := ocli.NewConfig(c.String("configDir"))
+				if err != nil {
+					return err
+				}
+
+				return ocli.ListTokens(c.Context, os.Stdout, cfg, c.String("vhost"), c.Bool("json"))
+			},
+		},
+		{
+			Name:      "revoke",
+			Usage:     "Revoke the token",
+			ArgsUsage: "<token id>",
+			Flags:     []cli.Flag{vhostFlag},
+			Action: func(c *cli.Context) error {
+				cfg, err := ocli.NewConfig(c.String("configDir"))
+				if err != nil {
+					return err
+				}
+
+				if c.NArg() != 1 {
+					return errors.New("Specify a token id to revoke.")
+				}
+				return ocli.RevokeToken(c.Context, cfg, c.String("vhost"), c.Args().First())
+			},
+		},
+	},
+}
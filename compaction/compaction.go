@@ -0,0 +1,134 @@
+package compaction
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/chunkstore"
+	"github.com/nyaxt/otaru/filesystem"
+	"github.com/nyaxt/otaru/inodedb"
+)
+
+// DefaultMinExcessChunks is the default minimum number of chunks compaction
+// needs to save for a file to be compacted.
+const DefaultMinExcessChunks = 4
+
+// IdealNumChunks returns the number of chunks cs would have if rewritten
+// sequentially, i.e. the number of chunk splits covered by cs.
+func IdealNumChunks(cs []inodedb.FileChunk) int {
+	n := 0
+	last := int64(-1)
+	for _, c := range cs {
+		if c.Length == 0 {
+			continue
+		}
+		l := c.Left() / chunkstore.ChunkSplitSize
+		r := (c.Right() - 1) / chunkstore.ChunkSplitSize
+		if l == last {
+			l++
+		}
+		if r >= l {
+			n += int(r - l + 1)
+		}
+		last = r
+	}
+	return n
+}
+
+type FragmentedFile struct {
+	ID        inodedb.ID
+	Path      string
+	NumChunks int
+	Ideal     int
+}
+
+// FindFragmentedFiles returns the files which have at least minExcessChunks
+// more chunks than IdealNumChunks.
+func FindFragmentedFiles(idb inodedb.DBChunkRefLister, minExcessChunks int) ([]FragmentedFile, error) {
+	refs, errs := idb.ListChunkRefs()
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("ListChunkRefs returned err: %v", errs)
+	}
+
+	csmap := make(map[inodedb.ID][]inodedb.FileChunk)
+	paths := make(map[inodedb.ID]string)
+	for _, ref := range refs {
+		csmap[ref.NodeID] = append(csmap[ref.NodeID], ref.FileChunk)
+		paths[ref.NodeID] = ref.Path
+	}
+
+	if minExcessChunks < 1 {
+		minExcessChunks = 1
+	}
+	ffs := make([]FragmentedFile, 0)
+	for id, cs := range csmap {
+		sort.Slice(cs, func(i, j int) bool { return cs[i].Offset < cs[j].Offset })
+		ideal := IdealNumChunks(cs)
+		if len(cs)-ideal < minExcessChunks {
+			continue
+		}
+		ffs = append(ffs, FragmentedFile{ID: id, Path: paths[id], NumChunks: len(cs), Ideal: ideal})
+	}
+	sort.Slice(ffs, func(i, j int) bool { return ffs[i].ID < ffs[j].ID })
+	return ffs, nil
+}
+
+type Report struct {
+	// Number of fragmented files found.
+	NumFragmented int
+	// Number of files compacted.
+	NumCompacted int
+	// Number of fragmented files skipped as they were open for writing.
+	NumSkipped int
+	// Total number of chunks of the compacted files before compaction, and
+	// IdealNumChunks of them.
+	NumChunksBefore int
+	NumChunksAfter  int
+	// Files which failed to be compacted.
+	Failed []string
+}
+
+// Compact rewrites the fragmented files in fs into maximal-size chunks.
+func Compact(ctx context.Context, fs *filesystem.FileSystem, idb inodedb.DBChunkRefLister, minExcessChunks int, dryrun bool) (*Report, error) {
+	start := time.Now()
+
+	ffs, err := FindFragmentedFiles(idb, minExcessChunks)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{NumFragmented: len(ffs)}
+	for _, ff := range ffs {
+		if err := ctx.Err(); err != nil {
+			zap.S().Infof("Detected cancel. Bailing out.")
+			return report, err
+		}
+
+		if dryrun {
+			zap.S().Infof("Dryrun found fragmented file \"%s\": %d chunks, ideally %d.", ff.Path, ff.NumChunks, ff.Ideal)
+			continue
+		}
+		ok, err := fs.CompactFile(ff.ID)
+		if err != nil {
+			zap.S().Errorf("Failed to compact file \"%s\": %v", ff.Path, err)
+			report.Failed = append(report.Failed, ff.Path)
+			continue
+		}
+		if !ok {
+			zap.S().Infof("Skipped compacting file \"%s\" open for writing.", ff.Path)
+			report.NumSkipped++
+			continue
+		}
+		report.NumCompacted++
+		report.NumChunksBefore += ff.NumChunks
+		report.NumChunksAfter += ff.Ideal
+	}
+
+	zap.S().Infof("Compaction done. Found %d fragmented files, compacted %d (%d chunks -> %d), skipped %d, %d failed. Dryrun: %t. Took %v.",
+		report.NumFragmented, report.NumCompacted, report.NumChunksBefore, report.NumChunksAfter, report.NumSkipped, len(report.Failed), dryrun, time.Since(start))
+	return report, nil
+}
@@ -0,0 +1,136 @@
+package compaction_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/chunkstore"
+	"github.com/nyaxt/otaru/compaction"
+	"github.com/nyaxt/otaru/filesystem"
+	"github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/scheduler"
+	tu "github.com/nyaxt/otaru/testutils"
+)
+
+func init() { tu.EnsureLogger() }
+
+var _ = scheduler.Task(&compaction.Task{})
+
+func TestIdealNumChunks(t *testing.T) {
+	const MB = 1024 * 1024
+	for _, tc := range []struct {
+		cs       []inodedb.FileChunk
+		expected int
+	}{
+		{nil, 0},
+		{[]inodedb.FileChunk{{Offset: 0, Length: 10}}, 1},
+		{[]inodedb.FileChunk{{Offset: 0, Length: 10}, {Offset: 10, Length: 10}, {Offset: 30, Length: 10}}, 1},
+		{[]inodedb.FileChunk{{Offset: 0, Length: 256 * MB}, {Offset: 256 * MB, Length: 10}}, 2},
+		{[]inodedb.FileChunk{{Offset: 0, Length: 10}, {Offset: 10, Length: 300 * MB}}, 2},
+		{[]inodedb.FileChunk{{Offset: 0, Length: 10}, {Offset: 600 * MB, Length: 10}}, 2},
+	} {
+		if n := compaction.IdealNumChunks(tc.cs); n != tc.expected {
+			t.Errorf("IdealNumChunks(%+v) = %d, expected %d", tc.cs, n, tc.expected)
+		}
+	}
+}
+
+func writeFragmented(t *testing.T, fs *filesystem.FileSystem, path string, n int) []byte {
+	if err := fs.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	id, err := fs.FindNodeFullPath(path)
+	if err != nil {
+		t.Fatalf("FindNodeFullPath failed: %v", err)
+	}
+
+	// Split chunks at every 10 bytes while writing.
+	origSplitSize := chunkstore.ChunkSplitSize
+	chunkstore.ChunkSplitSize = 10
+	defer func() { chunkstore.ChunkSplitSize = origSplitSize }()
+
+	content := make([]byte, n*10)
+	for i := 0; i < n; i++ {
+		p := bytes.Repeat([]byte{byte('a' + i)}, 10)
+		copy(content[i*10:], p)
+
+		fh, err := fs.OpenFile(id, flags.O_RDWR)
+		if err != nil {
+			t.Fatalf("OpenFile failed: %v", err)
+		}
+		if err := fh.PWrite(p, int64(i*10)); err != nil {
+			t.Fatalf("PWrite failed: %v", err)
+		}
+		fh.Close()
+	}
+	return content
+}
+
+func readAll(t *testing.T, fs *filesystem.FileSystem, path string) []byte {
+	fh, err := fs.OpenFileFullPath(path, flags.O_RDONLY, 0644)
+	if err != nil {
+		t.Fatalf("OpenFileFullPath failed: %v", err)
+	}
+	defer fh.Close()
+
+	b := make([]byte, fh.Size())
+	if _, err := fh.ReadAt(b, 0); err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	return b
+}
+
+func TestCompact(t *testing.T) {
+	bs := tu.TestFileBlobStore()
+	idb := tu.TestINodeDB()
+	fs := filesystem.NewFileSystem(idb, bs, tu.TestCipher(), zap.L())
+	lister := idb.(inodedb.DBChunkRefLister)
+	ctx := context.Background()
+
+	fragmented := writeFragmented(t, fs, "/fragmented.txt", 8)
+	open := writeFragmented(t, fs, "/open.txt", 8)
+	if err := fs.WriteFile("/ok.txt", tu.HelloWorld, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	ffs, err := compaction.FindFragmentedFiles(lister, compaction.DefaultMinExcessChunks)
+	if err != nil {
+		t.Fatalf("FindFragmentedFiles failed: %v", err)
+	}
+	if len(ffs) != 2 || ffs[0].NumChunks != 8 || ffs[0].Ideal != 1 {
+		t.Errorf("Unexpected fragmented files: %+v", ffs)
+	}
+
+	fh, err := fs.OpenFileFullPath("/open.txt", flags.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("OpenFileFullPath failed: %v", err)
+	}
+
+	report, err := compaction.Compact(ctx, fs, lister, compaction.DefaultMinExcessChunks, false)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if report.NumFragmented != 2 || report.NumCompacted != 1 || report.NumSkipped != 1 || len(report.Failed) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	fh.Close()
+
+	if b := readAll(t, fs, "/fragmented.txt"); !bytes.Equal(b, fragmented) {
+		t.Errorf("Unexpected content after compaction: %q", b)
+	}
+	if b := readAll(t, fs, "/open.txt"); !bytes.Equal(b, open) {
+		t.Errorf("Unexpected content: %q", b)
+	}
+
+	ffs, err = compaction.FindFragmentedFiles(lister, compaction.DefaultMinExcessChunks)
+	if err != nil {
+		t.Fatalf("FindFragmentedFiles failed: %v", err)
+	}
+	if len(ffs) != 1 || ffs[0].Path != "/open.txt" {
+		t.Errorf("Unexpected fragmented files after compaction: %+v", ffs)
+	}
+}
@@ -0,0 +1,34 @@
+package compaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nyaxt/otaru/filesystem"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/util"
+)
+
+type Task struct {
+	FS              *filesystem.FileSystem
+	IDB             inodedb.DBChunkRefLister
+	MinExcessChunks int
+	DryRun          bool
+}
+
+type Result struct {
+	Report *Report
+	Error  error
+}
+
+func (r Result) Err() error { return r.Error }
+
+func (t *Task) Run(ctx context.Context) scheduler.Result {
+	report, err := Compact(ctx, t.FS, t.IDB, t.MinExcessChunks, t.DryRun)
+	return Result{report, err}
+}
+
+func (t *Task) String() string {
+	return fmt.Sprintf("compaction.Task{%s, minExcessChunks: %d, dryrun: %t}", util.TryGetImplName(t.IDB), t.MinExcessChunks, t.DryRun)
+}
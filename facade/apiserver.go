@@ -1,8 +1,13 @@
 package facade
 
 import (
+	"fmt"
+
 	"github.com/nyaxt/otaru/apiserver"
+	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/apiserver/ratelimit"
 	"github.com/nyaxt/otaru/assets/webui"
+	"github.com/nyaxt/otaru/chunkstore"
 	"github.com/nyaxt/otaru/otaruapiserver"
 	"go.uber.org/zap"
 )
@@ -13,18 +18,47 @@ func (o *Otaru) buildApiServerOptions(cfg *ApiServerConfig) ([]apiserver.Option,
 		zap.S().Infof("Overriding embedded WebUI and serving WebUI at %s", override)
 	}
 
+	acl, err := clientauth.NewACL(cfg.ACL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid ACL: %v", err)
+	}
+
 	options := []apiserver.Option{
 		apiserver.ListenAddr(cfg.ListenAddr),
 		apiserver.TLSCertKey(cfg.Certs, cfg.Key),
 		apiserver.ClientCACert(cfg.ClientCACert),
+		apiserver.ACL(acl),
 		apiserver.CORSAllowedOrigins(cfg.CORSAllowedOrigins),
 		apiserver.SetDefaultHandler(webui.WebUIHandler(override, "/index.otaru-server.html")),
-		otaruapiserver.InstallBlobstoreService(o.S, o.DefaultBS, o.CBS),
+		otaruapiserver.InstallBlobstoreService(o.S, o.ResilientBS, o.CBS, chunkstore.NewVerifyChunk(o.C), o.QuarantineBS, o.GetScrubTask()),
 		otaruapiserver.InstallFileHandler(o.FS),
 		otaruapiserver.InstallFileSystemService(o.FS),
 		otaruapiserver.InstallINodeDBService(o.IDBS),
 		otaruapiserver.InstallSystemService(),
 	}
 
+	if len(cfg.RateLimit) > 0 {
+		limiter, err := ratelimit.New(cfg.RateLimit)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid rate limits: %v", err)
+		}
+		options = append(options, apiserver.RateLimiter(limiter))
+	}
+
+	if o.AuditLog != nil {
+		options = append(options, otaruapiserver.InstallAuditLogService(o.AuditLog))
+	}
+
+	if cfg.EnableTokenAuth {
+		tokens, err := clientauth.NewTokenStore(cfg.TokensFile)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options,
+			apiserver.TokenStore(tokens),
+			otaruapiserver.InstallTokenService(tokens),
+		)
+	}
+
 	return options, nil
 }
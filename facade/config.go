@@ -14,7 +14,12 @@ import (
 	"github.com/naoina/toml"
 	"go.uber.org/zap"
 
+	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/apiserver/ratelimit"
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/filesystem"
 	"github.com/nyaxt/otaru/util"
+	"github.com/nyaxt/otaru/util/bwlimit"
 	"github.com/nyaxt/otaru/util/readpem"
 )
 
@@ -25,6 +30,26 @@ type Config struct {
 	UseSeparateBucketForMetadata bool
 	CredentialsFilePath          string
 
+	// If non-empty, replicate blobs to these GCS buckets in addition to BucketName.
+	ReplicaBucketNames []string
+	// Number of buckets a blob write needs to succeed on. All buckets if 0.
+	ReplicaWriteQuorum int
+
+	// If non-empty, move chunk blobs untouched for "ColdTierAfterDays" days to this GCS bucket,
+	// which is expected to have a colder storage class. The blobs are moved back on read.
+	ColdBucketName    string `toml:"cold_bucket_name"`
+	ColdTierAfterDays int64  `toml:"cold_tier_after_days"`
+
+	// Number of retries of a failed backend blobstore operation.
+	BackendMaxRetries int
+	// Timeout in seconds of opening a backend blob stream, or of a list, size or remove operation.
+	// Reads and writes on an opened stream are not timed out. No timeout if 0.
+	BackendTimeout int64 `toml:"backend_timeout"`
+	// Open the circuit breaker after this many consecutive backend failures. Disabled if 0.
+	BackendBreakerThreshold int
+	// Keep the circuit breaker open for "BackendBreakerCooldown" seconds.
+	BackendBreakerCooldown int64 `toml:"backend_breaker_cooldown"`
+
 	CacheDir string
 	// Cache size high watermark: discard cache when cache dir usage reach here.
 	CacheHighWatermarkInBytes int64
@@ -32,10 +57,43 @@ type Config struct {
 	// Cache size low watermark: when discarding cache, try to reduce cache dir usage under here.
 	CacheLowWatermarkInBytes int64
 	CacheLowWatermark        string
+	// Policy to choose cache entries to discard: "lru" (default), "lfu", "size", or "2q".
+	CacheEvictionPolicy string
+	// If true, verify integrity of all cached blobs on startup.
+	VerifyCacheOnStartup bool
+
+	// Throughput limit of cache writebacks to the backend per second. Unlimited if 0.
+	// Lifted on shutdown.
+	UploadBandwidthLimitInBytes int64
+	UploadBandwidthLimit        string
+	// Throughput limit of cache fetches from the backend per second. Unlimited if 0.
+	DownloadBandwidthLimitInBytes int64
+	DownloadBandwidthLimit        string
+	// If non-empty, bandwidth limits are lifted during this daily window. e.g. "01:00-07:00"
+	FullBandwidthWindow string
 
 	ReadOnly   bool
 	LocalDebug bool
 
+	// If true, update the access time of files on reads, as the relatime mount option.
+	Relatime bool
+
+	// Map the uid/gid stored in the filesystem to the local ones, to show the right owners
+	// of the files written on other machines.
+	UidMap []IDMapEntry `toml:"uid_map"`
+	GidMap []IDMapEntry `toml:"gid_map"`
+
+	// If non-empty, keep the audit log of filesystem mutations in this dir.
+	AuditLogDir string
+	// Rotate the audit log once it exceeds this size. Defaults to 16MB.
+	AuditLogMaxSizeInBytes int64
+	AuditLogMaxSize        string
+	// Number of rotated audit log files kept in AuditLogDir. Defaults to 8.
+	AuditLogMaxFiles int
+	// If true, store the rotated audit log files encrypted in the metadata blobstore.
+	// Rotated files are kept locally until stored, even beyond AuditLogMaxFiles.
+	ArchiveAuditLog bool
+
 	Password string
 
 	// If non-empty, perform fuse mount.
@@ -44,6 +102,32 @@ type Config struct {
 	// Run GC every "GCPeriod" seconds.
 	GCPeriod int64 `toml:"gc_period"`
 
+	// Scrub backend blobs every "ScrubPeriod" seconds. Disabled if <= 0.
+	ScrubPeriod int64 `toml:"scrub_period"`
+
+	// Compact files fragmented into many small chunks every "CompactPeriod" seconds. Disabled if <= 0.
+	CompactPeriod int64 `toml:"compact_period"`
+
+	// In read only mode, apply new transactions by the writer instance every
+	// "FollowTxLogPeriod" seconds, instead of serving a frozen state. Disabled if <= 0.
+	FollowTxLogPeriod int64 `toml:"follow_txlog_period"`
+
+	// The writer holds the global lock as a lease of "LockLeaseDuration" seconds, renewed periodically.
+	// A lock left by a crashed writer is taken over once the lease expires. The lock never expires if <= 0.
+	// Writes are refused while the lease can't be renewed, until a renewal succeeds.
+	LockLeaseDuration int64 `toml:"lock_lease_duration"`
+
+	// If the global lock is held by other writer, ask it to flush and release the lock, and wait
+	// for "WriterHandoffTimeout" seconds. Fail immediately if <= 0.
+	WriterHandoffTimeout int64 `toml:"writer_handoff_timeout"`
+
+	// If non-empty, use a local stand-in of Cloud Datastore persisted to this file, instead of Cloud Datastore.
+	LocalDatastorePath string
+
+	// If non-empty, send Cloud Storage requests to this GCS compatible endpoint, e.g. "http://localhost:4443".
+	// Credentials are optional if both GCSEndpoint and LocalDatastorePath are given.
+	GCSEndpoint string `toml:"gcs_endpoint"`
+
 	Logger    *zap.Logger
 	ApiServer ApiServerConfig
 }
@@ -62,10 +146,49 @@ type ApiServerConfig struct {
 	ClientCACert     *x509.Certificate
 	ClientCACertFile string `toml:"client_ca_cert_file"`
 
+	// If true, accept bearer tokens stored in TokensFile, in addition to client certs.
+	EnableTokenAuth bool
+	// Defaults to "${OTARUDIR}/tokens.json".
+	TokensFile string
+
+	// Per-user access control of the filesystem APIs, keyed by the client certificate identity.
+	ACL []clientauth.ACLEntry `toml:"acl"`
+
+	// Per-user rate and concurrency limits of the API requests, by user or role.
+	RateLimit []ratelimit.Rule `toml:"rate_limit"`
+
 	WebUIRootPath      string   `toml:"webui_root_path"`
 	CORSAllowedOrigins []string `toml:"cors_allowed_origins"`
 }
 
+type IDMapEntry struct {
+	// The id stored in the filesystem.
+	Stored uint32
+	// The id on this machine.
+	Local uint32
+}
+
+func idMapFromEntries(es []IDMapEntry) map[uint32]uint32 {
+	m := make(map[uint32]uint32, len(es))
+	for _, e := range es {
+		m[e.Stored] = e.Local
+	}
+	return m
+}
+
+// IDMap returns the uid/gid mapping specified in the config, or nil if none.
+func (cfg *Config) IDMap() (*filesystem.IDMap, error) {
+	if len(cfg.UidMap) == 0 && len(cfg.GidMap) == 0 {
+		return nil, nil
+	}
+	return filesystem.NewIDMap(idMapFromEntries(cfg.UidMap), idMapFromEntries(cfg.GidMap))
+}
+
+// NoCredentialsRequired returns true if otaru doesn't talk to Google Cloud services which require credentials.
+func (cfg *Config) NoCredentialsRequired() bool {
+	return cfg.LocalDebug || (cfg.GCSEndpoint != "" && cfg.LocalDatastorePath != "")
+}
+
 func DefaultConfigDir() string {
 	return path.Join(os.Getenv("HOME"), ".otaru")
 }
@@ -113,12 +236,19 @@ func NewConfig(configdir string) (*Config, error) {
 		CacheHighWatermarkInBytes:    math.MaxInt64,
 		CacheLowWatermarkInBytes:     math.MaxInt64,
 		GCPeriod:                     15 * 60,
+		LockLeaseDuration:            60,
+		ColdTierAfterDays:            30,
+		BackendMaxRetries:            3,
+		BackendTimeout:               60,
+		BackendBreakerThreshold:      5,
+		BackendBreakerCooldown:       30,
 		ApiServer: ApiServerConfig{
 			ListenAddr:       ":10246",
 			EnableDebug:      false,
 			CertsFile:        path.Join(configdir, "cert.pem"),
 			KeyFile:          path.Join(configdir, "cert-key.pem"),
 			ClientCACertFile: path.Join(configdir, "clientauth-ca.pem"),
+			TokensFile:       path.Join(configdir, "tokens.json"),
 		},
 	}
 
@@ -133,17 +263,32 @@ func NewConfig(configdir string) (*Config, error) {
 
 	if cfg.CredentialsFilePath == "" {
 		cfg.CredentialsFilePath = FindGCPServiceAccountJSON(configdir)
-		if cfg.CredentialsFilePath == "" {
+		if cfg.CredentialsFilePath == "" && !cfg.NoCredentialsRequired() {
 			return nil, fmt.Errorf("Failed to find Google Cloud service account json.")
 		}
 	}
 
+	if cfg.LocalDatastorePath != "" {
+		cfg.LocalDatastorePath = os.ExpandEnv(cfg.LocalDatastorePath)
+	}
+
 	cfg.CacheDir = os.ExpandEnv(cfg.CacheDir)
 	cfg.CacheDir, err = filepath.Abs(cfg.CacheDir)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to resolve cache dir to absolute path \"%s\": %v", cfg.CacheDir, err)
 	}
 
+	if cfg.AuditLogDir != "" {
+		cfg.AuditLogDir = os.ExpandEnv(cfg.AuditLogDir)
+	}
+	if cfg.AuditLogMaxSize != "" {
+		bytes, err := humanize.ParseBytes(cfg.AuditLogMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse audit_log_max_size \"%s\"", cfg.AuditLogMaxSize)
+		}
+		cfg.AuditLogMaxSizeInBytes = int64(bytes)
+	}
+
 	if cfg.CacheHighWatermark != "" {
 		bytes, err := humanize.ParseBytes(cfg.CacheHighWatermark)
 		if err != nil {
@@ -163,6 +308,30 @@ func NewConfig(configdir string) (*Config, error) {
 			humanize.Bytes(uint64(cfg.CacheLowWatermarkInBytes)), humanize.Bytes(uint64(cfg.CacheHighWatermarkInBytes)))
 	}
 
+	if _, err := cachedblobstore.EvictionPolicyFromName(cfg.CacheEvictionPolicy); err != nil {
+		return nil, fmt.Errorf("Failed to parse cache_eviction_policy: %v", err)
+	}
+
+	if cfg.UploadBandwidthLimit != "" {
+		bytes, err := humanize.ParseBytes(cfg.UploadBandwidthLimit)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse upload_bandwidth_limit \"%s\"", cfg.UploadBandwidthLimit)
+		}
+		cfg.UploadBandwidthLimitInBytes = int64(bytes)
+	}
+	if cfg.DownloadBandwidthLimit != "" {
+		bytes, err := humanize.ParseBytes(cfg.DownloadBandwidthLimit)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse download_bandwidth_limit \"%s\"", cfg.DownloadBandwidthLimit)
+		}
+		cfg.DownloadBandwidthLimitInBytes = int64(bytes)
+	}
+	if cfg.FullBandwidthWindow != "" {
+		if _, err := bwlimit.ParseWindow(cfg.FullBandwidthWindow); err != nil {
+			return nil, fmt.Errorf("Failed to parse full_bandwidth_window: %v", err)
+		}
+	}
+
 	if cfg.Password != "" {
 		s.Warnf("Storing password directly on config file is not recommended.")
 	} else {
@@ -180,6 +349,10 @@ func NewConfig(configdir string) (*Config, error) {
 		}
 	}
 
+	if cfg.BackendMaxRetries < 0 {
+		return nil, fmt.Errorf("Config Error: backend_max_retries must not be negative.")
+	}
+
 	if cfg.ProjectName == "" {
 		return nil, fmt.Errorf("Config Error: ProjectName must be given.")
 	}
@@ -187,7 +360,7 @@ func NewConfig(configdir string) (*Config, error) {
 		return nil, fmt.Errorf("Config Error: BucketName must be given.")
 	}
 
-	if !cfg.LocalDebug {
+	if !cfg.LocalDebug && cfg.CredentialsFilePath != "" {
 		cfg.CredentialsFilePath = os.ExpandEnv(cfg.CredentialsFilePath)
 		if _, err := os.Stat(cfg.CredentialsFilePath); err != nil {
 			if os.IsNotExist(err) {
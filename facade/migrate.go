@@ -0,0 +1,92 @@
+package facade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/chunkstore"
+	oflags "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/migrate"
+	"github.com/nyaxt/otaru/util"
+)
+
+// Migrate copies all blobs, the inodedb snapshot locator entries and the
+// inodedb transaction log from the otaru instance configured by srccfg to the
+// one configured by dstcfg.
+//
+// The source is opened read only without acquiring its global lock, so it
+// may keep serving while migrating. Migrate may be run again to resume an
+// interrupted migration, or to catch up with changes made to the source.
+func Migrate(ctx context.Context, srccfg, dstcfg *Config, opts migrate.Options) (*migrate.Report, error) {
+	if srccfg.Password != dstcfg.Password {
+		return nil, errors.New("Source and destination must share the same password.")
+	}
+	if dstcfg.ReadOnly {
+		return nil, errors.New("Migrate operation can't be performed to a read only destination.")
+	}
+
+	src := &Otaru{ReadOnly: true}
+	defer src.Close()
+	if err := src.initCrypt(srccfg); err != nil {
+		return nil, fmt.Errorf("initCrypt: %v", err)
+	}
+	if err := src.initCloudDatastoreConfig(srccfg); err != nil {
+		return nil, fmt.Errorf("initCloudDatastore (source): %v", err)
+	}
+	if err := src.initBackendBlobStore(srccfg, oflags.O_RDONLY); err != nil {
+		return nil, fmt.Errorf("initBackendBlobStore (source): %v", err)
+	}
+	if err := src.initINodeDBLogIO(srccfg, oflags.O_RDONLY); err != nil {
+		return nil, fmt.Errorf("initINodeDBLogIO (source): %v", err)
+	}
+
+	dst := &Otaru{}
+	defer dst.Close()
+	if err := dst.initCrypt(dstcfg); err != nil {
+		return nil, fmt.Errorf("initCrypt: %v", err)
+	}
+	if err := dst.initCloudDatastore(ctx, dstcfg); err != nil {
+		return nil, fmt.Errorf("initCloudDatastore (destination): %v", err)
+	}
+	if err := dst.initBackendBlobStore(dstcfg, oflags.O_RDWRCREATE); err != nil {
+		return nil, fmt.Errorf("initBackendBlobStore (destination): %v", err)
+	}
+	if err := dst.initINodeDBLogIO(dstcfg, oflags.O_RDWRCREATE); err != nil {
+		return nil, fmt.Errorf("initINodeDBLogIO (destination): %v", err)
+	}
+
+	srcbs, ok := src.BackendBS.(migrate.BlobListerSizer)
+	if !ok {
+		return nil, fmt.Errorf("Backend blobstore \"%s\" don't support ListBlobs() and BlobSize()", util.TryGetImplName(src.BackendBS))
+	}
+	dstbs, ok := dst.BackendBS.(migrate.BlobListerSizer)
+	if !ok {
+		return nil, fmt.Errorf("Backend blobstore \"%s\" don't support ListBlobs() and BlobSize()", util.TryGetImplName(dst.BackendBS))
+	}
+
+	if opts.QueryVersion == nil {
+		opts.QueryVersion = chunkstore.NewQueryChunkVersion(src.C)
+	}
+	report, err := migrate.CopyBlobs(ctx, srcbs, dstbs, opts)
+	if err != nil {
+		return report, err
+	}
+	if opts.DryRun {
+		zap.S().Infof("Migrate: dryrun. Skipping inodedb migration.")
+		return report, nil
+	}
+
+	// Snapshot locator entries refer to the snapshot blobs, so they need to be
+	// migrated after the blobs. Transactions are applied on top of the snapshots.
+	if _, err := migrate.SSLocator(src.SSLoc, dst.SSLoc); err != nil {
+		return report, err
+	}
+	if _, err := migrate.TxLog(src.TxIO, dst.TxIO); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/multierr"
@@ -13,10 +14,15 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/nyaxt/otaru/apiserver"
+	"github.com/nyaxt/otaru/auditlog"
 	"github.com/nyaxt/otaru/blobstore"
 	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/blobstore/replicatedblobstore"
+	"github.com/nyaxt/otaru/blobstore/resilientblobstore"
+	"github.com/nyaxt/otaru/blobstore/tieredblobstore"
 	"github.com/nyaxt/otaru/btncrypt"
 	"github.com/nyaxt/otaru/chunkstore"
+	"github.com/nyaxt/otaru/compaction"
 	"github.com/nyaxt/otaru/filesystem"
 	oflags "github.com/nyaxt/otaru/flags"
 	"github.com/nyaxt/otaru/fuse"
@@ -29,10 +35,13 @@ import (
 	"github.com/nyaxt/otaru/inodedb"
 	"github.com/nyaxt/otaru/inodedb/blobstoredbstatesnapshotio"
 	"github.com/nyaxt/otaru/inodedb/inodedbsyncer"
+	"github.com/nyaxt/otaru/inodedb/txlogfollower"
 	"github.com/nyaxt/otaru/logger"
 	"github.com/nyaxt/otaru/metadata"
 	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/scrub"
 	"github.com/nyaxt/otaru/util"
+	"github.com/nyaxt/otaru/util/bwlimit"
 )
 
 var mylog = logger.Registry().Category("facade")
@@ -48,13 +57,26 @@ type Otaru struct {
 	Tsrc  oauth2.TokenSource
 	DSCfg *datastore.Config
 	GL    *datastore.GlobalLocker
-
-	MetadataBS blobstore.BlobStore
-	DefaultBS  blobstore.BlobStore
-
-	BackendBS blobstore.BlobStore
+	// LeaseRenewer keeps the lease of GL renewed while serving.
+	LeaseRenewer *datastore.LeaseRenewer
+	// leaseExpiring is set while the lease of GL couldn't be renewed. Writes
+	// are refused meanwhile.
+	leaseExpiring atomic.Bool
+	// leaseLost is set once the lock entry of GL was found held by other
+	// writer, or gone.
+	leaseLost atomic.Bool
+	closing   atomic.Bool
+
+	MetadataBS   blobstore.BlobStore
+	DefaultBS    blobstore.BlobStore
+	ReplicatedBS *replicatedblobstore.ReplicatedBlobStore
+	TieredBS     *tieredblobstore.TieredBlobStore
+
+	ResilientBS *resilientblobstore.ResilientBlobStore
+	BackendBS   blobstore.BlobStore
 
 	CacheTgtBS         *blobstore.FileBlobStore
+	QuarantineBS       *blobstore.FileBlobStore
 	CBS                *cachedblobstore.CachedBlobStore
 	AutoReduceCacheJob scheduler.ID
 	SaveStateJob       scheduler.ID
@@ -70,11 +92,18 @@ type Otaru struct {
 	IDBS       *inodedb.DBService
 	IDBSyncJob scheduler.ID
 
-	FS *filesystem.FileSystem
+	FS       *filesystem.FileSystem
+	AuditLog *auditlog.Log
 
 	AutoBlobstoreGCJob    scheduler.ID
 	AutoINodeDBTxLogGCJob scheduler.ID
 	AutoINodeDBSSGCJob    scheduler.ID
+	AutoScrubJob          scheduler.ID
+	AutoReplicaRepairJob  scheduler.ID
+	AutoTierJob           scheduler.ID
+	AutoCompactJob        scheduler.ID
+
+	FollowTxLogJob scheduler.ID
 }
 
 func BootstrapLogger() {
@@ -120,6 +149,7 @@ func Mkfs(cfg *Config) error {
 
 func Serve(ctx context.Context, cfg *Config) error {
 	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	o := &Otaru{}
 	defer o.Close()
@@ -142,6 +172,24 @@ func Serve(ctx context.Context, cfg *Config) error {
 	if err := o.initCloudDatastore(ctx, cfg); err != nil {
 		return fmt.Errorf("initCloudDatastore: %v", err)
 	}
+	if o.GL != nil && !o.ReadOnly && o.GL.LeaseDuration > 0 {
+		o.LeaseRenewer = o.GL.StartRenewing(func(requestedBy string) {
+			// Stop serving. Close flushes all changes and releases the lock.
+			zap.S().Infof("Handing off the global lock to host \"%s\". Shutting down.", requestedBy)
+			cancel()
+		}, func(expiring bool) {
+			if expiring {
+				zap.S().Errorf("The global lock lease is expiring. Refusing writes until it is renewed.")
+			} else {
+				zap.S().Infof("The global lock lease is renewed. Accepting writes again.")
+			}
+			o.leaseExpiring.Store(expiring)
+		}, func(err error) {
+			zap.S().Errorf("Lost the global lock: %v. Shutting down without flushing changes.", err)
+			o.leaseLost.Store(true)
+			cancel()
+		})
+	}
 	if err := o.initBlobStore(cfg, flags); err != nil {
 		return fmt.Errorf("initBlobStore: %v", err)
 	}
@@ -155,13 +203,40 @@ func Serve(ctx context.Context, cfg *Config) error {
 	if err != nil {
 		return fmt.Errorf("NewDB failed: %v", err)
 	}
+	o.IDBBE.SetWriteFence(o.isWriteFenced)
 
 	o.IDBS = inodedb.NewDBService(o.IDBBE)
 	if !cfg.ReadOnly {
 		o.IDBSyncJob = o.R.RunEveryPeriod(inodedbsyncer.NewSyncTask(o.IDBS), 30*time.Second)
+	} else if cfg.FollowTxLogPeriod > 0 {
+		zap.S().Infof("Following txlog every %d seconds.", cfg.FollowTxLogPeriod)
+		o.FollowTxLogJob = o.R.RunEveryPeriod(txlogfollower.NewTask(o.IDBS, o.CBS), time.Duration(cfg.FollowTxLogPeriod)*time.Second)
 	}
 
 	o.FS = filesystem.NewFileSystem(o.IDBS, o.CBS, o.C, cfg.Logger)
+	o.FS.SetRelatime(cfg.Relatime)
+	idmap, err := cfg.IDMap()
+	if err != nil {
+		return fmt.Errorf("Failed to load uid/gid map: %v", err)
+	}
+	o.FS.SetIDMap(idmap)
+
+	if cfg.AuditLogDir != "" {
+		alcfg := auditlog.Config{
+			Dir:      cfg.AuditLogDir,
+			MaxSize:  cfg.AuditLogMaxSizeInBytes,
+			MaxFiles: cfg.AuditLogMaxFiles,
+		}
+		if cfg.ArchiveAuditLog && !o.ReadOnly {
+			alcfg.ArchiveBS = o.CBS
+			alcfg.Cipher = o.C
+		}
+		o.AuditLog, err = auditlog.New(alcfg)
+		if err != nil {
+			return fmt.Errorf("Failed to init audit log: %v", err)
+		}
+		o.FS.SetAuditRecorder(o.AuditLog)
+	}
 
 	if o.ReadOnly {
 		zap.S().Infof("No GC tasks are scheduled in read only mode.")
@@ -178,6 +253,20 @@ func Serve(ctx context.Context, cfg *Config) error {
 		if t := o.GetINodeDBSSGCTask(NoDryRun); t != nil {
 			o.AutoINodeDBSSGCJob = o.R.RunEveryPeriod(t, time.Duration(cfg.GCPeriod)*time.Second)
 		}
+		if o.ReplicatedBS != nil {
+			o.AutoReplicaRepairJob = o.R.RunEveryPeriod(&replicatedblobstore.RepairTask{o.ReplicatedBS, NoDryRun}, time.Duration(cfg.GCPeriod)*time.Second)
+		}
+		if o.TieredBS != nil && cfg.ColdTierAfterDays > 0 {
+			o.AutoTierJob = o.R.RunEveryPeriod(o.GetTierTask(time.Duration(cfg.ColdTierAfterDays)*24*time.Hour, NoDryRun), time.Duration(cfg.GCPeriod)*time.Second)
+		}
+	}
+
+	if cfg.ScrubPeriod > 0 {
+		o.AutoScrubJob = o.R.RunEveryPeriod(o.GetScrubTask(), time.Duration(cfg.ScrubPeriod)*time.Second)
+	}
+	if !o.ReadOnly && cfg.CompactPeriod > 0 {
+		const NoDryRun = false
+		o.AutoCompactJob = o.R.RunEveryPeriod(o.GetCompactionTask(NoDryRun), time.Duration(cfg.CompactPeriod)*time.Second)
 	}
 
 	apiopts, err := o.buildApiServerOptions(&cfg.ApiServer)
@@ -222,18 +311,50 @@ func (o *Otaru) initCrypt(cfg *Config) error {
 }
 
 func (o *Otaru) initCloudDatastore(ctx context.Context, cfg *Config) error {
-	if !cfg.LocalDebug {
+	if err := o.initCloudDatastoreConfig(cfg); err != nil {
+		return err
+	}
+	if o.GL != nil && o.ReadOnly && cfg.FollowTxLogPeriod > 0 {
+		zap.S().Infof("Not checking the global lock, as read only instances following txlog coexist with the writer.")
+	} else if o.GL != nil {
+		err := o.GL.Lock(ctx, o.ReadOnly)
+		if _, ok := err.(*datastore.ErrLockTaken); ok && !o.ReadOnly && cfg.WriterHandoffTimeout > 0 {
+			zap.S().Infof("%v. Requesting handoff.", err)
+			hctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.WriterHandoffTimeout)*time.Second)
+			err = o.GL.LockWithHandoff(hctx)
+			cancel()
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to acquire global lock: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// initCloudDatastoreConfig is initCloudDatastore without acquiring the global lock.
+func (o *Otaru) initCloudDatastoreConfig(cfg *Config) error {
+	if !cfg.LocalDebug && (cfg.CredentialsFilePath != "" || !cfg.NoCredentialsRequired()) {
 		var err error
 		// FIXME: move below
 		o.Tsrc, err = auth.GetGCloudTokenSource(cfg.CredentialsFilePath)
 		if err != nil {
 			return fmt.Errorf("Failed to init GCloudClientSource: %v", err)
 		}
+	}
+
+	if cfg.LocalDatastorePath != "" {
+		ld, err := datastore.OpenLocalDatastore(cfg.LocalDatastorePath)
+		if err != nil {
+			return fmt.Errorf("Failed to init LocalDatastore: %v", err)
+		}
+		o.DSCfg = datastore.NewLocalConfig(cfg.BucketName, o.C, ld)
+	} else if !cfg.LocalDebug {
 		o.DSCfg = datastore.NewConfig(cfg.ProjectName, cfg.BucketName, o.C, o.Tsrc)
+	}
+	if o.DSCfg != nil {
 		o.GL = datastore.NewGlobalLocker(o.DSCfg, GenHostName(), "FIXME: fill info")
-		if err := o.GL.Lock(ctx, o.ReadOnly); err != nil {
-			return fmt.Errorf("Failed to acquire global lock: %v", err)
-		}
+		o.GL.LeaseDuration = time.Duration(cfg.LockLeaseDuration) * time.Second
 	}
 
 	return nil
@@ -246,17 +367,106 @@ func (o *Otaru) initBlobStore(cfg *Config, flags int) error {
 	if err != nil {
 		return fmt.Errorf("Failed to init FileBlobStore: %v", err)
 	}
+	o.QuarantineBS, err = blobstore.NewFileBlobStore(path.Join(cfg.CacheDir, "quarantine"), oflags.O_RDWRCREATE)
+	if err != nil {
+		return fmt.Errorf("Failed to init FileBlobStore (cache quarantine): %v", err)
+	}
+
+	if err := o.initBackendBlobStore(cfg, flags); err != nil {
+		return err
+	}
+
+	queryFn := chunkstore.NewQueryChunkVersion(o.C)
+	o.CBS, err = cachedblobstore.New(o.BackendBS, o.CacheTgtBS, o.S, flags, queryFn)
+	if err != nil {
+		return fmt.Errorf("Failed to init CachedBlobStore: %v", err)
+	}
+	if err := o.CBS.RestoreState(o.C); err != nil {
+		zap.S().Warnf("Attempted to restore cachedblobstore state but failed: %v", err)
+	}
+
+	bwcfg := cachedblobstore.BandwidthLimitConfig{
+		UploadBytesPerSec:   cfg.UploadBandwidthLimitInBytes,
+		DownloadBytesPerSec: cfg.DownloadBandwidthLimitInBytes,
+	}
+	if cfg.FullBandwidthWindow != "" {
+		w, err := bwlimit.ParseWindow(cfg.FullBandwidthWindow)
+		if err != nil {
+			return err
+		}
+		bwcfg.FullBandwidthWindow = &w
+	}
+	o.CBS.SetBandwidthLimit(bwcfg)
+
+	policy, err := cachedblobstore.EvictionPolicyFromName(cfg.CacheEvictionPolicy)
+	if err != nil {
+		return err
+	}
+	o.CBS.SetEvictionPolicy(policy)
+
+	if cfg.VerifyCacheOnStartup {
+		const NoDryRun = false
+		report, err := o.CBS.VerifyCache(context.Background(), chunkstore.NewVerifyChunk(o.C), o.QuarantineBS, NoDryRun)
+		if err != nil {
+			return fmt.Errorf("Failed to verify cache: %v", err)
+		}
+		if len(report.DataLoss) > 0 {
+			zap.S().Errorf("Cache verification found %d corrupted blobs with changes not written back: %v", len(report.DataLoss), report.DataLoss)
+		}
+	}
+
+	if o.R != nil {
+		o.AutoReduceCacheJob = cachedblobstore.SetupAutoReduceCache(o.CBS, o.R, cfg.CacheHighWatermarkInBytes, cfg.CacheLowWatermarkInBytes)
+		if !o.ReadOnly {
+			o.SaveStateJob = o.R.RunEveryPeriod(cachedblobstore.SaveStateTask{o.CBS, o.C}, 30*time.Second)
+		}
+	}
+
+	return nil
+}
+
+// initBackendBlobStore initializes the backend blobstores without the local cache.
+func (o *Otaru) initBackendBlobStore(cfg *Config, flags int) error {
+	var err error
+
+	queryFn := chunkstore.NewQueryChunkVersion(o.C)
 
 	if !cfg.LocalDebug {
-		o.DefaultBS, err = gcs.NewGCSBlobStore(cfg.ProjectName, cfg.BucketName, o.Tsrc, flags)
+		o.DefaultBS, err = gcs.NewGCSBlobStore(cfg.ProjectName, cfg.BucketName, o.Tsrc, cfg.GCSEndpoint, flags)
 		if err != nil {
 			return fmt.Errorf("Failed to init GCSBlobStore: %v", err)
 		}
+		if len(cfg.ReplicaBucketNames) > 0 {
+			replicas := []blobstore.BlobStore{o.DefaultBS}
+			for _, bucketname := range cfg.ReplicaBucketNames {
+				bs, err := gcs.NewGCSBlobStore(cfg.ProjectName, bucketname, o.Tsrc, cfg.GCSEndpoint, flags)
+				if err != nil {
+					return fmt.Errorf("Failed to init GCSBlobStore (replica %s): %v", bucketname, err)
+				}
+				replicas = append(replicas, bs)
+			}
+			o.ReplicatedBS, err = replicatedblobstore.New(replicas, cfg.ReplicaWriteQuorum, queryFn)
+			if err != nil {
+				return fmt.Errorf("Failed to init ReplicatedBlobStore: %v", err)
+			}
+			o.DefaultBS = o.ReplicatedBS
+		}
+		if cfg.ColdBucketName != "" {
+			coldbs, err := gcs.NewGCSBlobStore(cfg.ProjectName, cfg.ColdBucketName, o.Tsrc, cfg.GCSEndpoint, flags)
+			if err != nil {
+				return fmt.Errorf("Failed to init GCSBlobStore (cold tier): %v", err)
+			}
+			o.TieredBS, err = tieredblobstore.New(o.DefaultBS, coldbs, o.C, flags)
+			if err != nil {
+				return fmt.Errorf("Failed to init TieredBlobStore: %v", err)
+			}
+			o.DefaultBS = o.TieredBS
+		}
 		if !cfg.UseSeparateBucketForMetadata {
 			o.BackendBS = o.DefaultBS
 		} else {
 			metabucketname := fmt.Sprintf("%s-meta", cfg.BucketName)
-			o.MetadataBS, err = gcs.NewGCSBlobStore(cfg.ProjectName, metabucketname, o.Tsrc, flags)
+			o.MetadataBS, err = gcs.NewGCSBlobStore(cfg.ProjectName, metabucketname, o.Tsrc, cfg.GCSEndpoint, flags)
 			if err != nil {
 				return fmt.Errorf("Failed to init GCSBlobStore (metadata): %v", err)
 			}
@@ -273,34 +483,36 @@ func (o *Otaru) initBlobStore(cfg *Config, flags int) error {
 		}
 	}
 
-	queryFn := chunkstore.NewQueryChunkVersion(o.C)
-	o.CBS, err = cachedblobstore.New(o.BackendBS, o.CacheTgtBS, o.S, flags, queryFn)
-	if err != nil {
-		return fmt.Errorf("Failed to init CachedBlobStore: %v", err)
-	}
-	if err := o.CBS.RestoreState(o.C); err != nil {
-		zap.S().Warnf("Attempted to restore cachedblobstore state but failed: %v", err)
-	}
+	rcfg := resilientblobstore.DefaultConfig()
+	rcfg.MaxRetries = cfg.BackendMaxRetries
+	rcfg.Timeout = time.Duration(cfg.BackendTimeout) * time.Second
+	rcfg.BreakerThreshold = cfg.BackendBreakerThreshold
+	rcfg.BreakerCooldown = time.Duration(cfg.BackendBreakerCooldown) * time.Second
+	o.ResilientBS = resilientblobstore.New(o.BackendBS, rcfg)
+	o.BackendBS = o.ResilientBS
 
-	if o.R != nil {
-		o.AutoReduceCacheJob = cachedblobstore.SetupAutoReduceCache(o.CBS, o.R, cfg.CacheHighWatermarkInBytes, cfg.CacheLowWatermarkInBytes)
-		if !o.ReadOnly {
-			o.SaveStateJob = o.R.RunEveryPeriod(cachedblobstore.SaveStateTask{o.CBS, o.C}, 30*time.Second)
-		}
+	return nil
+}
+
+func (o *Otaru) initINodeDBIO(cfg *Config, flags int) error {
+	if err := o.initINodeDBLogIO(cfg, flags); err != nil {
+		return err
 	}
+	o.SIO = blobstoredbstatesnapshotio.New(o.CBS, o.C, o.SSLoc)
+	o.CTxIO = inodedb.NewCachedDBTransactionLogIO(o.TxIO)
 
 	return nil
 }
 
-func (o *Otaru) initINodeDBIO(cfg *Config, flags int) error {
-	if !cfg.LocalDebug {
+// initINodeDBLogIO initializes the snapshot locator and the transaction log, which don't depend on blobstores.
+func (o *Otaru) initINodeDBLogIO(cfg *Config, flags int) error {
+	if o.DSCfg != nil {
 		o.SSLoc = datastore.NewINodeDBSSLocator(o.DSCfg, flags)
 	} else {
 		o.SSLoc = blobstoredbstatesnapshotio.SimpleSSLocator{}
 	}
-	o.SIO = blobstoredbstatesnapshotio.New(o.CBS, o.C, o.SSLoc)
 
-	if !cfg.LocalDebug {
+	if o.DSCfg != nil {
 		txio := datastore.NewDBTransactionLogIO(o.DSCfg, flags)
 		o.TxIO = txio
 		if o.R != nil && !cfg.ReadOnly {
@@ -309,15 +521,26 @@ func (o *Otaru) initINodeDBIO(cfg *Config, flags int) error {
 	} else {
 		o.TxIO = inodedb.NewSimpleDBTransactionLogIO()
 	}
-	o.CTxIO = inodedb.NewCachedDBTransactionLogIO(o.TxIO)
 
 	return nil
 }
 
+// isWriteFenced reports if writes are refused, as the lease of GL is expiring.
+// Close still flushes the changes accepted before that.
+func (o *Otaru) isWriteFenced() bool {
+	return o.leaseExpiring.Load() && !o.closing.Load()
+}
+
 func (o *Otaru) Close() error {
 	var me error
 	ctx := context.Background()
 
+	// Don't write anything, as the new lock holder may be writing. An
+	// expiring lease doesn't stop the flush, as no other writer is known to
+	// hold the lock.
+	flush := !o.ReadOnly && !o.leaseLost.Load()
+	o.closing.Store(true)
+
 	if o.R != nil {
 		o.R.Stop()
 	}
@@ -326,24 +549,31 @@ func (o *Otaru) Close() error {
 		o.S.AbortAllAndStop()
 	}
 
-	if o.FS != nil && !o.ReadOnly {
+	if o.FS != nil && flush {
 		if err := o.FS.Sync(); err != nil {
 			me = multierr.Append(me, err)
 		}
 	}
 
+	if o.AuditLog != nil {
+		// Wait for the archive of the rotated logs before closing CBS.
+		if err := o.AuditLog.Close(); err != nil {
+			me = multierr.Append(me, err)
+		}
+	}
+
 	if o.IDBS != nil {
 		o.IDBS.Quit()
 	}
 
-	if o.IDBBE != nil && !o.ReadOnly {
+	if o.IDBBE != nil && flush {
 		if err := o.IDBBE.Sync(); err != nil {
 			me = multierr.Append(me, err)
 		}
 	}
 
 	if o.CBS != nil {
-		if !o.ReadOnly {
+		if flush {
 			if err := o.CBS.SaveState(o.C); err != nil {
 				me = multierr.Append(me, err)
 			}
@@ -353,7 +583,16 @@ func (o *Otaru) Close() error {
 		}
 	}
 
-	if o.GL != nil && !o.ReadOnly {
+	if o.ResilientBS != nil {
+		// Interrupt the retry backoffs still in progress. Done after CBS.Quit,
+		// so that the final writebacks get all their retries.
+		o.ResilientBS.Quit()
+	}
+
+	if o.LeaseRenewer != nil {
+		o.LeaseRenewer.Stop()
+	}
+	if o.GL != nil && flush {
 		if err := o.GL.Unlock(ctx); err != nil {
 			me = multierr.Append(me, err)
 		}
@@ -379,3 +618,15 @@ func (o *Otaru) GetINodeDBTxLogGCTask(dryrun bool) scheduler.Task {
 func (o *Otaru) GetINodeDBSSGCTask(dryrun bool) scheduler.Task {
 	return &inodedbssgc.Task{o.SIO, dryrun}
 }
+
+func (o *Otaru) GetTierTask(minAge time.Duration, dryrun bool) *tieredblobstore.TierTask {
+	return &tieredblobstore.TierTask{o.TieredBS, o.IDBS, o.CBS, minAge, dryrun}
+}
+
+func (o *Otaru) GetScrubTask() *scrub.Task {
+	return &scrub.Task{o.BackendBS, o.C, o.IDBS, o.CBS}
+}
+
+func (o *Otaru) GetCompactionTask(dryrun bool) *compaction.Task {
+	return &compaction.Task{o.FS, o.IDBS, compaction.DefaultMinExcessChunks, dryrun}
+}
@@ -0,0 +1,175 @@
+package facade_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/cli"
+	"github.com/nyaxt/otaru/facade"
+	tu "github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/testutils/fakegcs"
+	"github.com/nyaxt/otaru/testutils/testca"
+)
+
+func init() {
+	tu.EnsureLogger()
+	// Serve is started multiple times in the process.
+	cachedblobstore.PrometheusRegisterer = nil
+}
+
+const testListenAddr = "localhost:30249"
+
+func testConfig(t *testing.T, gcsEndpoint string) *facade.Config {
+	tmpdir := t.TempDir()
+	return &facade.Config{
+		ProjectName:               "otaru-test",
+		BucketName:                "otaru-e2e",
+		Password:                  "otaru-e2e-password",
+		CacheDir:                  filepath.Join(tmpdir, "cache"),
+		CacheHighWatermarkInBytes: math.MaxInt64,
+		CacheLowWatermarkInBytes:  math.MaxInt64,
+		GCSEndpoint:               gcsEndpoint,
+		LocalDatastorePath:        filepath.Join(tmpdir, "datastore.gob"),
+		Logger:                    zap.L(),
+		ApiServer: facade.ApiServerConfig{
+			ListenAddr:   testListenAddr,
+			Certs:        testca.Certs,
+			Key:          testca.Key.Parsed,
+			ClientCACert: testca.ClientAuthCACert,
+		},
+	}
+}
+
+func testCliConfig() *cli.CliConfig {
+	return &cli.CliConfig{
+		Host: map[string]*cli.Host{
+			"default": {
+				ApiEndpoint: testListenAddr,
+				CACert:      testca.CACert,
+				Certs:       testca.ClientAuthAdminCerts,
+				Key:         testca.ClientAuthAdminKey.Parsed,
+			},
+		},
+	}
+}
+
+// startServe runs facade.Serve until the returned stop func is called.
+func startServe(t *testing.T, cfg *facade.Config) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errC := make(chan error, 1)
+	go func() {
+		errC <- facade.Serve(ctx, cfg)
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		resp, err := testca.TLSHTTPClient.Get("https://" + testListenAddr + "/healthz")
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		select {
+		case err := <-errC:
+			cancel()
+			t.Fatalf("Serve failed: %v", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			cancel()
+			t.Fatalf("Timed out waiting for apiserver: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return func() {
+		cancel()
+		if err := <-errC; err != nil {
+			t.Errorf("Serve failed: %v", err)
+		}
+	}
+}
+
+func TestMkfsServe(t *testing.T) {
+	gcs := fakegcs.NewServer()
+	defer gcs.Close()
+
+	cfg := testConfig(t, gcs.URL())
+	if err := facade.Mkfs(cfg); err != nil {
+		t.Fatalf("Mkfs failed: %v", err)
+	}
+
+	ctx := context.Background()
+	ccfg := testCliConfig()
+
+	stop := startServe(t, cfg)
+	{
+		w, err := cli.NewWriter("otaru://default/hello.txt", cli.WithCliConfig(ccfg), cli.WithContext(ctx))
+		if err != nil {
+			stop()
+			t.Fatalf("NewWriter failed: %v", err)
+		}
+		if _, err := w.Write(tu.HelloWorld); err != nil {
+			t.Errorf("Write failed: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+	}
+	stop()
+
+	if len(gcs.ObjectNames(cfg.BucketName)) == 0 {
+		t.Errorf("No blobs were written to the GCS bucket.")
+	}
+
+	// Start from an empty cache, so that contents are fetched from GCS.
+	if err := os.RemoveAll(cfg.CacheDir); err != nil {
+		t.Fatalf("Failed to remove cache dir: %v", err)
+	}
+
+	if _, err := exec.LookPath("fusermount"); err == nil {
+		cfg.FuseMountPoint = t.TempDir()
+	}
+	stop = startServe(t, cfg)
+	defer stop()
+
+	r, err := cli.NewReader("otaru://default/hello.txt", cli.WithCliConfig(ccfg), cli.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	b, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if string(b) != string(tu.HelloWorld) {
+		t.Errorf("Unexpected content read via API: %q", b)
+	}
+
+	if cfg.FuseMountPoint == "" {
+		t.Logf("fusermount not found. Skipping FUSE checks.")
+		return
+	}
+	// fuse.Serve mounts asynchronously.
+	var fb []byte
+	for i := 0; i < 100; i++ {
+		if fb, err = os.ReadFile(filepath.Join(cfg.FuseMountPoint, "hello.txt")); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read file via FUSE: %v", err)
+	}
+	if string(fb) != string(tu.HelloWorld) {
+		t.Errorf("Unexpected content read via FUSE: %q", fb)
+	}
+}
@@ -0,0 +1,233 @@
+package filesystem
+
+import (
+	"fmt"
+
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/util"
+)
+
+// AccessMode is a combination of the permissions requested, as the mask of access(2).
+type AccessMode uint32
+
+const (
+	AccessExec  AccessMode = 1 // X_OK
+	AccessWrite AccessMode = 2 // W_OK
+	AccessRead  AccessMode = 4 // R_OK
+)
+
+func (m AccessMode) String() string {
+	ret := []byte("---")
+	if m&AccessRead != 0 {
+		ret[0] = 'r'
+	}
+	if m&AccessWrite != 0 {
+		ret[1] = 'w'
+	}
+	if m&AccessExec != 0 {
+		ret[2] = 'x'
+	}
+	return string(ret)
+}
+
+// Caller is the local identity of a process accessing the filesystem.
+type Caller struct {
+	Uid uint32
+	Gid uint32
+	// Supplementary groups of the process.
+	Groups []uint32
+}
+
+func (c Caller) IsRoot() bool { return c.Uid == 0 }
+
+func (c Caller) InGroup(gid uint32) bool {
+	if c.Gid == gid {
+		return true
+	}
+	for _, g := range c.Groups {
+		if g == gid {
+			return true
+		}
+	}
+	return false
+}
+
+// permBits returns the rwx bits of permmode which apply to the caller, as the owner, group or other.
+func (c Caller) permBits(a Attr) AccessMode {
+	switch {
+	case c.Uid == a.Uid:
+		return AccessMode(a.PermMode>>6) & 7
+	case c.InGroup(a.Gid):
+		return AccessMode(a.PermMode>>3) & 7
+	default:
+		return AccessMode(a.PermMode) & 7
+	}
+}
+
+// CheckAccess returns EACCES unless the caller has all the permissions in mode on the node.
+// As in POSIX, root is allowed everything but executing a file without any execute bit.
+func (fs *FileSystem) CheckAccess(id inodedb.ID, c Caller, mode AccessMode) error {
+	a, err := fs.Attr(id)
+	if err != nil {
+		return err
+	}
+	return checkAccess(a, c, mode)
+}
+
+func checkAccess(a Attr, c Caller, mode AccessMode) error {
+	if c.IsRoot() {
+		if mode&AccessExec != 0 && a.Type != inodedb.DirNodeT && a.PermMode&0111 == 0 {
+			return util.EACCES
+		}
+		return nil
+	}
+	if mode&^c.permBits(a) != 0 {
+		return util.EACCES
+	}
+	return nil
+}
+
+// CheckSetAttr returns an error unless the caller may update the attributes of the node:
+// Only root may change the owner. The owner may change the group to the one it belongs to,
+// and the permissions and times. Others with write access may only set the times to now.
+func (fs *FileSystem) CheckSetAttr(id inodedb.ID, c Caller, a Attr, valid ValidAttrFields) error {
+	if c.IsRoot() || valid&^TimesSetToNow == 0 {
+		return nil
+	}
+
+	cur, err := fs.Attr(id)
+	if err != nil {
+		return err
+	}
+	if valid&UidValid != 0 && a.Uid != cur.Uid {
+		return util.EPERM
+	}
+	if valid&(GidValid|PermModeValid|ModifiedTValid|AccessedTValid) == 0 {
+		return nil
+	}
+	if c.Uid != cur.Uid {
+		// Updating the times to now only, as touch(1), is allowed with write access.
+		if valid&(GidValid|PermModeValid) == 0 && valid&TimesSetToNow != 0 {
+			return checkAccess(cur, c, AccessWrite)
+		}
+		return util.EPERM
+	}
+	if valid&GidValid != 0 && a.Gid != cur.Gid && !c.InGroup(a.Gid) {
+		return util.EPERM
+	}
+	return nil
+}
+
+// StickyBit of PermMode restricts removing and renaming the entries of a directory to their owners.
+const StickyBit = 01000
+
+// CheckRemoveEntry returns EPERM if the caller may not remove nor rename the entry name of the directory.
+// In a directory with the sticky bit, only root and the owners of the directory or the entry may do so.
+// The caller is expected to have checked the write access to the directory.
+func (fs *FileSystem) CheckRemoveEntry(dirID inodedb.ID, name string, c Caller) error {
+	if c.IsRoot() {
+		return nil
+	}
+
+	dir, err := fs.Attr(dirID)
+	if err != nil {
+		return err
+	}
+	if dir.PermMode&StickyBit == 0 || c.Uid == dir.Uid {
+		return nil
+	}
+
+	entries, err := fs.DirEntries(dirID)
+	if err != nil {
+		return err
+	}
+	id, ok := entries[name]
+	if !ok {
+		// Let the operation itself report ENOENT.
+		return nil
+	}
+	a, err := fs.Attr(id)
+	if err != nil {
+		return err
+	}
+	if c.Uid != a.Uid {
+		return util.EPERM
+	}
+	return nil
+}
+
+// IDMap translates the uid/gid stored in the filesystem to the local ones of
+// this machine. Ids not in the map are kept as is.
+type IDMap struct {
+	// Uid maps a stored uid to the local uid.
+	Uid map[uint32]uint32
+	// Gid maps a stored gid to the local gid.
+	Gid map[uint32]uint32
+
+	revUid map[uint32]uint32
+	revGid map[uint32]uint32
+}
+
+func reverseIDs(m map[uint32]uint32) (map[uint32]uint32, error) {
+	rev := make(map[uint32]uint32, len(m))
+	for stored, local := range m {
+		if prev, ok := rev[local]; ok {
+			return nil, fmt.Errorf("Both ids %d and %d are mapped to %d", prev, stored, local)
+		}
+		rev[local] = stored
+	}
+	return rev, nil
+}
+
+func NewIDMap(uid, gid map[uint32]uint32) (*IDMap, error) {
+	revUid, err := reverseIDs(uid)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid uid map: %v", err)
+	}
+	revGid, err := reverseIDs(gid)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid gid map: %v", err)
+	}
+	return &IDMap{Uid: uid, Gid: gid, revUid: revUid, revGid: revGid}, nil
+}
+
+func mapID(m map[uint32]uint32, id uint32) uint32 {
+	if mapped, ok := m[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+func (m *IDMap) LocalUid(stored uint32) uint32 {
+	if m == nil {
+		return stored
+	}
+	return mapID(m.Uid, stored)
+}
+
+func (m *IDMap) LocalGid(stored uint32) uint32 {
+	if m == nil {
+		return stored
+	}
+	return mapID(m.Gid, stored)
+}
+
+func (m *IDMap) StoredUid(local uint32) uint32 {
+	if m == nil {
+		return local
+	}
+	return mapID(m.revUid, local)
+}
+
+func (m *IDMap) StoredGid(local uint32) uint32 {
+	if m == nil {
+		return local
+	}
+	return mapID(m.revGid, local)
+}
+
+// SetIDMap sets the uid/gid mapping applied to the attributes of the nodes.
+// The uid/gid passed to and returned from FileSystem are local ones.
+func (fs *FileSystem) SetIDMap(m *IDMap) {
+	fs.idmap = m
+}
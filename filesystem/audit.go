@@ -0,0 +1,181 @@
+package filesystem
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/nyaxt/otaru/auditlog"
+	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/inodedb"
+)
+
+const (
+	AuditOpCreateFile = "create_file"
+	AuditOpCreateDir  = "create_dir"
+	AuditOpRemove     = "remove"
+	AuditOpRename     = "rename"
+	AuditOpSetAttr    = "setattr"
+	AuditOpTruncate   = "truncate"
+	AuditOpWrite      = "write"
+)
+
+// SetAuditRecorder sets the recorder of the mutations made via Audited().
+func (fs *FileSystem) SetAuditRecorder(r auditlog.Recorder) {
+	fs.auditRecorder = r
+}
+
+// AuditedFileSystem applies mutations to the FileSystem on behalf of the actor,
+// and records them to the audit log.
+type AuditedFileSystem struct {
+	fs    *FileSystem
+	actor auditlog.Actor
+}
+
+func (fs *FileSystem) Audited(actor auditlog.Actor) *AuditedFileSystem {
+	return &AuditedFileSystem{fs: fs, actor: actor}
+}
+
+// pathForAudit returns the current path of the node. Nodes no longer
+// reachable from the root dir are recorded by their id.
+func (fs *FileSystem) pathForAudit(id inodedb.ID) string {
+	p, err := fs.CurrentPath(id)
+	if err != nil {
+		return fmt.Sprintf("<id %d>", id)
+	}
+	return p
+}
+
+func (afs *AuditedFileSystem) record(op, p, newp string, id inodedb.ID, txid inodedb.TxID, err error) {
+	r := afs.fs.auditRecorder
+	if r == nil {
+		return
+	}
+
+	e := auditlog.Entry{
+		Time:    time.Now(),
+		Source:  afs.actor.Source,
+		User:    afs.actor.User,
+		Op:      op,
+		Path:    p,
+		NewPath: newp,
+		ID:      id,
+		TxID:    txid,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.Record(e)
+}
+
+func (afs *AuditedFileSystem) childPath(dirID inodedb.ID, name string) string {
+	return path.Join(afs.fs.pathForAudit(dirID), name)
+}
+
+func (afs *AuditedFileSystem) createNode(dirID inodedb.ID, name string, typ inodedb.Type, permmode uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
+	id, txid, err := afs.fs.createNode(dirID, name, typ, permmode, uid, gid, modifiedT)
+
+	op := AuditOpCreateFile
+	if typ == inodedb.DirNodeT {
+		op = AuditOpCreateDir
+	}
+	afs.record(op, afs.childPath(dirID, name), "", id, txid, err)
+	return id, err
+}
+
+func (afs *AuditedFileSystem) CreateFile(dirID inodedb.ID, name string, permmode uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
+	return afs.createNode(dirID, name, inodedb.FileNodeT, permmode, uid, gid, modifiedT)
+}
+
+func (afs *AuditedFileSystem) CreateDir(dirID inodedb.ID, name string, permmode uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
+	return afs.createNode(dirID, name, inodedb.DirNodeT, permmode, uid, gid, modifiedT)
+}
+
+func (afs *AuditedFileSystem) createNodeFullPath(fullpath string, typ inodedb.Type, perm uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
+	if len(fullpath) < 1 || fullpath[0] != '/' {
+		return 0, fmt.Errorf("Path must start with /, but given: %v", fullpath)
+	}
+
+	parent := filepath.Dir(fullpath)
+	dirID, err := afs.fs.FindNodeFullPath(parent)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to find parent \"%s\": %v", parent, err)
+	}
+
+	return afs.createNode(dirID, filepath.Base(fullpath), typ, perm, uid, gid, modifiedT)
+}
+
+func (afs *AuditedFileSystem) CreateFileFullPath(fullpath string, perm uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
+	return afs.createNodeFullPath(fullpath, inodedb.FileNodeT, perm&0777, uid, gid, modifiedT)
+}
+
+func (afs *AuditedFileSystem) CreateDirFullPath(fullpath string, perm uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
+	return afs.createNodeFullPath(fullpath, inodedb.DirNodeT, perm, uid, gid, modifiedT)
+}
+
+func (afs *AuditedFileSystem) Remove(dirID inodedb.ID, name string) error {
+	txid, err := afs.fs.remove(dirID, name)
+	afs.record(AuditOpRemove, afs.childPath(dirID, name), "", 0, txid, err)
+	return err
+}
+
+func (afs *AuditedFileSystem) Rename(srcDirID inodedb.ID, srcName string, dstDirID inodedb.ID, dstName string) error {
+	txid, err := afs.fs.rename(srcDirID, srcName, dstDirID, dstName)
+	afs.record(AuditOpRename, afs.childPath(srcDirID, srcName), afs.childPath(dstDirID, dstName), 0, txid, err)
+	return err
+}
+
+func (afs *AuditedFileSystem) SetAttr(id inodedb.ID, a Attr, valid ValidAttrFields) error {
+	txid, err := afs.fs.setAttr(id, a, valid)
+	afs.record(AuditOpSetAttr, afs.fs.pathForAudit(id), "", id, txid, err)
+	return err
+}
+
+func (afs *AuditedFileSystem) TruncateFile(id inodedb.ID, newsize int64) error {
+	err := afs.fs.TruncateFile(id, newsize)
+	afs.record(AuditOpTruncate, afs.fs.pathForAudit(id), "", id, 0, err)
+	return err
+}
+
+// OpenFile opens the file as FileSystem.OpenFile. If the file is modified via
+// the returned handle, a write is recorded when the handle is closed.
+func (afs *AuditedFileSystem) OpenFile(id inodedb.ID, flags int) (*FileHandle, error) {
+	fh, err := afs.fs.OpenFile(id, flags)
+	if err != nil {
+		if fl.IsWriteAllowed(flags) {
+			afs.record(AuditOpWrite, afs.fs.pathForAudit(id), "", id, 0, err)
+		}
+		return nil, err
+	}
+
+	if fl.IsWriteAllowed(flags) {
+		fh.audit = &handleAudit{afs: afs, id: id}
+		if fl.IsWriteTruncate(flags) {
+			fh.audit.markWritten()
+		}
+	}
+	return fh, nil
+}
+
+// handleAudit tracks if a FileHandle opened via AuditedFileSystem modified the file.
+type handleAudit struct {
+	afs     *AuditedFileSystem
+	id      inodedb.ID
+	written int32
+}
+
+func (ha *handleAudit) markWritten() {
+	if ha == nil {
+		return
+	}
+	atomic.StoreInt32(&ha.written, 1)
+}
+
+func (ha *handleAudit) closed() {
+	if ha == nil || atomic.SwapInt32(&ha.written, 0) == 0 {
+		return
+	}
+	ha.afs.record(AuditOpWrite, ha.afs.fs.pathForAudit(ha.id), "", ha.id, 0, nil)
+}
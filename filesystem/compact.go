@@ -0,0 +1,120 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nyaxt/otaru/chunkstore"
+	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/util"
+)
+
+const compactBufLen = 1024 * 1024
+
+func (fs *FileSystem) isOpenForWriting(id inodedb.ID) bool {
+	fs.muOpenFiles.Lock()
+	of, ok := fs.openFiles[id]
+	fs.muOpenFiles.Unlock()
+	if !ok {
+		return false
+	}
+
+	of.mu.Lock()
+	defer of.mu.Unlock()
+	return of.hasWriteHandleWithoutLock(nil)
+}
+
+// hasWriteHandleWithoutLock returns true if of has a write handle other than except.
+func (of *OpenFile) hasWriteHandleWithoutLock(except *FileHandle) bool {
+	for _, h := range of.handles {
+		if h != except && fl.IsWriteAllowed(h.flags) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteChunksWithLock copies the content of cs into new chunks, and returns the new chunk list.
+// Ranges not covered by cs are kept as holes, except in between chunks sharing a chunk split.
+func (of *OpenFile) rewriteChunksWithLock(cs []inodedb.FileChunk) ([]inodedb.FileChunk, error) {
+	caio := chunkstore.NewSimpleDBChunksArrayIO()
+	dst := chunkstore.NewChunkedFileIO(of.fs.bs, of.fs.c, caio)
+	dst.SetOrigFilename(of.fs.tryGetOrigPath(of.nlock.ID))
+	defer dst.Close()
+
+	buf := make([]byte, compactBufLen)
+	for _, c := range cs {
+		for o := c.Left(); o < c.Right(); {
+			p := buf[:util.Int64Min(int64(len(buf)), c.Right()-o)]
+			n, err := of.cfio.ReadAt(p, o)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read chunk %+v: %w", c, err)
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("Unexpected zero length read of chunk %+v at offset %d", c, o)
+			}
+			if err := dst.PWrite(p[:n], o); err != nil {
+				return nil, fmt.Errorf("Failed to write compacted chunk at offset %d: %w", o, err)
+			}
+			o += int64(n)
+		}
+	}
+	return caio.Cs, nil
+}
+
+// CompactFile rewrites the content of the file into maximal-size chunks, and
+// swaps the chunk list with a single UpdateChunksOp. It returns false
+// without compacting if the file is open for writing.
+// The old chunk blobs are left to be removed by GC.
+func (fs *FileSystem) CompactFile(id inodedb.ID) (bool, error) {
+	if !fl.IsWriteAllowed(fs.bs.Flags()) {
+		return false, util.EACCES
+	}
+	if fs.isOpenForWriting(id) {
+		return false, nil
+	}
+
+	fh, err := fs.OpenFile(id, fl.O_RDWR)
+	if err != nil {
+		if errors.Is(err, inodedb.ErrLockTaken) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer fh.Close()
+
+	of := fh.of
+	of.mu.Lock()
+	defer of.mu.Unlock()
+
+	// The file may be opened for writing in between.
+	if of.hasWriteHandleWithoutLock(fh) {
+		return false, nil
+	}
+
+	cs, err := NewINodeDBChunksArrayIO(fs.idb, of.nlock).Read()
+	if err != nil {
+		return false, err
+	}
+	newcs, err := of.rewriteChunksWithLock(cs)
+	if err != nil {
+		return false, err
+	}
+
+	tx := inodedb.DBTransaction{Ops: []inodedb.DBOperation{
+		&inodedb.UpdateChunksOp{NodeLock: of.nlock, Chunks: newcs},
+	}}
+	if _, err := fs.idb.ApplyTransaction(tx); err != nil {
+		return false, fmt.Errorf("Failed to apply tx for swapping chunks: %w", err)
+	}
+	fs.logger.Sugar().Infof("Compacted file %d: %d chunks -> %d chunks", id, len(cs), len(newcs))
+
+	if err := of.cfio.Close(); err != nil {
+		fs.logger.Sugar().Warnf("Closing ChunkedFileIO of the old chunks failed: %v", err)
+	}
+	of.cfio = chunkstore.NewChunkedFileIO(fs.bs, fs.c, NewINodeDBChunksArrayIO(fs.idb, of.nlock))
+	of.cfio.SetOrigFilename(fs.tryGetOrigPath(id))
+
+	return true, nil
+}
@@ -2,6 +2,7 @@ package filesystem
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"github.com/nyaxt/otaru/auditlog"
 	"github.com/nyaxt/otaru/blobstore"
 	"github.com/nyaxt/otaru/btncrypt"
 	"github.com/nyaxt/otaru/chunkstore"
@@ -30,6 +32,11 @@ type FileSystem struct {
 	muOrigPath sync.Mutex
 	origpath   map[inodedb.ID]string
 
+	relatime bool
+	idmap    *IDMap
+
+	auditRecorder auditlog.Recorder
+
 	logger *zap.Logger
 }
 
@@ -49,6 +56,34 @@ func NewFileSystem(idb inodedb.DBHandler, bs blobstore.RandomAccessBlobStore, c
 	return fs
 }
 
+// SetRelatime enables updating the access time of files on reads.
+// As relatime, the access time is only updated if it is older than the
+// modified or changed time, or by RelatimeInterval.
+func (fs *FileSystem) SetRelatime(enabled bool) {
+	fs.relatime = enabled
+}
+
+const RelatimeInterval = 24 * time.Hour
+
+func (fs *FileSystem) touchAccessedT(id inodedb.ID, now time.Time) error {
+	v, _, err := fs.idb.QueryNode(id, false)
+	if err != nil {
+		return err
+	}
+	at := v.GetAccessedT()
+	if at.After(v.GetModifiedT()) && at.After(v.GetChangeT()) && now.Sub(at) < RelatimeInterval {
+		return nil
+	}
+
+	tx := inodedb.DBTransaction{Ops: []inodedb.DBOperation{
+		&inodedb.UpdateAccessedTOp{ID: id, AccessedT: now},
+	}}
+	if _, err := fs.idb.ApplyTransaction(tx); err != nil {
+		return fmt.Errorf("Failed to update AccessedT: %w", err)
+	}
+	return nil
+}
+
 type FileSystemStats struct {
 	NumOpenFiles int `json:"num_open_files"`
 	NumOrigPath  int `json:"num_orig_path"`
@@ -105,7 +140,7 @@ func (fs *FileSystem) Sync() error {
 	var me error
 	if s, ok := fs.idb.(util.Syncer); ok {
 		if err := s.Sync(); err != nil {
-			me = multierr.Append(me, fmt.Errorf("Failed to sync INodeDB: %v", err))
+			me = multierr.Append(me, fmt.Errorf("Failed to sync INodeDB: %w", err))
 		}
 	}
 
@@ -158,40 +193,52 @@ func (fs *FileSystem) DirEntries(id inodedb.ID) (map[string]inodedb.ID, error) {
 }
 
 func (fs *FileSystem) Rename(srcDirID inodedb.ID, srcName string, dstDirID inodedb.ID, dstName string) error {
+	_, err := fs.rename(srcDirID, srcName, dstDirID, dstName)
+	return err
+}
+
+func (fs *FileSystem) rename(srcDirID inodedb.ID, srcName string, dstDirID inodedb.ID, dstName string) (inodedb.TxID, error) {
 	tx := inodedb.DBTransaction{Ops: []inodedb.DBOperation{
 		&inodedb.RenameOp{
 			SrcDirID: srcDirID, SrcName: srcName,
 			DstDirID: dstDirID, DstName: dstName,
 		},
 	}}
-	if _, err := fs.idb.ApplyTransaction(tx); err != nil {
-		return err
+	txid, err := fs.idb.ApplyTransaction(tx)
+	if err != nil {
+		return 0, err
 	}
 
 	// FIXME: fs.setOrigPathForId
 
-	return nil
+	return txid, nil
 }
 
 func (fs *FileSystem) Remove(dirID inodedb.ID, name string) error {
+	_, err := fs.remove(dirID, name)
+	return err
+}
+
+func (fs *FileSystem) remove(dirID inodedb.ID, name string) (inodedb.TxID, error) {
 	tx := inodedb.DBTransaction{Ops: []inodedb.DBOperation{
 		&inodedb.RemoveOp{
 			NodeLock: inodedb.NodeLock{dirID, inodedb.NoTicket}, Name: name,
 		},
 	}}
-	if _, err := fs.idb.ApplyTransaction(tx); err != nil {
-		return err
+	txid, err := fs.idb.ApplyTransaction(tx)
+	if err != nil {
+		return 0, err
 	}
 
 	// FIXME: fs.setOrigPathForId
 
-	return nil
+	return txid, nil
 }
 
-func (fs *FileSystem) createNode(dirID inodedb.ID, name string, typ inodedb.Type, permmode uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
+func (fs *FileSystem) createNode(dirID inodedb.ID, name string, typ inodedb.Type, permmode uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, inodedb.TxID, error) {
 	nlock, err := fs.idb.LockNode(inodedb.AllocateNewNodeID)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer func() {
 		if err := fs.idb.UnlockNode(nlock); err != nil {
@@ -203,36 +250,44 @@ func (fs *FileSystem) createNode(dirID inodedb.ID, name string, typ inodedb.Type
 	origpath := fmt.Sprintf("%s/%s", dirorigpath, name)
 
 	tx := inodedb.DBTransaction{Ops: []inodedb.DBOperation{
-		&inodedb.CreateNodeOp{NodeLock: nlock, OrigPath: origpath, ParentID: dirID, Type: typ, PermMode: permmode, Uid: uid, Gid: gid, ModifiedT: modifiedT},
+		&inodedb.CreateNodeOp{NodeLock: nlock, OrigPath: origpath, ParentID: dirID, Type: typ, PermMode: permmode, Uid: fs.idmap.StoredUid(uid), Gid: fs.idmap.StoredGid(gid), ModifiedT: modifiedT},
 		&inodedb.HardLinkOp{NodeLock: inodedb.NodeLock{dirID, inodedb.NoTicket}, Name: name, TargetID: nlock.ID},
 	}}
-	if _, err := fs.idb.ApplyTransaction(tx); err != nil {
-		return 0, err
+	txid, err := fs.idb.ApplyTransaction(tx)
+	if err != nil {
+		return 0, 0, err
 	}
 
 	fs.setOrigPathForId(nlock.ID, origpath)
 
-	return nlock.ID, nil
+	return nlock.ID, txid, nil
 }
 
 func (fs *FileSystem) CreateFile(dirID inodedb.ID, name string, permmode uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
-	return fs.createNode(dirID, name, inodedb.FileNodeT, permmode, uid, gid, modifiedT)
+	id, _, err := fs.createNode(dirID, name, inodedb.FileNodeT, permmode, uid, gid, modifiedT)
+	return id, err
 }
 
 func (fs *FileSystem) CreateDir(dirID inodedb.ID, name string, permmode uint16, uid, gid uint32, modifiedT time.Time) (inodedb.ID, error) {
-	return fs.createNode(dirID, name, inodedb.DirNodeT, permmode, uid, gid, modifiedT)
+	id, _, err := fs.createNode(dirID, name, inodedb.DirNodeT, permmode, uid, gid, modifiedT)
+	return id, err
 }
 
 type Attr struct {
 	ID   inodedb.ID   `json:"id"`
 	Type inodedb.Type `json:"type"`
 	Size int64        `json:"size"`
+	// Total size of the chunks backing the file, excluding holes.
+	AllocatedSize int64 `json:"allocated_size"`
 
 	OrigPath  string    `json:"orig_path"`
 	Uid       uint32    `json:"uid"`
 	Gid       uint32    `json:"gid"`
 	PermMode  uint16    `json:"mode_perm"`
 	ModifiedT time.Time `json:"modified_t"`
+	ChangeT   time.Time `json:"change_t"`
+	CreatedT  time.Time `json:"created_t"`
+	AccessedT time.Time `json:"accessed_t"`
 }
 
 func (fs *FileSystem) Attr(id inodedb.ID) (Attr, error) {
@@ -242,20 +297,26 @@ func (fs *FileSystem) Attr(id inodedb.ID) (Attr, error) {
 	}
 
 	size := int64(0)
+	allocated := int64(0)
 	if fn, ok := v.(*inodedb.FileNodeView); ok {
 		size = fn.Size
+		allocated = chunkstore.AllocatedSize(fn.Chunks)
 	}
 
 	a := Attr{
-		ID:   v.GetID(),
-		Type: v.GetType(),
-		Size: size,
+		ID:            v.GetID(),
+		Type:          v.GetType(),
+		Size:          size,
+		AllocatedSize: allocated,
 
 		OrigPath:  v.GetOrigPath(),
-		Uid:       v.GetUid(),
-		Gid:       v.GetGid(),
+		Uid:       fs.idmap.LocalUid(v.GetUid()),
+		Gid:       fs.idmap.LocalGid(v.GetGid()),
 		PermMode:  fl.MaskPermMode(v.GetPermMode(), fs.bs.Flags()),
 		ModifiedT: v.GetModifiedT(),
+		ChangeT:   v.GetChangeT(),
+		CreatedT:  v.GetCreatedT(),
+		AccessedT: v.GetAccessedT(),
 	}
 	return a, nil
 }
@@ -267,6 +328,10 @@ const (
 	GidValid
 	PermModeValid
 	ModifiedTValid
+	AccessedTValid
+	// TimesSetToNow tells that the times requested are the current time, as
+	// touch(1) without -d. It doesn't update any attribute by itself.
+	TimesSetToNow
 )
 
 func (valid ValidAttrFields) String() string {
@@ -284,6 +349,12 @@ func (valid ValidAttrFields) String() string {
 	if valid&ModifiedTValid != 0 {
 		b.WriteString("ModifiedTValid|")
 	}
+	if valid&AccessedTValid != 0 {
+		b.WriteString("AccessedTValid|")
+	}
+	if valid&TimesSetToNow != 0 {
+		b.WriteString("TimesSetToNow|")
+	}
 	// trim last "|"
 	if b.Len() > 0 {
 		b.Truncate(b.Len() - 1)
@@ -293,14 +364,19 @@ func (valid ValidAttrFields) String() string {
 }
 
 func (fs *FileSystem) SetAttr(id inodedb.ID, a Attr, valid ValidAttrFields) error {
+	_, err := fs.setAttr(id, a, valid)
+	return err
+}
+
+func (fs *FileSystem) setAttr(id inodedb.ID, a Attr, valid ValidAttrFields) (inodedb.TxID, error) {
 	fs.logger.Sugar().Infof("SetAttr id: %d, a: %+v, valid: %s", id, a, valid)
 
 	ops := make([]inodedb.DBOperation, 0, 4)
 	if valid&UidValid != 0 {
-		ops = append(ops, &inodedb.UpdateUidOp{ID: id, Uid: a.Uid})
+		ops = append(ops, &inodedb.UpdateUidOp{ID: id, Uid: fs.idmap.StoredUid(a.Uid)})
 	}
 	if valid&GidValid != 0 {
-		ops = append(ops, &inodedb.UpdateGidOp{ID: id, Gid: a.Gid})
+		ops = append(ops, &inodedb.UpdateGidOp{ID: id, Gid: fs.idmap.StoredGid(a.Gid)})
 	}
 	if valid&PermModeValid != 0 {
 		ops = append(ops, &inodedb.UpdatePermModeOp{ID: id, PermMode: a.PermMode})
@@ -308,11 +384,22 @@ func (fs *FileSystem) SetAttr(id inodedb.ID, a Attr, valid ValidAttrFields) erro
 	if valid&ModifiedTValid != 0 {
 		ops = append(ops, &inodedb.UpdateModifiedTOp{ID: id, ModifiedT: a.ModifiedT})
 	}
+	if valid&AccessedTValid != 0 {
+		ops = append(ops, &inodedb.UpdateAccessedTOp{ID: id, AccessedT: a.AccessedT})
+	}
 
-	if _, err := fs.idb.ApplyTransaction(inodedb.DBTransaction{Ops: ops}); err != nil {
-		return err
+	txid, err := fs.idb.ApplyTransaction(inodedb.DBTransaction{Ops: ops})
+	if err != nil {
+		return 0, err
 	}
-	return nil
+
+	fs.muOpenFiles.Lock()
+	of := fs.openFiles[id]
+	fs.muOpenFiles.Unlock()
+	if of != nil {
+		of.forgetAccessedT()
+	}
+	return txid, nil
 }
 
 func (fs *FileSystem) IsDir(id inodedb.ID) (bool, error) {
@@ -327,6 +414,9 @@ func (fs *FileSystem) IsDir(id inodedb.ID) (bool, error) {
 type FileHandle struct {
 	of    *OpenFile
 	flags int
+
+	// audit is set on the write handles opened via AuditedFileSystem.
+	audit *handleAudit
 }
 
 type OpenFile struct {
@@ -341,6 +431,11 @@ type OpenFile struct {
 
 	mu sync.Mutex
 
+	// accessedT is the last time touchAccessedT found the access time up to
+	// date, so that reads within RelatimeInterval don't query the db.
+	accessedT   time.Time
+	muAccessedT sync.Mutex
+
 	logger *zap.Logger
 }
 
@@ -408,7 +503,7 @@ func (fs *FileSystem) OpenFile(id inodedb.ID, flags int) (*FileHandle, error) {
 
 	if fl.IsWriteTruncate(flags) {
 		if err := of.truncateWithLock(0); err != nil {
-			return nil, fmt.Errorf("Failed to truncate file: %v", err)
+			return nil, backendErrno(fmt.Errorf("Failed to truncate file: %w", err))
 		}
 	}
 
@@ -426,13 +521,23 @@ func (fs *FileSystem) closeOpenFile(id inodedb.ID) {
 func (fs *FileSystem) TruncateFile(id inodedb.ID, newsize int64) error {
 	fh, err := fs.OpenFile(id, fl.O_WRONLY)
 	if err != nil {
-		return fmt.Errorf("Failed to OpenFile: %v", err)
+		return fmt.Errorf("Failed to OpenFile: %w", err)
 	}
 	defer fh.Close()
 
 	return fh.Truncate(newsize)
 }
 
+func (fs *FileSystem) Allocate(id inodedb.ID, mode AllocateMode, offset, length int64) error {
+	fh, err := fs.OpenFile(id, fl.O_WRONLY)
+	if err != nil {
+		return fmt.Errorf("Failed to OpenFile: %w", err)
+	}
+	defer fh.Close()
+
+	return fh.Allocate(mode, offset, length)
+}
+
 func (fs *FileSystem) SyncFile(id inodedb.ID) error {
 	if !fl.IsWriteAllowed(fs.bs.Flags()) {
 		// no need to sync if fs is readonly
@@ -441,7 +546,7 @@ func (fs *FileSystem) SyncFile(id inodedb.ID) error {
 
 	fh, err := fs.OpenFile(id, fl.O_WRONLY)
 	if err != nil {
-		return fmt.Errorf("Failed to OpenFile: %v", err)
+		return fmt.Errorf("Failed to OpenFile: %w", err)
 	}
 	defer fh.Close()
 
@@ -533,22 +638,51 @@ func (of *OpenFile) downgradeToReadLock() {
 	of.cfio = chunkstore.NewChunkedFileIO(of.fs.bs, of.fs.c, caio)
 }
 
+func (of *OpenFile) touchAccessedT() error {
+	if !of.fs.relatime || !fl.IsWriteAllowed(of.fs.bs.Flags()) {
+		return nil
+	}
+	now := time.Now()
+
+	of.muAccessedT.Lock()
+	defer of.muAccessedT.Unlock()
+
+	if !of.accessedT.IsZero() && now.Sub(of.accessedT) < RelatimeInterval {
+		return nil
+	}
+	if err := of.fs.touchAccessedT(of.nlock.ID, now); err != nil {
+		return err
+	}
+	of.accessedT = now
+	return nil
+}
+
+// forgetAccessedT makes the next read recheck the access time, as relatime
+// updates it again once the file is modified or changed.
+func (of *OpenFile) forgetAccessedT() {
+	of.muAccessedT.Lock()
+	of.accessedT = time.Time{}
+	of.muAccessedT.Unlock()
+}
+
 func (of *OpenFile) updateModifiedTWithoutLock() error {
+	of.forgetAccessedT()
 	tx := inodedb.DBTransaction{Ops: []inodedb.DBOperation{
 		&inodedb.UpdateModifiedTOp{ID: of.nlock.ID, ModifiedT: time.Now()},
 	}}
 	if _, err := of.fs.idb.ApplyTransaction(tx); err != nil {
-		return fmt.Errorf("Failed to update ModifiedT size: %v", err)
+		return fmt.Errorf("Failed to update ModifiedT size: %w", err)
 	}
 	return nil
 }
 
 func (of *OpenFile) updateSizeWithoutLock(newsize int64) error {
+	of.forgetAccessedT()
 	tx := inodedb.DBTransaction{Ops: []inodedb.DBOperation{
 		&inodedb.UpdateSizeOp{NodeLock: of.nlock, Size: newsize},
 	}}
 	if _, err := of.fs.idb.ApplyTransaction(tx); err != nil {
-		return fmt.Errorf("Failed to update FileNode size: %v", err)
+		return fmt.Errorf("Failed to update FileNode size: %w", err)
 	}
 	return nil
 }
@@ -557,7 +691,7 @@ func (of *OpenFile) updateSizeWithoutLock(newsize int64) error {
 func (of *OpenFile) sizeMayFailWithoutLock() (int64, error) {
 	v, _, err := of.fs.idb.QueryNode(of.nlock.ID, false)
 	if err != nil {
-		return 0, fmt.Errorf("Failed to QueryNode inodedb: %v", err)
+		return 0, fmt.Errorf("Failed to QueryNode inodedb: %w", err)
 	}
 	fv, ok := v.(*inodedb.FileNodeView)
 	if !ok {
@@ -630,7 +764,168 @@ func (of *OpenFile) ReadAt(p []byte, offset int64) (int, error) {
 	of.mu.Lock()
 	defer of.mu.Unlock()
 
-	return of.wc.ReadAtThrough(p, offset, of.cfio)
+	n, err := of.wc.ReadAtThrough(p, offset, of.cfio)
+	if err != nil {
+		return n, err
+	}
+
+	// Holes at the end of the file are not backed by any chunk.
+	size, err := of.sizeMayFailWithoutLock()
+	if err != nil {
+		return n, err
+	}
+	if right := util.Int64Min(offset+int64(len(p)), size); offset+int64(n) < right {
+		zp := p[n : right-offset]
+		for i := range zp {
+			zp[i] = 0
+		}
+		n = int(right - offset)
+	}
+	return n, nil
+}
+
+// prepareSeekWithLock flushes the write cache so that the chunks reflect
+// all the data written, and returns the file size.
+func (of *OpenFile) prepareSeekWithLock(offset int64) (int64, error) {
+	if offset < 0 {
+		return 0, util.EINVAL
+	}
+	if err := of.wc.Sync(of.cfio); err != nil {
+		return 0, fmt.Errorf("FileWriteCache sync failed: %w", err)
+	}
+	size, err := of.sizeMayFailWithoutLock()
+	if err != nil {
+		return 0, err
+	}
+	if offset >= size {
+		return 0, util.ENXIO
+	}
+	return size, nil
+}
+
+// SeekData returns the offset of the first data at or after offset, as lseek(2) SEEK_DATA.
+func (of *OpenFile) SeekData(offset int64) (int64, error) {
+	of.mu.Lock()
+	defer of.mu.Unlock()
+
+	size, err := of.prepareSeekWithLock(offset)
+	if err != nil {
+		return 0, err
+	}
+	o := of.cfio.SeekData(offset)
+	if o < 0 || o >= size {
+		return 0, util.ENXIO
+	}
+	return o, nil
+}
+
+// SeekHole returns the offset of the first hole at or after offset, as lseek(2) SEEK_HOLE.
+// The end of the file is considered a hole.
+func (of *OpenFile) SeekHole(offset int64) (int64, error) {
+	of.mu.Lock()
+	defer of.mu.Unlock()
+
+	size, err := of.prepareSeekWithLock(offset)
+	if err != nil {
+		return 0, err
+	}
+	return util.Int64Min(of.cfio.SeekHole(offset), size), nil
+}
+
+// PunchHole deallocates the range [offset, offset+length) without changing the file size.
+// The range reads as zeros afterwards. Only whole chunks in the range are freed, see
+// ChunkedFileIO.PunchHole.
+func (of *OpenFile) PunchHole(offset, length int64) error {
+	return of.Allocate(AllocatePunchHole|AllocateKeepSize, offset, length)
+}
+
+// AllocateMode specifies the Allocate operation. The values match the fallocate(2) mode flags.
+type AllocateMode uint32
+
+const (
+	// AllocateKeepSize keeps the file size even if the range extends past the end of the file.
+	AllocateKeepSize AllocateMode = 0x01
+	// AllocatePunchHole deallocates the range. Must be specified with AllocateKeepSize.
+	AllocatePunchHole AllocateMode = 0x02
+	// AllocateZeroRange overwrites the range with zeros.
+	AllocateZeroRange AllocateMode = 0x10
+)
+
+func (m AllocateMode) String() string {
+	var b bytes.Buffer
+
+	if m&AllocateKeepSize != 0 {
+		b.WriteString("KeepSize|")
+	}
+	if m&AllocatePunchHole != 0 {
+		b.WriteString("PunchHole|")
+	}
+	if m&AllocateZeroRange != 0 {
+		b.WriteString("ZeroRange|")
+	}
+	if m&^(AllocateKeepSize|AllocatePunchHole|AllocateZeroRange) != 0 {
+		fmt.Fprintf(&b, "Unknown(%#x)|", uint32(m))
+	}
+
+	if b.Len() == 0 {
+		return "Allocate"
+	}
+	return b.String()[:b.Len()-1]
+}
+
+func (m AllocateMode) validate() error {
+	if m&^(AllocateKeepSize|AllocatePunchHole|AllocateZeroRange) != 0 {
+		return util.ENOTSUP
+	}
+	if m&AllocatePunchHole != 0 {
+		if m&AllocateKeepSize == 0 || m&AllocateZeroRange != 0 {
+			return util.ENOTSUP
+		}
+	}
+	return nil
+}
+
+// Allocate manipulates the allocated chunks of the range [offset, offset+length), as fallocate(2).
+// Otaru doesn't reserve backend storage ahead, so by default Allocate only
+// extends the file size to cover the range unless AllocateKeepSize is
+// specified. The holes in the range are left sparse, and read as zeros.
+func (of *OpenFile) Allocate(mode AllocateMode, offset, length int64) error {
+	of.mu.Lock()
+	defer of.mu.Unlock()
+
+	if err := mode.validate(); err != nil {
+		return err
+	}
+	if offset < 0 || length <= 0 {
+		return util.EINVAL
+	}
+	size, err := of.sizeMayFailWithoutLock()
+	if err != nil {
+		return err
+	}
+
+	// Holes read as zeros, so ZeroRange deallocates the range just as PunchHole.
+	punched := false
+	if mode&(AllocatePunchHole|AllocateZeroRange) != 0 {
+		if l := util.Int64Min(length, size-offset); l > 0 {
+			if err := of.wc.Sync(of.cfio); err != nil {
+				return fmt.Errorf("FileWriteCache sync failed: %w", err)
+			}
+			if err := of.cfio.PunchHole(offset, l); err != nil {
+				return err
+			}
+			punched = true
+		}
+	}
+
+	right := offset + length
+	if mode&AllocateKeepSize == 0 && right > size {
+		return of.updateSizeWithoutLock(right)
+	}
+	if punched {
+		return of.updateModifiedTWithoutLock()
+	}
+	return nil
 }
 
 func (of *OpenFile) Sync() error {
@@ -638,7 +933,7 @@ func (of *OpenFile) Sync() error {
 	defer of.mu.Unlock()
 
 	if err := of.wc.Sync(of.cfio); err != nil {
-		return fmt.Errorf("FileWriteCache sync failed: %v", err)
+		return fmt.Errorf("FileWriteCache sync failed: %w", err)
 	}
 	return nil
 }
@@ -679,6 +974,30 @@ func (of *OpenFile) truncateWithLock(newsize int64) error {
 	}
 }
 
+// temporaryError is implemented by errors from the backend blobstore which
+// tell whether the operation may succeed if retried later.
+type temporaryError interface {
+	error
+	Temporary() bool
+}
+
+// backendErrno maps backend blobstore failures to EAGAIN if temporary, or
+// EIO otherwise. Other errors are returned as is.
+func backendErrno(err error) error {
+	if err == nil {
+		return nil
+	}
+	var te temporaryError
+	if !errors.As(err, &te) {
+		return err
+	}
+	zap.S().Warnf("Backend blobstore failure: %v", err)
+	if te.Temporary() {
+		return util.EAGAIN
+	}
+	return util.EIO
+}
+
 func (fh *FileHandle) ID() inodedb.ID {
 	return fh.of.nlock.ID
 }
@@ -688,11 +1007,12 @@ func (fh *FileHandle) PWrite(p []byte, offset int64) error {
 		return util.EBADF
 	}
 
+	fh.audit.markWritten()
 	if fl.IsWriteAppend(fh.flags) {
-		return fh.of.Append(p)
+		return backendErrno(fh.of.Append(p))
 	}
 
-	return fh.of.PWrite(p, offset)
+	return backendErrno(fh.of.PWrite(p, offset))
 }
 
 func (fh *FileHandle) ReadAt(p []byte, offset int64) (int, error) {
@@ -700,7 +1020,13 @@ func (fh *FileHandle) ReadAt(p []byte, offset int64) (int, error) {
 		return 0, util.EBADF
 	}
 
-	return fh.of.ReadAt(p, offset)
+	n, err := fh.of.ReadAt(p, offset)
+	if err == nil {
+		if err := fh.of.touchAccessedT(); err != nil {
+			fh.of.logger.Sugar().Warnf("Failed to update access time: %v", err)
+		}
+	}
+	return n, backendErrno(err)
 }
 
 func (fh *FileHandle) Sync() error {
@@ -708,7 +1034,7 @@ func (fh *FileHandle) Sync() error {
 		return nil
 	}
 
-	return fh.of.Sync()
+	return backendErrno(fh.of.Sync())
 }
 
 func (fh *FileHandle) Size() int64 {
@@ -720,11 +1046,49 @@ func (fh *FileHandle) Truncate(newsize int64) error {
 		return util.EBADF
 	}
 
-	return fh.of.Truncate(newsize)
+	fh.audit.markWritten()
+	return backendErrno(fh.of.Truncate(newsize))
+}
+
+// SeekData, SeekHole and PunchHole are for FileSystem users only. The mount
+// doesn't serve them: github.com/nyaxt/fuse decodes neither FUSE_LSEEK nor
+// FUSE_FALLOCATE. The kernel falls back to treating the whole file as data
+// for lseek(2) SEEK_DATA/SEEK_HOLE on the mount.
+func (fh *FileHandle) SeekData(offset int64) (int64, error) {
+	o, err := fh.of.SeekData(offset)
+	return o, backendErrno(err)
+}
+
+func (fh *FileHandle) SeekHole(offset int64) (int64, error) {
+	o, err := fh.of.SeekHole(offset)
+	return o, backendErrno(err)
+}
+
+func (fh *FileHandle) PunchHole(offset, length int64) error {
+	if !fl.IsWriteAllowed(fh.flags) {
+		return util.EBADF
+	}
+
+	fh.audit.markWritten()
+	return backendErrno(fh.of.PunchHole(offset, length))
+}
+
+// Allocate implements the fallocate(2) modes for FileSystem users only. The
+// mount has no fallocate: github.com/nyaxt/fuse doesn't decode
+// FUSE_FALLOCATE and answers it with ENOSYS, so fallocate(2) on the mount
+// fails with EOPNOTSUPP.
+func (fh *FileHandle) Allocate(mode AllocateMode, offset, length int64) error {
+	if !fl.IsWriteAllowed(fh.flags) {
+		return util.EBADF
+	}
+
+	fh.audit.markWritten()
+	return backendErrno(fh.of.Allocate(mode, offset, length))
 }
 
 func (fh *FileHandle) Close() {
 	fh.of.CloseHandle(fh)
+	fh.audit.closed()
 }
 
 func (fh *FileHandle) Attr() (Attr, error) {
@@ -1,14 +1,17 @@
 package filesystem_test
 
 import (
+	"github.com/nyaxt/otaru/auditlog"
 	"github.com/nyaxt/otaru/filesystem"
 	"github.com/nyaxt/otaru/flags"
 	"github.com/nyaxt/otaru/inodedb"
 	"github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/util"
 	"go.uber.org/zap"
 
 	"bytes"
 	"testing"
+	"time"
 )
 
 func init() { testutils.EnsureLogger() }
@@ -82,3 +85,436 @@ func TestFile_CloseOpenFile(t *testing.T) {
 		t.Errorf("NumOpenFiles %d != 0", stats.NumOpenFiles)
 	}
 }
+
+func TestFile_SparseHoles(t *testing.T) {
+	idb, err := inodedb.NewEmptyDB(inodedb.NewSimpleDBStateSnapshotIO(), inodedb.NewSimpleDBTransactionLogIO())
+	if err != nil {
+		t.Fatalf("NewEmptyDB failed: %v", err)
+	}
+
+	bs := testutils.TestFileBlobStore()
+	fs := filesystem.NewFileSystem(idb, bs, testutils.TestCipher(), zap.L())
+	h, err := fs.OpenFileFullPath("/sparse.bin", flags.O_RDWRCREATE, 0666)
+	if err != nil {
+		t.Fatalf("OpenFileFullPath failed: %v", err)
+	}
+	defer h.Close()
+
+	holeo := int64(4 * 1024 * 1024)
+	if err := h.PWrite(testutils.HelloWorld, holeo); err != nil {
+		t.Fatalf("PWrite failed: %v", err)
+	}
+	if err := h.Truncate(2 * holeo); err != nil {
+		t.Fatalf("Truncate failed: %v", err)
+	}
+	if err := h.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	a, err := h.Attr()
+	if err != nil {
+		t.Fatalf("Attr failed: %v", err)
+	}
+	if a.Size != 2*holeo || a.AllocatedSize != int64(len(testutils.HelloWorld)) {
+		t.Errorf("Unexpected Size %d, AllocatedSize %d", a.Size, a.AllocatedSize)
+	}
+
+	// Both the hole in the middle and at the end read as zeros.
+	for _, o := range []int64{0, 2*holeo - 32} {
+		buf := bytes.Repeat([]byte{0xff}, 32)
+		n, err := h.ReadAt(buf, o)
+		if err != nil {
+			t.Fatalf("ReadAt failed: %v", err)
+		}
+		if n != len(buf) || !bytes.Equal(buf, make([]byte, 32)) {
+			t.Errorf("Unexpected read of the hole at %d: n=%d %v", o, n, buf)
+		}
+	}
+
+	if o, err := h.SeekData(0); err != nil || o != holeo {
+		t.Errorf("Unexpected SeekData: %d, %v", o, err)
+	}
+	if o, err := h.SeekHole(holeo); err != nil || o != holeo+int64(len(testutils.HelloWorld)) {
+		t.Errorf("Unexpected SeekHole: %d, %v", o, err)
+	}
+	if _, err := h.SeekData(holeo + int64(len(testutils.HelloWorld))); err != util.ENXIO {
+		t.Errorf("Expected ENXIO for SeekData in the trailing hole, got %v", err)
+	}
+	if _, err := h.SeekHole(2 * holeo); err != util.ENXIO {
+		t.Errorf("Expected ENXIO for SeekHole past EOF, got %v", err)
+	}
+
+	if err := h.PunchHole(holeo, holeo); err != nil {
+		t.Fatalf("PunchHole failed: %v", err)
+	}
+	a, err = h.Attr()
+	if err != nil {
+		t.Fatalf("Attr failed: %v", err)
+	}
+	if a.Size != 2*holeo || a.AllocatedSize != 0 {
+		t.Errorf("Unexpected Size %d, AllocatedSize %d after PunchHole", a.Size, a.AllocatedSize)
+	}
+}
+
+func TestFileSystem_Allocate(t *testing.T) {
+	idb, err := inodedb.NewEmptyDB(inodedb.NewSimpleDBStateSnapshotIO(), inodedb.NewSimpleDBTransactionLogIO())
+	if err != nil {
+		t.Fatalf("NewEmptyDB failed: %v", err)
+	}
+
+	bs := testutils.TestFileBlobStore()
+	fs := filesystem.NewFileSystem(idb, bs, testutils.TestCipher(), zap.L())
+	h, err := fs.OpenFileFullPath("/prealloc.bin", flags.O_RDWRCREATE, 0666)
+	if err != nil {
+		t.Fatalf("OpenFileFullPath failed: %v", err)
+	}
+	defer h.Close()
+
+	holeo := int64(4 * 1024 * 1024)
+	hwlen := int64(len(testutils.HelloWorld))
+	if err := h.PWrite(testutils.HelloWorld, holeo); err != nil {
+		t.Fatalf("PWrite failed: %v", err)
+	}
+	if err := h.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// Preallocate the hole and past the end of the file, keeping the data.
+	// The holes stay sparse.
+	if err := fs.Allocate(h.ID(), 0, 0, 2*holeo); err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+	a, err := h.Attr()
+	if err != nil {
+		t.Fatalf("Attr failed: %v", err)
+	}
+	if a.Size != 2*holeo || a.AllocatedSize != hwlen {
+		t.Errorf("Unexpected Size %d, AllocatedSize %d", a.Size, a.AllocatedSize)
+	}
+	buf := make([]byte, hwlen)
+	if _, err := h.ReadAt(buf, holeo); err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	if !bytes.Equal(buf, testutils.HelloWorld) {
+		t.Errorf("Allocate overwrote the existing data: %v", buf)
+	}
+	if _, err := h.ReadAt(buf, 2*holeo-hwlen); err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	if !bytes.Equal(buf, make([]byte, hwlen)) {
+		t.Errorf("Allocated range doesn't read as zeros: %v", buf)
+	}
+
+	if err := h.Allocate(filesystem.AllocateKeepSize, 2*holeo, holeo); err != nil {
+		t.Fatalf("Allocate with KeepSize failed: %v", err)
+	}
+	if a, err := h.Attr(); err != nil || a.Size != 2*holeo || a.AllocatedSize != hwlen {
+		t.Errorf("Unexpected Attr after Allocate with KeepSize: %+v, %v", a, err)
+	}
+	if n, err := h.ReadAt(buf, 2*holeo-2); err != nil || n != 2 {
+		t.Errorf("Read past the end of the file: n=%d, %v", n, err)
+	}
+
+	if err := h.Allocate(filesystem.AllocateZeroRange, holeo+1, 3); err != nil {
+		t.Fatalf("Allocate with ZeroRange failed: %v", err)
+	}
+	if _, err := h.ReadAt(buf, holeo); err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	expected := append([]byte{}, testutils.HelloWorld...)
+	copy(expected[1:4], []byte{0, 0, 0})
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("Unexpected content after ZeroRange: %v", buf)
+	}
+
+	if err := h.Allocate(filesystem.AllocatePunchHole, 0, holeo); err != util.ENOTSUP {
+		t.Errorf("Expected ENOTSUP for PunchHole without KeepSize, got %v", err)
+	}
+	if err := h.Allocate(filesystem.AllocatePunchHole|filesystem.AllocateKeepSize, 0, holeo); err != nil {
+		t.Fatalf("Allocate with PunchHole failed: %v", err)
+	}
+	if a, err := h.Attr(); err != nil || a.Size != 2*holeo || a.AllocatedSize != hwlen {
+		t.Errorf("Unexpected Attr after PunchHole: %+v, %v", a, err)
+	}
+}
+
+func TestFileSystem_Relatime(t *testing.T) {
+	idb, err := inodedb.NewEmptyDB(inodedb.NewSimpleDBStateSnapshotIO(), inodedb.NewSimpleDBTransactionLogIO())
+	if err != nil {
+		t.Fatalf("NewEmptyDB failed: %v", err)
+	}
+
+	bs := testutils.TestFileBlobStore()
+	fs := filesystem.NewFileSystem(idb, bs, testutils.TestCipher(), zap.L())
+	h, err := fs.OpenFileFullPath("/hello.txt", flags.O_RDWRCREATE, 0666)
+	if err != nil {
+		t.Fatalf("OpenFileFullPath failed: %v", err)
+	}
+	defer h.Close()
+	if err := h.PWrite(testutils.HelloWorld, 0); err != nil {
+		t.Fatalf("PWrite failed: %v", err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	if err := fs.SetAttr(h.ID(), filesystem.Attr{AccessedT: old}, filesystem.AccessedTValid); err != nil {
+		t.Fatalf("SetAttr failed: %v", err)
+	}
+
+	buf := make([]byte, len(testutils.HelloWorld))
+	if _, err := h.ReadAt(buf, 0); err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	if a, err := h.Attr(); err != nil || !a.AccessedT.Equal(old) {
+		t.Errorf("AccessedT updated without relatime: %+v, %v", a, err)
+	}
+
+	fs.SetRelatime(true)
+	if _, err := h.ReadAt(buf, 0); err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	a, err := h.Attr()
+	if err != nil {
+		t.Fatalf("Attr failed: %v", err)
+	}
+	if !a.AccessedT.After(old) || a.CreatedT.IsZero() || a.ChangeT.Before(a.CreatedT) {
+		t.Errorf("Unexpected times after read with relatime: %+v", a)
+	}
+
+	// Setting the times makes the next read recheck the access time.
+	if err := fs.SetAttr(h.ID(), filesystem.Attr{AccessedT: old}, filesystem.AccessedTValid); err != nil {
+		t.Fatalf("SetAttr failed: %v", err)
+	}
+	if _, err := h.ReadAt(buf, 0); err != nil {
+		t.Fatalf("ReadAt failed: %v", err)
+	}
+	if a, err := h.Attr(); err != nil || !a.AccessedT.After(old) {
+		t.Errorf("AccessedT not updated after SetAttr: %+v, %v", a, err)
+	}
+}
+
+func TestFileSystem_Permissions(t *testing.T) {
+	idb, err := inodedb.NewEmptyDB(inodedb.NewSimpleDBStateSnapshotIO(), inodedb.NewSimpleDBTransactionLogIO())
+	if err != nil {
+		t.Fatalf("NewEmptyDB failed: %v", err)
+	}
+
+	bs := testutils.TestFileBlobStore()
+	fs := filesystem.NewFileSystem(idb, bs, testutils.TestCipher(), zap.L())
+	id, err := fs.CreateFile(inodedb.RootDirID, "private.txt", 0640, 1000, 100, time.Now())
+	if err != nil {
+		t.Fatalf("CreateFile failed: %v", err)
+	}
+
+	owner := filesystem.Caller{Uid: 1000, Gid: 1000}
+	member := filesystem.Caller{Uid: 1001, Gid: 1001, Groups: []uint32{100}}
+	other := filesystem.Caller{Uid: 1002, Gid: 1002}
+	root := filesystem.Caller{}
+	for _, tc := range []struct {
+		c        filesystem.Caller
+		mode     filesystem.AccessMode
+		expected error
+	}{
+		{owner, filesystem.AccessRead | filesystem.AccessWrite, nil},
+		{owner, filesystem.AccessExec, util.EACCES},
+		{member, filesystem.AccessRead, nil},
+		{member, filesystem.AccessWrite, util.EACCES},
+		{other, filesystem.AccessRead, util.EACCES},
+		{root, filesystem.AccessRead | filesystem.AccessWrite, nil},
+		{root, filesystem.AccessExec, util.EACCES},
+	} {
+		if err := fs.CheckAccess(id, tc.c, tc.mode); err != tc.expected {
+			t.Errorf("CheckAccess(%+v, %v): expected %v, got %v", tc.c, tc.mode, tc.expected, err)
+		}
+	}
+
+	if err := fs.CheckSetAttr(id, member, filesystem.Attr{PermMode: 0666}, filesystem.PermModeValid); err != util.EPERM {
+		t.Errorf("Expected EPERM for chmod by non-owner, got %v", err)
+	}
+	if err := fs.CheckSetAttr(id, owner, filesystem.Attr{Uid: 1001}, filesystem.UidValid); err != util.EPERM {
+		t.Errorf("Expected EPERM for chown by non-root, got %v", err)
+	}
+	if err := fs.CheckSetAttr(id, owner, filesystem.Attr{Gid: 100}, filesystem.GidValid); err != nil {
+		t.Errorf("chgrp to the own group failed: %v", err)
+	}
+	if err := fs.CheckSetAttr(id, owner, filesystem.Attr{Gid: 101}, filesystem.GidValid); err != util.EPERM {
+		t.Errorf("Expected EPERM for chgrp to other group, got %v", err)
+	}
+
+	// Non-owners with write access may only touch the times to now.
+	shared, err := fs.CreateFile(inodedb.RootDirID, "shared.txt", 0666, 1000, 100, time.Now())
+	if err != nil {
+		t.Fatalf("CreateFile failed: %v", err)
+	}
+	times := filesystem.ModifiedTValid | filesystem.AccessedTValid
+	past := filesystem.Attr{ModifiedT: time.Unix(0, 0), AccessedT: time.Unix(0, 0)}
+	if err := fs.CheckSetAttr(shared, other, past, times|filesystem.TimesSetToNow); err != nil {
+		t.Errorf("Touching the times to now with write access failed: %v", err)
+	}
+	if err := fs.CheckSetAttr(shared, other, past, times); err != util.EPERM {
+		t.Errorf("Expected EPERM for setting times by non-owner, got %v", err)
+	}
+	if err := fs.CheckSetAttr(id, other, past, times|filesystem.TimesSetToNow); err != util.EACCES {
+		t.Errorf("Expected EACCES for touching without write access, got %v", err)
+	}
+	if err := fs.CheckSetAttr(shared, owner, past, times); err != nil {
+		t.Errorf("Setting times by the owner failed: %v", err)
+	}
+
+	// Only the owners may remove the entries of a sticky dir.
+	tmp, err := fs.CreateDir(inodedb.RootDirID, "tmp", filesystem.StickyBit|0777, 0, 0, time.Now())
+	if err != nil {
+		t.Fatalf("CreateDir failed: %v", err)
+	}
+	if _, err := fs.CreateFile(tmp, "owned.txt", 0666, 1000, 100, time.Now()); err != nil {
+		t.Fatalf("CreateFile failed: %v", err)
+	}
+	for _, tc := range []struct {
+		c        filesystem.Caller
+		name     string
+		expected error
+	}{
+		{owner, "owned.txt", nil},
+		{other, "owned.txt", util.EPERM},
+		{root, "owned.txt", nil},
+		{other, "nonexistent.txt", nil},
+	} {
+		if err := fs.CheckRemoveEntry(tmp, tc.name, tc.c); err != tc.expected {
+			t.Errorf("CheckRemoveEntry(%+v, %q): expected %v, got %v", tc.c, tc.name, tc.expected, err)
+		}
+	}
+	if err := fs.CheckRemoveEntry(inodedb.RootDirID, "private.txt", other); err != nil {
+		t.Errorf("CheckRemoveEntry in non-sticky dir failed: %v", err)
+	}
+
+	// Files owned by uid 1000 on the machine the filesystem was written show up as uid 501.
+	m, err := filesystem.NewIDMap(map[uint32]uint32{1000: 501}, map[uint32]uint32{100: 20})
+	if err != nil {
+		t.Fatalf("NewIDMap failed: %v", err)
+	}
+	fs.SetIDMap(m)
+	if a, err := fs.Attr(id); err != nil || a.Uid != 501 || a.Gid != 20 {
+		t.Errorf("Unexpected mapped Attr: %+v, %v", a, err)
+	}
+	if err := fs.CheckAccess(id, filesystem.Caller{Uid: 501, Gid: 20}, filesystem.AccessWrite); err != nil {
+		t.Errorf("CheckAccess by the mapped owner failed: %v", err)
+	}
+	id2, err := fs.CreateFile(inodedb.RootDirID, "local.txt", 0644, 501, 20, time.Now())
+	if err != nil {
+		t.Fatalf("CreateFile failed: %v", err)
+	}
+	fs.SetIDMap(nil)
+	if a, err := fs.Attr(id2); err != nil || a.Uid != 1000 || a.Gid != 100 {
+		t.Errorf("Unexpected stored Attr: %+v, %v", a, err)
+	}
+
+	if _, err := filesystem.NewIDMap(map[uint32]uint32{1000: 501, 1001: 501}, nil); err == nil {
+		t.Errorf("NewIDMap should fail for ids mapped to the same id")
+	}
+}
+
+type testAuditRecorder struct {
+	entries []auditlog.Entry
+}
+
+func (r *testAuditRecorder) Record(e auditlog.Entry) {
+	r.entries = append(r.entries, e)
+}
+
+func TestFileSystem_Audited(t *testing.T) {
+	idb, err := inodedb.NewEmptyDB(inodedb.NewSimpleDBStateSnapshotIO(), inodedb.NewSimpleDBTransactionLogIO())
+	if err != nil {
+		t.Fatalf("NewEmptyDB failed: %v", err)
+	}
+
+	bs := testutils.TestFileBlobStore()
+	fs := filesystem.NewFileSystem(idb, bs, testutils.TestCipher(), zap.L())
+	r := &testAuditRecorder{}
+	fs.SetAuditRecorder(r)
+
+	// Mutations not via Audited() are not recorded.
+	if _, err := fs.CreateDir(inodedb.RootDirID, "internal", 0755, 0, 0, time.Now()); err != nil {
+		t.Fatalf("CreateDir failed: %v", err)
+	}
+
+	afs := fs.Audited(auditlog.Actor{Source: "grpc", User: "alice"})
+	dirID, err := afs.CreateDirFullPath("/docs", 0755, 0, 0, time.Now())
+	if err != nil {
+		t.Fatalf("CreateDirFullPath failed: %v", err)
+	}
+	id, err := afs.CreateFile(dirID, "hello.txt", 0644, 0, 0, time.Now())
+	if err != nil {
+		t.Fatalf("CreateFile failed: %v", err)
+	}
+	h, err := afs.OpenFile(id, flags.O_RDWR)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	if err := h.PWrite(testutils.HelloWorld, 0); err != nil {
+		t.Fatalf("PWrite failed: %v", err)
+	}
+	h.Close()
+	// Read only handles are not recorded.
+	h, err = afs.OpenFile(id, flags.O_RDONLY)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	h.Close()
+	if err := afs.SetAttr(id, filesystem.Attr{PermMode: 0600}, filesystem.PermModeValid); err != nil {
+		t.Fatalf("SetAttr failed: %v", err)
+	}
+	if err := afs.Rename(dirID, "hello.txt", inodedb.RootDirID, "world.txt"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	// Mutations by id after a rename are recorded with the current path.
+	if err := afs.SetAttr(id, filesystem.Attr{PermMode: 0644}, filesystem.PermModeValid); err != nil {
+		t.Fatalf("SetAttr failed: %v", err)
+	}
+	if err := afs.Remove(dirID, "nonexistent.txt"); err == nil {
+		t.Fatalf("Remove of nonexistent file should fail")
+	}
+	if err := afs.Remove(inodedb.RootDirID, "world.txt"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	expected := []struct {
+		op, path, newpath string
+		hasTxID, hasError bool
+	}{
+		{filesystem.AuditOpCreateDir, "/docs", "", true, false},
+		{filesystem.AuditOpCreateFile, "/docs/hello.txt", "", true, false},
+		{filesystem.AuditOpWrite, "/docs/hello.txt", "", false, false},
+		{filesystem.AuditOpSetAttr, "/docs/hello.txt", "", true, false},
+		{filesystem.AuditOpRename, "/docs/hello.txt", "/world.txt", true, false},
+		{filesystem.AuditOpSetAttr, "/world.txt", "", true, false},
+		{filesystem.AuditOpRemove, "/docs/nonexistent.txt", "", false, true},
+		{filesystem.AuditOpRemove, "/world.txt", "", true, false},
+	}
+	if len(r.entries) != len(expected) {
+		t.Fatalf("Unexpected entries: %+v", r.entries)
+	}
+	var lastTxID inodedb.TxID
+	for i, e := range r.entries {
+		exp := expected[i]
+		if e.Source != "grpc" || e.User != "alice" {
+			t.Errorf("entries[%d]: unexpected actor %s:%s", i, e.Source, e.User)
+		}
+		if e.Op != exp.op || e.Path != exp.path || e.NewPath != exp.newpath {
+			t.Errorf("entries[%d]: %+v, expected %+v", i, e, exp)
+		}
+		if (e.TxID != 0) != exp.hasTxID {
+			t.Errorf("entries[%d]: unexpected TxID %v", i, e.TxID)
+		}
+		if e.TxID != 0 {
+			if e.TxID <= lastTxID {
+				t.Errorf("entries[%d]: TxID %v not increasing", i, e.TxID)
+			}
+			lastTxID = e.TxID
+		}
+		if (e.Error != "") != exp.hasError {
+			t.Errorf("entries[%d]: unexpected Error %q", i, e.Error)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("entries[%d]: Time not set", i)
+		}
+	}
+}
@@ -121,3 +121,82 @@ func (fs *FileSystem) CreateDirFullPath(fullpath string, perm uint16, uid, gid u
 	name := filepath.Base(fullpath)
 	return fs.CreateDir(id, name, perm, uid, gid, modifiedT)
 }
+
+// CurrentPath returns the path the node is currently reachable at. Unlike
+// Attr.OrigPath, the path the node was created at, it follows renames.
+// It fails with ENOENT if the node is not reachable from the root dir.
+func (fs *FileSystem) CurrentPath(id inodedb.ID) (string, error) {
+	if id == inodedb.RootDirID {
+		return "/", nil
+	}
+
+	v, _, err := fs.idb.QueryNode(id, false)
+	if err != nil {
+		return "", err
+	}
+	if dv, ok := v.(*inodedb.DirNodeView); ok {
+		parentPath, err := fs.CurrentPath(dv.ParentID)
+		if err != nil {
+			return "", err
+		}
+		return fs.findEntryPath(dv.ParentID, parentPath, id)
+	}
+
+	// File nodes don't link to their parent dir. Try the paths the file was
+	// last looked up at and created at, and search the whole tree as a last resort.
+	fs.muOrigPath.Lock()
+	cachedpath := fs.origpath[id]
+	fs.muOrigPath.Unlock()
+	for _, p := range []string{cachedpath, v.GetOrigPath()} {
+		if len(p) < 1 || p[0] != '/' {
+			continue
+		}
+		if pid, err := fs.FindNodeFullPath(p); err == nil && pid == id {
+			return filepath.Clean(p), nil
+		}
+	}
+	return fs.searchPath(id)
+}
+
+func (fs *FileSystem) findEntryPath(dirID inodedb.ID, dirPath string, id inodedb.ID) (string, error) {
+	entries, err := fs.DirEntries(dirID)
+	if err != nil {
+		return "", err
+	}
+	for name, eid := range entries {
+		if eid == id {
+			return filepath.Join(dirPath, name), nil
+		}
+	}
+	return "", util.ENOENT
+}
+
+// searchPath walks the tree from the root dir to find the path of the node.
+func (fs *FileSystem) searchPath(id inodedb.ID) (string, error) {
+	type dir struct {
+		id   inodedb.ID
+		path string
+	}
+	q := []dir{{inodedb.RootDirID, "/"}}
+	for len(q) > 0 {
+		d := q[0]
+		q = q[1:]
+
+		entries, err := fs.DirEntries(d.id)
+		if err != nil {
+			if err == util.ENOTDIR || util.IsNotExist(err) {
+				// Not a dir, or removed meanwhile.
+				continue
+			}
+			return "", err
+		}
+		for name, eid := range entries {
+			p := filepath.Join(d.path, name)
+			if eid == id {
+				return p, nil
+			}
+			q = append(q, dir{eid, p})
+		}
+	}
+	return "", util.ENOENT
+}
@@ -29,3 +29,14 @@ func TestRegression_PWriteAfterSync(t *testing.T) {
 	}
 	// Test PASS if no panic
 }
+
+func TestRegression_PWriteAfterTruncateEmpty(t *testing.T) {
+	wc := filewritecache.New()
+	wc.Truncate(0)
+
+	if err := wc.PWrite([]byte{1, 2, 3}, 0); err != nil {
+		t.Errorf("PWrite failed: %v", err)
+		return
+	}
+	// Test PASS if no panic
+}
@@ -19,8 +19,11 @@ var poolPatchP = sync.Pool{New: func() interface{} { return make([]byte, 0, MaxP
 func NewPatch(offset int64, p []byte) Patch {
 	var pcopy []byte
 	if len(p) <= MaxPatchContentLen {
-		pcopy = poolPatchP.Get().([]byte)[:len(p)]
-	} else {
+		if buf := poolPatchP.Get().([]byte); cap(buf) >= len(p) {
+			pcopy = buf[:len(p)]
+		}
+	}
+	if pcopy == nil {
 		pcopy = make([]byte, len(p))
 	}
 	copy(pcopy, p)
@@ -153,7 +156,9 @@ func (ps Patches) Truncate(size int64) Patches {
 
 		if p.Left() >= size {
 			// drop the patch
-			poolPatchP.Put(p.P)
+			if p.P != nil {
+				poolPatchP.Put(p.P)
+			}
 			continue
 		}
 
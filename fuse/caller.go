@@ -0,0 +1,85 @@
+package fuse
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	bfuse "github.com/nyaxt/fuse"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/auditlog"
+	"github.com/nyaxt/otaru/filesystem"
+	"github.com/nyaxt/otaru/inodedb"
+)
+
+// readSupplementaryGroups reads the supplementary groups of the process from procfs,
+// as the FUSE request only carries its uid and gid.
+func readSupplementaryGroups(pid uint32) ([]uint32, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if !strings.HasPrefix(line, "Groups:") {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(line, "Groups:"))
+		groups := make([]uint32, 0, len(fields))
+		for _, f := range fields {
+			g, err := strconv.ParseUint(f, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse group %q: %v", f, err)
+			}
+			groups = append(groups, uint32(g))
+		}
+		return groups, nil
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func callerFromHeader(h bfuse.Header) filesystem.Caller {
+	c := filesystem.Caller{Uid: h.Uid, Gid: h.Gid}
+	if c.IsRoot() {
+		return c
+	}
+
+	groups, err := readSupplementaryGroups(h.Pid)
+	if err != nil {
+		// The process may have exited already. Check only with the primary group.
+		zap.S().Debugf("Failed to read supplementary groups of pid %d: %v", h.Pid, err)
+	}
+	c.Groups = groups
+	return c
+}
+
+// accessModeFromOpenFlags returns the permissions required to open the node with the flags.
+func accessModeFromOpenFlags(f bfuse.OpenFlags) filesystem.AccessMode {
+	var mode filesystem.AccessMode
+	if f.IsReadOnly() || f.IsReadWrite() {
+		mode |= filesystem.AccessRead
+	}
+	if f.IsWriteOnly() || f.IsReadWrite() || f&bfuse.OpenTruncate != 0 {
+		mode |= filesystem.AccessWrite
+	}
+	return mode
+}
+
+// audited returns the view of fs recording the mutations by the caller of the request.
+func audited(fs *filesystem.FileSystem, h bfuse.Header) *filesystem.AuditedFileSystem {
+	return fs.Audited(auditlog.Actor{Source: "fuse", User: fmt.Sprintf("uid=%d", h.Uid)})
+}
+
+func checkAccess(fs *filesystem.FileSystem, id inodedb.ID, h bfuse.Header, mode filesystem.AccessMode) error {
+	return fs.CheckAccess(id, callerFromHeader(h), mode)
+}
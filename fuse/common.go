@@ -25,20 +25,27 @@ func otaruSetattr(fs *filesystem.FileSystem, id inodedb.ID, req *bfuse.SetattrRe
 	}
 	if req.Valid.Mode() {
 		valid |= filesystem.PermModeValid
-		a.PermMode = uint16(req.Mode & os.ModePerm)
+		a.PermMode = permModeFromFileMode(req.Mode)
 	}
 	if req.Valid.Atime() {
-		// otaru fs doesn't keep atime. set mtime instead.
-		valid |= filesystem.ModifiedTValid
-		a.ModifiedT = req.Atime
+		valid |= filesystem.AccessedTValid
+		a.AccessedT = req.Atime
 	}
 	if req.Valid.Mtime() {
 		valid |= filesystem.ModifiedTValid
 		a.ModifiedT = req.Mtime
 	}
+	if (req.Valid.Atime() || req.Valid.Mtime()) &&
+		(!req.Valid.Atime() || req.Valid.AtimeNow()) &&
+		(!req.Valid.Mtime() || req.Valid.MtimeNow()) {
+		valid |= filesystem.TimesSetToNow
+	}
 
 	if valid != 0 {
-		if err := fs.SetAttr(id, a, valid); err != nil {
+		if err := fs.CheckSetAttr(id, callerFromHeader(req.Header), a, valid); err != nil {
+			return err
+		}
+		if err := audited(fs, req.Header).SetAttr(id, a, valid); err != nil {
 			return err
 		}
 	}
@@ -46,6 +53,23 @@ func otaruSetattr(fs *filesystem.FileSystem, id inodedb.ID, req *bfuse.SetattrRe
 	return nil
 }
 
+// permModeFromFileMode returns the rwx bits and the sticky bit of m, as stored in Attr.PermMode.
+func permModeFromFileMode(m os.FileMode) uint16 {
+	pm := uint16(m & os.ModePerm)
+	if m&os.ModeSticky != 0 {
+		pm |= filesystem.StickyBit
+	}
+	return pm
+}
+
+func fileModeFromPermMode(pm uint16) os.FileMode {
+	m := os.FileMode(pm) & os.ModePerm
+	if pm&filesystem.StickyBit != 0 {
+		m |= os.ModeSticky
+	}
+	return m
+}
+
 func Bazil2OtaruFlags(bf bfuse.OpenFlags) int {
 	ret := 0
 	if bf.IsReadOnly() {
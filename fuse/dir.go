@@ -29,11 +29,11 @@ func (d DirNode) Attr(ctx context.Context, a *bfuse.Attr) error {
 	a.Valid = 1 * time.Minute
 	a.Nlink = 1
 	a.Inode = uint64(d.id)
-	a.Mode = os.ModeDir | (os.FileMode(attr.PermMode) & os.ModePerm)
-	a.Atime = attr.ModifiedT
+	a.Mode = os.ModeDir | fileModeFromPermMode(attr.PermMode)
+	a.Atime = attr.AccessedT
 	a.Mtime = attr.ModifiedT
-	a.Ctime = attr.ModifiedT
-	a.Crtime = attr.ModifiedT
+	a.Ctime = attr.ChangeT
+	a.Crtime = attr.CreatedT
 	a.Size = uint64(attr.Size)
 	a.Uid = attr.Uid
 	a.Gid = attr.Gid
@@ -58,7 +58,23 @@ func (d DirNode) Setattr(ctx context.Context, req *bfuse.SetattrRequest, resp *b
 	return nil
 }
 
-func (d DirNode) Lookup(ctx context.Context, name string) (bfs.Node, error) {
+func (d DirNode) Access(ctx context.Context, req *bfuse.AccessRequest) error {
+	return checkAccess(d.fs, d.id, req.Header, filesystem.AccessMode(req.Mask))
+}
+
+func (d DirNode) Open(ctx context.Context, req *bfuse.OpenRequest, resp *bfuse.OpenResponse) (bfs.Handle, error) {
+	if err := checkAccess(d.fs, d.id, req.Header, accessModeFromOpenFlags(req.Flags)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d DirNode) Lookup(ctx context.Context, req *bfuse.LookupRequest, resp *bfuse.LookupResponse) (bfs.Node, error) {
+	if err := checkAccess(d.fs, d.id, req.Header, filesystem.AccessExec); err != nil {
+		return nil, err
+	}
+	name := req.Name
+
 	entries, err := d.fs.DirEntries(d.id)
 	if err != nil {
 		return nil, err
@@ -86,13 +102,18 @@ func (d DirNode) Create(ctx context.Context, req *bfuse.CreateRequest, resp *bfu
 		return nil, nil, bfuse.EPERM
 	}
 
+	if err := checkAccess(d.fs, d.id, req.Header, filesystem.AccessWrite|filesystem.AccessExec); err != nil {
+		return nil, nil, err
+	}
+
+	afs := audited(d.fs, req.Header)
 	permmode := uint16(req.Mode &^ req.Umask & os.ModePerm)
-	id, err := d.fs.CreateFile(d.id, req.Name, permmode, req.Uid, req.Gid, time.Now())
+	id, err := afs.CreateFile(d.id, req.Name, permmode, req.Uid, req.Gid, time.Now())
 	if err != nil {
 		return nil, nil, err
 	}
 
-	h, err := d.fs.OpenFile(id, Bazil2OtaruFlags(req.Flags))
+	h, err := afs.OpenFile(id, Bazil2OtaruFlags(req.Flags))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -142,7 +163,21 @@ func (d DirNode) Rename(ctx context.Context, req *bfuse.RenameRequest, newDir bf
 		return fmt.Errorf("Node for provided target dir is not DirNode!")
 	}
 
-	if err := d.fs.Rename(d.id, req.OldName, newdn.id, req.NewName); err != nil {
+	if err := checkAccess(d.fs, d.id, req.Header, filesystem.AccessWrite|filesystem.AccessExec); err != nil {
+		return err
+	}
+	if err := checkAccess(d.fs, newdn.id, req.Header, filesystem.AccessWrite|filesystem.AccessExec); err != nil {
+		return err
+	}
+	c := callerFromHeader(req.Header)
+	if err := d.fs.CheckRemoveEntry(d.id, req.OldName, c); err != nil {
+		return err
+	}
+	if err := d.fs.CheckRemoveEntry(newdn.id, req.NewName, c); err != nil {
+		return err
+	}
+
+	if err := audited(d.fs, req.Header).Rename(d.id, req.OldName, newdn.id, req.NewName); err != nil {
 		return err
 	}
 
@@ -150,7 +185,14 @@ func (d DirNode) Rename(ctx context.Context, req *bfuse.RenameRequest, newDir bf
 }
 
 func (d DirNode) Remove(ctx context.Context, req *bfuse.RemoveRequest) error {
-	if err := d.fs.Remove(d.id, req.Name); err != nil {
+	if err := checkAccess(d.fs, d.id, req.Header, filesystem.AccessWrite|filesystem.AccessExec); err != nil {
+		return err
+	}
+	if err := d.fs.CheckRemoveEntry(d.id, req.Name, callerFromHeader(req.Header)); err != nil {
+		return err
+	}
+
+	if err := audited(d.fs, req.Header).Remove(d.id, req.Name); err != nil {
 		return err
 	}
 
@@ -158,8 +200,12 @@ func (d DirNode) Remove(ctx context.Context, req *bfuse.RemoveRequest) error {
 }
 
 func (d DirNode) Mkdir(ctx context.Context, req *bfuse.MkdirRequest) (bfs.Node, error) {
-	permmode := uint16(req.Mode &^ req.Umask & os.ModePerm)
-	id, err := d.fs.CreateDir(d.id, req.Name, permmode, req.Uid, req.Gid, time.Now())
+	if err := checkAccess(d.fs, d.id, req.Header, filesystem.AccessWrite|filesystem.AccessExec); err != nil {
+		return nil, err
+	}
+
+	permmode := permModeFromFileMode(req.Mode &^ req.Umask)
+	id, err := audited(d.fs, req.Header).CreateDir(d.id, req.Name, permmode, req.Uid, req.Gid, time.Now())
 	if err != nil {
 		return nil, err
 	}
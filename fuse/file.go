@@ -3,7 +3,6 @@ package fuse
 import (
 	"fmt"
 	"math"
-	"os"
 	"syscall"
 	"time"
 
@@ -37,12 +36,12 @@ func (n FileNode) Attr(ctx context.Context, a *bfuse.Attr) error {
 	a.Valid = 1 * time.Minute
 	a.Inode = uint64(n.id)
 	a.Size = uint64(attr.Size)
-	a.Blocks = a.Size / blockSize
-	a.Mode = os.FileMode(attr.PermMode) & os.ModePerm
-	a.Atime = attr.ModifiedT
+	a.Blocks = uint64((attr.AllocatedSize + blockSize - 1) / blockSize)
+	a.Mode = fileModeFromPermMode(attr.PermMode)
+	a.Atime = attr.AccessedT
 	a.Mtime = attr.ModifiedT
-	a.Ctime = attr.ModifiedT
-	a.Crtime = attr.ModifiedT
+	a.Ctime = attr.ChangeT
+	a.Crtime = attr.CreatedT
 	a.Nlink = 1
 	a.Uid = attr.Uid
 	a.Gid = attr.Gid
@@ -59,7 +58,10 @@ func (n FileNode) Setattr(ctx context.Context, req *bfuse.SetattrRequest, resp *
 		if req.Size > math.MaxInt64 {
 			return fmt.Errorf("specified size too big: %d", req.Size)
 		}
-		if err := n.fs.TruncateFile(n.id, int64(req.Size)); err != nil {
+		if err := checkAccess(n.fs, n.id, req.Header, filesystem.AccessWrite); err != nil {
+			return err
+		}
+		if err := audited(n.fs, req.Header).TruncateFile(n.id, int64(req.Size)); err != nil {
 			return err
 		}
 	}
@@ -75,10 +77,18 @@ func (n FileNode) Setattr(ctx context.Context, req *bfuse.SetattrRequest, resp *
 	return nil
 }
 
+func (n FileNode) Access(ctx context.Context, req *bfuse.AccessRequest) error {
+	return checkAccess(n.fs, n.id, req.Header, filesystem.AccessMode(req.Mask))
+}
+
 func (n FileNode) Open(ctx context.Context, req *bfuse.OpenRequest, resp *bfuse.OpenResponse) (bfs.Handle, error) {
 	zap.S().Debugf("Open flags: %s", req.Flags.String())
 
-	fh, err := n.fs.OpenFile(n.id, Bazil2OtaruFlags(req.Flags))
+	if err := checkAccess(n.fs, n.id, req.Header, accessModeFromOpenFlags(req.Flags)); err != nil {
+		return nil, err
+	}
+
+	fh, err := audited(n.fs, req.Header).OpenFile(n.id, Bazil2OtaruFlags(req.Flags))
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +51,10 @@ func Serve(ctx context.Context, bucketName string, mountpoint string, ofs *files
 	fsName := fmt.Sprintf("otaru+gs://%s", bucketName)
 	volName := fmt.Sprintf("Otaru %s", bucketName)
 
+	// The mount doesn't negotiate FUSE_POSIX_LOCKS nor FUSE_FLOCK_LOCKS, so
+	// the kernel keeps flock(2) and fcntl(2) locks local to this host and
+	// never sends GETLK/SETLK/SETLKW, which github.com/nyaxt/fuse can't
+	// decode. Don't add a mount option enabling either flag until it can.
 	c, err := bfuse.Mount(
 		mountpoint,
 		bfuse.FSName(fsName),
@@ -2,6 +2,7 @@ package testutils
 
 import (
 	"log"
+	"sync"
 
 	"golang.org/x/oauth2"
 
@@ -9,12 +10,27 @@ import (
 	"github.com/nyaxt/otaru/gcloud/auth"
 	"github.com/nyaxt/otaru/gcloud/datastore"
 	"github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/testutils/fakegcs"
 )
 
+// CredentialsFilePath is the path to the Google Cloud service account json file.
+// Empty if not found, in which case tests run against local stand-ins where available.
 var CredentialsFilePath string
 
+// localDS is shared among all TestDSConfig, so that they observe each other's writes as with Cloud Datastore.
+var localDS = datastore.NewLocalDatastore()
+
+// fakeGCS is started on first TestGCSEndpoint call, and shared among all tests in the process.
+var (
+	fakeGCSOnce sync.Once
+	fakeGCS     *fakegcs.Server
+)
+
 func init() {
 	CredentialsFilePath = facade.FindGCPServiceAccountJSON("")
+}
+
+func requireCredentials() {
 	if CredentialsFilePath == "" {
 		panic("Failed to find Google Cloud service account json file.")
 	}
@@ -30,6 +46,7 @@ func TestConfig() *facade.Config {
 }
 
 func TestTokenSource() oauth2.TokenSource {
+	requireCredentials()
 	clisrc, err := auth.GetGCloudTokenSource(CredentialsFilePath)
 	if err != nil {
 		log.Fatalf("Failed to create TestTokenSource: %v", err)
@@ -37,7 +54,33 @@ func TestTokenSource() oauth2.TokenSource {
 	return clisrc
 }
 
+// TestDSConfig returns a Config for the Cloud Datastore of the test project, or a
+// LocalDatastore if Google Cloud credentials are not available.
 func TestDSConfig(rootKeyStr string) *datastore.Config {
+	if CredentialsFilePath == "" {
+		return datastore.NewLocalConfig(rootKeyStr, testutils.TestCipher(), localDS)
+	}
 	projectName := TestConfig().ProjectName
 	return datastore.NewConfig(projectName, rootKeyStr, testutils.TestCipher(), TestTokenSource())
 }
+
+// TestGCSEndpoint returns the endpoint to be given to gcs.NewGCSBlobStore. It is the
+// fake GCS server if Google Cloud credentials are not available, and "" otherwise.
+func TestGCSEndpoint() string {
+	if CredentialsFilePath != "" {
+		return ""
+	}
+	fakeGCSOnce.Do(func() {
+		fakeGCS = fakegcs.NewServer()
+	})
+	return fakeGCS.URL()
+}
+
+// TestGCSTokenSource returns the TokenSource to be given to gcs.NewGCSBlobStore,
+// which is nil if Google Cloud credentials are not available.
+func TestGCSTokenSource() oauth2.TokenSource {
+	if CredentialsFilePath == "" {
+		return nil
+	}
+	return TestTokenSource()
+}
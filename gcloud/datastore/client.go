@@ -0,0 +1,124 @@
+package datastore
+
+import (
+	"context"
+
+	"cloud.google.com/go/datastore"
+)
+
+// Client is the subset of Cloud Datastore API used by otaru.
+type Client interface {
+	NewTransaction(ctx context.Context) (Transaction, error)
+	Close() error
+}
+
+// Transaction is a Cloud Datastore transaction.
+// Like Cloud Datastore, reads in a Transaction don't observe its own uncommitted mutations.
+type Transaction interface {
+	// Get loads the entity at key into dst. Returns datastore.ErrNoSuchEntity if there is no such entity.
+	Get(key *datastore.Key, dst interface{}) error
+	Put(key *datastore.Key, src interface{}) error
+	Delete(key *datastore.Key) error
+	DeleteMulti(keys []*datastore.Key) error
+
+	// Run runs the query q in the transaction.
+	Run(ctx context.Context, q *Query) Iterator
+
+	// Commit applies the mutations of the transaction. Returns datastore.ErrConcurrentTransaction
+	// if the entity groups read in the transaction were modified by other transactions.
+	Commit() error
+	Rollback() error
+}
+
+// Iterator iterates over query results. Next returns iterator.Done at the end of results.
+type Iterator interface {
+	Next(dst interface{}) (*datastore.Key, error)
+}
+
+// Query is an ancestor query of entities of Kind.
+type Query struct {
+	Kind     string
+	Ancestor *datastore.Key
+
+	// If non-nil, only entities with key >= KeyGE are returned.
+	KeyGE *datastore.Key
+	// If non-nil, only entities with key < KeyLT are returned.
+	KeyLT *datastore.Key
+
+	// If non-empty, results are ordered by the property in descending order. Otherwise, ordered by key.
+	OrderDesc string
+	Offset    int
+	// Max number of results. Unlimited if 0.
+	Limit int
+
+	// If true, dst given to Iterator.Next is ignored.
+	KeysOnly bool
+}
+
+type cloudClient struct {
+	cli *datastore.Client
+}
+
+var _ = Client(cloudClient{})
+
+func (c cloudClient) NewTransaction(ctx context.Context) (Transaction, error) {
+	tx, err := c.cli.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cloudTransaction{c.cli, tx}, nil
+}
+
+func (c cloudClient) Close() error { return c.cli.Close() }
+
+type cloudTransaction struct {
+	cli *datastore.Client
+	tx  *datastore.Transaction
+}
+
+func (t cloudTransaction) Get(key *datastore.Key, dst interface{}) error {
+	return t.tx.Get(key, dst)
+}
+
+func (t cloudTransaction) Put(key *datastore.Key, src interface{}) error {
+	_, err := t.tx.Put(key, src)
+	return err
+}
+
+func (t cloudTransaction) Delete(key *datastore.Key) error {
+	return t.tx.Delete(key)
+}
+
+func (t cloudTransaction) DeleteMulti(keys []*datastore.Key) error {
+	return t.tx.DeleteMulti(keys)
+}
+
+func (t cloudTransaction) Run(ctx context.Context, q *Query) Iterator {
+	dq := datastore.NewQuery(q.Kind).Ancestor(q.Ancestor)
+	if q.KeyGE != nil {
+		dq = dq.Filter("__key__ >=", q.KeyGE)
+	}
+	if q.KeyLT != nil {
+		dq = dq.Filter("__key__ <", q.KeyLT)
+	}
+	if q.OrderDesc != "" {
+		dq = dq.Order("-" + q.OrderDesc)
+	}
+	if q.Offset != 0 {
+		dq = dq.Offset(q.Offset)
+	}
+	if q.Limit != 0 {
+		dq = dq.Limit(q.Limit)
+	}
+	if q.KeysOnly {
+		dq = dq.KeysOnly()
+	}
+	return t.cli.Run(ctx, dq.Transaction(t.tx))
+}
+
+func (t cloudTransaction) Commit() error {
+	_, err := t.tx.Commit()
+	return err
+}
+
+func (t cloudTransaction) Rollback() error { return t.tx.Rollback() }
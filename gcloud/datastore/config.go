@@ -17,6 +17,9 @@ type Config struct {
 	rootKeyStr  string
 	c           *btncrypt.Cipher
 	tsrc        oauth2.TokenSource
+
+	// If non-nil, used instead of Cloud Datastore.
+	local *LocalDatastore
 }
 
 func NewConfig(projectName, rootKeyStr string, c *btncrypt.Cipher, tsrc oauth2.TokenSource) *Config {
@@ -38,6 +41,30 @@ func NewConfig(projectName, rootKeyStr string, c *btncrypt.Cipher, tsrc oauth2.T
 	}
 }
 
-func (cfg *Config) getClient(ctx context.Context) (*datastore.Client, error) {
-	return datastore.NewClient(ctx, cfg.projectName, option.WithTokenSource(cfg.tsrc))
+// NewLocalConfig returns a Config which uses the LocalDatastore ld instead of Cloud Datastore.
+func NewLocalConfig(rootKeyStr string, c *btncrypt.Cipher, ld *LocalDatastore) *Config {
+	if len(rootKeyStr) == 0 {
+		panic("empty rootKeyStr")
+	}
+	if ld == nil {
+		panic("nil LocalDatastore")
+	}
+
+	return &Config{
+		rootKeyStr: rootKeyStr,
+		c:          c,
+		local:      ld,
+	}
+}
+
+func (cfg *Config) getClient(ctx context.Context) (Client, error) {
+	if cfg.local != nil {
+		return cfg.local.NewClient(), nil
+	}
+
+	cli, err := datastore.NewClient(ctx, cfg.projectName, option.WithTokenSource(cfg.tsrc))
+	if err != nil {
+		return nil, err
+	}
+	return cloudClient{cli}, nil
 }
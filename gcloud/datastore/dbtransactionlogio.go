@@ -177,13 +177,13 @@ func (txio *DBTransactionLogIO) Sync() error {
 		return err
 	}
 
-	if _, err := dstx.Put(key, txbatch); err != nil {
+	if err := dstx.Put(key, txbatch); err != nil {
 		rollback()
 		dstx.Rollback()
 		return err
 	}
 
-	if _, err := dstx.Commit(); err != nil {
+	if err := dstx.Commit(); err != nil {
 		rollback()
 		return err
 	}
@@ -240,13 +240,12 @@ func (txio *DBTransactionLogIO) queryTransactionsOnce(minID inodedb.TxID) ([]ino
 		return nil, err
 	}
 
-	q := datastore.NewQuery(kindTransaction).Ancestor(txio.rootKey).Transaction(dstx)
+	q := &Query{Kind: kindTransaction, Ancestor: txio.rootKey}
 	if minID != inodedb.AnyVersion {
-		minKey := txio.encodeKey(minID)
-		q = q.Filter("__key__ >=", minKey)
+		q.KeyGE = txio.encodeKey(minID)
 	}
 
-	it := cli.Run(context.Background(), q)
+	it := dstx.Run(context.Background(), q)
 	for {
 		var stx storedbtx
 		key, err := it.Next(&stx)
@@ -272,7 +271,7 @@ func (txio *DBTransactionLogIO) queryTransactionsOnce(minID inodedb.TxID) ([]ino
 	}
 
 	// FIXME: not sure if Rollback() is better
-	if _, err := dstx.Commit(); err != nil {
+	if err := dstx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -329,8 +328,8 @@ func (txio *DBTransactionLogIO) DeleteTransactions(smallerThanID inodedb.TxID) e
 
 		keys := []*datastore.Key{}
 		ltkey := txio.encodeKey(smallerThanID)
-		q := datastore.NewQuery(kindTransaction).Ancestor(txio.rootKey).Filter("__key__ <", ltkey).KeysOnly().Transaction(dstx)
-		it := cli.Run(context.Background(), q)
+		q := &Query{Kind: kindTransaction, Ancestor: txio.rootKey, KeyLT: ltkey, KeysOnly: true}
+		it := dstx.Run(context.Background(), q)
 		for {
 			k, err := it.Next(nil)
 			if err != nil {
@@ -353,7 +352,7 @@ func (txio *DBTransactionLogIO) DeleteTransactions(smallerThanID inodedb.TxID) e
 			return err
 		}
 
-		if _, err := dstx.Commit(); err != nil {
+		if err := dstx.Commit(); err != nil {
 			return err
 		}
 		ndel += len(keys)
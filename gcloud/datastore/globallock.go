@@ -22,6 +22,16 @@ type lockEntry struct {
 	CreatedAt time.Time `datastore:,noindex`
 	HostName  string    `datastore:,noindex`
 	Info      string    `datastore:,noindex`
+
+	// ExpiresAt is the time the lease expires unless renewed.
+	// The zero value means the lock never expires.
+	ExpiresAt time.Time `datastore:,noindex`
+	// HandoffRequestedBy is the host name of the writer waiting for the lock holder to release the lock.
+	HandoffRequestedBy string `datastore:,noindex`
+}
+
+func (e lockEntry) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
 }
 
 type GlobalLocker struct {
@@ -29,6 +39,10 @@ type GlobalLocker struct {
 	rootKey      *datastore.Key
 	lockEntryKey *datastore.Key
 
+	// LeaseDuration is the duration the lock is valid for without renewal.
+	// If <= 0, the lock never expires and has to be released explicitly.
+	LeaseDuration time.Duration
+
 	lockEntry
 }
 
@@ -57,8 +71,16 @@ func (e *ErrLockTaken) Error() string {
 	return fmt.Sprintf("GlobalLock is taken by host \"%s\" at %s. Info: %s", e.HostName, e.CreatedAt, e.Info)
 }
 
+func (l *GlobalLocker) expiresAt(now time.Time) time.Time {
+	if l.LeaseDuration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(l.LeaseDuration)
+}
+
 // Lock attempts to acquire the global lock.
 // If the lock was already taken by other GlobalLocker instance, it will return an ErrLockTaken.
+// A lock whose lease has expired is taken over.
 func (l *GlobalLocker) tryLockOnce(ctx context.Context, readOnly bool) error {
 	start := time.Now()
 	cli, err := l.cfg.getClient(ctx)
@@ -73,23 +95,27 @@ func (l *GlobalLocker) tryLockOnce(ctx context.Context, readOnly bool) error {
 	}
 
 	var e lockEntry
-	if err := dstx.Get(l.lockEntryKey, &e); err != datastore.ErrNoSuchEntity {
-		dstx.Rollback()
-		if err == nil {
+	if err := dstx.Get(l.lockEntryKey, &e); err == nil {
+		if !e.expired(start) {
+			dstx.Rollback()
 			return &ErrLockTaken{CreatedAt: e.CreatedAt, HostName: e.HostName, Info: e.Info}
-		} else {
-			return err
 		}
+		zap.S().Warnf("GlobalLocker.tryLockOnce(): The lease of the lock entry expired at %s. Taking over: %+v", e.ExpiresAt, e)
+	} else if err != datastore.ErrNoSuchEntity {
+		dstx.Rollback()
+		return err
 	}
 
 	if !readOnly {
 		l.lockEntry.CreatedAt = start
-		if _, err := dstx.Put(l.lockEntryKey, &l.lockEntry); err != nil {
+		l.lockEntry.ExpiresAt = l.expiresAt(start)
+		l.lockEntry.HandoffRequestedBy = ""
+		if err := dstx.Put(l.lockEntryKey, &l.lockEntry); err != nil {
 			dstx.Rollback()
 			return err
 		}
 	}
-	if _, err := dstx.Commit(); err != nil {
+	if err := dstx.Commit(); err != nil {
 		return err
 	}
 
@@ -104,6 +130,122 @@ func (l *GlobalLocker) Lock(ctx context.Context, readOnly bool) error {
 	}, lklog)
 }
 
+// LockWithHandoff acquires the global lock for writing. If the lock is taken
+// by other GlobalLocker instance, it asks the holder to release the lock, and
+// waits for the lock until ctx is done.
+func (l *GlobalLocker) LockWithHandoff(ctx context.Context) error {
+	zap.S().Infof("GlobalLocker.LockWithHandoff() started.")
+	for {
+		err := l.Lock(ctx, false)
+		if _, ok := err.(*ErrLockTaken); !ok {
+			return err
+		}
+		if err := l.RequestHandoff(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Gave up waiting for the lock holder to hand off: %w", err)
+		case <-time.After(HandoffPollInterval):
+		}
+	}
+}
+
+// HandoffPollInterval is the interval LockWithHandoff retries acquiring the lock.
+var HandoffPollInterval = 1 * time.Second
+
+func (l *GlobalLocker) requestHandoffOnce(ctx context.Context) error {
+	cli, err := l.cfg.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	dstx, err := cli.NewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var e lockEntry
+	if err := dstx.Get(l.lockEntryKey, &e); err != nil {
+		dstx.Rollback()
+		if err == datastore.ErrNoSuchEntity {
+			// The lock is already released.
+			return nil
+		}
+		return err
+	}
+	if e.HandoffRequestedBy == l.HostName || checkLock(l.lockEntry, e, checkCreatedAt) {
+		dstx.Rollback()
+		return nil
+	}
+	zap.S().Infof("GlobalLocker.RequestHandoff(): Requesting host \"%s\" to hand off the lock.", e.HostName)
+	e.HandoffRequestedBy = l.HostName
+	if err := dstx.Put(l.lockEntryKey, &e); err != nil {
+		dstx.Rollback()
+		return err
+	}
+	return dstx.Commit()
+}
+
+// RequestHandoff asks the current lock holder to flush its changes and release the lock.
+// The holder notices the request on its next Renew.
+func (l *GlobalLocker) RequestHandoff(ctx context.Context) error {
+	return gcutil.RetryIfNeeded(func() error {
+		return l.requestHandoffOnce(ctx)
+	}, lklog)
+}
+
+func (l *GlobalLocker) renewOnce(ctx context.Context) (string, error) {
+	now := time.Now()
+	cli, err := l.cfg.getClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cli.Close()
+
+	dstx, err := cli.NewTransaction(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var e lockEntry
+	if err := dstx.Get(l.lockEntryKey, &e); err != nil {
+		dstx.Rollback()
+		if err == datastore.ErrNoSuchEntity {
+			return "", ErrNoLock
+		}
+		return "", err
+	}
+	if !checkLock(l.lockEntry, e, checkCreatedAt) {
+		dstx.Rollback()
+		return "", &ErrLockTaken{CreatedAt: e.CreatedAt, HostName: e.HostName, Info: e.Info}
+	}
+	e.ExpiresAt = l.expiresAt(now)
+	if err := dstx.Put(l.lockEntryKey, &e); err != nil {
+		dstx.Rollback()
+		return "", err
+	}
+	if err := dstx.Commit(); err != nil {
+		return "", err
+	}
+	l.lockEntry.ExpiresAt = e.ExpiresAt
+	return e.HandoffRequestedBy, nil
+}
+
+// Renew extends the lease of the global lock held by this GlobalLocker.
+// It returns the host name of the writer which requested handoff, if any.
+// If the lock was taken over by other GlobalLocker, Renew will fail with ErrLockTaken.
+func (l *GlobalLocker) Renew(ctx context.Context) (handoffRequestedBy string, err error) {
+	err = gcutil.RetryIfNeeded(func() error {
+		var err error
+		handoffRequestedBy, err = l.renewOnce(ctx)
+		return err
+	}, lklog)
+	return
+}
+
 // ForceUnlock releases the global lock entry forcibly, even if it was held by other GlobalLocker instance.
 // If there was no lock, ForceUnlock will log an warning, but return no error.
 func (l *GlobalLocker) forceUnlockOnce(ctx context.Context) error {
@@ -132,7 +274,7 @@ func (l *GlobalLocker) forceUnlockOnce(ctx context.Context) error {
 		return err
 	}
 
-	if _, err := dstx.Commit(); err != nil {
+	if err := dstx.Commit(); err != nil {
 		return err
 	}
 
@@ -154,8 +296,8 @@ func closeEnough(a, b time.Time) bool {
 }
 
 const (
-	checkCreatedAt  = false
-	ignoreCreatedAt = true
+	checkCreatedAt  = true
+	ignoreCreatedAt = false
 )
 
 // Unlock releases the global lock previously taken by this GlobalLocker.
@@ -172,7 +314,7 @@ func (l *GlobalLocker) UnlockIgnoreCreatedAt(ctx context.Context) error {
 }
 
 func checkLock(a, b lockEntry, checkCreatedAtFlag bool) bool {
-	if checkCreatedAt && !closeEnough(a.CreatedAt, b.CreatedAt) {
+	if checkCreatedAtFlag && !closeEnough(a.CreatedAt, b.CreatedAt) {
 		return false
 	}
 	if a.HostName != b.HostName {
@@ -212,7 +354,7 @@ func (l *GlobalLocker) unlockInternalOnce(checkCreatedAtFlag bool) error {
 		return err
 	}
 
-	if _, err := dstx.Commit(); err != nil {
+	if err := dstx.Commit(); err != nil {
 		return err
 	}
 
@@ -265,3 +407,122 @@ func (l *GlobalLocker) Query(ctx context.Context) (lockEntry, error) {
 	}, lklog)
 	return le, err
 }
+
+// LeaseRenewer renews the lease of the global lock held by a GlobalLocker
+// periodically, until Stop is called.
+type LeaseRenewer struct {
+	l          *GlobalLocker
+	onHandoff  func(requestedBy string)
+	onExpiring func(expiring bool)
+	onLost     func(err error)
+
+	cancel context.CancelFunc
+	doneC  chan struct{}
+}
+
+// StartRenewing starts renewing the lease every LeaseDuration/4.
+// onHandoff is called once when other writer requested handoff. The lease is kept renewed
+// until Stop, so that the holder can flush its changes before releasing the lock.
+// onExpiring(true) is called if the lease couldn't be renewed until LeaseDuration/4 before
+// it expires. The margin is left for the holder to stop writing before other writer can
+// take over the lock, and to tolerate clock skew between hosts. Renewing is retried, and
+// onExpiring(false) is called once a renewal confirms the lock is still held.
+// onLost is called if the lock entry is held by other writer, or is gone. The lock is
+// never regained then, so renewing stops after onLost is called.
+func (l *GlobalLocker) StartRenewing(onHandoff func(requestedBy string), onExpiring func(expiring bool), onLost func(err error)) *LeaseRenewer {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &LeaseRenewer{
+		l:          l,
+		onHandoff:  onHandoff,
+		onExpiring: onExpiring,
+		onLost:     onLost,
+		cancel:     cancel,
+		doneC:      make(chan struct{}),
+	}
+	go r.run(ctx, l.lockEntry.ExpiresAt)
+	return r
+}
+
+type renewResult struct {
+	handoffRequestedBy string
+	expiresAt          time.Time
+	err                error
+}
+
+func (r *LeaseRenewer) run(ctx context.Context, expiresAt time.Time) {
+	defer close(r.doneC)
+
+	ticker := time.NewTicker(r.l.LeaseDuration / 4)
+	defer ticker.Stop()
+
+	margin := r.l.LeaseDuration / 4
+	deadline := time.NewTimer(time.Until(expiresAt) - margin)
+	defer deadline.Stop()
+
+	// Renew in a separate goroutine, so that a stuck Renew doesn't hold off the deadline.
+	resC := make(chan renewResult, 1)
+	renewing := false
+	defer func() {
+		if renewing {
+			r.cancel()
+			<-resC
+		}
+	}()
+
+	handoffNotified := false
+	expiring := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			zap.S().Warnf("Failed to renew the global lock lease before its safety margin. Retrying.")
+			expiring = true
+			r.onExpiring(true)
+		case <-ticker.C:
+			if !renewing {
+				renewing = true
+				go func() {
+					handoffRequestedBy, err := r.l.Renew(ctx)
+					resC <- renewResult{handoffRequestedBy, r.l.lockEntry.ExpiresAt, err}
+				}()
+			}
+		case res := <-resC:
+			renewing = false
+			if res.err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				_, taken := res.err.(*ErrLockTaken)
+				if taken || res.err == ErrNoLock {
+					r.onLost(res.err)
+					return
+				}
+				zap.S().Warnf("Failed to renew the global lock lease: %v", res.err)
+				continue
+			}
+
+			if expiring {
+				// The deadline has fired and been received.
+				zap.S().Infof("Renewed the global lock lease after it was expiring.")
+				expiring = false
+				r.onExpiring(false)
+			} else if !deadline.Stop() {
+				<-deadline.C
+			}
+			deadline.Reset(time.Until(res.expiresAt) - margin)
+
+			if res.handoffRequestedBy != "" && !handoffNotified {
+				zap.S().Infof("Host \"%s\" requested handoff of the global lock.", res.handoffRequestedBy)
+				handoffNotified = true
+				r.onHandoff(res.handoffRequestedBy)
+			}
+		}
+	}
+}
+
+// Stop stops renewing the lease. The lock is not released.
+func (r *LeaseRenewer) Stop() {
+	r.cancel()
+	<-r.doneC
+}
@@ -3,6 +3,7 @@ package datastore_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	authtu "github.com/nyaxt/otaru/gcloud/auth/testutils"
 	"github.com/nyaxt/otaru/gcloud/datastore"
@@ -145,3 +146,184 @@ func TestGlobalLocker_ReadOnlyLock(t *testing.T) {
 		t.Errorf("lr.Lock() unexpected (no) err: %v", err)
 	}
 }
+
+func TestGlobalLocker_LeaseExpiry(t *testing.T) {
+	muTest.Lock()
+	defer muTest.Unlock()
+
+	ctx := context.Background()
+
+	l1 := datastore.NewGlobalLocker(authtu.TestDSConfig(authtu.TestBucketName), "otaru-unittest-1", "hogefuga")
+	l2 := datastore.NewGlobalLocker(authtu.TestDSConfig(authtu.TestBucketName), "otaru-unittest-2", "foobar")
+	l1.LeaseDuration = 100 * time.Millisecond
+
+	if err := l1.ForceUnlock(ctx); err != nil {
+		t.Errorf("ForceUnlock() failed: %v", err)
+		return
+	}
+	defer l1.ForceUnlock(ctx)
+
+	if err := l1.Lock(ctx, notReadOnly); err != nil {
+		t.Errorf("l1.Lock() failed: %v", err)
+	}
+	err := l2.Lock(ctx, notReadOnly)
+	if _, ok := err.(*datastore.ErrLockTaken); !ok {
+		t.Errorf("l2.Lock() unexpected (no) err: %v", err)
+	}
+	if _, err := l1.Renew(ctx); err != nil {
+		t.Errorf("l1.Renew() failed: %v", err)
+	}
+
+	// l2 takes over the lock after the lease expires.
+	time.Sleep(200 * time.Millisecond)
+	if err := l2.Lock(ctx, notReadOnly); err != nil {
+		t.Errorf("l2.Lock() failed: %v", err)
+	}
+
+	// l1 renew should fail
+	_, err = l1.Renew(ctx)
+	if _, ok := err.(*datastore.ErrLockTaken); !ok {
+		t.Errorf("l1.Renew() unexpected (no) err: %v", err)
+	}
+
+	// l2 lease never expires.
+	time.Sleep(200 * time.Millisecond)
+	err = l1.Lock(ctx, notReadOnly)
+	if _, ok := err.(*datastore.ErrLockTaken); !ok {
+		t.Errorf("l1.Lock() unexpected (no) err: %v", err)
+	}
+}
+
+func TestGlobalLocker_Handoff(t *testing.T) {
+	muTest.Lock()
+	defer muTest.Unlock()
+
+	ctx := context.Background()
+
+	l1 := datastore.NewGlobalLocker(authtu.TestDSConfig(authtu.TestBucketName), "otaru-unittest-1", "hogefuga")
+	l2 := datastore.NewGlobalLocker(authtu.TestDSConfig(authtu.TestBucketName), "otaru-unittest-2", "foobar")
+	l1.LeaseDuration = 200 * time.Millisecond
+	l2.LeaseDuration = 200 * time.Millisecond
+
+	origInterval := datastore.HandoffPollInterval
+	datastore.HandoffPollInterval = 10 * time.Millisecond
+	defer func() { datastore.HandoffPollInterval = origInterval }()
+
+	if err := l1.ForceUnlock(ctx); err != nil {
+		t.Errorf("ForceUnlock() failed: %v", err)
+		return
+	}
+	defer l1.ForceUnlock(ctx)
+
+	if err := l1.Lock(ctx, notReadOnly); err != nil {
+		t.Errorf("l1.Lock() failed: %v", err)
+	}
+
+	handoffC := make(chan string, 1)
+	r := l1.StartRenewing(func(requestedBy string) {
+		handoffC <- requestedBy
+	}, func(expiring bool) {
+		t.Errorf("l1 lease expiring: %t", expiring)
+	}, func(err error) {
+		t.Errorf("l1 lost the lock: %v", err)
+	})
+
+	lockedC := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		lockedC <- l2.LockWithHandoff(ctx)
+	}()
+
+	select {
+	case requestedBy := <-handoffC:
+		if requestedBy != "otaru-unittest-2" {
+			t.Errorf("Unexpected handoff requester: %q", requestedBy)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for handoff request.")
+	}
+
+	// The lease is kept renewed while l1 flushes.
+	time.Sleep(300 * time.Millisecond)
+	select {
+	case err := <-lockedC:
+		t.Fatalf("l2 acquired the lock before l1 released it: %v", err)
+	default:
+	}
+
+	r.Stop()
+	if err := l1.Unlock(ctx); err != nil {
+		t.Errorf("l1.Unlock() failed: %v", err)
+	}
+	if err := <-lockedC; err != nil {
+		t.Errorf("l2.LockWithHandoff() failed: %v", err)
+	}
+	if err := l2.Unlock(ctx); err != nil {
+		t.Errorf("l2.Unlock() failed: %v", err)
+	}
+}
+
+func TestGlobalLocker_LeaseExpiring(t *testing.T) {
+	muTest.Lock()
+	defer muTest.Unlock()
+
+	ctx := context.Background()
+
+	l1 := datastore.NewGlobalLocker(authtu.TestDSConfig(authtu.TestBucketName), "otaru-unittest-1", "hogefuga")
+	l2 := datastore.NewGlobalLocker(authtu.TestDSConfig(authtu.TestBucketName), "otaru-unittest-2", "foobar")
+	l1.LeaseDuration = 200 * time.Millisecond
+
+	if err := l1.ForceUnlock(ctx); err != nil {
+		t.Errorf("ForceUnlock() failed: %v", err)
+		return
+	}
+	defer l1.ForceUnlock(ctx)
+
+	if err := l1.Lock(ctx, notReadOnly); err != nil {
+		t.Errorf("l1.Lock() failed: %v", err)
+	}
+
+	// The lease isn't renewed before the safety margin preceding its expiry.
+	time.Sleep(140 * time.Millisecond)
+	expiringC := make(chan bool, 2)
+	lostC := make(chan error, 1)
+	r := l1.StartRenewing(func(requestedBy string) {
+		t.Errorf("Unexpected handoff request from %q", requestedBy)
+	}, func(expiring bool) {
+		expiringC <- expiring
+	}, func(err error) {
+		lostC <- err
+	})
+	defer r.Stop()
+
+	// The lock is kept, as a later renewal confirms it's still held.
+	for _, expected := range []bool{true, false} {
+		select {
+		case expiring := <-expiringC:
+			if expiring != expected {
+				t.Errorf("Unexpected onExpiring(%t), expected %t", expiring, expected)
+			}
+		case err := <-lostC:
+			t.Fatalf("Unexpected onLost: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for onExpiring(%t).", expected)
+		}
+	}
+
+	// The lock is lost once other writer holds it.
+	if err := l1.ForceUnlock(ctx); err != nil {
+		t.Fatalf("ForceUnlock() failed: %v", err)
+	}
+	if err := l2.Lock(ctx, notReadOnly); err != nil {
+		t.Fatalf("l2.Lock() failed: %v", err)
+	}
+	select {
+	case err := <-lostC:
+		if _, ok := err.(*datastore.ErrLockTaken); !ok {
+			t.Errorf("Unexpected onLost err: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the lock to be lost.")
+	}
+}
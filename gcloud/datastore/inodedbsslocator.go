@@ -56,8 +56,8 @@ func (loc *INodeDBSSLocator) tryLocateOnce(history int) (string, int64, error) {
 		return "", 0, err
 	}
 
-	q := datastore.NewQuery(kindINodeDBSS).Ancestor(loc.rootKey).Order("-TxID").Offset(history).Limit(1).Transaction(dstx)
-	it := cli.Run(context.TODO(), q)
+	q := &Query{Kind: kindINodeDBSS, Ancestor: loc.rootKey, OrderDesc: "TxID", Offset: history, Limit: 1}
+	it := dstx.Run(context.TODO(), q)
 	var e sslocentry
 	if _, err := it.Next(&e); err != nil {
 		dstx.Rollback()
@@ -67,7 +67,7 @@ func (loc *INodeDBSSLocator) tryLocateOnce(history int) (string, int64, error) {
 		return "", 0, err
 	}
 
-	if _, err := dstx.Commit(); err != nil {
+	if err := dstx.Commit(); err != nil {
 		return "", 0, err
 	}
 
@@ -99,11 +99,11 @@ func (loc *INodeDBSSLocator) tryPutOnce(blobpath string, txid int64) error {
 	}
 
 	key := datastore.IDKey(kindINodeDBSS, int64(e.TxID), loc.rootKey)
-	if _, err := dstx.Put(key, &e); err != nil {
+	if err := dstx.Put(key, &e); err != nil {
 		dstx.Rollback()
 		return err
 	}
-	if _, err := dstx.Commit(); err != nil {
+	if err := dstx.Commit(); err != nil {
 		return err
 	}
 
@@ -149,8 +149,8 @@ func (loc *INodeDBSSLocator) DeleteOld(ctx context.Context, threshold int, dryRu
 		}
 
 		keys := make([]*datastore.Key, 0)
-		q := datastore.NewQuery(kindINodeDBSS).Ancestor(loc.rootKey).Order("-TxID").Offset(threshold).Transaction(dstx)
-		it := cli.Run(ctx, q)
+		q := &Query{Kind: kindINodeDBSS, Ancestor: loc.rootKey, OrderDesc: "TxID", Offset: threshold}
+		it := dstx.Run(ctx, q)
 		for {
 			var e sslocentry
 			k, err := it.Next(&e)
@@ -177,7 +177,7 @@ func (loc *INodeDBSSLocator) DeleteOld(ctx context.Context, threshold int, dryRu
 			}
 		}
 
-		if _, err := dstx.Commit(); err != nil {
+		if err := dstx.Commit(); err != nil {
 			return nil, err
 		}
 		ndel += len(keys)
@@ -0,0 +1,540 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/datastore"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+)
+
+// MaxMutationsPerTx is the max number of mutations LocalDatastore allows in a transaction.
+// This mirrors the limit of Cloud Datastore.
+const MaxMutationsPerTx = maxWriteEntriesPerTx
+
+var (
+	ErrTxDone           = errors.New("datastore: transaction already committed or rolled back")
+	ErrTooManyMutations = fmt.Errorf("datastore: more than %d mutations in a transaction", MaxMutationsPerTx)
+)
+
+type localEntity struct {
+	key   *datastore.Key
+	props []datastore.Property
+}
+
+// LocalDatastore is an in-process stand-in of Cloud Datastore, optionally backed by a file.
+// It implements the transactional semantics otaru relies on: transactions are
+// serializable, and a transaction fails to commit with datastore.ErrConcurrentTransaction if
+// any entity group it read was modified after the read.
+type LocalDatastore struct {
+	// If non-empty, entities are persisted to the file on each commit.
+	path string
+
+	mu       sync.Mutex
+	entities map[string]*localEntity
+	// version of entity groups, keyed by encoded root key.
+	groupVers map[string]int64
+}
+
+func NewLocalDatastore() *LocalDatastore {
+	return &LocalDatastore{
+		entities:  make(map[string]*localEntity),
+		groupVers: make(map[string]int64),
+	}
+}
+
+// OpenLocalDatastore opens a LocalDatastore persisted to the file at path.
+// The file is created on the first commit if it doesn't exist.
+// The file must not be shared by multiple processes.
+func OpenLocalDatastore(path string) (*LocalDatastore, error) {
+	ld := NewLocalDatastore()
+	ld.path = path
+
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ld, nil
+		}
+		return nil, fmt.Errorf("Failed to read local datastore file \"%s\": %v", path, err)
+	}
+
+	var ses []storedEntity
+	if err := gob.NewDecoder(bytes.NewReader(bs)).Decode(&ses); err != nil {
+		return nil, fmt.Errorf("Failed to decode local datastore file \"%s\": %v", path, err)
+	}
+	for _, se := range ses {
+		key, err := datastore.DecodeKey(se.Key)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode key \"%s\": %v", se.Key, err)
+		}
+		props := make([]datastore.Property, 0, len(se.Props))
+		for _, sp := range se.Props {
+			props = append(props, datastore.Property{Name: sp.Name, Value: sp.Value, NoIndex: sp.NoIndex})
+		}
+		ld.entities[se.Key] = &localEntity{key: key, props: props}
+	}
+	zap.S().Infof("Loaded %d entities from local datastore file \"%s\".", len(ses), path)
+	return ld, nil
+}
+
+func (*LocalDatastore) ImplName() string { return "gcloud/datastore.LocalDatastore" }
+
+// NewClient returns a Client accessing ld.
+func (ld *LocalDatastore) NewClient() Client { return localClient{ld} }
+
+type storedProperty struct {
+	Name    string
+	Value   interface{}
+	NoIndex bool
+}
+
+type storedEntity struct {
+	Key   string
+	Props []storedProperty
+}
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+func (ld *LocalDatastore) persistWithLock() error {
+	if ld.path == "" {
+		return nil
+	}
+
+	ks := make([]string, 0, len(ld.entities))
+	for k := range ld.entities {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+
+	ses := make([]storedEntity, 0, len(ks))
+	for _, k := range ks {
+		e := ld.entities[k]
+		sps := make([]storedProperty, 0, len(e.props))
+		for _, p := range e.props {
+			sps = append(sps, storedProperty{Name: p.Name, Value: p.Value, NoIndex: p.NoIndex})
+		}
+		ses = append(ses, storedEntity{Key: k, Props: sps})
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(ses); err != nil {
+		return fmt.Errorf("Failed to encode entities: %v", err)
+	}
+
+	tmppath := ld.path + ".tmp"
+	if err := ioutil.WriteFile(tmppath, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("Failed to write local datastore file: %v", err)
+	}
+	if err := os.Rename(tmppath, ld.path); err != nil {
+		return fmt.Errorf("Failed to rename local datastore file: %v", err)
+	}
+	return nil
+}
+
+func rootKeyOf(key *datastore.Key) *datastore.Key {
+	for key.Parent != nil {
+		key = key.Parent
+	}
+	return key
+}
+
+func groupOf(key *datastore.Key) string {
+	return rootKeyOf(key).Encode()
+}
+
+func hasAncestor(key, ancestor *datastore.Key) bool {
+	for ; key != nil; key = key.Parent {
+		if key.Equal(ancestor) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareKeys orders keys the way Cloud Datastore does: by path elements from the root,
+// with numeric IDs before names.
+func compareKeys(a, b *datastore.Key) int {
+	pa, pb := keyPath(a), keyPath(b)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		ea, eb := pa[i], pb[i]
+		if ea.Kind != eb.Kind {
+			if ea.Kind < eb.Kind {
+				return -1
+			}
+			return 1
+		}
+		if (ea.Name == "") != (eb.Name == "") {
+			if ea.Name == "" {
+				return -1
+			}
+			return 1
+		}
+		if ea.ID != eb.ID {
+			if ea.ID < eb.ID {
+				return -1
+			}
+			return 1
+		}
+		if ea.Name != eb.Name {
+			if ea.Name < eb.Name {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+func keyPath(key *datastore.Key) []*datastore.Key {
+	var path []*datastore.Key
+	for ; key != nil; key = key.Parent {
+		path = append([]*datastore.Key{key}, path...)
+	}
+	return path
+}
+
+func compareValues(a, b interface{}) (int, error) {
+	switch av := a.(type) {
+	case int64:
+		if bv, ok := b.(int64); ok {
+			switch {
+			case av < bv:
+				return -1, nil
+			case av > bv:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			switch {
+			case av < bv:
+				return -1, nil
+			case av > bv:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			switch {
+			case av.Before(bv):
+				return -1, nil
+			case av.After(bv):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, fmt.Errorf("Unsupported comparison of property values %T and %T", a, b)
+}
+
+func propertyValue(props []datastore.Property, name string) (interface{}, bool) {
+	for _, p := range props {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+func saveEntity(src interface{}) ([]datastore.Property, error) {
+	var props []datastore.Property
+	var err error
+	if pls, ok := src.(datastore.PropertyLoadSaver); ok {
+		props, err = pls.Save()
+	} else {
+		props, err = datastore.SaveStruct(src)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return copyProps(props), nil
+}
+
+func loadEntity(dst interface{}, props []datastore.Property) error {
+	props = copyProps(props)
+	if pls, ok := dst.(datastore.PropertyLoadSaver); ok {
+		return pls.Load(props)
+	}
+	return datastore.LoadStruct(dst, props)
+}
+
+func copyProps(props []datastore.Property) []datastore.Property {
+	ret := make([]datastore.Property, len(props))
+	copy(ret, props)
+	for i, p := range ret {
+		if bs, ok := p.Value.([]byte); ok {
+			ret[i].Value = append([]byte{}, bs...)
+		}
+	}
+	return ret
+}
+
+type localClient struct {
+	ld *LocalDatastore
+}
+
+var _ = Client(localClient{})
+
+func (c localClient) NewTransaction(ctx context.Context) (Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &localTransaction{ld: c.ld, reads: make(map[string]int64)}, nil
+}
+
+func (localClient) Close() error { return nil }
+
+type localMutation struct {
+	key *datastore.Key
+	// nil for deletion.
+	props []datastore.Property
+}
+
+type localTransaction struct {
+	ld *LocalDatastore
+
+	mu        sync.Mutex
+	reads     map[string]int64
+	mutations []localMutation
+	done      bool
+}
+
+// readGroupWithLock records that the tx read entity group of key.
+// Requires both tx.mu and tx.ld.mu held.
+func (tx *localTransaction) readGroupWithLock(key *datastore.Key) {
+	g := groupOf(key)
+	if _, ok := tx.reads[g]; ok {
+		return
+	}
+	tx.reads[g] = tx.ld.groupVers[g]
+}
+
+func (tx *localTransaction) Get(key *datastore.Key, dst interface{}) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return ErrTxDone
+	}
+
+	tx.ld.mu.Lock()
+	defer tx.ld.mu.Unlock()
+
+	tx.readGroupWithLock(key)
+	e, ok := tx.ld.entities[key.Encode()]
+	if !ok {
+		return datastore.ErrNoSuchEntity
+	}
+	return loadEntity(dst, e.props)
+}
+
+func (tx *localTransaction) mutate(m localMutation) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return ErrTxDone
+	}
+	if m.key == nil || m.key.Incomplete() {
+		return datastore.ErrInvalidKey
+	}
+
+	tx.mutations = append(tx.mutations, m)
+	return nil
+}
+
+func (tx *localTransaction) Put(key *datastore.Key, src interface{}) error {
+	props, err := saveEntity(src)
+	if err != nil {
+		return err
+	}
+	return tx.mutate(localMutation{key: key, props: props})
+}
+
+func (tx *localTransaction) Delete(key *datastore.Key) error {
+	return tx.mutate(localMutation{key: key})
+}
+
+func (tx *localTransaction) DeleteMulti(keys []*datastore.Key) error {
+	for _, key := range keys {
+		if err := tx.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type localIterator struct {
+	es       []*localEntity
+	keysOnly bool
+	err      error
+}
+
+func (it *localIterator) Next(dst interface{}) (*datastore.Key, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+	if len(it.es) == 0 {
+		return nil, iterator.Done
+	}
+
+	e := it.es[0]
+	it.es = it.es[1:]
+	if !it.keysOnly && dst != nil {
+		if err := loadEntity(dst, e.props); err != nil {
+			return e.key, err
+		}
+	}
+	return e.key, nil
+}
+
+func (tx *localTransaction) Run(ctx context.Context, q *Query) Iterator {
+	if err := ctx.Err(); err != nil {
+		return &localIterator{err: err}
+	}
+	if q.Ancestor == nil {
+		return &localIterator{err: errors.New("datastore: only ancestor queries are allowed in a transaction")}
+	}
+
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return &localIterator{err: ErrTxDone}
+	}
+
+	tx.ld.mu.Lock()
+	defer tx.ld.mu.Unlock()
+
+	tx.readGroupWithLock(q.Ancestor)
+
+	es := make([]*localEntity, 0)
+	for _, e := range tx.ld.entities {
+		if e.key.Kind != q.Kind || !hasAncestor(e.key, q.Ancestor) {
+			continue
+		}
+		if q.KeyGE != nil && compareKeys(e.key, q.KeyGE) < 0 {
+			continue
+		}
+		if q.KeyLT != nil && compareKeys(e.key, q.KeyLT) >= 0 {
+			continue
+		}
+		if q.OrderDesc != "" {
+			// Cloud Datastore excludes entities without the property from the results.
+			if _, ok := propertyValue(e.props, q.OrderDesc); !ok {
+				continue
+			}
+		}
+		es = append(es, e)
+	}
+
+	var sortErr error
+	sort.Slice(es, func(i, j int) bool {
+		if q.OrderDesc != "" {
+			vi, _ := propertyValue(es[i].props, q.OrderDesc)
+			vj, _ := propertyValue(es[j].props, q.OrderDesc)
+			c, err := compareValues(vi, vj)
+			if err != nil {
+				sortErr = err
+			}
+			if c != 0 {
+				return c > 0
+			}
+		}
+		return compareKeys(es[i].key, es[j].key) < 0
+	})
+	if sortErr != nil {
+		return &localIterator{err: sortErr}
+	}
+
+	if q.Offset >= len(es) {
+		es = es[:0]
+	} else {
+		es = es[q.Offset:]
+	}
+	if q.Limit > 0 && len(es) > q.Limit {
+		es = es[:q.Limit]
+	}
+	return &localIterator{es: es, keysOnly: q.KeysOnly}
+}
+
+func (tx *localTransaction) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+
+	if len(tx.mutations) > MaxMutationsPerTx {
+		return ErrTooManyMutations
+	}
+
+	ld := tx.ld
+	ld.mu.Lock()
+	defer ld.mu.Unlock()
+
+	for g, ver := range tx.reads {
+		if ld.groupVers[g] != ver {
+			return datastore.ErrConcurrentTransaction
+		}
+	}
+	if len(tx.mutations) == 0 {
+		return nil
+	}
+
+	type undo struct {
+		k string
+		e *localEntity
+	}
+	undos := make([]undo, 0, len(tx.mutations))
+	groups := make(map[string]struct{})
+	for _, m := range tx.mutations {
+		k := m.key.Encode()
+		undos = append(undos, undo{k, ld.entities[k]})
+		groups[groupOf(m.key)] = struct{}{}
+
+		if m.props == nil {
+			delete(ld.entities, k)
+		} else {
+			ld.entities[k] = &localEntity{key: m.key, props: m.props}
+		}
+	}
+
+	if err := ld.persistWithLock(); err != nil {
+		for i := len(undos) - 1; i >= 0; i-- {
+			u := undos[i]
+			if u.e == nil {
+				delete(ld.entities, u.k)
+			} else {
+				ld.entities[u.k] = u.e
+			}
+		}
+		return err
+	}
+
+	for g := range groups {
+		ld.groupVers[g]++
+	}
+	return nil
+}
+
+func (tx *localTransaction) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	return nil
+}
@@ -0,0 +1,186 @@
+package datastore_test
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	gds "cloud.google.com/go/datastore"
+	"google.golang.org/api/iterator"
+
+	"github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/gcloud/datastore"
+	"github.com/nyaxt/otaru/inodedb"
+	tu "github.com/nyaxt/otaru/testutils"
+)
+
+type testEntity struct {
+	Name string
+	N    int64
+}
+
+func putEntities(t *testing.T, cli datastore.Client, es map[*gds.Key]testEntity) {
+	ctx := context.Background()
+	tx, err := cli.NewTransaction(ctx)
+	if err != nil {
+		t.Fatalf("NewTransaction failed: %v", err)
+	}
+	for k, e := range es {
+		e := e
+		if err := tx.Put(k, &e); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+}
+
+func TestLocalDatastore_ConcurrentTransaction(t *testing.T) {
+	ctx := context.Background()
+	cli := datastore.NewLocalDatastore().NewClient()
+
+	root := gds.NameKey("TestKind", "root", nil)
+	k := gds.IDKey("TestKind", 1, root)
+	putEntities(t, cli, map[*gds.Key]testEntity{k: {"a", 1}})
+
+	tx1, _ := cli.NewTransaction(ctx)
+	tx2, _ := cli.NewTransaction(ctx)
+
+	var e1, e2 testEntity
+	if err := tx1.Get(k, &e1); err != nil {
+		t.Fatalf("tx1.Get failed: %v", err)
+	}
+	if err := tx2.Get(k, &e2); err != nil {
+		t.Fatalf("tx2.Get failed: %v", err)
+	}
+
+	e1.N++
+	if err := tx1.Put(k, &e1); err != nil {
+		t.Fatalf("tx1.Put failed: %v", err)
+	}
+	if err := tx1.Commit(); err != nil {
+		t.Fatalf("tx1.Commit failed: %v", err)
+	}
+
+	e2.N++
+	if err := tx2.Put(k, &e2); err != nil {
+		t.Fatalf("tx2.Put failed: %v", err)
+	}
+	if err := tx2.Commit(); err != gds.ErrConcurrentTransaction {
+		t.Errorf("tx2.Commit unexpected err: %v", err)
+	}
+
+	tx3, _ := cli.NewTransaction(ctx)
+	defer tx3.Rollback()
+	var e testEntity
+	if err := tx3.Get(k, &e); err != nil {
+		t.Fatalf("tx3.Get failed: %v", err)
+	}
+	if e.N != 2 {
+		t.Errorf("unexpected N: %d", e.N)
+	}
+	if err := tx3.Get(gds.IDKey("TestKind", 2, root), &e); err != gds.ErrNoSuchEntity {
+		t.Errorf("Get of non-existent entity unexpected err: %v", err)
+	}
+}
+
+func TestLocalDatastore_Query(t *testing.T) {
+	ctx := context.Background()
+	cli := datastore.NewLocalDatastore().NewClient()
+
+	root := gds.NameKey("TestKind", "root", nil)
+	otherRoot := gds.NameKey("TestKind", "other", nil)
+	es := make(map[*gds.Key]testEntity)
+	for i := int64(1); i <= 5; i++ {
+		es[gds.IDKey("TestKind", i, root)] = testEntity{"x", 10 - i}
+	}
+	es[gds.IDKey("TestKind", 100, otherRoot)] = testEntity{"y", 100}
+	putEntities(t, cli, es)
+
+	query := func(q *datastore.Query) []int64 {
+		tx, _ := cli.NewTransaction(ctx)
+		defer tx.Rollback()
+
+		ids := []int64{}
+		it := tx.Run(ctx, q)
+		for {
+			var e testEntity
+			k, err := it.Next(&e)
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Next failed: %v", err)
+			}
+			ids = append(ids, k.ID)
+		}
+		return ids
+	}
+	assertIDs := func(actual []int64, expected ...int64) {
+		t.Helper()
+		if len(actual) != len(expected) {
+			t.Errorf("expected %v, got %v", expected, actual)
+			return
+		}
+		for i := range actual {
+			if actual[i] != expected[i] {
+				t.Errorf("expected %v, got %v", expected, actual)
+				return
+			}
+		}
+	}
+
+	assertIDs(query(&datastore.Query{Kind: "TestKind", Ancestor: root}), 1, 2, 3, 4, 5)
+	assertIDs(query(&datastore.Query{Kind: "TestKind", Ancestor: root, KeyGE: gds.IDKey("TestKind", 3, root)}), 3, 4, 5)
+	assertIDs(query(&datastore.Query{Kind: "TestKind", Ancestor: root, KeyLT: gds.IDKey("TestKind", 3, root), KeysOnly: true}), 1, 2)
+	assertIDs(query(&datastore.Query{Kind: "TestKind", Ancestor: root, OrderDesc: "N", Offset: 1, Limit: 2}), 2, 3)
+	assertIDs(query(&datastore.Query{Kind: "OtherKind", Ancestor: root}))
+}
+
+func TestLocalDatastore_TooManyMutations(t *testing.T) {
+	ctx := context.Background()
+	cli := datastore.NewLocalDatastore().NewClient()
+
+	root := gds.NameKey("TestKind", "root", nil)
+	tx, _ := cli.NewTransaction(ctx)
+	for i := 1; i <= datastore.MaxMutationsPerTx+1; i++ {
+		if err := tx.Delete(gds.IDKey("TestKind", int64(i), root)); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != datastore.ErrTooManyMutations {
+		t.Errorf("Commit unexpected err: %v", err)
+	}
+}
+
+func TestLocalDatastore_PersistToFile(t *testing.T) {
+	dbpath := path.Join(t.TempDir(), "datastore.gob")
+
+	ld, err := datastore.OpenLocalDatastore(dbpath)
+	if err != nil {
+		t.Fatalf("OpenLocalDatastore failed: %v", err)
+	}
+	txio := datastore.NewDBTransactionLogIO(datastore.NewLocalConfig("persist", tu.TestCipher(), ld), flags.O_RDWRCREATE)
+	if err := txio.AppendTransaction(inodedb.DBTransaction{TxID: 123, Ops: []inodedb.DBOperation{
+		&inodedb.CreateNodeOp{NodeLock: inodedb.NodeLock{ID: 2, Ticket: 1}, OrigPath: "/foo", Type: inodedb.FileNodeT},
+	}}); err != nil {
+		t.Fatalf("AppendTransaction failed: %v", err)
+	}
+	if err := txio.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	ld2, err := datastore.OpenLocalDatastore(dbpath)
+	if err != nil {
+		t.Fatalf("OpenLocalDatastore (reopen) failed: %v", err)
+	}
+	txio2 := datastore.NewDBTransactionLogIO(datastore.NewLocalConfig("persist", tu.TestCipher(), ld2), flags.O_RDONLY)
+	txs, err := txio2.QueryTransactions(inodedb.AnyVersion)
+	if err != nil {
+		t.Fatalf("QueryTransactions failed: %v", err)
+	}
+	if len(txs) != 1 || txs[0].TxID != 123 {
+		t.Errorf("unexpected txs: %+v", txs)
+	}
+}
@@ -1,7 +1,9 @@
 package gcs
 
 import (
+	"errors"
 	"io"
+	"strings"
 
 	"context"
 
@@ -68,8 +70,24 @@ type GCSBlobStore struct {
 
 var _ = blobstore.BlobStore(&GCSBlobStore{})
 
-func NewGCSBlobStore(projectName string, bucketName string, tsrc oauth2.TokenSource, flags int) (*GCSBlobStore, error) {
-	client, err := storage.NewClient(context.Background(), option.WithTokenSource(tsrc))
+// NewGCSBlobStore creates a GCSBlobStore backed by the bucket bucketName.
+// If endpoint is non-empty, requests are sent to the GCS compatible server at
+// endpoint (e.g. "http://localhost:4443") instead of Google Cloud Storage.
+// tsrc may be nil only if endpoint is given, in which case requests are not authenticated.
+func NewGCSBlobStore(projectName string, bucketName string, tsrc oauth2.TokenSource, endpoint string, flags int) (*GCSBlobStore, error) {
+	var opts []option.ClientOption
+	if endpoint != "" {
+		opts = append(opts, option.WithEndpoint(strings.TrimSuffix(endpoint, "/")+"/storage/v1/"))
+	}
+	if tsrc != nil {
+		opts = append(opts, option.WithTokenSource(tsrc))
+	} else if endpoint != "" {
+		opts = append(opts, option.WithoutAuthentication())
+	} else {
+		return nil, errors.New("TokenSource must be given for Google Cloud Storage.")
+	}
+
+	client, err := storage.NewClient(context.Background(), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -84,9 +102,12 @@ func NewGCSBlobStore(projectName string, bucketName string, tsrc oauth2.TokenSou
 
 type Writer struct {
 	gcsw       *storage.Writer
+	cancel     context.CancelFunc
 	bucketName string
 }
 
+var _ = blobstore.WriteAborter(&Writer{})
+
 func (bs *GCSBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
 	if !oflags.IsWriteAllowed(bs.flags) {
 		return nil, util.EACCES
@@ -96,9 +117,10 @@ func (bs *GCSBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
 	zap.S().Infof("OpenWriter(bucketName: %q, %q)", bs.bucketName, blobpath)
 
 	obj := bs.bucket.Object(blobpath)
-	gcsw := obj.NewWriter(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	gcsw := obj.NewWriter(ctx)
 	gcsw.ContentType = "application/octet-stream"
-	return &Writer{gcsw, bs.bucketName}, nil
+	return &Writer{gcsw, cancel, bs.bucketName}, nil
 }
 
 func (w *Writer) Write(p []byte) (int, error) {
@@ -107,6 +129,8 @@ func (w *Writer) Write(p []byte) (int, error) {
 }
 
 func (w *Writer) Close() error {
+	defer w.cancel()
+
 	issuedCloseWriterOps.WithLabelValues(w.bucketName).Inc()
 	if err := w.gcsw.Close(); err != nil {
 		return err
@@ -115,6 +139,14 @@ func (w *Writer) Close() error {
 	return nil
 }
 
+// Abort cancels the upload, so that the object is left as before OpenWriter.
+func (w *Writer) Abort() error {
+	w.cancel()
+	// Close fails with the cancellation, without committing the object.
+	w.gcsw.Close()
+	return nil
+}
+
 type Reader struct {
 	rc         io.ReadCloser
 	bucketName string
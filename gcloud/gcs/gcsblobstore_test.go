@@ -19,7 +19,8 @@ func testGCSBlobStore(f int) *gcs.GCSBlobStore {
 	bs, err := gcs.NewGCSBlobStore(
 		authtu.TestConfig().ProjectName,
 		authtu.TestBucketName,
-		authtu.TestTokenSource(),
+		authtu.TestGCSTokenSource(),
+		authtu.TestGCSEndpoint(),
 		f,
 	)
 	if err != nil {
@@ -155,3 +156,58 @@ func TestGCSBlobStore_ReadOnly(t *testing.T) {
 		}
 	}
 }
+
+func TestGCSBlobStore_LargeBlob(t *testing.T) {
+	bs := testGCSBlobStore(flags.O_RDWR)
+
+	// Larger than the default storage.Writer ChunkSize of 16MiB, so that the blob is
+	// uploaded in multiple chunks.
+	data := util.RandomBytes(17 * 1024 * 1024)
+	w, err := bs.OpenWriter("large")
+	if err != nil {
+		t.Fatalf("Failed to open writer: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+	defer func() {
+		if err := bs.RemoveBlob("large"); err != nil {
+			t.Errorf("Failed to remove blob: %v", err)
+		}
+	}()
+
+	size, err := bs.BlobSize("large")
+	if err != nil {
+		t.Fatalf("BlobSize failed: %v", err)
+	}
+	if size != int64(len(data)) {
+		t.Errorf("Unexpected BlobSize: %d", size)
+	}
+
+	r, err := bs.OpenReader("large")
+	if err != nil {
+		t.Fatalf("Failed to open reader: %v", err)
+	}
+	defer r.Close()
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(data, buf) {
+		t.Errorf("Read content != Write content")
+	}
+}
+
+func TestGCSBlobStore_NotExist(t *testing.T) {
+	bs := testGCSBlobStore(flags.O_RDWR)
+
+	if _, err := bs.OpenReader("nonexistent"); err != util.ENOENT {
+		t.Errorf("Expected ENOENT from OpenReader. got %v", err)
+	}
+	if _, err := bs.BlobSize("nonexistent"); err != util.ENOENT {
+		t.Errorf("Expected ENOENT from BlobSize. got %v", err)
+	}
+}
@@ -29,6 +29,11 @@ type INodeCommon struct {
 	Gid       uint32    `json:"gid"`
 	PermMode  uint16    `json:"perm_mode"`
 	ModifiedT time.Time `json:"modified_t"`
+	// ChangeT is updated on any change to the node including its attributes, as POSIX ctime.
+	ChangeT  time.Time `json:"change_t"`
+	CreatedT time.Time `json:"created_t"`
+	// AccessedT is updated on reads only if the filesystem tracks access time.
+	AccessedT time.Time `json:"accessed_t"`
 }
 
 func (n INodeCommon) GetID() ID               { return n.ID }
@@ -37,6 +42,9 @@ func (n INodeCommon) GetUid() uint32          { return n.Uid }
 func (n INodeCommon) GetGid() uint32          { return n.Gid }
 func (n INodeCommon) GetPermMode() uint16     { return n.PermMode }
 func (n INodeCommon) GetModifiedT() time.Time { return n.ModifiedT }
+func (n INodeCommon) GetChangeT() time.Time   { return n.ChangeT }
+func (n INodeCommon) GetCreatedT() time.Time  { return n.CreatedT }
+func (n INodeCommon) GetAccessedT() time.Time { return n.AccessedT }
 
 type NodeView interface {
 	// GetVersion() TxID
@@ -47,6 +55,9 @@ type NodeView interface {
 	GetGid() uint32
 	GetPermMode() uint16
 	GetModifiedT() time.Time
+	GetChangeT() time.Time
+	GetCreatedT() time.Time
+	GetAccessedT() time.Time
 
 	GetType() Type
 }
@@ -122,6 +133,21 @@ type DBFscker interface {
 	Fsck() ([]string, []error)
 }
 
+// ChunkRef is a reference to a chunk blob from a file node reachable from the root dir.
+type ChunkRef struct {
+	FileChunk
+	NodeID ID
+	Path   string
+}
+
+type DBChunkRefLister interface {
+	ListChunkRefs() ([]ChunkRef, []error)
+}
+
 type TriggerSyncer interface {
 	TriggerSync() <-chan error
 }
+
+type CatchUpper interface {
+	CatchUp() ([]DBTransaction, bool, error)
+}
@@ -14,6 +14,35 @@ type DBOperation interface {
 
 type OpMeta struct {
 	Kind string `json:"kind"`
+
+	// IssuedT is the time the op was first applied. It is logged with the op
+	// so that replaying the txlog, or catching up on another host, yields
+	// the same node times as the original apply.
+	IssuedT time.Time `json:"issued_t"`
+}
+
+func (m *OpMeta) opMeta() *OpMeta { return m }
+
+// issuedT returns the time to stamp on the nodes modified by the op. Ops
+// logged before IssuedT was recorded fall back to the current time.
+func (m *OpMeta) issuedT() time.Time {
+	if m.IssuedT.IsZero() {
+		return time.Now()
+	}
+	return m.IssuedT
+}
+
+// stampIssuedT sets IssuedT of the ops which don't have one yet.
+func stampIssuedT(ops []DBOperation, now time.Time) {
+	for _, op := range ops {
+		h, ok := op.(interface{ opMeta() *OpMeta })
+		if !ok {
+			continue
+		}
+		if m := h.opMeta(); m.IssuedT.IsZero() {
+			m.IssuedT = now
+		}
+	}
 }
 
 type InitializeFileSystemOp struct {
@@ -33,7 +62,7 @@ func (op *InitializeFileSystemOp) Apply(s *DBState) error {
 	}
 
 	n := &DirNode{
-		INodeCommon: INodeCommon{ID: RootDirID, OrigPath: "/", Uid: 0, Gid: 0, PermMode: 0777, ModifiedT: time.Now()},
+		INodeCommon: newINodeCommon(RootDirID, "/", 0, 0, 0777, op.issuedT()),
 		ParentID:    RootDirID,
 		Entries:     make(map[string]ID),
 	}
@@ -68,15 +97,16 @@ func (op *CreateNodeOp) Apply(s *DBState) error {
 	}
 
 	var n INode
+	c := newINodeCommon(op.ID, op.OrigPath, op.Uid, op.Gid, op.PermMode, op.issuedT())
 	switch op.Type {
 	case FileNodeT:
 		n = &FileNode{
-			INodeCommon: INodeCommon{ID: op.ID, OrigPath: op.OrigPath, Uid: op.Uid, Gid: op.Gid, PermMode: op.PermMode, ModifiedT: time.Now()},
+			INodeCommon: c,
 			Size:        0,
 		}
 	case DirNodeT:
 		n = &DirNode{
-			INodeCommon: INodeCommon{ID: op.ID, OrigPath: op.OrigPath, Uid: op.Uid, Gid: op.Gid, PermMode: op.PermMode, ModifiedT: time.Now()},
+			INodeCommon: c,
 			ParentID:    op.ParentID,
 			Entries:     make(map[string]ID),
 		}
@@ -112,7 +142,8 @@ func (op *HardLinkOp) Apply(s *DBState) error {
 		return util.ENOTDIR
 	}
 
-	if _, ok := s.nodes[op.TargetID]; !ok {
+	tgtn, ok := s.nodes[op.TargetID]
+	if !ok {
 		return util.ENOENT
 	}
 
@@ -120,7 +151,10 @@ func (op *HardLinkOp) Apply(s *DBState) error {
 		return util.EEXIST
 	}
 	dn.Entries[op.Name] = op.TargetID
-	dn.ModifiedT = time.Now()
+	now := op.issuedT()
+	dn.ModifiedT = now
+	dn.ChangeT = now
+	nodeCommon(tgtn).ChangeT = now
 
 	return nil
 }
@@ -170,7 +204,9 @@ func (op *UpdateSizeOp) Apply(s *DBState) error {
 	}
 
 	fn.Size = op.Size
-	fn.ModifiedT = time.Now()
+	now := op.issuedT()
+	fn.ModifiedT = now
+	fn.ChangeT = now
 	return nil
 }
 
@@ -193,6 +229,7 @@ func (op *UpdateUidOp) Apply(s *DBState) error {
 	default:
 		return fmt.Errorf("UpdateUidOp: Unsupported node type: %d", n.GetType())
 	}
+	nodeCommon(n).ChangeT = op.issuedT()
 	return nil
 }
 
@@ -215,6 +252,7 @@ func (op *UpdateGidOp) Apply(s *DBState) error {
 	default:
 		return fmt.Errorf("UpdateGidOp: Unsupported node type: %d", n.GetType())
 	}
+	nodeCommon(n).ChangeT = op.issuedT()
 	return nil
 }
 
@@ -238,6 +276,7 @@ func (op *UpdatePermModeOp) Apply(s *DBState) error {
 	default:
 		return fmt.Errorf("UpdatePermModeOp: Unsupported node type: %d", n.GetType())
 	}
+	nodeCommon(n).ChangeT = op.issuedT()
 	return nil
 }
 
@@ -260,6 +299,26 @@ func (op *UpdateModifiedTOp) Apply(s *DBState) error {
 	default:
 		return fmt.Errorf("UpdateModifiedTOp: Unsupported node type: %d", n.GetType())
 	}
+	nodeCommon(n).ChangeT = op.issuedT()
+	return nil
+}
+
+type UpdateAccessedTOp struct {
+	OpMeta    `json:",inline"`
+	ID        `json:"id"`
+	AccessedT time.Time `json:"accessed_t"`
+}
+
+func (op *UpdateAccessedTOp) Apply(s *DBState) error {
+	n, ok := s.nodes[op.ID]
+	if !ok {
+		return util.ENOENT
+	}
+	c := nodeCommon(n)
+	if c == nil {
+		return fmt.Errorf("UpdateAccessedTOp: Unsupported node type: %d", n.GetType())
+	}
+	c.AccessedT = op.AccessedT
 	return nil
 }
 
@@ -315,10 +374,13 @@ func (op *RenameOp) Apply(s *DBState) error {
 	}
 
 	delete(srcdn.Entries, op.SrcName)
-	now := time.Now()
+	now := op.issuedT()
 	srcdn.ModifiedT = now
+	srcdn.ChangeT = now
 	dstdn.Entries[op.DstName] = id
 	dstdn.ModifiedT = now
+	dstdn.ChangeT = now
+	nodeCommon(mn).ChangeT = now
 	return nil
 }
 
@@ -346,7 +408,8 @@ func (op *RemoveOp) Apply(s *DBState) error {
 	if !ok {
 		return util.ENOENT
 	}
-	if tgtnode, ok := s.nodes[tgtid]; ok {
+	tgtnode, tgtExists := s.nodes[tgtid]
+	if tgtExists {
 		if tgtdirnode, ok := tgtnode.(*DirNode); ok {
 			if len(tgtdirnode.Entries) != 0 {
 				return util.ENOTEMPTY
@@ -355,7 +418,12 @@ func (op *RemoveOp) Apply(s *DBState) error {
 	}
 
 	delete(dn.Entries, op.Name)
-	dn.ModifiedT = time.Now()
+	now := op.issuedT()
+	dn.ModifiedT = now
+	dn.ChangeT = now
+	if tgtExists {
+		nodeCommon(tgtnode).ChangeT = now
+	}
 	return nil
 }
 
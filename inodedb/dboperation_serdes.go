@@ -26,6 +26,8 @@ func SetOpMeta(op DBOperation) error {
 		op.(*UpdatePermModeOp).Kind = "UpdatePermModeOp"
 	case *UpdateModifiedTOp:
 		op.(*UpdateModifiedTOp).Kind = "UpdateModifiedTOp"
+	case *UpdateAccessedTOp:
+		op.(*UpdateAccessedTOp).Kind = "UpdateAccessedTOp"
 	case *RenameOp:
 		op.(*RenameOp).Kind = "RenameOp"
 	case *RemoveOp:
@@ -140,6 +142,12 @@ func ResolveDBOperations(msgs []*json.RawMessage) ([]DBOperation, error) {
 				return nil, err
 			}
 			ops = append(ops, &op)
+		case "UpdateAccessedTOp":
+			var op UpdateAccessedTOp
+			if err := json.Unmarshal([]byte(*msg), &op); err != nil {
+				return nil, err
+			}
+			ops = append(ops, &op)
 		case "RenameOp":
 			var op RenameOp
 			if err := json.Unmarshal([]byte(*msg), &op); err != nil {
@@ -18,6 +18,7 @@ type DBService struct {
 var _ = DBHandler(&DBService{})
 var _ = util.Syncer(&DBService{})
 var _ = TriggerSyncer(&DBService{})
+var _ = CatchUpper(&DBService{})
 
 func NewDBService(h DBHandler) *DBService {
 	s := &DBService{
@@ -154,4 +155,32 @@ func (srv *DBService) Fsck() (foundblobpaths []string, errs []error) {
 	return
 }
 
+func (srv *DBService) ListChunkRefs() (refs []ChunkRef, errs []error) {
+	ch := make(chan struct{})
+	srv.reqC <- func() {
+		if prov, ok := srv.h.(DBChunkRefLister); ok {
+			refs, errs = prov.ListChunkRefs()
+		} else {
+			refs, errs = nil, []error{fmt.Errorf("DBHandler doesn't support ListChunkRefs")}
+		}
+		close(ch)
+	}
+	<-ch
+	return
+}
+
+func (srv *DBService) CatchUp() (txs []DBTransaction, restored bool, err error) {
+	ch := make(chan struct{})
+	srv.reqC <- func() {
+		if cu, ok := srv.h.(CatchUpper); ok {
+			txs, restored, err = cu.CatchUp()
+		} else {
+			err = fmt.Errorf("DBHandler doesn't support CatchUp")
+		}
+		close(ch)
+	}
+	<-ch
+	return
+}
+
 func (*DBService) ImplName() string { return "inodedb.DBService" }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"path"
 	"strconv"
 	"syscall"
 	"time"
@@ -161,6 +162,26 @@ func (fn *FileNode) View() NodeView {
 	return v
 }
 
+// nodeCommon returns the INodeCommon of n, or nil for an unknown node type.
+func nodeCommon(n INode) *INodeCommon {
+	switch n := n.(type) {
+	case *FileNode:
+		return &n.INodeCommon
+	case *DirNode:
+		return &n.INodeCommon
+	default:
+		return nil
+	}
+}
+
+// newINodeCommon returns INodeCommon with all the timestamps set to now.
+func newINodeCommon(id ID, origPath string, uid, gid uint32, permMode uint16, now time.Time) INodeCommon {
+	return INodeCommon{
+		ID: id, OrigPath: origPath, Uid: uid, Gid: gid, PermMode: permMode,
+		ModifiedT: now, ChangeT: now, CreatedT: now, AccessedT: now,
+	}
+}
+
 type DirNode struct {
 	INodeCommon
 	ParentID ID
@@ -201,7 +222,10 @@ type DB struct {
 	txLogIO    DBTransactionLogIO
 
 	readOnly bool
-	stats    DBServiceStats
+	// writeFenced, if set, reports that writes are refused for now, e.g.
+	// while the lease of the global lock can't be renewed.
+	writeFenced func() bool
+	stats       DBServiceStats
 }
 
 var _ = DBHandler(&DB{})
@@ -291,6 +315,37 @@ func (db *DB) RestoreVersion(version TxID) error {
 	return nil
 }
 
+// CatchUp applies transactions appended to txLogIO by other DB instances since
+// the current version. Only allowed in read-only mode, in which the DB state is
+// never modified locally. Returns the applied transactions. If the transactions
+// needed to catch up were already garbage collected, the state is restored
+// from the latest snapshot instead, and restored is true.
+func (db *DB) CatchUp() (txs []DBTransaction, restored bool, err error) {
+	if !db.readOnly {
+		return nil, false, fmt.Errorf("CatchUp is only allowed in read only mode.")
+	}
+
+	txlog, err := db.txLogIO.QueryTransactions(db.state.version + 1)
+	if err != nil {
+		return nil, false, fmt.Errorf("Failed to query txlog: %v", err)
+	}
+	if len(txlog) > 0 && txlog[0].TxID != db.state.version+1 {
+		zap.S().Infof("CatchUp: txlog starts from ver %d, but current ver is %d. Restoring latest snapshot.", txlog[0].TxID, db.state.version)
+		if err := db.RestoreVersion(LatestVersion); err != nil {
+			return nil, false, err
+		}
+		return nil, true, nil
+	}
+
+	for _, tx := range txlog {
+		if _, err := db.applyTransactionInternal(tx, skipTxLog); err != nil {
+			return txs, false, fmt.Errorf("Failed to apply tx %d: %v", tx.TxID, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, false, nil
+}
+
 func (db *DB) applyTransactionInternal(tx DBTransaction, writeTxLogFlag bool) (TxID, error) {
 	zap.S().Debugf("applyTransactionInternal(%+v, writeTxLog: %t)", tx, writeTxLogFlag)
 
@@ -299,6 +354,9 @@ func (db *DB) applyTransactionInternal(tx DBTransaction, writeTxLogFlag bool) (T
 	} else if tx.TxID != db.state.version+1 {
 		return 0, fmt.Errorf("Attempted to apply tx %d to dbver %d. Next accepted tx is %d", tx.TxID, db.state.version, db.state.version+1)
 	}
+	if writeTxLogFlag == writeTxLog {
+		stampIssuedT(tx.Ops, time.Now())
+	}
 
 	for _, op := range tx.Ops {
 		if err := op.Apply(db.state); err != nil {
@@ -322,10 +380,23 @@ func (db *DB) applyTransactionInternal(tx DBTransaction, writeTxLogFlag bool) (T
 	return tx.TxID, nil
 }
 
+// SetWriteFence makes ApplyTransaction and Sync fail with EROFS while fenced
+// returns true. It must be called before the DB is used.
+func (db *DB) SetWriteFence(fenced func() bool) {
+	db.writeFenced = fenced
+}
+
+func (db *DB) isWriteFenced() bool {
+	return db.writeFenced != nil && db.writeFenced()
+}
+
 func (db *DB) ApplyTransaction(tx DBTransaction) (TxID, error) {
 	if db.readOnly {
 		return 0, util.EACCES
 	}
+	if db.isWriteFenced() {
+		return 0, util.EROFS
+	}
 
 	return db.applyTransactionInternal(tx, writeTxLog)
 }
@@ -407,6 +478,9 @@ func (db *DB) Sync() error {
 	if db.readOnly {
 		return util.EACCES
 	}
+	if db.isWriteFenced() {
+		return util.EROFS
+	}
 
 	return <-db.TriggerSync()
 }
@@ -454,6 +528,49 @@ func (db *DB) Fsck() ([]string, []error) {
 	return db.fsckRecursive(RootDirID, foundblobpaths, errs)
 }
 
+func (db *DB) listChunkRefsRecursive(id ID, p string, refs []ChunkRef, errs []error) ([]ChunkRef, []error) {
+	n, ok := db.state.nodes[id]
+	if !ok {
+		errs = append(errs, fmt.Errorf("Node ID %d not found", id))
+		return refs, errs
+	}
+	switch n.GetType() {
+	case FileNodeT:
+		fn, ok := n.(*FileNode)
+		if !ok {
+			errs = append(errs, fmt.Errorf("Node ID %d said it is FileNodeT, but cast failed", id))
+		} else {
+			for _, fc := range fn.Chunks {
+				refs = append(refs, ChunkRef{FileChunk: fc, NodeID: id, Path: p})
+			}
+		}
+
+	case DirNodeT:
+		dn, ok := n.(*DirNode)
+		if !ok {
+			errs = append(errs, fmt.Errorf("Node ID %d said it is DirNodeT, but cast failed", id))
+		} else {
+			for name, cid := range dn.Entries {
+				refs, errs = db.listChunkRefsRecursive(cid, path.Join(p, name), refs, errs)
+			}
+		}
+
+	default:
+		errs = append(errs, fmt.Errorf("Node ID %d has unknown type %v", id, n.GetType()))
+	}
+	return refs, errs
+}
+
+var _ = DBChunkRefLister(&DB{})
+
+// ListChunkRefs lists all chunks referenced from the file nodes reachable
+// from the root dir, along with the paths of the file nodes.
+func (db *DB) ListChunkRefs() ([]ChunkRef, []error) {
+	refs := make([]ChunkRef, 0)
+	errs := make([]error, 0)
+	return db.listChunkRefsRecursive(RootDirID, "/", refs, errs)
+}
+
 var _ = DBServiceStatsProvider(&DB{})
 
 func (db *DB) GetStats() DBServiceStats {
@@ -1,9 +1,12 @@
 package inodedb_test
 
 import (
+	"encoding/gob"
 	"testing"
+	"time"
 
 	i "github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/util"
 )
 
 func TestInitialState(t *testing.T) {
@@ -106,6 +109,41 @@ func TestCreateFile(t *testing.T) {
 	}
 }
 
+func TestWriteFence(t *testing.T) {
+	db, err := i.NewEmptyDB(i.NewSimpleDBStateSnapshotIO(), i.NewSimpleDBTransactionLogIO())
+	if err != nil {
+		t.Fatalf("Failed to NewEmptyDB: %v", err)
+	}
+	fenced := true
+	db.SetWriteFence(func() bool { return fenced })
+
+	nlock, err := db.LockNode(i.AllocateNewNodeID)
+	if err != nil {
+		t.Fatalf("Failed to LockNode: %v", err)
+	}
+	tx := i.DBTransaction{Ops: []i.DBOperation{
+		&i.CreateNodeOp{NodeLock: nlock, OrigPath: "/hoge.txt", Type: i.FileNodeT},
+		&i.HardLinkOp{NodeLock: i.NodeLock{1, i.NoTicket}, Name: "hoge.txt", TargetID: nlock.ID},
+	}}
+	if _, err := db.ApplyTransaction(tx); err != util.EROFS {
+		t.Errorf("Expected EROFS while fenced, got: %v", err)
+	}
+	if err := db.Sync(); err != util.EROFS {
+		t.Errorf("Expected EROFS on Sync while fenced, got: %v", err)
+	}
+	if _, _, err := db.QueryNode(1, false); err != nil {
+		t.Errorf("QueryNode failed while fenced: %v", err)
+	}
+
+	fenced = false
+	if _, err := db.ApplyTransaction(tx); err != nil {
+		t.Errorf("Failed to apply tx after the fence is lifted: %v", err)
+	}
+	if err := db.Sync(); err != nil {
+		t.Errorf("Failed to Sync after the fence is lifted: %v", err)
+	}
+}
+
 func TestINodeDB_Rollback(t *testing.T) {
 	sio := i.NewSimpleDBStateSnapshotIO()
 	txio := i.NewSimpleDBTransactionLogIO()
@@ -132,3 +170,232 @@ func TestINodeDB_Rollback(t *testing.T) {
 		t.Errorf("ApplyTransaction succeeded unexpectedly!")
 	}
 }
+
+func createFileForTesting(t *testing.T, db *i.DB, name string) i.ID {
+	nlock, err := db.LockNode(i.AllocateNewNodeID)
+	if err != nil {
+		t.Fatalf("Failed to LockNode: %v", err)
+	}
+	tx := i.DBTransaction{Ops: []i.DBOperation{
+		&i.CreateNodeOp{NodeLock: nlock, OrigPath: "/" + name, Type: i.FileNodeT},
+		&i.HardLinkOp{NodeLock: i.NodeLock{1, i.NoTicket}, Name: name, TargetID: nlock.ID},
+	}}
+	if _, err := db.ApplyTransaction(tx); err != nil {
+		t.Fatalf("Failed to apply tx: %v", err)
+	}
+	if err := db.UnlockNode(nlock); err != nil {
+		t.Fatalf("Failed to UnlockNode: %v", err)
+	}
+	return nlock.ID
+}
+
+func TestCatchUp(t *testing.T) {
+	sio := i.NewSimpleDBStateSnapshotIO()
+	txio := i.NewSimpleDBTransactionLogIO()
+
+	wdb, err := i.NewEmptyDB(sio, txio)
+	if err != nil {
+		t.Fatalf("Failed to NewEmptyDB: %v", err)
+	}
+	if _, _, err := wdb.CatchUp(); err == nil {
+		t.Errorf("CatchUp on writable db succeeded unexpectedly!")
+	}
+
+	rdb, err := i.NewDB(sio, txio, true)
+	if err != nil {
+		t.Fatalf("Failed to NewDB: %v", err)
+	}
+
+	hogeID := createFileForTesting(t, wdb, "hoge.txt")
+	if _, _, err := rdb.QueryNode(hogeID, false); err != util.ENOENT {
+		t.Errorf("Expected ENOENT before CatchUp. got %v", err)
+	}
+
+	txs, restored, err := rdb.CatchUp()
+	if err != nil {
+		t.Fatalf("CatchUp failed: %v", err)
+	}
+	if len(txs) != 1 || restored {
+		t.Errorf("Unexpected CatchUp result. txs: %v, restored: %t", txs, restored)
+	}
+	if _, _, err := rdb.QueryNode(hogeID, false); err != nil {
+		t.Errorf("QueryNode after CatchUp failed: %v", err)
+	}
+
+	// No new tx.
+	txs, restored, err = rdb.CatchUp()
+	if err != nil || len(txs) != 0 || restored {
+		t.Errorf("Unexpected CatchUp result. txs: %v, restored: %t, err: %v", txs, restored, err)
+	}
+
+	// The txlog needed to catch up is gone after a snapshot.
+	fugaID := createFileForTesting(t, wdb, "fuga.txt")
+	if err := wdb.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if err := txio.DeleteTransactions(wdb.GetStats().Version + 1); err != nil {
+		t.Fatalf("DeleteTransactions failed: %v", err)
+	}
+	piyoID := createFileForTesting(t, wdb, "piyo.txt")
+
+	txs, restored, err = rdb.CatchUp()
+	if err != nil {
+		t.Fatalf("CatchUp failed: %v", err)
+	}
+	if !restored {
+		t.Errorf("CatchUp should restore from snapshot.")
+	}
+	for _, id := range []i.ID{hogeID, fugaID, piyoID} {
+		if _, _, err := rdb.QueryNode(id, false); err != nil {
+			t.Errorf("QueryNode(%d) after CatchUp failed: %v", id, err)
+		}
+	}
+	if rdb.GetStats().Version != wdb.GetStats().Version {
+		t.Errorf("Version mismatch after CatchUp: %d != %d", rdb.GetStats().Version, wdb.GetStats().Version)
+	}
+}
+
+func TestNodeTimes(t *testing.T) {
+	sio := i.NewSimpleDBStateSnapshotIO()
+	txio := i.NewSimpleDBTransactionLogIO()
+
+	db, err := i.NewEmptyDB(sio, txio)
+	if err != nil {
+		t.Fatalf("Failed to NewEmptyDB: %v", err)
+	}
+	id := createFileForTesting(t, db, "hoge.txt")
+
+	v, _, err := db.QueryNode(id, false)
+	if err != nil {
+		t.Fatalf("QueryNode failed: %v", err)
+	}
+	createdT := v.GetCreatedT()
+	if createdT.IsZero() || v.GetChangeT().Before(createdT) || !v.GetAccessedT().Equal(createdT) {
+		t.Errorf("Unexpected times of a new node: %+v", v)
+	}
+
+	mt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	at := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	tx := i.DBTransaction{Ops: []i.DBOperation{
+		&i.UpdateModifiedTOp{ID: id, ModifiedT: mt},
+		&i.UpdateAccessedTOp{ID: id, AccessedT: at},
+	}}
+	if _, err := db.ApplyTransaction(tx); err != nil {
+		t.Fatalf("Failed to apply tx: %v", err)
+	}
+	v, _, err = db.QueryNode(id, false)
+	if err != nil {
+		t.Fatalf("QueryNode failed: %v", err)
+	}
+	changeT := v.GetChangeT()
+	if !v.GetModifiedT().Equal(mt) || !v.GetAccessedT().Equal(at) || changeT.Before(createdT) || !v.GetCreatedT().Equal(createdT) {
+		t.Errorf("Unexpected times after update: %+v", v)
+	}
+
+	// The times survive a snapshot.
+	if err := db.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	rdb, err := i.NewDB(sio, i.NewSimpleDBTransactionLogIO(), true)
+	if err != nil {
+		t.Fatalf("Failed to NewDB: %v", err)
+	}
+	v, _, err = rdb.QueryNode(id, false)
+	if err != nil {
+		t.Fatalf("QueryNode failed: %v", err)
+	}
+	if !v.GetModifiedT().Equal(mt) || !v.GetAccessedT().Equal(at) || !v.GetChangeT().Equal(changeT) || !v.GetCreatedT().Equal(createdT) {
+		t.Errorf("Unexpected times after restoring the snapshot: %+v", v)
+	}
+}
+
+func TestNodeTimes_TxLogReplay(t *testing.T) {
+	sio := i.NewSimpleDBStateSnapshotIO()
+	txio := i.NewSimpleDBTransactionLogIO()
+
+	db, err := i.NewEmptyDB(sio, txio)
+	if err != nil {
+		t.Fatalf("Failed to NewEmptyDB: %v", err)
+	}
+	id := createFileForTesting(t, db, "hoge.txt")
+	tx := i.DBTransaction{Ops: []i.DBOperation{
+		&i.UpdatePermModeOp{ID: id, PermMode: 0600},
+	}}
+	if _, err := db.ApplyTransaction(tx); err != nil {
+		t.Fatalf("Failed to apply tx: %v", err)
+	}
+	want, _, err := db.QueryNode(id, false)
+	if err != nil {
+		t.Fatalf("QueryNode failed: %v", err)
+	}
+
+	// Round trip the txlog through json as the persistent txlog does.
+	txs, err := txio.QueryTransactions(2)
+	if err != nil {
+		t.Fatalf("QueryTransactions failed: %v", err)
+	}
+	replayio := i.NewSimpleDBTransactionLogIO()
+	for _, tx := range txs {
+		jsonb, err := i.EncodeDBOperationsToJson(tx.Ops)
+		if err != nil {
+			t.Fatalf("EncodeDBOperationsToJson failed: %v", err)
+		}
+		ops, err := i.DecodeDBOperationsFromJson(jsonb)
+		if err != nil {
+			t.Fatalf("DecodeDBOperationsFromJson failed: %v", err)
+		}
+		if err := replayio.AppendTransaction(i.DBTransaction{TxID: tx.TxID, Ops: ops}); err != nil {
+			t.Fatalf("AppendTransaction failed: %v", err)
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	rdb, err := i.NewDB(sio, replayio, true)
+	if err != nil {
+		t.Fatalf("Failed to NewDB: %v", err)
+	}
+	v, _, err := rdb.QueryNode(id, false)
+	if err != nil {
+		t.Fatalf("QueryNode failed: %v", err)
+	}
+	if !v.GetCreatedT().Equal(want.GetCreatedT()) || !v.GetChangeT().Equal(want.GetChangeT()) || !v.GetModifiedT().Equal(want.GetModifiedT()) {
+		t.Errorf("Replaying the txlog changed the node times: got %+v, want %+v", v, want)
+	}
+}
+
+func TestNodeTimes_LegacySnapshot(t *testing.T) {
+	mt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Snapshot format without the node times.
+	sio := i.NewSimpleDBStateSnapshotIO()
+	enc := gob.NewEncoder(&sio.Buf)
+	root := &i.DirNode{
+		INodeCommon: i.INodeCommon{ID: i.RootDirID, OrigPath: "/", PermMode: 0777, ModifiedT: mt},
+		ParentID:    i.RootDirID,
+		Entries:     map[string]i.ID{},
+	}
+	if err := enc.Encode(uint64(1)); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if err := root.EncodeToGob(enc); err != nil {
+		t.Fatalf("EncodeToGob failed: %v", err)
+	}
+	if err := enc.Encode(i.RootDirID); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if err := enc.Encode(i.TxID(1)); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	db, err := i.NewDB(sio, i.NewSimpleDBTransactionLogIO(), true)
+	if err != nil {
+		t.Fatalf("Failed to NewDB: %v", err)
+	}
+	v, _, err := db.QueryNode(i.RootDirID, false)
+	if err != nil {
+		t.Fatalf("QueryNode failed: %v", err)
+	}
+	if !v.GetChangeT().Equal(mt) || !v.GetCreatedT().Equal(mt) || !v.GetAccessedT().Equal(mt) {
+		t.Errorf("Legacy snapshot times don't fall back to ModifiedT: %+v", v)
+	}
+}
@@ -36,3 +36,17 @@ func (io *SimpleDBTransactionLogIO) QueryTransactions(minID TxID) ([]DBTransacti
 	}
 	return result, nil
 }
+
+func (io *SimpleDBTransactionLogIO) DeleteTransactions(smallerThanID TxID) error {
+	if io.readOnly {
+		return util.EACCES
+	}
+	txs := []DBTransaction{}
+	for _, tx := range io.txs {
+		if tx.TxID >= smallerThanID {
+			txs = append(txs, tx)
+		}
+	}
+	io.txs = txs
+	return nil
+}
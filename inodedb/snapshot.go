@@ -3,6 +3,8 @@ package inodedb
 import (
 	"encoding/gob"
 	"fmt"
+	"io"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -28,9 +30,27 @@ func (s *DBState) EncodeToGob(enc *gob.Encoder) error {
 	if err := enc.Encode(s.version); err != nil {
 		return fmt.Errorf("Failed to encode version: %v", err)
 	}
+
+	// The node timestamps added after ModifiedT follow the original format,
+	// so that snapshots taken before them can still be decoded.
+	times := make(map[ID]nodeTimes, len(s.nodes))
+	for id, node := range s.nodes {
+		if c := nodeCommon(node); c != nil {
+			times[id] = nodeTimes{ChangeT: c.ChangeT, CreatedT: c.CreatedT, AccessedT: c.AccessedT}
+		}
+	}
+	if err := enc.Encode(times); err != nil {
+		return fmt.Errorf("Failed to encode node times: %v", err)
+	}
 	return nil
 }
 
+type nodeTimes struct {
+	ChangeT   time.Time
+	CreatedT  time.Time
+	AccessedT time.Time
+}
+
 func DecodeDBStateFromGob(dec *gob.Decoder) (*DBState, error) {
 	s := NewDBState()
 
@@ -53,6 +73,22 @@ func DecodeDBStateFromGob(dec *gob.Decoder) (*DBState, error) {
 		return nil, fmt.Errorf("Failed to decode version: %v", err)
 	}
 
+	var times map[ID]nodeTimes
+	if err := dec.Decode(&times); err != nil {
+		if err != io.EOF {
+			return nil, fmt.Errorf("Failed to decode node times: %v", err)
+		}
+		zap.S().Infof("The state snapshot doesn't have node times. Falling back to ModifiedT.")
+	}
+	for id, n := range s.nodes {
+		c := nodeCommon(n)
+		if t, ok := times[id]; ok {
+			c.ChangeT, c.CreatedT, c.AccessedT = t.ChangeT, t.CreatedT, t.AccessedT
+		} else {
+			c.ChangeT, c.CreatedT, c.AccessedT = c.ModifiedT, c.ModifiedT, c.ModifiedT
+		}
+	}
+
 	return s, nil
 }
 
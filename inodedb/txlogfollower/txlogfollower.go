@@ -0,0 +1,167 @@
+// Package txlogfollower keeps a read-only inodedb up to date with the
+// transactions appended by the writer instance of the filesystem.
+package txlogfollower
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/inodedb"
+	oprometheus "github.com/nyaxt/otaru/prometheus"
+	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/util"
+)
+
+const promSubsystem = "txlogfollower"
+
+var (
+	appliedTxsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "applied_txs",
+		Help:      "Number of transactions applied by following the txlog.",
+	})
+	restoredCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "restored",
+		Help:      "Number of times the state was restored from a snapshot, since the txlog needed to catch up was already gone.",
+	})
+	numStaleBlobsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: oprometheus.Namespace,
+		Subsystem: promSubsystem,
+		Name:      "num_stale_blobs",
+		Help:      "Number of blobs recently updated by the writer, and invalidated on each follow.",
+	})
+)
+
+type DB interface {
+	inodedb.CatchUpper
+	inodedb.DBHandler
+	inodedb.DBChunkRefLister
+}
+
+type BlobInvalidator interface {
+	InvalidateBackendVersion(blobpath string) bool
+}
+
+// DefaultStaleGracePeriod is the default of Task.StaleGracePeriod.
+const DefaultStaleGracePeriod = 5 * time.Minute
+
+// Task applies new transactions in the txlog to DB, and invalidates caches of
+// the blobs updated by the transactions.
+type Task struct {
+	DB          DB
+	Invalidator BlobInvalidator
+
+	// The writer appends a transaction before the updated blob content is
+	// written back to the backend. Blobs updated by a transaction are
+	// invalidated on each Run for StaleGracePeriod, so that the content
+	// written back later is observed.
+	StaleGracePeriod time.Duration
+
+	mu    sync.Mutex
+	stale map[string]time.Time
+}
+
+var _ = scheduler.Task(&Task{})
+
+func NewTask(db DB, inv BlobInvalidator) *Task {
+	return &Task{
+		DB:               db,
+		Invalidator:      inv,
+		StaleGracePeriod: DefaultStaleGracePeriod,
+		stale:            make(map[string]time.Time),
+	}
+}
+
+func (t *Task) markStaleNode(id inodedb.ID, now time.Time) {
+	v, _, err := t.DB.QueryNode(id, false)
+	if err != nil {
+		// The node may be removed by a later transaction.
+		if !util.IsNotExist(err) {
+			zap.S().Warnf("Failed to query node %d updated by txlog: %v", id, err)
+		}
+		return
+	}
+	if fv, ok := v.(*inodedb.FileNodeView); ok {
+		for _, fc := range fv.Chunks {
+			t.stale[fc.BlobPath] = now
+		}
+	}
+}
+
+func (t *Task) markStale(txs []inodedb.DBTransaction, now time.Time) {
+	for _, tx := range txs {
+		for _, op := range tx.Ops {
+			switch op := op.(type) {
+			case *inodedb.UpdateChunksOp:
+				for _, fc := range op.Chunks {
+					t.stale[fc.BlobPath] = now
+				}
+			case *inodedb.UpdateSizeOp:
+				t.markStaleNode(op.NodeLock.ID, now)
+			case *inodedb.UpdateModifiedTOp:
+				t.markStaleNode(op.ID, now)
+			}
+		}
+	}
+}
+
+func (t *Task) markAllStale(now time.Time) {
+	refs, errs := t.DB.ListChunkRefs()
+	for _, err := range errs {
+		zap.S().Warnf("Failed to list chunks after restoring snapshot: %v", err)
+	}
+	for _, ref := range refs {
+		t.stale[ref.BlobPath] = now
+	}
+}
+
+func (t *Task) Follow(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txs, restored, err := t.DB.CatchUp()
+	now := time.Now()
+	if restored {
+		restoredCounter.Inc()
+		zap.S().Infof("Restored inodedb from the latest snapshot. Invalidating all blobs.")
+		t.markAllStale(now)
+	}
+	if len(txs) > 0 {
+		appliedTxsCounter.Add(float64(len(txs)))
+		zap.S().Infof("Applied %d txs from txlog. Latest tx: %d", len(txs), txs[len(txs)-1].TxID)
+		t.markStale(txs, now)
+	}
+
+	for blobpath, updated := range t.stale {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ok := t.Invalidator.InvalidateBackendVersion(blobpath)
+		if ok && now.Sub(updated) > t.StaleGracePeriod {
+			delete(t.stale, blobpath)
+		}
+	}
+	numStaleBlobsGauge.Set(float64(len(t.stale)))
+
+	if err != nil {
+		return fmt.Errorf("Failed to catch up txlog: %v", err)
+	}
+	return nil
+}
+
+func (t *Task) Run(ctx context.Context) scheduler.Result {
+	return scheduler.ErrorResult{t.Follow(ctx)}
+}
+
+func (t *Task) String() string {
+	return fmt.Sprintf("txlogfollower.Task{%s}", util.Describe(t.DB))
+}
@@ -0,0 +1,117 @@
+package txlogfollower_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/inodedb/txlogfollower"
+	tu "github.com/nyaxt/otaru/testutils"
+)
+
+func init() { tu.EnsureLogger() }
+
+type mockInvalidator struct {
+	inuse       map[string]bool
+	invalidated []string
+}
+
+func (m *mockInvalidator) InvalidateBackendVersion(blobpath string) bool {
+	m.invalidated = append(m.invalidated, blobpath)
+	return !m.inuse[blobpath]
+}
+
+func (m *mockInvalidator) pop() []string {
+	ret := m.invalidated
+	m.invalidated = nil
+	sort.Strings(ret)
+	return ret
+}
+
+func TestFollow(t *testing.T) {
+	sio := inodedb.NewSimpleDBStateSnapshotIO()
+	txio := inodedb.NewSimpleDBTransactionLogIO()
+
+	wdb, err := inodedb.NewEmptyDB(sio, txio)
+	if err != nil {
+		t.Fatalf("Failed to NewEmptyDB: %v", err)
+	}
+	rdb, err := inodedb.NewDB(sio, txio, true)
+	if err != nil {
+		t.Fatalf("Failed to NewDB: %v", err)
+	}
+
+	inv := &mockInvalidator{inuse: map[string]bool{"b": true}}
+	task := txlogfollower.NewTask(rdb, inv)
+	task.StaleGracePeriod = time.Hour
+
+	nlock, err := wdb.LockNode(inodedb.AllocateNewNodeID)
+	if err != nil {
+		t.Fatalf("Failed to LockNode: %v", err)
+	}
+	for _, tx := range []inodedb.DBTransaction{
+		{Ops: []inodedb.DBOperation{
+			&inodedb.CreateNodeOp{NodeLock: nlock, OrigPath: "/hoge.txt", Type: inodedb.FileNodeT},
+			&inodedb.HardLinkOp{NodeLock: inodedb.NodeLock{inodedb.RootDirID, inodedb.NoTicket}, Name: "hoge.txt", TargetID: nlock.ID},
+		}},
+		{Ops: []inodedb.DBOperation{
+			&inodedb.UpdateChunksOp{NodeLock: nlock, Chunks: []inodedb.FileChunk{
+				{Offset: 0, Length: 10, BlobPath: "a"},
+				{Offset: 10, Length: 10, BlobPath: "b"},
+			}},
+		}},
+	} {
+		if _, err := wdb.ApplyTransaction(tx); err != nil {
+			t.Fatalf("Failed to apply tx: %v", err)
+		}
+	}
+
+	if err := task.Follow(context.Background()); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if _, _, err := rdb.QueryNode(nlock.ID, false); err != nil {
+		t.Errorf("QueryNode after Follow failed: %v", err)
+	}
+	if got := inv.pop(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Unexpected invalidated blobs: %v", got)
+	}
+
+	// Blobs are invalidated again within the grace period.
+	if err := task.Follow(context.Background()); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if got := inv.pop(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Unexpected invalidated blobs: %v", got)
+	}
+
+	// Blobs no longer in use are forgotten after the grace period.
+	task.StaleGracePeriod = 0
+	if err := task.Follow(context.Background()); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	inv.pop()
+	if err := task.Follow(context.Background()); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if got := inv.pop(); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("Unexpected invalidated blobs: %v", got)
+	}
+
+	// Size updates invalidate all chunks of the file.
+	task.StaleGracePeriod = time.Hour
+	inv.inuse = nil
+	if _, err := wdb.ApplyTransaction(inodedb.DBTransaction{Ops: []inodedb.DBOperation{
+		&inodedb.UpdateSizeOp{NodeLock: nlock, Size: 20},
+	}}); err != nil {
+		t.Fatalf("Failed to apply tx: %v", err)
+	}
+	if err := task.Follow(context.Background()); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if got := inv.pop(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Unexpected invalidated blobs: %v", got)
+	}
+}
@@ -8,13 +8,33 @@ import (
 
 const INodeDBSnapshotBlobpathPrefix = "META_INODEDB_SNAPSHOT"
 const VersionCacheBlobpath = "META_VERSION_CACHE"
+const CacheUsageStatsBlobpath = "META_CACHE_USAGE_STATS"
+const TierMapBlobpath = "META_TIER_MAP"
+const AuditLogBlobpathPrefix = "META_AUDITLOG"
+const ReplicaTombstoneBlobpathPrefix = "META_REPLICA_TOMBSTONE"
 
 func IsMetadataBlobpath(blobpath string) bool {
 	return strings.HasPrefix(blobpath, "META_")
 }
 
+// IsCacheStateBlobpath returns true if the blob holds a local cache state,
+// which is never written back to the backend.
+func IsCacheStateBlobpath(blobpath string) bool {
+	return blobpath == VersionCacheBlobpath || blobpath == CacheUsageStatsBlobpath
+}
+
 func GenINodeDBSnapshotBlobpath() string {
 	return fmt.Sprintf("%s.%s",
 		INodeDBSnapshotBlobpathPrefix,
 		time.Now().Format("2006-01-02.150405.000"))
 }
+
+// AuditLogBlobpath returns the blobpath to archive the audit log rotated at the timestamp ts.
+func AuditLogBlobpath(ts string) string {
+	return fmt.Sprintf("%s.%s", AuditLogBlobpathPrefix, ts)
+}
+
+// ReplicaTombstoneBlobpath returns the blobpath recording the removal of the blob from the replicas.
+func ReplicaTombstoneBlobpath(blobpath string) string {
+	return fmt.Sprintf("%s.%s", ReplicaTombstoneBlobpathPrefix, blobpath)
+}
@@ -0,0 +1,247 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/version"
+	"github.com/nyaxt/otaru/metadata"
+	"github.com/nyaxt/otaru/util"
+)
+
+const DefaultParallelism = 8
+
+type Options struct {
+	// Number of blobs copied in parallel. DefaultParallelism if 0.
+	Parallelism int
+	// Used to verify chunk blob versions. Metadata blobs, and all blobs if
+	// nil, are verified by the hash of their contents.
+	QueryVersion version.QueryFunc
+	// If non-nil, called with the current progress after each blob.
+	Progress func(Progress)
+	// If true, only report the blobs which would be copied.
+	DryRun bool
+}
+
+type Progress struct {
+	NumBlobs int
+	// Number of blobs copied.
+	NumCopied int
+	// Number of blobs skipped as they were already in the destination.
+	NumSkipped  int
+	NumFailed   int
+	BytesCopied int64
+	Elapsed     time.Duration
+}
+
+func (p Progress) NumDone() int { return p.NumCopied + p.NumSkipped + p.NumFailed }
+
+type Report struct {
+	Progress
+	Failed []string
+}
+
+type BlobListerSizer interface {
+	blobstore.BlobStore
+	blobstore.BlobLister
+	blobstore.BlobSizer
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+func queryBlobVersion(bs blobstore.BlobStore, blobpath string, q version.QueryFunc) (version.Version, error) {
+	r, err := bs.OpenReader(blobpath)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return q(r)
+}
+
+func hashBlob(bs blobstore.BlobStore, blobpath string) ([]byte, error) {
+	r, err := bs.OpenReader(blobpath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// isInSync returns nil if dst has the same blob as src, compared by size
+// and by chunk version. Metadata blobs, which don't have a chunk version,
+// are compared by the hash of their contents.
+func isInSync(src, dst blobstore.BlobSizer, blobpath string, opts *Options) error {
+	srcsize, err := src.BlobSize(blobpath)
+	if err != nil {
+		return fmt.Errorf("Failed to query source blob size: %v", err)
+	}
+	dstsize, err := dst.BlobSize(blobpath)
+	if err != nil {
+		return fmt.Errorf("Failed to query destination blob size: %v", err)
+	}
+	if srcsize != dstsize {
+		return fmt.Errorf("Size mismatch. src: %d, dst: %d", srcsize, dstsize)
+	}
+
+	if opts.QueryVersion == nil || metadata.IsMetadataBlobpath(blobpath) {
+		srchash, err := hashBlob(src.(blobstore.BlobStore), blobpath)
+		if err != nil {
+			return fmt.Errorf("Failed to hash source blob: %v", err)
+		}
+		dsthash, err := hashBlob(dst.(blobstore.BlobStore), blobpath)
+		if err != nil {
+			return fmt.Errorf("Failed to hash destination blob: %v", err)
+		}
+		if !bytes.Equal(srchash, dsthash) {
+			return fmt.Errorf("Content mismatch.")
+		}
+		return nil
+	}
+	srcver, err := queryBlobVersion(src.(blobstore.BlobStore), blobpath, opts.QueryVersion)
+	if err != nil {
+		return fmt.Errorf("Failed to query source blob version: %v", err)
+	}
+	dstver, err := queryBlobVersion(dst.(blobstore.BlobStore), blobpath, opts.QueryVersion)
+	if err != nil {
+		return fmt.Errorf("Failed to query destination blob version: %v", err)
+	}
+	if srcver != dstver {
+		return fmt.Errorf("Version mismatch. src: %d, dst: %d", srcver, dstver)
+	}
+	return nil
+}
+
+func copyBlob(src, dst blobstore.BlobStore, blobpath string) (int64, error) {
+	r, err := src.OpenReader(blobpath)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to open source blob: %v", err)
+	}
+	defer r.Close()
+
+	w, err := dst.OpenWriter(blobpath)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to open destination blob: %v", err)
+	}
+	cw := &countingWriter{w: w}
+	if _, err := io.Copy(cw, r); err != nil {
+		w.Close()
+		return cw.n, fmt.Errorf("Failed to copy blob: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return cw.n, fmt.Errorf("Failed to close destination blob: %v", err)
+	}
+	return cw.n, nil
+}
+
+// CopyBlobs copies all blobs in src to dst in parallel. Blobs already in dst
+// with the same size and chunk version are skipped, so an interrupted
+// migration can be resumed by running it again. Each copied blob is verified
+// the same way.
+func CopyBlobs(ctx context.Context, src, dst BlobListerSizer, opts Options) (*Report, error) {
+	start := time.Now()
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = DefaultParallelism
+	}
+
+	bps, err := src.ListBlobs()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list source blobs: %v", err)
+	}
+	sort.Strings(bps)
+	zap.S().Infof("Migrate: found %d blobs in source \"%s\".", len(bps), util.TryGetImplName(src))
+
+	var mu sync.Mutex
+	report := &Report{Progress: Progress{NumBlobs: len(bps)}}
+	update := func(blobpath string, copied bool, n int64, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case err != nil:
+			zap.S().Errorf("Migrate: failed to migrate blob \"%s\": %v", blobpath, err)
+			report.NumFailed++
+			report.Failed = append(report.Failed, blobpath)
+		case copied:
+			report.NumCopied++
+		default:
+			report.NumSkipped++
+		}
+		report.BytesCopied += n
+		report.Elapsed = time.Since(start)
+		if opts.Progress != nil {
+			opts.Progress(report.Progress)
+		}
+	}
+
+	bpC := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for bp := range bpC {
+				if err := isInSync(src, dst, bp, &opts); err == nil {
+					update(bp, false, 0, nil)
+					continue
+				} else if !util.IsNotExist(err) {
+					zap.S().Debugf("Migrate: blob \"%s\" needs copy: %v", bp, err)
+				}
+				if opts.DryRun {
+					zap.S().Infof("Migrate: dryrun would copy blob \"%s\"", bp)
+					update(bp, true, 0, nil)
+					continue
+				}
+
+				n, err := copyBlob(src, dst, bp)
+				if err == nil {
+					if verr := isInSync(src, dst, bp, &opts); verr != nil {
+						err = fmt.Errorf("Verification failed: %v", verr)
+					}
+				}
+				update(bp, true, n, err)
+			}
+		}()
+	}
+
+	for _, bp := range bps {
+		if ctx.Err() != nil {
+			break
+		}
+		bpC <- bp
+	}
+	close(bpC)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	zap.S().Infof("Migrate: blobs done. Copied: %d, Skipped: %d, Failed: %d, Bytes: %d. Took %v.",
+		report.NumCopied, report.NumSkipped, report.NumFailed, report.BytesCopied, time.Since(start))
+	if report.NumFailed > 0 {
+		return report, fmt.Errorf("Failed to migrate %d blobs.", report.NumFailed)
+	}
+	return report, nil
+}
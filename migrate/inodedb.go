@@ -0,0 +1,112 @@
+package migrate
+
+import (
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/inodedb/blobstoredbstatesnapshotio"
+	"github.com/nyaxt/otaru/util"
+)
+
+// TxLog appends the transactions in src newer than the last transaction in
+// dst to dst. Returns the number of transactions migrated.
+func TxLog(src, dst inodedb.DBTransactionLogIO) (int, error) {
+	dsttxs, err := dst.QueryTransactions(inodedb.AnyVersion)
+	if err != nil && !util.IsNotExist(err) {
+		return 0, fmt.Errorf("Failed to query destination txlog: %v", err)
+	}
+	var lastID inodedb.TxID
+	for _, tx := range dsttxs {
+		if tx.TxID > lastID {
+			lastID = tx.TxID
+		}
+	}
+
+	srctxs, err := src.QueryTransactions(lastID + 1)
+	if err != nil {
+		if util.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("Failed to query source txlog: %v", err)
+	}
+	sort.Slice(srctxs, func(i, j int) bool { return srctxs[i].TxID < srctxs[j].TxID })
+
+	for _, tx := range srctxs {
+		if err := dst.AppendTransaction(tx); err != nil {
+			return 0, fmt.Errorf("Failed to append transaction %v: %v", tx.TxID, err)
+		}
+	}
+	if syncer, ok := dst.(util.Syncer); ok {
+		if err := syncer.Sync(); err != nil {
+			return 0, fmt.Errorf("Failed to sync destination txlog: %v", err)
+		}
+	}
+	zap.S().Infof("Migrate: migrated %d transactions after TxID %v.", len(srctxs), lastID)
+	return len(srctxs), nil
+}
+
+// MaxSSLocatorHistory is the maximum number of snapshot locator entries migrated.
+const MaxSSLocatorHistory = 100
+
+type ssentry struct {
+	blobpath string
+	txid     int64
+}
+
+// locateAll returns up to MaxSSLocatorHistory entries of loc, newest first.
+func locateAll(loc blobstoredbstatesnapshotio.SSLocator) ([]ssentry, error) {
+	var es []ssentry
+	seen := make(map[string]struct{})
+	for history := 0; history < MaxSSLocatorHistory; history++ {
+		bp, txid, err := loc.Locate(history)
+		if err != nil {
+			if history == 0 {
+				return nil, err
+			}
+			break
+		}
+		// Some locators return the same entry for any history.
+		if _, ok := seen[bp]; ok {
+			break
+		}
+		seen[bp] = struct{}{}
+		es = append(es, ssentry{bp, txid})
+	}
+	return es, nil
+}
+
+// SSLocator puts the snapshot locator entries in src to dst, oldest first.
+// The entries dst already has are skipped. Returns the number of entries
+// migrated.
+func SSLocator(src, dst blobstoredbstatesnapshotio.SSLocator) (int, error) {
+	es, err := locateAll(src)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to locate any snapshot in source: %v", err)
+	}
+	// Locators report an empty history differently, so any error is taken
+	// as no entries. The entries are then put again, which is harmless.
+	dstes, err := locateAll(dst)
+	if err != nil {
+		zap.S().Infof("Migrate: no snapshot located in destination: %v", err)
+	}
+	has := make(map[ssentry]struct{})
+	for _, e := range dstes {
+		has[e] = struct{}{}
+	}
+
+	n := 0
+	for i := len(es) - 1; i >= 0; i-- {
+		if _, ok := has[es[i]]; ok {
+			continue
+		}
+		if err := dst.Put(es[i].blobpath, es[i].txid); err != nil {
+			return n, fmt.Errorf("Failed to put snapshot location (%s, %d): %v", es[i].blobpath, es[i].txid, err)
+		}
+		n++
+	}
+	zap.S().Infof("Migrate: migrated %d snapshot locator entries. %d were already in the destination.", n, len(es)-n)
+	return n, nil
+}
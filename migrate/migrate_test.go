@@ -0,0 +1,244 @@
+package migrate_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/version"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/metadata"
+	"github.com/nyaxt/otaru/migrate"
+	tu "github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/util"
+)
+
+func init() { tu.EnsureLogger() }
+
+func TestCopyBlobs(t *testing.T) {
+	src := tu.TestFileBlobStoreOfName("src")
+	dst := tu.TestFileBlobStoreOfName("dst")
+
+	for i := 0; i < 20; i++ {
+		if err := tu.WriteVersionedBlob(src, fmt.Sprintf("blob%d", i), byte(i+1)); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	// Already migrated.
+	if err := tu.WriteVersionedBlob(dst, "blob0", 1); err != nil {
+		t.Fatalf("%v", err)
+	}
+	// Stale in destination.
+	if err := tu.WriteVersionedBlob(dst, "blob1", 1); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	numProgress := 0
+	opts := migrate.Options{
+		Parallelism:  4,
+		QueryVersion: tu.TestQueryVersion,
+		Progress:     func(migrate.Progress) { numProgress++ },
+	}
+	report, err := migrate.CopyBlobs(context.Background(), src, dst, opts)
+	if err != nil {
+		t.Fatalf("CopyBlobs failed: %v", err)
+	}
+	if report.NumBlobs != 20 || report.NumCopied != 19 || report.NumSkipped != 1 || report.NumFailed != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if numProgress != 20 {
+		t.Errorf("Expected 20 progress updates, got %d", numProgress)
+	}
+	for i := 0; i < 20; i++ {
+		if err := tu.AssertBlobVersion(dst, fmt.Sprintf("blob%d", i), version.Version(i+1)); err != nil {
+			t.Errorf("%v", err)
+		}
+	}
+
+	// Resume.
+	report, err = migrate.CopyBlobs(context.Background(), src, dst, opts)
+	if err != nil {
+		t.Fatalf("CopyBlobs failed: %v", err)
+	}
+	if report.NumCopied != 0 || report.NumSkipped != 20 {
+		t.Errorf("Unexpected report on resume: %+v", report)
+	}
+}
+
+func TestCopyBlobs_DryRun(t *testing.T) {
+	src := tu.TestFileBlobStoreOfName("src")
+	dst := tu.TestFileBlobStoreOfName("dst")
+
+	if err := tu.WriteVersionedBlob(src, "blob", 1); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	opts := migrate.Options{QueryVersion: tu.TestQueryVersion, DryRun: true}
+	report, err := migrate.CopyBlobs(context.Background(), src, dst, opts)
+	if err != nil {
+		t.Fatalf("CopyBlobs failed: %v", err)
+	}
+	if report.NumCopied != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if _, err := dst.BlobSize("blob"); !util.IsNotExist(err) {
+		t.Errorf("Blob copied on dryrun. err: %v", err)
+	}
+}
+
+func writeBlob(t *testing.T, bs blobstore.BlobStore, blobpath string, data string) {
+	w, err := bs.OpenWriter(blobpath)
+	if err != nil {
+		t.Fatalf("OpenWriter failed: %v", err)
+	}
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func TestCopyBlobs_Metadata(t *testing.T) {
+	src := tu.TestFileBlobStoreOfName("src")
+	dst := tu.TestFileBlobStoreOfName("dst")
+
+	bp := metadata.GenINodeDBSnapshotBlobpath()
+	writeBlob(t, src, bp, "new snapshot")
+	// Same size, but different contents.
+	writeBlob(t, dst, bp, "old snapshot")
+
+	opts := migrate.Options{QueryVersion: tu.TestQueryVersion}
+	report, err := migrate.CopyBlobs(context.Background(), src, dst, opts)
+	if err != nil {
+		t.Fatalf("CopyBlobs failed: %v", err)
+	}
+	if report.NumCopied != 1 || report.NumSkipped != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	r, err := dst.OpenReader(bp)
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer r.Close()
+	if b, err := ioutil.ReadAll(r); err != nil || string(b) != "new snapshot" {
+		t.Errorf("Unexpected destination blob: %q, err: %v", b, err)
+	}
+
+	report, err = migrate.CopyBlobs(context.Background(), src, dst, opts)
+	if err != nil {
+		t.Fatalf("CopyBlobs failed: %v", err)
+	}
+	if report.NumCopied != 0 || report.NumSkipped != 1 {
+		t.Errorf("Unexpected report on resume: %+v", report)
+	}
+}
+
+func TestTxLog(t *testing.T) {
+	src := inodedb.NewSimpleDBTransactionLogIO()
+	dst := inodedb.NewSimpleDBTransactionLogIO()
+
+	for id := inodedb.TxID(1); id <= 5; id++ {
+		if err := src.AppendTransaction(inodedb.DBTransaction{TxID: id}); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	if err := dst.AppendTransaction(inodedb.DBTransaction{TxID: 1}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	n, err := migrate.TxLog(src, dst)
+	if err != nil {
+		t.Fatalf("TxLog failed: %v", err)
+	}
+	if n != 4 {
+		t.Errorf("Expected 4 txs migrated, got %d", n)
+	}
+	txs, err := dst.QueryTransactions(inodedb.AnyVersion)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(txs) != 5 {
+		t.Fatalf("Expected 5 txs in dst, got %d", len(txs))
+	}
+	for i, tx := range txs {
+		if tx.TxID != inodedb.TxID(i+1) {
+			t.Errorf("Unexpected txid at %d: %v", i, tx.TxID)
+		}
+	}
+
+	n, err = migrate.TxLog(src, dst)
+	if err != nil {
+		t.Fatalf("TxLog failed: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("Expected no txs migrated on rerun, got %d", n)
+	}
+}
+
+type sslocentry struct {
+	blobpath string
+	txid     int64
+}
+
+// testSSLocator keeps the entries newest first.
+type testSSLocator struct {
+	es []sslocentry
+}
+
+func (loc *testSSLocator) Locate(history int) (string, int64, error) {
+	if history >= len(loc.es) {
+		return "", 0, util.ENOENT
+	}
+	e := loc.es[history]
+	return e.blobpath, e.txid, nil
+}
+
+func (*testSSLocator) GenerateBlobpath() string { return "" }
+
+func (loc *testSSLocator) Put(blobpath string, txid int64) error {
+	loc.es = append([]sslocentry{{blobpath, txid}}, loc.es...)
+	return nil
+}
+
+func (*testSSLocator) DeleteOld(ctx context.Context, threshold int, dryRun bool) ([]string, error) {
+	return nil, nil
+}
+
+func TestSSLocator(t *testing.T) {
+	src := &testSSLocator{}
+	for i := int64(1); i <= 3; i++ {
+		src.Put(fmt.Sprintf("ss%d", i), i*10)
+	}
+	dst := &testSSLocator{}
+
+	n, err := migrate.SSLocator(src, dst)
+	if err != nil {
+		t.Fatalf("SSLocator failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 entries migrated, got %d", n)
+	}
+	bp, txid, err := dst.Locate(0)
+	if err != nil || bp != "ss3" || txid != 30 {
+		t.Errorf("Unexpected latest entry: %s %d %v", bp, txid, err)
+	}
+	bp, txid, err = dst.Locate(2)
+	if err != nil || bp != "ss1" || txid != 10 {
+		t.Errorf("Unexpected oldest entry: %s %d %v", bp, txid, err)
+	}
+
+	// Resume.
+	if n, err := migrate.SSLocator(src, dst); err != nil || n != 0 {
+		t.Errorf("Expected no entries migrated on resume, got %d, err: %v", n, err)
+	}
+	if len(dst.es) != 3 {
+		t.Errorf("Entries put again on resume: %v", dst.es)
+	}
+
+	if _, err := migrate.SSLocator(&testSSLocator{}, dst); err == nil {
+		t.Errorf("Expected error on empty source")
+	}
+}
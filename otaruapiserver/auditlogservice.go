@@ -0,0 +1,70 @@
+package otaruapiserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/nyaxt/otaru/apiserver"
+	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/auditlog"
+	"github.com/nyaxt/otaru/pb"
+)
+
+type auditLogService struct {
+	log *auditlog.Log
+	pb.UnimplementedAuditLogServiceServer
+}
+
+func timeFromUnixOrZero(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(t, 0)
+}
+
+func (svc *auditLogService) QueryAuditLog(ctx context.Context, req *pb.QueryAuditLogRequest) (*pb.QueryAuditLogResponse, error) {
+	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	q := auditlog.Query{
+		Since:      timeFromUnixOrZero(req.SinceTime),
+		Until:      timeFromUnixOrZero(req.UntilTime),
+		User:       req.User,
+		Op:         req.Op,
+		PathPrefix: req.PathPrefix,
+		Limit:      int(req.Limit),
+	}
+	es, err := svc.log.Query(q)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "Failed to query audit log: %v", err)
+	}
+
+	pes := make([]*pb.AuditLogEntry, 0, len(es))
+	for _, e := range es {
+		pes = append(pes, &pb.AuditLogEntry{
+			Time:    e.Time.Unix(),
+			Source:  e.Source,
+			User:    e.User,
+			Op:      e.Op,
+			Path:    e.Path,
+			NewPath: e.NewPath,
+			Id:      uint64(e.ID),
+			Txid:    uint64(e.TxID),
+			Error:   e.Error,
+		})
+	}
+	return &pb.QueryAuditLogResponse{Entry: pes}, nil
+}
+
+func InstallAuditLogService(log *auditlog.Log) apiserver.Option {
+	svc := &auditLogService{log: log}
+
+	return apiserver.RegisterService(
+		func(s *grpc.Server) { pb.RegisterAuditLogServiceServer(s, svc) },
+		pb.RegisterAuditLogServiceHandlerFromEndpoint,
+	)
+}
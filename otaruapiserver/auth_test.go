@@ -7,10 +7,18 @@ import (
 	"bytes"
 	"context"
 	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/nyaxt/otaru/apiserver"
+	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/apiserver/ratelimit"
 	"github.com/nyaxt/otaru/cli"
 	"github.com/nyaxt/otaru/filesystem"
 	"github.com/nyaxt/otaru/inodedb"
@@ -41,7 +49,7 @@ const (
 	EnableClientAuth
 )
 
-func runTestServer(t *testing.T, auth Auth) *testServer {
+func runTestServer(t *testing.T, auth Auth, extraOpts ...apiserver.Option) *testServer {
 	t.Helper()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -71,7 +79,9 @@ func runTestServer(t *testing.T, auth Auth) *testServer {
 			apiserver.TLSCertKey(testca.Certs, testca.Key.Parsed),
 			otaruapiserver.InstallSystemService(),
 			otaruapiserver.InstallFileHandler(ts.fs),
+			otaruapiserver.InstallFileSystemService(ts.fs),
 		}
+		opts = append(opts, extraOpts...)
 		if auth == EnableClientAuth {
 			opts = append(opts, apiserver.ClientCACert(testca.ClientAuthCACert))
 		}
@@ -347,3 +357,373 @@ func TestAuth_InvalidCert(t *testing.T) {
 	t.Run("httpRead", genHttpReadTest(cfg, ts.inodeRead, false))
 	t.Run("httpWrite", genHttpWriteTest(cfg, ts.inodeWrite, false))
 }
+
+func TestAuth_ACL(t *testing.T) {
+	acl, err := clientauth.NewACL([]clientauth.ACLEntry{
+		{User: "alice", PathPrefix: "/", Permission: clientauth.PermNone},
+		{User: "alice", PathPrefix: "/foo.txt", Permission: clientauth.PermRead},
+		{User: "bob", PathPrefix: "/hoge.txt", Permission: clientauth.PermWrite},
+	})
+	if err != nil {
+		t.Fatalf("NewACL: %v", err)
+	}
+	ts := runTestServer(t, EnableClientAuth, apiserver.ACL(acl))
+	defer ts.Terminate()
+
+	admincfg := testCliConfig(&cli.Host{
+		ApiEndpoint: testListenAddr,
+		CACert:      testca.CACert,
+		Certs:       testca.ClientAuthAdminCerts,
+		Key:         testca.ClientAuthAdminKey.Parsed,
+	})
+	readonlycfg := testCliConfig(&cli.Host{
+		ApiEndpoint: testListenAddr,
+		CACert:      testca.CACert,
+		Certs:       testca.ClientAuthReadOnlyCerts,
+		Key:         testca.ClientAuthReadOnlyKey.Parsed,
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		ctx := context.Background()
+
+		ci, err := cli.QueryConnectionInfo(admincfg, "default")
+		if err != nil {
+			t.Fatalf("QueryConnectionInfo: %v", err)
+		}
+
+		conn, err := ci.DialGrpc(ctx)
+		if err != nil {
+			t.Fatalf("DialGrpc: %v", err)
+		}
+		defer conn.Close()
+
+		fsc := pb.NewFileSystemServiceClient(conn)
+		if _, err := fsc.FindNodeFullPath(ctx, &pb.FindNodeFullPathRequest{Path: "/foo.txt"}); err != nil {
+			t.Errorf("FindNodeFullPath: %v", err)
+		}
+		if _, err := fsc.Attr(ctx, &pb.AttrRequest{Id: uint64(ts.inodeWrite)}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for Attr outside the ACL, got %v", err)
+		}
+		if _, err := fsc.ListDir(ctx, &pb.ListDirRequest{Path: "/"}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for ListDir outside the ACL, got %v", err)
+		}
+		if _, err := fsc.Remove(ctx, &pb.RemoveRequest{Name: "/foo.txt"}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for Remove of a read only file, got %v", err)
+		}
+	})
+
+	t.Run("httpReadAdmin", genHttpReadTest(admincfg, ts.inodeRead, true))
+	t.Run("httpWriteAdmin", genHttpWriteTest(admincfg, ts.inodeWrite, false))
+	t.Run("httpReadReadOnly", genHttpReadTest(readonlycfg, ts.inodeRead, false))
+	t.Run("httpWriteReadOnly", genHttpWriteTest(readonlycfg, ts.inodeWrite, true))
+}
+
+func TestAuth_ACLAfterRename(t *testing.T) {
+	acl, err := clientauth.NewACL([]clientauth.ACLEntry{
+		{User: "alice", PathPrefix: "/", Permission: clientauth.PermNone},
+		{User: "alice", PathPrefix: "/pub", Permission: clientauth.PermWrite},
+	})
+	if err != nil {
+		t.Fatalf("NewACL: %v", err)
+	}
+	ts := runTestServer(t, EnableClientAuth, apiserver.ACL(acl))
+	defer ts.Terminate()
+
+	now := time.Now()
+	pubID, err := ts.fs.CreateDirFullPath("/pub", 0755, 1000, 1000, now)
+	if err != nil {
+		t.Fatalf("CreateDirFullPath: %v", err)
+	}
+	subID, err := ts.fs.CreateDirFullPath("/pub/sub", 0755, 1000, 1000, now)
+	if err != nil {
+		t.Fatalf("CreateDirFullPath: %v", err)
+	}
+	secretID, err := ts.fs.CreateDirFullPath("/secret", 0755, 1000, 1000, now)
+	if err != nil {
+		t.Fatalf("CreateDirFullPath: %v", err)
+	}
+	if err := ts.fs.WriteFile("/pub/a.txt", testutils.HelloWorld, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := ts.fs.WriteFile("/pub/sub/b.txt", testutils.HelloWorld, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	aID, err := ts.fs.FindNodeFullPath("/pub/a.txt")
+	if err != nil {
+		t.Fatalf("FindNodeFullPath: %v", err)
+	}
+	bID, err := ts.fs.FindNodeFullPath("/pub/sub/b.txt")
+	if err != nil {
+		t.Fatalf("FindNodeFullPath: %v", err)
+	}
+
+	cfg := testCliConfig(&cli.Host{
+		ApiEndpoint: testListenAddr,
+		CACert:      testca.CACert,
+		Certs:       testca.ClientAuthAdminCerts,
+		Key:         testca.ClientAuthAdminKey.Parsed,
+	})
+	ctx := context.Background()
+	ci, err := cli.QueryConnectionInfo(cfg, "default")
+	if err != nil {
+		t.Fatalf("QueryConnectionInfo: %v", err)
+	}
+	conn, err := ci.DialGrpc(ctx)
+	if err != nil {
+		t.Fatalf("DialGrpc: %v", err)
+	}
+	defer conn.Close()
+	fsc := pb.NewFileSystemServiceClient(conn)
+
+	for _, id := range []inodedb.ID{aID, bID} {
+		if _, err := fsc.ReadFile(ctx, &pb.ReadFileRequest{Id: uint64(id), Length: 1}); err != nil {
+			t.Errorf("ReadFile(%d) before rename: %v", id, err)
+		}
+	}
+
+	// Move a file and a dir into the denied prefix. Their OrigPaths stay under /pub.
+	if err := ts.fs.Rename(pubID, "a.txt", secretID, "a.txt"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if err := ts.fs.Rename(pubID, "sub", secretID, "sub"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	for _, id := range []inodedb.ID{aID, bID, subID} {
+		if _, err := fsc.Attr(ctx, &pb.AttrRequest{Id: uint64(id)}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for Attr(%d) after rename, got %v", id, err)
+		}
+	}
+	for _, id := range []inodedb.ID{aID, bID} {
+		if _, err := fsc.ReadFile(ctx, &pb.ReadFileRequest{Id: uint64(id), Length: 1}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for ReadFile(%d) after rename, got %v", id, err)
+		}
+		if _, err := fsc.WriteFile(ctx, &pb.WriteFileRequest{Id: uint64(id), Body: []byte("x")}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for WriteFile(%d) after rename, got %v", id, err)
+		}
+	}
+	if _, err := fsc.ListDir(ctx, &pb.ListDirRequest{Id: []uint64{uint64(subID)}}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for ListDir after rename, got %v", err)
+	}
+	t.Run("httpRead", genHttpReadTest(cfg, aID, false))
+
+	// Moving back into the allowed prefix restores the access.
+	if err := ts.fs.Rename(secretID, "a.txt", pubID, "c.txt"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	resp, err := fsc.Attr(ctx, &pb.AttrRequest{Id: uint64(aID)})
+	if err != nil {
+		t.Fatalf("Attr after moving back: %v", err)
+	}
+	if resp.Entry.Name != "c.txt" {
+		t.Errorf("Expected the current name, got %q", resp.Entry.Name)
+	}
+	t.Run("httpReadMovedBack", genHttpReadTest(cfg, aID, true))
+}
+
+func TestAuth_BearerToken(t *testing.T) {
+	tokens, err := clientauth.NewTokenStore(filepath.Join(t.TempDir(), "tokens.json"))
+	if err != nil {
+		t.Fatalf("NewTokenStore: %v", err)
+	}
+	_, adminSecret, err := tokens.Create("", clientauth.RoleAdmin, "", time.Time{}, "test admin")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	_, scopedSecret, err := tokens.Create("scoped", clientauth.RoleReadOnly, "/foo.txt", time.Now().Add(time.Hour), "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	_, expiredSecret, err := tokens.Create("expired", clientauth.RoleAdmin, "", time.Now().Add(-time.Hour), "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	ts := runTestServer(t, EnableClientAuth, apiserver.TokenStore(tokens), otaruapiserver.InstallTokenService(tokens))
+	defer ts.Terminate()
+
+	tokenCliConfig := func(token string) *cli.CliConfig {
+		return testCliConfig(&cli.Host{
+			ApiEndpoint: testListenAddr,
+			CACert:      testca.CACert,
+			Token:       token,
+		})
+	}
+	dial := func(t *testing.T, cfg *cli.CliConfig) *grpc.ClientConn {
+		t.Helper()
+
+		ci, err := cli.QueryConnectionInfo(cfg, "default")
+		if err != nil {
+			t.Fatalf("QueryConnectionInfo: %v", err)
+		}
+		conn, err := ci.DialGrpc(context.Background())
+		if err != nil {
+			t.Fatalf("DialGrpc: %v", err)
+		}
+		return conn
+	}
+
+	t.Run("grpcAdmin", func(t *testing.T) {
+		ctx := context.Background()
+		conn := dial(t, tokenCliConfig(adminSecret))
+		defer conn.Close()
+
+		ssc := pb.NewSystemInfoServiceClient(conn)
+		if _, err := ssc.AuthTestAdmin(ctx, &pb.AuthTestRequest{}); err != nil {
+			t.Errorf("AuthTestAdmin: %v", err)
+		}
+
+		tsc := pb.NewTokenServiceClient(conn)
+		cresp, err := tsc.CreateToken(ctx, &pb.CreateTokenRequest{Role: "readonly"})
+		if err != nil {
+			t.Fatalf("CreateToken: %v", err)
+		}
+		lresp, err := tsc.ListTokens(ctx, &pb.ListTokensRequest{})
+		if err != nil {
+			t.Fatalf("ListTokens: %v", err)
+		}
+		if len(lresp.Token) != 4 {
+			t.Errorf("Unexpected ListTokens: %v", lresp.Token)
+		}
+		for _, tv := range lresp.Token {
+			if strings.Contains(tv.String(), cresp.Secret) {
+				t.Errorf("ListTokens leaks the secret: %v", tv)
+			}
+		}
+
+		conn2 := dial(t, tokenCliConfig(cresp.Secret))
+		defer conn2.Close()
+		ssc2 := pb.NewSystemInfoServiceClient(conn2)
+		if _, err := ssc2.AuthTestReadOnly(ctx, &pb.AuthTestRequest{}); err != nil {
+			t.Errorf("AuthTestReadOnly with the created token: %v", err)
+		}
+		if _, err := ssc2.AuthTestAdmin(ctx, &pb.AuthTestRequest{}); err == nil {
+			t.Errorf("AuthTestAdmin with the readonly token should fail.")
+		}
+
+		if _, err := tsc.RevokeToken(ctx, &pb.RevokeTokenRequest{Id: cresp.Token.Id}); err != nil {
+			t.Fatalf("RevokeToken: %v", err)
+		}
+		if _, err := ssc2.AuthTestAnonymous(ctx, &pb.AuthTestRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated with the revoked token, got %v", err)
+		}
+	})
+
+	t.Run("grpcScoped", func(t *testing.T) {
+		ctx := context.Background()
+		conn := dial(t, tokenCliConfig(scopedSecret))
+		defer conn.Close()
+
+		fsc := pb.NewFileSystemServiceClient(conn)
+		if _, err := fsc.Attr(ctx, &pb.AttrRequest{Id: uint64(ts.inodeRead)}); err != nil {
+			t.Errorf("Attr in the scope: %v", err)
+		}
+		if _, err := fsc.Attr(ctx, &pb.AttrRequest{Id: uint64(ts.inodeWrite)}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for Attr outside the scope, got %v", err)
+		}
+	})
+
+	t.Run("grpcInvalid", func(t *testing.T) {
+		ctx := context.Background()
+		for _, secret := range []string{expiredSecret, "bogus", adminSecret + "x"} {
+			conn := dial(t, tokenCliConfig(secret))
+			ssc := pb.NewSystemInfoServiceClient(conn)
+			if _, err := ssc.AuthTestAnonymous(ctx, &pb.AuthTestRequest{}); status.Code(err) != codes.Unauthenticated {
+				t.Errorf("Expected Unauthenticated for token %q, got %v", secret, err)
+			}
+			conn.Close()
+		}
+	})
+
+	t.Run("httpReadScoped", genHttpReadTest(tokenCliConfig(scopedSecret), ts.inodeRead, true))
+	t.Run("httpWriteScoped", genHttpWriteTest(tokenCliConfig(scopedSecret), ts.inodeWrite, false))
+	t.Run("httpWriteAdmin", genHttpWriteTest(tokenCliConfig(adminSecret), ts.inodeWrite, true))
+	t.Run("httpReadExpired", genHttpReadTest(tokenCliConfig(expiredSecret), ts.inodeRead, false))
+}
+
+func TestAuth_RateLimit(t *testing.T) {
+	limiter, err := ratelimit.New([]ratelimit.Rule{
+		{Role: clientauth.RoleReadOnly, RequestsPerSec: 2},
+	})
+	if err != nil {
+		t.Fatalf("ratelimit.New: %v", err)
+	}
+	ts := runTestServer(t, EnableClientAuth, apiserver.RateLimiter(limiter))
+	defer ts.Terminate()
+
+	admincfg := testCliConfig(&cli.Host{
+		ApiEndpoint: testListenAddr,
+		CACert:      testca.CACert,
+		Certs:       testca.ClientAuthAdminCerts,
+		Key:         testca.ClientAuthAdminKey.Parsed,
+	})
+	readonlycfg := testCliConfig(&cli.Host{
+		ApiEndpoint: testListenAddr,
+		CACert:      testca.CACert,
+		Certs:       testca.ClientAuthReadOnlyCerts,
+		Key:         testca.ClientAuthReadOnlyKey.Parsed,
+	})
+
+	dial := func(t *testing.T, cfg *cli.CliConfig) *grpc.ClientConn {
+		t.Helper()
+
+		ci, err := cli.QueryConnectionInfo(cfg, "default")
+		if err != nil {
+			t.Fatalf("QueryConnectionInfo: %v", err)
+		}
+		conn, err := ci.DialGrpc(context.Background())
+		if err != nil {
+			t.Fatalf("DialGrpc: %v", err)
+		}
+		return conn
+	}
+
+	t.Run("grpc", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn := dial(t, readonlycfg)
+		defer conn.Close()
+		ssc := pb.NewSystemInfoServiceClient(conn)
+		for i := 0; i < 2; i++ {
+			if _, err := ssc.AuthTestReadOnly(ctx, &pb.AuthTestRequest{}); err != nil {
+				t.Fatalf("AuthTestReadOnly #%d: %v", i, err)
+			}
+		}
+		_, err := ssc.AuthTestReadOnly(ctx, &pb.AuthTestRequest{})
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("Expected ResourceExhausted, got %v", err)
+		}
+		if ds := status.Convert(err).Details(); len(ds) != 1 {
+			t.Errorf("Expected RetryInfo in the details, got %v", ds)
+		}
+
+		// Admins have no limits.
+		adminconn := dial(t, admincfg)
+		defer adminconn.Close()
+		adminssc := pb.NewSystemInfoServiceClient(adminconn)
+		for i := 0; i < 10; i++ {
+			if _, err := adminssc.AuthTestAdmin(ctx, &pb.AuthTestRequest{}); err != nil {
+				t.Fatalf("AuthTestAdmin #%d: %v", i, err)
+			}
+		}
+	})
+
+	t.Run("http", func(t *testing.T) {
+		ci, err := cli.QueryConnectionInfo(readonlycfg, "default")
+		if err != nil {
+			t.Fatalf("QueryConnectionInfo: %v", err)
+		}
+		var lastErr error
+		for i := 0; i < 3; i++ {
+			r, err := cli.NewReaderHttpForTesting(ci, uint64(ts.inodeRead))
+			if err != nil {
+				lastErr = err
+				break
+			}
+			r.Close()
+		}
+		if lastErr == nil || !strings.Contains(lastErr.Error(), "429") {
+			t.Errorf("Expected status code 429, got %v", lastErr)
+		}
+	})
+}
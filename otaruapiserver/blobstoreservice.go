@@ -6,15 +6,20 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/nyaxt/otaru/apiserver"
 	"github.com/nyaxt/otaru/apiserver/clientauth"
 	"github.com/nyaxt/otaru/blobstore"
 	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/blobstore/resilientblobstore"
+	"github.com/nyaxt/otaru/blobstore/version"
 	"github.com/nyaxt/otaru/flags"
 	"github.com/nyaxt/otaru/pb"
 	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/scrub"
 	"github.com/nyaxt/otaru/util"
+	"github.com/nyaxt/otaru/util/bwlimit"
 )
 
 type blobstoreService struct {
@@ -22,6 +27,10 @@ type blobstoreService struct {
 	bbs blobstore.BlobStore
 	cbs *cachedblobstore.CachedBlobStore
 
+	verify       version.VerifyFunc
+	quarantinebs blobstore.BlobStore
+	scrubTask    *scrub.Task
+
 	pb.UnimplementedBlobstoreServiceServer
 }
 
@@ -35,12 +44,23 @@ func (svc *blobstoreService) GetConfig(ctx context.Context, in *pb.GetBlobstoreC
 		beFlags = flags.FlagsToString(reader.Flags())
 	}
 
-	return &pb.GetBlobstoreConfigResponse{
+	resp := &pb.GetBlobstoreConfigResponse{
 		BackendImplName: util.TryGetImplName(svc.bbs),
 		BackendFlags:    beFlags,
 		CacheImplName:   util.TryGetImplName(svc.cbs),
 		CacheFlags:      flags.FlagsToString(svc.cbs.Flags()),
-	}, nil
+	}
+	if rbs, ok := svc.bbs.(*resilientblobstore.ResilientBlobStore); ok {
+		st := rbs.BreakerStatus()
+		resp.BackendBreaker = &pb.CircuitBreakerStatus{
+			State:               st.State.String(),
+			ConsecutiveFailures: int32(st.ConsecutiveFailures),
+		}
+		if !st.OpenedAt.IsZero() {
+			resp.BackendBreaker.OpenedTime = st.OpenedAt.Unix()
+		}
+	}
+	return resp, nil
 }
 
 func (svc *blobstoreService) GetEntries(ctx context.Context, in *pb.GetEntriesRequest) (*pb.GetEntriesResponse, error) {
@@ -95,9 +115,117 @@ func (svc *blobstoreService) ReduceCache(ctx context.Context, req *pb.ReduceCach
 	return &pb.ReduceCacheResponse{Success: true, ErrorMessage: "ok"}, nil
 }
 
-func InstallBlobstoreService(s *scheduler.Scheduler, bbs blobstore.BlobStore, cbs *cachedblobstore.CachedBlobStore) apiserver.Option {
+func (svc *blobstoreService) GetBandwidthLimit(ctx context.Context, req *pb.GetBandwidthLimitRequest) (*pb.GetBandwidthLimitResponse, error) {
+	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	cfg := svc.cbs.BandwidthLimit()
+	l := &pb.BandwidthLimit{
+		UploadBytesPerSec:   cfg.UploadBytesPerSec,
+		DownloadBytesPerSec: cfg.DownloadBytesPerSec,
+	}
+	if cfg.FullBandwidthWindow != nil {
+		l.FullBandwidthWindow = cfg.FullBandwidthWindow.String()
+	}
+	return &pb.GetBandwidthLimitResponse{
+		Limit:           l,
+		IsFullBandwidth: svc.cbs.IsFullBandwidth(),
+	}, nil
+}
+
+func (svc *blobstoreService) SetBandwidthLimit(ctx context.Context, req *pb.SetBandwidthLimitRequest) (*pb.SetBandwidthLimitResponse, error) {
+	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	l := req.Limit
+	if l == nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "limit must be specified.")
+	}
+	if l.UploadBytesPerSec < 0 || l.DownloadBytesPerSec < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "negative bandwidth limit given.")
+	}
+
+	cfg := cachedblobstore.BandwidthLimitConfig{
+		UploadBytesPerSec:   l.UploadBytesPerSec,
+		DownloadBytesPerSec: l.DownloadBytesPerSec,
+	}
+	if l.FullBandwidthWindow != "" {
+		w, err := bwlimit.ParseWindow(l.FullBandwidthWindow)
+		if err != nil {
+			return nil, grpc.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		cfg.FullBandwidthWindow = &w
+	}
+	svc.cbs.SetBandwidthLimit(cfg)
+
+	return &pb.SetBandwidthLimitResponse{}, nil
+}
+
+func (svc *blobstoreService) VerifyCache(ctx context.Context, req *pb.VerifyCacheRequest) (*pb.VerifyCacheResponse, error) {
+	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	jv := svc.s.RunImmediatelyBlock(&cachedblobstore.VerifyCacheTask{
+		CBS: svc.cbs, Verify: svc.verify, QuarantineBS: svc.quarantinebs, DryRun: req.DryRun})
+	if err := jv.Result.Err(); err != nil {
+		return nil, grpc.Errorf(codes.Internal, "VerifyCache failed: %v", err)
+	}
+	report := jv.Result.(cachedblobstore.VerifyCacheResult).Report
+
+	return &pb.VerifyCacheResponse{
+		NumVerified: int64(report.NumVerified),
+		NumStale:    int64(report.NumStale),
+		Quarantined: report.Quarantined,
+		DataLoss:    report.DataLoss,
+		Skipped:     report.Skipped,
+	}, nil
+}
+
+func (svc *blobstoreService) Scrub(ctx context.Context, req *pb.ScrubRequest) (*pb.ScrubResponse, error) {
+	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	t := *svc.scrubTask
+	jv := svc.s.RunImmediatelyBlock(&t)
+	if err := jv.Result.Err(); err != nil {
+		return nil, grpc.Errorf(codes.Internal, "Scrub failed: %v", err)
+	}
+	report := jv.Result.(scrub.Result).Report
+
+	bad := make([]*pb.ScrubBadBlob, 0, len(report.Bad))
+	for _, b := range report.Bad {
+		refs := make([]*pb.ScrubBlobRef, 0, len(b.Refs))
+		for _, ref := range b.Refs {
+			refs = append(refs, &pb.ScrubBlobRef{
+				Id:     uint64(ref.NodeID),
+				Path:   ref.Path,
+				Offset: ref.Offset,
+				Length: ref.Length,
+			})
+		}
+		bad = append(bad, &pb.ScrubBadBlob{
+			Blobpath: b.BlobPath,
+			Error:    b.Err.Error(),
+			Refs:     refs,
+		})
+	}
+	return &pb.ScrubResponse{
+		NumVerified: int64(report.NumVerified),
+		NumBytes:    report.NumBytes,
+		BadBlobs:    bad,
+		NumSkipped:  int64(report.NumSkipped),
+	}, nil
+}
+
+func InstallBlobstoreService(s *scheduler.Scheduler, bbs blobstore.BlobStore, cbs *cachedblobstore.CachedBlobStore, verify version.VerifyFunc, quarantinebs blobstore.BlobStore, scrubTask *scrub.Task) apiserver.Option {
 	svc := &blobstoreService{
 		s: s, bbs: bbs, cbs: cbs,
+		verify: verify, quarantinebs: quarantinebs,
+		scrubTask: scrubTask,
 	}
 
 	return apiserver.RegisterService(
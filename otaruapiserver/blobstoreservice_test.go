@@ -0,0 +1,78 @@
+package otaruapiserver_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/blobstore/resilientblobstore"
+	"github.com/nyaxt/otaru/cli"
+	"github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/otaruapiserver"
+	"github.com/nyaxt/otaru/pb"
+	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/testutils/testca"
+)
+
+var errBackendDown = errors.New("backend down")
+
+type downBlobStore struct {
+	blobstore.BlobStore
+}
+
+func (downBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	return nil, errBackendDown
+}
+
+func TestBlobstoreService_GetConfigBreaker(t *testing.T) {
+	cfg := resilientblobstore.DefaultConfig()
+	cfg.MaxRetries = 0
+	cfg.BreakerThreshold = 1
+	rbs := resilientblobstore.New(downBlobStore{testutils.TestFileBlobStoreOfName("backend")}, cfg)
+	defer rbs.Quit()
+
+	s := scheduler.NewScheduler()
+	defer s.AbortAllAndStop()
+	cbs, err := cachedblobstore.New(rbs, testutils.TestFileBlobStoreOfName("cache"), s, flags.O_RDWRCREATE, testutils.TestQueryVersion)
+	if err != nil {
+		t.Fatalf("Failed to create CachedBlobStore: %v", err)
+	}
+	defer cbs.Quit()
+
+	if _, err := rbs.OpenReader("foo"); !errors.Is(err, errBackendDown) {
+		t.Fatalf("Expected errBackendDown, got %v", err)
+	}
+
+	ts := runTestServer(t, NoAuth, otaruapiserver.InstallBlobstoreService(s, rbs, cbs, nil, nil, nil))
+	defer ts.Terminate()
+
+	ctx := context.Background()
+	ci, err := cli.QueryConnectionInfo(testCliConfig(&cli.Host{
+		ApiEndpoint: testListenAddr,
+		CACert:      testca.CACert,
+	}), "default")
+	if err != nil {
+		t.Fatalf("QueryConnectionInfo: %v", err)
+	}
+	conn, err := ci.DialGrpc(ctx)
+	if err != nil {
+		t.Fatalf("DialGrpc: %v", err)
+	}
+	defer conn.Close()
+
+	resp, err := pb.NewBlobstoreServiceClient(conn).GetConfig(ctx, &pb.GetBlobstoreConfigRequest{})
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	st := resp.BackendBreaker
+	if st == nil {
+		t.Fatalf("GetConfig didn't return the backend breaker status.")
+	}
+	if st.State != resilientblobstore.BreakerOpen.String() || st.ConsecutiveFailures != 1 || st.OpenedTime == 0 {
+		t.Errorf("Unexpected breaker status: %+v", st)
+	}
+}
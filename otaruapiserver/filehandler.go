@@ -14,10 +14,14 @@ import (
 
 	"github.com/nyaxt/otaru/apiserver"
 	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/apiserver/ratelimit"
+	"github.com/nyaxt/otaru/auditlog"
 	"github.com/nyaxt/otaru/blobstore"
 	"github.com/nyaxt/otaru/filesystem"
 	"github.com/nyaxt/otaru/flags"
 	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/util"
+	"github.com/nyaxt/otaru/util/bwlimit"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +33,9 @@ type content struct {
 	h      *filesystem.FileHandle
 	offset int64
 	size   int64
+
+	ctx  context.Context
+	wait bwlimit.WaitFunc
 }
 
 var _ = io.ReadSeeker(&content{})
@@ -37,6 +44,11 @@ func (c *content) Read(p []byte) (int, error) {
 	// FIXME: not sure if this handles eof correctly
 	n, err := c.h.ReadAt(p, c.offset)
 	c.offset += int64(n)
+	if n > 0 {
+		if werr := c.wait(c.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
 	return n, err
 }
 
@@ -61,9 +73,29 @@ func (c *content) Seek(offset int64, whence int) (int64, error) {
 	return c.offset, nil
 }
 
-func (fh *fileHandler) serveGet(w http.ResponseWriter, r *http.Request, ui clientauth.UserInfo, id inodedb.ID, filename string) {
-	if ui.Role < clientauth.RoleReadOnly {
+// checkPermission checks the permission of the user on the node by its current path, and writes an error response on failure.
+// It returns the current path of the node on success.
+func (fh *fileHandler) checkPermission(w http.ResponseWriter, r *http.Request, ui clientauth.UserInfo, id inodedb.ID, perm clientauth.Permission) (string, bool) {
+	p, err := fh.fs.CurrentPath(id)
+	if err != nil {
+		if util.IsNotExist(err) {
+			http.Error(w, "", http.StatusNotFound)
+			return "", false
+		}
+		zap.S().Debugf("checkPermission(id: %v). CurrentPath failed: %v", id, err)
+		http.Error(w, "Failed to find path", http.StatusInternalServerError)
+		return "", false
+	}
+	if !clientauth.CheckPathPermission(r.Context(), ui, p, perm) {
 		http.Error(w, "", http.StatusForbidden)
+		return "", false
+	}
+	return p, true
+}
+
+func (fh *fileHandler) serveGet(w http.ResponseWriter, r *http.Request, ui clientauth.UserInfo, id inodedb.ID, filename string) {
+	p, ok := fh.checkPermission(w, r, ui, id, clientauth.PermRead)
+	if !ok {
 		return
 	}
 
@@ -83,7 +115,7 @@ func (fh *fileHandler) serveGet(w http.ResponseWriter, r *http.Request, ui clien
 	}
 
 	if filename == "" {
-		filename = filepath.Base(a.OrigPath)
+		filename = filepath.Base(p)
 		if filename == "" {
 			filename = fmt.Sprintf("%d.bin", id)
 		}
@@ -97,17 +129,18 @@ func (fh *fileHandler) serveGet(w http.ResponseWriter, r *http.Request, ui clien
 	}
 	w.Header().Set("Content-Type", ctype)
 
-	c := &content{h: h, offset: 0, size: a.Size}
+	rl := ratelimit.LimiterFromContext(r.Context())
+	c := &content{h: h, offset: 0, size: a.Size, ctx: r.Context(), wait: rl.ReadWaitFunc(ui)}
 	http.ServeContent(w, r, filename, a.ModifiedT, c)
 }
 
 func (fh *fileHandler) servePut(w http.ResponseWriter, r *http.Request, ui clientauth.UserInfo, id inodedb.ID, filename string) {
-	if ui.Role < clientauth.RoleAdmin {
-		http.Error(w, "", http.StatusForbidden)
+	if _, ok := fh.checkPermission(w, r, ui, id, clientauth.PermWrite); !ok {
 		return
 	}
 
-	h, err := fh.fs.OpenFile(id, flags.O_WRONLY)
+	afs := fh.fs.Audited(auditlog.Actor{Source: "http", User: ui.User})
+	h, err := afs.OpenFile(id, flags.O_WRONLY)
 	if err != nil {
 		zap.S().Debugf("servePut(id: %v). OpenFile failed: %v", id, err)
 		http.Error(w, "Failed to open file", http.StatusInternalServerError)
@@ -116,7 +149,9 @@ func (fh *fileHandler) servePut(w http.ResponseWriter, r *http.Request, ui clien
 
 	// FIXME: parse offset
 	offset := int64(0)
-	nw, err := io.Copy(&blobstore.OffsetWriter{h, offset}, r.Body)
+	rl := ratelimit.LimiterFromContext(r.Context())
+	body := bwlimit.NewReader(r.Context(), r.Body, rl.WriteWaitFunc(ui))
+	nw, err := io.Copy(&blobstore.OffsetWriter{h, offset}, body)
 	if err != nil {
 		h.Close()
 
@@ -132,15 +167,29 @@ func (fh *fileHandler) servePut(w http.ResponseWriter, r *http.Request, ui clien
 	w.Write([]byte("\"ok\""))
 }
 
+// writeRateLimitError responds 429 with the Retry-After header for the error from ratelimit.Limiter.Begin.
+func writeRateLimitError(w http.ResponseWriter, err error) {
+	if e, ok := err.(*ratelimit.LimitExceededError); ok {
+		w.Header().Set("Retry-After", strconv.FormatInt(e.RetryAfterSeconds(), 10))
+	}
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+}
+
 func (fh *fileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	tlsui, err := clientauth.UserInfoFromTLSConnectionState(r.TLS)
+	ui, err := clientauth.UserInfoFromHTTPRequest(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
 	}
-	ui := tlsui
 	zap.S().Debugf("ui: %+v", ui)
 
+	release, err := ratelimit.LimiterFromContext(r.Context()).Begin(ui)
+	if err != nil {
+		writeRateLimitError(w, err)
+		return
+	}
+	defer release()
+
 	// path: /inodeid/filename
 	args := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
 
@@ -14,6 +14,7 @@ import (
 
 	"github.com/nyaxt/otaru/apiserver"
 	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/auditlog"
 	"github.com/nyaxt/otaru/filesystem"
 	"github.com/nyaxt/otaru/flags"
 	"github.com/nyaxt/otaru/inodedb"
@@ -50,16 +51,45 @@ func attrToINodeView(id inodedb.ID, name string, a filesystem.Attr) *pb.INodeVie
 		Gid:          a.Gid,
 		PermMode:     uint32(a.PermMode),
 		ModifiedTime: a.ModifiedT.Unix(),
+		ChangeTime:   a.ChangeT.Unix(),
+		CreatedTime:  a.CreatedT.Unix(),
+		AccessedTime: a.AccessedT.Unix(),
 	}
 }
 
-func (svc *fileSystemService) ListDir(ctx context.Context, req *pb.ListDirRequest) (*pb.ListDirResponse, error) {
-	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleReadOnly); err != nil {
-		return nil, err
+// audited returns the view of the filesystem recording the mutations by the caller.
+func (svc *fileSystemService) audited(ctx context.Context) *filesystem.AuditedFileSystem {
+	return svc.fs.Audited(auditlog.Actor{Source: "grpc", User: clientauth.UserInfoFromContext(ctx).User})
+}
+
+// nodePath returns the current path of the node, which the permissions on the node are checked against.
+// Nodes not reachable from the root dir are reported as not found.
+func (svc *fileSystemService) nodePath(id inodedb.ID) (string, error) {
+	p, err := svc.fs.CurrentPath(id)
+	if err != nil {
+		if util.IsNotExist(err) {
+			return "", grpc.Errorf(codes.NotFound, "Specified node not found.")
+		}
+		return "", grpc.Errorf(codes.Internal, fmt.Sprintf("CurrentPath(%d) failed: %v", id, err))
+	}
+	return p, nil
+}
+
+// requireNodePermission checks the permission of the caller on the node, by its current path.
+func (svc *fileSystemService) requireNodePermission(ctx context.Context, id inodedb.ID, perm clientauth.Permission) error {
+	p, err := svc.nodePath(id)
+	if err != nil {
+		return err
 	}
+	return clientauth.RequirePathPermissionGRPC(ctx, p, perm)
+}
 
+func (svc *fileSystemService) ListDir(ctx context.Context, req *pb.ListDirRequest) (*pb.ListDirResponse, error) {
 	ids := req.Id
 	if len(ids) == 0 {
+		if err := clientauth.RequirePathPermissionGRPC(ctx, req.Path, clientauth.PermRead); err != nil {
+			return nil, err
+		}
 		id, err := svc.fs.FindNodeFullPath(req.Path)
 		if err != nil {
 			if util.IsNotExist(err) {
@@ -73,6 +103,9 @@ func (svc *fileSystemService) ListDir(ctx context.Context, req *pb.ListDirReques
 	ls := make([]*pb.ListDirResponse_Listing, 0, len(ids))
 	for _, nid := range ids {
 		id := inodedb.ID(nid)
+		if err := svc.requireNodePermission(ctx, id, clientauth.PermRead); err != nil {
+			return nil, err
+		}
 		isDir, err := svc.fs.IsDir(id)
 		if err != nil {
 			return nil, grpc.Errorf(codes.Internal, fmt.Sprintf("IsDir(%d) failed: %v", id, err))
@@ -103,7 +136,7 @@ func (svc *fileSystemService) ListDir(ctx context.Context, req *pb.ListDirReques
 }
 
 func (svc *fileSystemService) FindNodeFullPath(ctx context.Context, req *pb.FindNodeFullPathRequest) (*pb.FindNodeFullPathResponse, error) {
-	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleReadOnly); err != nil {
+	if err := clientauth.RequirePathPermissionGRPC(ctx, req.Path, clientauth.PermRead); err != nil {
 		return nil, err
 	}
 
@@ -118,12 +151,11 @@ func (svc *fileSystemService) FindNodeFullPath(ctx context.Context, req *pb.Find
 }
 
 func (svc *fileSystemService) Attr(ctx context.Context, req *pb.AttrRequest) (*pb.AttrResponse, error) {
-	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleReadOnly); err != nil {
-		return nil, err
-	}
-
 	id := inodedb.ID(req.Id)
 	if id == 0 {
+		if err := clientauth.RequirePathPermissionGRPC(ctx, req.Path, clientauth.PermRead); err != nil {
+			return nil, err
+		}
 		var err error
 		id, err = svc.fs.FindNodeFullPath(req.Path)
 		if err != nil {
@@ -141,16 +173,21 @@ func (svc *fileSystemService) Attr(ctx context.Context, req *pb.AttrRequest) (*p
 		}
 		return nil, grpc.Errorf(codes.Internal, fmt.Sprintf("Attr(%d) failed: %v", id, err))
 	}
+	p := req.Path
+	if req.Id != 0 {
+		if p, err = svc.nodePath(id); err != nil {
+			return nil, err
+		}
+		if err := clientauth.RequirePathPermissionGRPC(ctx, p, clientauth.PermRead); err != nil {
+			return nil, err
+		}
+	}
 
-	inv := attrToINodeView(id, path.Base(attr.OrigPath), attr)
+	inv := attrToINodeView(id, path.Base(p), attr)
 	return &pb.AttrResponse{Entry: inv}, nil
 }
 
 func (svc *fileSystemService) Create(ctx context.Context, req *pb.CreateRequest) (*pb.CreateResponse, error) {
-	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
-		return nil, err
-	}
-
 	dirId := inodedb.ID(req.DirId)
 	permMode := uint16(req.PermMode & 0777)
 	var modifiedT time.Time
@@ -162,12 +199,15 @@ func (svc *fileSystemService) Create(ctx context.Context, req *pb.CreateRequest)
 	if dirId == 0 {
 		// Fullpath mode.
 		fullpath := req.Name
+		if err := clientauth.RequirePathPermissionGRPC(ctx, fullpath, clientauth.PermWrite); err != nil {
+			return nil, err
+		}
 		var id inodedb.ID
 		var err error
 		if req.Type == pb.INodeType_FILE {
-			id, err = svc.fs.CreateFileFullPath(fullpath, permMode, req.Uid, req.Gid, modifiedT)
+			id, err = svc.audited(ctx).CreateFileFullPath(fullpath, permMode, req.Uid, req.Gid, modifiedT)
 		} else if req.Type == pb.INodeType_DIR {
-			id, err = svc.fs.CreateDirFullPath(fullpath, permMode, req.Uid, req.Gid, modifiedT)
+			id, err = svc.audited(ctx).CreateDirFullPath(fullpath, permMode, req.Uid, req.Gid, modifiedT)
 		} else {
 			return nil, grpc.Errorf(codes.InvalidArgument, "invalid Type %d given.", req.Type)
 		}
@@ -184,12 +224,19 @@ func (svc *fileSystemService) Create(ctx context.Context, req *pb.CreateRequest)
 		return &pb.CreateResponse{Id: uint64(id), IsNew: true}, nil
 	}
 
+	dirPath, err := svc.nodePath(dirId)
+	if err != nil {
+		return nil, err
+	}
+	if err := clientauth.RequirePathPermissionGRPC(ctx, path.Join(dirPath, req.Name), clientauth.PermWrite); err != nil {
+		return nil, err
+	}
+
 	var id inodedb.ID
-	var err error
 	if req.Type == pb.INodeType_FILE {
-		id, err = svc.fs.CreateFile(dirId, req.Name, permMode, req.Uid, req.Gid, modifiedT)
+		id, err = svc.audited(ctx).CreateFile(dirId, req.Name, permMode, req.Uid, req.Gid, modifiedT)
 	} else if req.Type == pb.INodeType_DIR {
-		id, err = svc.fs.CreateDir(dirId, req.Name, permMode, req.Uid, req.Gid, modifiedT)
+		id, err = svc.audited(ctx).CreateDir(dirId, req.Name, permMode, req.Uid, req.Gid, modifiedT)
 	} else {
 		return nil, grpc.Errorf(codes.InvalidArgument, "invalid Type %d given.", req.Type)
 	}
@@ -212,13 +259,12 @@ func (svc *fileSystemService) Create(ctx context.Context, req *pb.CreateRequest)
 }
 
 func (svc *fileSystemService) Remove(ctx context.Context, req *pb.RemoveRequest) (*pb.RemoveResponse, error) {
-	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
-		return nil, err
-	}
-
 	dirId := inodedb.ID(req.DirId)
 	name := req.Name
 	if dirId == 0 {
+		if err := clientauth.RequirePathPermissionGRPC(ctx, req.Name, clientauth.PermWrite); err != nil {
+			return nil, err
+		}
 		// lookup dir id
 		parent := filepath.Dir(req.Name)
 		var err error
@@ -227,9 +273,17 @@ func (svc *fileSystemService) Remove(ctx context.Context, req *pb.RemoveRequest)
 			return nil, grpc.Errorf(codes.InvalidArgument, "Failed to find parent %q: %v", parent, err)
 		}
 		name = filepath.Base(req.Name)
+	} else {
+		dirPath, err := svc.nodePath(dirId)
+		if err != nil {
+			return nil, err
+		}
+		if err := clientauth.RequirePathPermissionGRPC(ctx, path.Join(dirPath, name), clientauth.PermWrite); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := svc.fs.Remove(dirId, name); err != nil {
+	if err := svc.audited(ctx).Remove(dirId, name); err != nil {
 		if util.IsNotExist(err) {
 			return nil, grpc.Errorf(codes.NotFound, "Target does not exist")
 		}
@@ -240,12 +294,11 @@ func (svc *fileSystemService) Remove(ctx context.Context, req *pb.RemoveRequest)
 }
 
 func (svc *fileSystemService) ReadFile(ctx context.Context, req *pb.ReadFileRequest) (*pb.ReadFileResponse, error) {
-	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleReadOnly); err != nil {
+	id := inodedb.ID(req.Id)
+	if err := svc.requireNodePermission(ctx, id, clientauth.PermRead); err != nil {
 		return nil, err
 	}
 
-	id := inodedb.ID(req.Id)
-
 	h, err := svc.fs.OpenFile(id, flags.O_RDONLY)
 	if err != nil {
 		return nil, grpc.Errorf(codes.Internal, fmt.Sprintf("OpenFile failed: %v", err))
@@ -265,13 +318,12 @@ func (svc *fileSystemService) ReadFile(ctx context.Context, req *pb.ReadFileRequ
 }
 
 func (svc *fileSystemService) WriteFile(ctx context.Context, req *pb.WriteFileRequest) (*pb.WriteFileResponse, error) {
-	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+	id := inodedb.ID(req.Id)
+	if err := svc.requireNodePermission(ctx, id, clientauth.PermWrite); err != nil {
 		return nil, err
 	}
 
-	id := inodedb.ID(req.Id)
-
-	h, err := svc.fs.OpenFile(id, flags.O_RDWR)
+	h, err := svc.audited(ctx).OpenFile(id, flags.O_RDWR)
 	if err != nil {
 		return nil, grpc.Errorf(codes.Internal, fmt.Sprintf("OpenFile failed: %v", err))
 	}
@@ -286,7 +338,10 @@ func (svc *fileSystemService) WriteFile(ctx context.Context, req *pb.WriteFileRe
 }
 
 func (svc *fileSystemService) Rename(ctx context.Context, req *pb.RenameRequest) (*pb.RenameResponse, error) {
-	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+	if err := clientauth.RequirePathPermissionGRPC(ctx, req.PathSrc, clientauth.PermWrite); err != nil {
+		return nil, err
+	}
+	if err := clientauth.RequirePathPermissionGRPC(ctx, req.PathDest, clientauth.PermWrite); err != nil {
 		return nil, err
 	}
 
@@ -305,7 +360,7 @@ func (svc *fileSystemService) Rename(ctx context.Context, req *pb.RenameRequest)
 	srcName := filepath.Base(req.PathSrc)
 	dstName := filepath.Base(req.PathDest)
 
-	if err := svc.fs.Rename(srcDirId, srcName, destDirId, dstName); err != nil {
+	if err := svc.audited(ctx).Rename(srcDirId, srcName, destDirId, dstName); err != nil {
 		if util.IsNotExist(err) {
 			return nil, grpc.Errorf(codes.NotFound, "Target does not exist")
 		}
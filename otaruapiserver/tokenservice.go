@@ -0,0 +1,96 @@
+package otaruapiserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/nyaxt/otaru/apiserver"
+	"github.com/nyaxt/otaru/apiserver/clientauth"
+	"github.com/nyaxt/otaru/pb"
+)
+
+type tokenService struct {
+	tokens *clientauth.TokenStore
+	pb.UnimplementedTokenServiceServer
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func tokenToView(t clientauth.Token) *pb.TokenView {
+	return &pb.TokenView{
+		Id:          t.ID,
+		User:        t.User,
+		Role:        t.Role.String(),
+		PathPrefix:  t.PathPrefix,
+		Description: t.Description,
+		CreatedTime: unixOrZero(t.CreatedT),
+		ExpiresTime: unixOrZero(t.ExpiresT),
+	}
+}
+
+func (svc *tokenService) CreateToken(ctx context.Context, req *pb.CreateTokenRequest) (*pb.CreateTokenResponse, error) {
+	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if !clientauth.IsValidRoleStr(req.Role) {
+		return nil, grpc.Errorf(codes.InvalidArgument, "Unknown role %q.", req.Role)
+	}
+	var expiresT time.Time
+	if req.ExpiresTime > 0 {
+		expiresT = time.Unix(req.ExpiresTime, 0)
+		if !expiresT.After(time.Now()) {
+			return nil, grpc.Errorf(codes.InvalidArgument, "Expiry %v is in the past.", expiresT)
+		}
+	}
+
+	t, secret, err := svc.tokens.Create(req.User, clientauth.RoleFromStr(req.Role), req.PathPrefix, expiresT, req.Description)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "Failed to create token: %v", err)
+	}
+	return &pb.CreateTokenResponse{Token: tokenToView(t), Secret: secret}, nil
+}
+
+func (svc *tokenService) ListTokens(ctx context.Context, req *pb.ListTokensRequest) (*pb.ListTokensResponse, error) {
+	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	ts := svc.tokens.List()
+	vs := make([]*pb.TokenView, 0, len(ts))
+	for _, t := range ts {
+		vs = append(vs, tokenToView(t))
+	}
+	return &pb.ListTokensResponse{Token: vs}, nil
+}
+
+func (svc *tokenService) RevokeToken(ctx context.Context, req *pb.RevokeTokenRequest) (*pb.RevokeTokenResponse, error) {
+	if err := clientauth.RequireRoleGRPC(ctx, clientauth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if err := svc.tokens.Revoke(req.Id); err != nil {
+		if err == clientauth.ErrUnknownToken {
+			return nil, grpc.Errorf(codes.NotFound, "Token %q not found.", req.Id)
+		}
+		return nil, grpc.Errorf(codes.Internal, "Failed to revoke token: %v", err)
+	}
+	return &pb.RevokeTokenResponse{}, nil
+}
+
+func InstallTokenService(tokens *clientauth.TokenStore) apiserver.Option {
+	svc := &tokenService{tokens: tokens}
+
+	return apiserver.RegisterService(
+		func(s *grpc.Server) { pb.RegisterTokenServiceServer(s, svc) },
+		pb.RegisterTokenServiceHandlerFromEndpoint,
+	)
+}
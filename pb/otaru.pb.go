@@ -7,7 +7,6 @@
 package pb
 
 import (
-	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -137,6 +136,9 @@ type INodeView struct {
 	Gid          uint32    `protobuf:"varint,6,opt,name=gid,proto3" json:"gid,omitempty"`
 	PermMode     uint32    `protobuf:"varint,7,opt,name=perm_mode,json=permMode,proto3" json:"perm_mode,omitempty"`
 	ModifiedTime int64     `protobuf:"varint,8,opt,name=modified_time,json=modifiedTime,proto3" json:"modified_time,omitempty"`
+	ChangeTime   int64     `protobuf:"varint,9,opt,name=change_time,json=changeTime,proto3" json:"change_time,omitempty"`
+	CreatedTime  int64     `protobuf:"varint,10,opt,name=created_time,json=createdTime,proto3" json:"created_time,omitempty"`
+	AccessedTime int64     `protobuf:"varint,11,opt,name=accessed_time,json=accessedTime,proto3" json:"accessed_time,omitempty"`
 }
 
 func (x *INodeView) Reset() {
@@ -227,6 +229,27 @@ func (x *INodeView) GetModifiedTime() int64 {
 	return 0
 }
 
+func (x *INodeView) GetChangeTime() int64 {
+	if x != nil {
+		return x.ChangeTime
+	}
+	return 0
+}
+
+func (x *INodeView) GetCreatedTime() int64 {
+	if x != nil {
+		return x.CreatedTime
+	}
+	return 0
+}
+
+func (x *INodeView) GetAccessedTime() int64 {
+	if x != nil {
+		return x.AccessedTime
+	}
+	return 0
+}
+
 type ListDirResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1058,6 +1081,69 @@ func (*GetBlobstoreConfigRequest) Descriptor() ([]byte, []int) {
 	return file_otaru_proto_rawDescGZIP(), []int{17}
 }
 
+type CircuitBreakerStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State               string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	ConsecutiveFailures int32  `protobuf:"varint,2,opt,name=consecutive_failures,json=consecutiveFailures,proto3" json:"consecutive_failures,omitempty"`
+	OpenedTime          int64  `protobuf:"varint,3,opt,name=opened_time,json=openedTime,proto3" json:"opened_time,omitempty"`
+}
+
+func (x *CircuitBreakerStatus) Reset() {
+	*x = CircuitBreakerStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CircuitBreakerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CircuitBreakerStatus) ProtoMessage() {}
+
+func (x *CircuitBreakerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CircuitBreakerStatus.ProtoReflect.Descriptor instead.
+func (*CircuitBreakerStatus) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CircuitBreakerStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CircuitBreakerStatus) GetConsecutiveFailures() int32 {
+	if x != nil {
+		return x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *CircuitBreakerStatus) GetOpenedTime() int64 {
+	if x != nil {
+		return x.OpenedTime
+	}
+	return 0
+}
+
 type GetBlobstoreConfigResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1067,12 +1153,14 @@ type GetBlobstoreConfigResponse struct {
 	BackendFlags    string `protobuf:"bytes,2,opt,name=backend_flags,json=backendFlags,proto3" json:"backend_flags,omitempty"`
 	CacheImplName   string `protobuf:"bytes,3,opt,name=cache_impl_name,json=cacheImplName,proto3" json:"cache_impl_name,omitempty"`
 	CacheFlags      string `protobuf:"bytes,4,opt,name=cache_flags,json=cacheFlags,proto3" json:"cache_flags,omitempty"`
+	// Set if the backend blobstore is guarded by a circuit breaker.
+	BackendBreaker *CircuitBreakerStatus `protobuf:"bytes,5,opt,name=backend_breaker,json=backendBreaker,proto3" json:"backend_breaker,omitempty"`
 }
 
 func (x *GetBlobstoreConfigResponse) Reset() {
 	*x = GetBlobstoreConfigResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[18]
+		mi := &file_otaru_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1085,7 +1173,7 @@ func (x *GetBlobstoreConfigResponse) String() string {
 func (*GetBlobstoreConfigResponse) ProtoMessage() {}
 
 func (x *GetBlobstoreConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[18]
+	mi := &file_otaru_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1098,7 +1186,7 @@ func (x *GetBlobstoreConfigResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBlobstoreConfigResponse.ProtoReflect.Descriptor instead.
 func (*GetBlobstoreConfigResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{18}
+	return file_otaru_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *GetBlobstoreConfigResponse) GetBackendImplName() string {
@@ -1129,6 +1217,13 @@ func (x *GetBlobstoreConfigResponse) GetCacheFlags() string {
 	return ""
 }
 
+func (x *GetBlobstoreConfigResponse) GetBackendBreaker() *CircuitBreakerStatus {
+	if x != nil {
+		return x.BackendBreaker
+	}
+	return nil
+}
+
 type ReduceCacheRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1141,7 +1236,7 @@ type ReduceCacheRequest struct {
 func (x *ReduceCacheRequest) Reset() {
 	*x = ReduceCacheRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[19]
+		mi := &file_otaru_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1154,7 +1249,7 @@ func (x *ReduceCacheRequest) String() string {
 func (*ReduceCacheRequest) ProtoMessage() {}
 
 func (x *ReduceCacheRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[19]
+	mi := &file_otaru_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1167,7 +1262,7 @@ func (x *ReduceCacheRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReduceCacheRequest.ProtoReflect.Descriptor instead.
 func (*ReduceCacheRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{19}
+	return file_otaru_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *ReduceCacheRequest) GetDryRun() bool {
@@ -1196,7 +1291,7 @@ type ReduceCacheResponse struct {
 func (x *ReduceCacheResponse) Reset() {
 	*x = ReduceCacheResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[20]
+		mi := &file_otaru_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1209,7 +1304,7 @@ func (x *ReduceCacheResponse) String() string {
 func (*ReduceCacheResponse) ProtoMessage() {}
 
 func (x *ReduceCacheResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[20]
+	mi := &file_otaru_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1222,7 +1317,7 @@ func (x *ReduceCacheResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReduceCacheResponse.ProtoReflect.Descriptor instead.
 func (*ReduceCacheResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{20}
+	return file_otaru_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *ReduceCacheResponse) GetSuccess() bool {
@@ -1248,7 +1343,7 @@ type GetEntriesRequest struct {
 func (x *GetEntriesRequest) Reset() {
 	*x = GetEntriesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[21]
+		mi := &file_otaru_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1261,7 +1356,7 @@ func (x *GetEntriesRequest) String() string {
 func (*GetEntriesRequest) ProtoMessage() {}
 
 func (x *GetEntriesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[21]
+	mi := &file_otaru_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1274,7 +1369,7 @@ func (x *GetEntriesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEntriesRequest.ProtoReflect.Descriptor instead.
 func (*GetEntriesRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{21}
+	return file_otaru_proto_rawDescGZIP(), []int{22}
 }
 
 type GetEntriesResponse struct {
@@ -1288,7 +1383,7 @@ type GetEntriesResponse struct {
 func (x *GetEntriesResponse) Reset() {
 	*x = GetEntriesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[22]
+		mi := &file_otaru_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1301,7 +1396,7 @@ func (x *GetEntriesResponse) String() string {
 func (*GetEntriesResponse) ProtoMessage() {}
 
 func (x *GetEntriesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[22]
+	mi := &file_otaru_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1314,7 +1409,7 @@ func (x *GetEntriesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEntriesResponse.ProtoReflect.Descriptor instead.
 func (*GetEntriesResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{22}
+	return file_otaru_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *GetEntriesResponse) GetEntry() []*GetEntriesResponse_Entry {
@@ -1324,29 +1419,35 @@ func (x *GetEntriesResponse) GetEntry() []*GetEntriesResponse_Entry {
 	return nil
 }
 
-type GetINodeDBStatsRequest struct {
+type BandwidthLimit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// 0 means unlimited.
+	UploadBytesPerSec   int64 `protobuf:"varint,1,opt,name=upload_bytes_per_sec,json=uploadBytesPerSec,proto3" json:"upload_bytes_per_sec,omitempty"`
+	DownloadBytesPerSec int64 `protobuf:"varint,2,opt,name=download_bytes_per_sec,json=downloadBytesPerSec,proto3" json:"download_bytes_per_sec,omitempty"`
+	// "HH:MM-HH:MM" daily window where limits are lifted. Empty if none.
+	FullBandwidthWindow string `protobuf:"bytes,3,opt,name=full_bandwidth_window,json=fullBandwidthWindow,proto3" json:"full_bandwidth_window,omitempty"`
 }
 
-func (x *GetINodeDBStatsRequest) Reset() {
-	*x = GetINodeDBStatsRequest{}
+func (x *BandwidthLimit) Reset() {
+	*x = BandwidthLimit{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[23]
+		mi := &file_otaru_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetINodeDBStatsRequest) String() string {
+func (x *BandwidthLimit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetINodeDBStatsRequest) ProtoMessage() {}
+func (*BandwidthLimit) ProtoMessage() {}
 
-func (x *GetINodeDBStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[23]
+func (x *BandwidthLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1357,41 +1458,55 @@ func (x *GetINodeDBStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetINodeDBStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetINodeDBStatsRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use BandwidthLimit.ProtoReflect.Descriptor instead.
+func (*BandwidthLimit) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{24}
 }
 
-type GetINodeDBStatsResponse struct {
+func (x *BandwidthLimit) GetUploadBytesPerSec() int64 {
+	if x != nil {
+		return x.UploadBytesPerSec
+	}
+	return 0
+}
+
+func (x *BandwidthLimit) GetDownloadBytesPerSec() int64 {
+	if x != nil {
+		return x.DownloadBytesPerSec
+	}
+	return 0
+}
+
+func (x *BandwidthLimit) GetFullBandwidthWindow() string {
+	if x != nil {
+		return x.FullBandwidthWindow
+	}
+	return ""
+}
+
+type GetBandwidthLimitRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	LastSync          int64  `protobuf:"varint,1,opt,name=last_sync,json=lastSync,proto3" json:"last_sync,omitempty"`
-	LastTx            int64  `protobuf:"varint,2,opt,name=last_tx,json=lastTx,proto3" json:"last_tx,omitempty"`
-	LastId            uint64 `protobuf:"varint,3,opt,name=last_id,json=lastId,proto3" json:"last_id,omitempty"`
-	Version           uint64 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
-	LastTicket        uint64 `protobuf:"varint,5,opt,name=last_ticket,json=lastTicket,proto3" json:"last_ticket,omitempty"`
-	NumberOfNodeLocks uint32 `protobuf:"varint,6,opt,name=number_of_node_locks,json=numberOfNodeLocks,proto3" json:"number_of_node_locks,omitempty"`
 }
 
-func (x *GetINodeDBStatsResponse) Reset() {
-	*x = GetINodeDBStatsResponse{}
+func (x *GetBandwidthLimitRequest) Reset() {
+	*x = GetBandwidthLimitRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[24]
+		mi := &file_otaru_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetINodeDBStatsResponse) String() string {
+func (x *GetBandwidthLimitRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetINodeDBStatsResponse) ProtoMessage() {}
+func (*GetBandwidthLimitRequest) ProtoMessage() {}
 
-func (x *GetINodeDBStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[24]
+func (x *GetBandwidthLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1402,76 +1517,91 @@ func (x *GetINodeDBStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetINodeDBStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetINodeDBStatsResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use GetBandwidthLimitRequest.ProtoReflect.Descriptor instead.
+func (*GetBandwidthLimitRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *GetINodeDBStatsResponse) GetLastSync() int64 {
-	if x != nil {
-		return x.LastSync
-	}
-	return 0
+type GetBandwidthLimitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit           *BandwidthLimit `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	IsFullBandwidth bool            `protobuf:"varint,2,opt,name=is_full_bandwidth,json=isFullBandwidth,proto3" json:"is_full_bandwidth,omitempty"`
 }
 
-func (x *GetINodeDBStatsResponse) GetLastTx() int64 {
-	if x != nil {
-		return x.LastTx
+func (x *GetBandwidthLimitResponse) Reset() {
+	*x = GetBandwidthLimitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *GetINodeDBStatsResponse) GetLastId() uint64 {
-	if x != nil {
-		return x.LastId
-	}
-	return 0
+func (x *GetBandwidthLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *GetINodeDBStatsResponse) GetVersion() uint64 {
-	if x != nil {
-		return x.Version
+func (*GetBandwidthLimitResponse) ProtoMessage() {}
+
+func (x *GetBandwidthLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *GetINodeDBStatsResponse) GetLastTicket() uint64 {
+// Deprecated: Use GetBandwidthLimitResponse.ProtoReflect.Descriptor instead.
+func (*GetBandwidthLimitResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetBandwidthLimitResponse) GetLimit() *BandwidthLimit {
 	if x != nil {
-		return x.LastTicket
+		return x.Limit
 	}
-	return 0
+	return nil
 }
 
-func (x *GetINodeDBStatsResponse) GetNumberOfNodeLocks() uint32 {
+func (x *GetBandwidthLimitResponse) GetIsFullBandwidth() bool {
 	if x != nil {
-		return x.NumberOfNodeLocks
+		return x.IsFullBandwidth
 	}
-	return 0
+	return false
 }
 
-type GetCategoriesRequest struct {
+type SetBandwidthLimitRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Limit *BandwidthLimit `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func (x *GetCategoriesRequest) Reset() {
-	*x = GetCategoriesRequest{}
+func (x *SetBandwidthLimitRequest) Reset() {
+	*x = SetBandwidthLimitRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[25]
+		mi := &file_otaru_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetCategoriesRequest) String() string {
+func (x *SetBandwidthLimitRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCategoriesRequest) ProtoMessage() {}
+func (*SetBandwidthLimitRequest) ProtoMessage() {}
 
-func (x *GetCategoriesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[25]
+func (x *SetBandwidthLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1482,34 +1612,41 @@ func (x *GetCategoriesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCategoriesRequest.ProtoReflect.Descriptor instead.
-func (*GetCategoriesRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use SetBandwidthLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetBandwidthLimitRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{27}
 }
 
-type GetSystemInfoRequest struct {
+func (x *SetBandwidthLimitRequest) GetLimit() *BandwidthLimit {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+type SetBandwidthLimitResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *GetSystemInfoRequest) Reset() {
-	*x = GetSystemInfoRequest{}
+func (x *SetBandwidthLimitResponse) Reset() {
+	*x = SetBandwidthLimitResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[26]
+		mi := &file_otaru_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetSystemInfoRequest) String() string {
+func (x *SetBandwidthLimitResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSystemInfoRequest) ProtoMessage() {}
+func (*SetBandwidthLimitResponse) ProtoMessage() {}
 
-func (x *GetSystemInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[26]
+func (x *SetBandwidthLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1520,46 +1657,36 @@ func (x *GetSystemInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSystemInfoRequest.ProtoReflect.Descriptor instead.
-func (*GetSystemInfoRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use SetBandwidthLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetBandwidthLimitResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{28}
 }
 
-type SystemInfoResponse struct {
+type VerifyCacheRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GoVersion    string `protobuf:"bytes,1,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
-	Os           string `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`
-	Arch         string `protobuf:"bytes,3,opt,name=arch,proto3" json:"arch,omitempty"`
-	NumGoroutine uint32 `protobuf:"varint,4,opt,name=num_goroutine,json=numGoroutine,proto3" json:"num_goroutine,omitempty"`
-	Hostname     string `protobuf:"bytes,5,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	Pid          uint64 `protobuf:"varint,6,opt,name=pid,proto3" json:"pid,omitempty"`
-	Uid          uint64 `protobuf:"varint,7,opt,name=uid,proto3" json:"uid,omitempty"`
-	MemAlloc     uint64 `protobuf:"varint,8,opt,name=mem_alloc,json=memAlloc,proto3" json:"mem_alloc,omitempty"`
-	MemSys       uint64 `protobuf:"varint,9,opt,name=mem_sys,json=memSys,proto3" json:"mem_sys,omitempty"`
-	NumGc        uint32 `protobuf:"varint,10,opt,name=num_gc,json=numGc,proto3" json:"num_gc,omitempty"`
-	NumFds       uint32 `protobuf:"varint,11,opt,name=num_fds,json=numFds,proto3" json:"num_fds,omitempty"`
+	DryRun bool `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 }
 
-func (x *SystemInfoResponse) Reset() {
-	*x = SystemInfoResponse{}
+func (x *VerifyCacheRequest) Reset() {
+	*x = VerifyCacheRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[27]
+		mi := &file_otaru_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SystemInfoResponse) String() string {
+func (x *VerifyCacheRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SystemInfoResponse) ProtoMessage() {}
+func (*VerifyCacheRequest) ProtoMessage() {}
 
-func (x *SystemInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[27]
+func (x *VerifyCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1570,111 +1697,1135 @@ func (x *SystemInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SystemInfoResponse.ProtoReflect.Descriptor instead.
-func (*SystemInfoResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use VerifyCacheRequest.ProtoReflect.Descriptor instead.
+func (*VerifyCacheRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *SystemInfoResponse) GetGoVersion() string {
+func (x *VerifyCacheRequest) GetDryRun() bool {
 	if x != nil {
-		return x.GoVersion
+		return x.DryRun
+	}
+	return false
+}
+
+type VerifyCacheResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NumVerified int64    `protobuf:"varint,1,opt,name=num_verified,json=numVerified,proto3" json:"num_verified,omitempty"`
+	NumStale    int64    `protobuf:"varint,2,opt,name=num_stale,json=numStale,proto3" json:"num_stale,omitempty"`
+	Quarantined []string `protobuf:"bytes,3,rep,name=quarantined,proto3" json:"quarantined,omitempty"`
+	DataLoss    []string `protobuf:"bytes,4,rep,name=data_loss,json=dataLoss,proto3" json:"data_loss,omitempty"`
+	Skipped     []string `protobuf:"bytes,5,rep,name=skipped,proto3" json:"skipped,omitempty"`
+}
+
+func (x *VerifyCacheResponse) Reset() {
+	*x = VerifyCacheResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyCacheResponse) ProtoMessage() {}
+
+func (x *VerifyCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyCacheResponse.ProtoReflect.Descriptor instead.
+func (*VerifyCacheResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *VerifyCacheResponse) GetNumVerified() int64 {
+	if x != nil {
+		return x.NumVerified
+	}
+	return 0
+}
+
+func (x *VerifyCacheResponse) GetNumStale() int64 {
+	if x != nil {
+		return x.NumStale
+	}
+	return 0
+}
+
+func (x *VerifyCacheResponse) GetQuarantined() []string {
+	if x != nil {
+		return x.Quarantined
+	}
+	return nil
+}
+
+func (x *VerifyCacheResponse) GetDataLoss() []string {
+	if x != nil {
+		return x.DataLoss
+	}
+	return nil
+}
+
+func (x *VerifyCacheResponse) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+type ScrubRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ScrubRequest) Reset() {
+	*x = ScrubRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScrubRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrubRequest) ProtoMessage() {}
+
+func (x *ScrubRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrubRequest.ProtoReflect.Descriptor instead.
+func (*ScrubRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{31}
+}
+
+type ScrubBlobRef struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Path   string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Offset int64  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length int64  `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+}
+
+func (x *ScrubBlobRef) Reset() {
+	*x = ScrubBlobRef{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScrubBlobRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrubBlobRef) ProtoMessage() {}
+
+func (x *ScrubBlobRef) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrubBlobRef.ProtoReflect.Descriptor instead.
+func (*ScrubBlobRef) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ScrubBlobRef) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScrubBlobRef) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ScrubBlobRef) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ScrubBlobRef) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type ScrubBadBlob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blobpath string          `protobuf:"bytes,1,opt,name=blobpath,proto3" json:"blobpath,omitempty"`
+	Error    string          `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Refs     []*ScrubBlobRef `protobuf:"bytes,3,rep,name=refs,proto3" json:"refs,omitempty"`
+}
+
+func (x *ScrubBadBlob) Reset() {
+	*x = ScrubBadBlob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScrubBadBlob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrubBadBlob) ProtoMessage() {}
+
+func (x *ScrubBadBlob) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrubBadBlob.ProtoReflect.Descriptor instead.
+func (*ScrubBadBlob) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ScrubBadBlob) GetBlobpath() string {
+	if x != nil {
+		return x.Blobpath
+	}
+	return ""
+}
+
+func (x *ScrubBadBlob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ScrubBadBlob) GetRefs() []*ScrubBlobRef {
+	if x != nil {
+		return x.Refs
+	}
+	return nil
+}
+
+type ScrubResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NumVerified int64           `protobuf:"varint,1,opt,name=num_verified,json=numVerified,proto3" json:"num_verified,omitempty"`
+	NumBytes    int64           `protobuf:"varint,2,opt,name=num_bytes,json=numBytes,proto3" json:"num_bytes,omitempty"`
+	BadBlobs    []*ScrubBadBlob `protobuf:"bytes,3,rep,name=bad_blobs,json=badBlobs,proto3" json:"bad_blobs,omitempty"`
+	NumSkipped  int64           `protobuf:"varint,4,opt,name=num_skipped,json=numSkipped,proto3" json:"num_skipped,omitempty"`
+}
+
+func (x *ScrubResponse) Reset() {
+	*x = ScrubResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScrubResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrubResponse) ProtoMessage() {}
+
+func (x *ScrubResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrubResponse.ProtoReflect.Descriptor instead.
+func (*ScrubResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ScrubResponse) GetNumVerified() int64 {
+	if x != nil {
+		return x.NumVerified
+	}
+	return 0
+}
+
+func (x *ScrubResponse) GetNumBytes() int64 {
+	if x != nil {
+		return x.NumBytes
+	}
+	return 0
+}
+
+func (x *ScrubResponse) GetBadBlobs() []*ScrubBadBlob {
+	if x != nil {
+		return x.BadBlobs
+	}
+	return nil
+}
+
+func (x *ScrubResponse) GetNumSkipped() int64 {
+	if x != nil {
+		return x.NumSkipped
+	}
+	return 0
+}
+
+type GetINodeDBStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetINodeDBStatsRequest) Reset() {
+	*x = GetINodeDBStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetINodeDBStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetINodeDBStatsRequest) ProtoMessage() {}
+
+func (x *GetINodeDBStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetINodeDBStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetINodeDBStatsRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{35}
+}
+
+type GetINodeDBStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LastSync          int64  `protobuf:"varint,1,opt,name=last_sync,json=lastSync,proto3" json:"last_sync,omitempty"`
+	LastTx            int64  `protobuf:"varint,2,opt,name=last_tx,json=lastTx,proto3" json:"last_tx,omitempty"`
+	LastId            uint64 `protobuf:"varint,3,opt,name=last_id,json=lastId,proto3" json:"last_id,omitempty"`
+	Version           uint64 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	LastTicket        uint64 `protobuf:"varint,5,opt,name=last_ticket,json=lastTicket,proto3" json:"last_ticket,omitempty"`
+	NumberOfNodeLocks uint32 `protobuf:"varint,6,opt,name=number_of_node_locks,json=numberOfNodeLocks,proto3" json:"number_of_node_locks,omitempty"`
+}
+
+func (x *GetINodeDBStatsResponse) Reset() {
+	*x = GetINodeDBStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetINodeDBStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetINodeDBStatsResponse) ProtoMessage() {}
+
+func (x *GetINodeDBStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetINodeDBStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetINodeDBStatsResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetINodeDBStatsResponse) GetLastSync() int64 {
+	if x != nil {
+		return x.LastSync
+	}
+	return 0
+}
+
+func (x *GetINodeDBStatsResponse) GetLastTx() int64 {
+	if x != nil {
+		return x.LastTx
+	}
+	return 0
+}
+
+func (x *GetINodeDBStatsResponse) GetLastId() uint64 {
+	if x != nil {
+		return x.LastId
+	}
+	return 0
+}
+
+func (x *GetINodeDBStatsResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GetINodeDBStatsResponse) GetLastTicket() uint64 {
+	if x != nil {
+		return x.LastTicket
+	}
+	return 0
+}
+
+func (x *GetINodeDBStatsResponse) GetNumberOfNodeLocks() uint32 {
+	if x != nil {
+		return x.NumberOfNodeLocks
+	}
+	return 0
+}
+
+type GetCategoriesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetCategoriesRequest) Reset() {
+	*x = GetCategoriesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoriesRequest) ProtoMessage() {}
+
+func (x *GetCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{37}
+}
+
+type GetSystemInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetSystemInfoRequest) Reset() {
+	*x = GetSystemInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSystemInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemInfoRequest) ProtoMessage() {}
+
+func (x *GetSystemInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetSystemInfoRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{38}
+}
+
+type SystemInfoResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GoVersion    string `protobuf:"bytes,1,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	Os           string `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`
+	Arch         string `protobuf:"bytes,3,opt,name=arch,proto3" json:"arch,omitempty"`
+	NumGoroutine uint32 `protobuf:"varint,4,opt,name=num_goroutine,json=numGoroutine,proto3" json:"num_goroutine,omitempty"`
+	Hostname     string `protobuf:"bytes,5,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Pid          uint64 `protobuf:"varint,6,opt,name=pid,proto3" json:"pid,omitempty"`
+	Uid          uint64 `protobuf:"varint,7,opt,name=uid,proto3" json:"uid,omitempty"`
+	MemAlloc     uint64 `protobuf:"varint,8,opt,name=mem_alloc,json=memAlloc,proto3" json:"mem_alloc,omitempty"`
+	MemSys       uint64 `protobuf:"varint,9,opt,name=mem_sys,json=memSys,proto3" json:"mem_sys,omitempty"`
+	NumGc        uint32 `protobuf:"varint,10,opt,name=num_gc,json=numGc,proto3" json:"num_gc,omitempty"`
+	NumFds       uint32 `protobuf:"varint,11,opt,name=num_fds,json=numFds,proto3" json:"num_fds,omitempty"`
+}
+
+func (x *SystemInfoResponse) Reset() {
+	*x = SystemInfoResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SystemInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemInfoResponse) ProtoMessage() {}
+
+func (x *SystemInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemInfoResponse.ProtoReflect.Descriptor instead.
+func (*SystemInfoResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SystemInfoResponse) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *SystemInfoResponse) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *SystemInfoResponse) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *SystemInfoResponse) GetNumGoroutine() uint32 {
+	if x != nil {
+		return x.NumGoroutine
+	}
+	return 0
+}
+
+func (x *SystemInfoResponse) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *SystemInfoResponse) GetPid() uint64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *SystemInfoResponse) GetUid() uint64 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *SystemInfoResponse) GetMemAlloc() uint64 {
+	if x != nil {
+		return x.MemAlloc
+	}
+	return 0
+}
+
+func (x *SystemInfoResponse) GetMemSys() uint64 {
+	if x != nil {
+		return x.MemSys
+	}
+	return 0
+}
+
+func (x *SystemInfoResponse) GetNumGc() uint32 {
+	if x != nil {
+		return x.NumGc
+	}
+	return 0
+}
+
+func (x *SystemInfoResponse) GetNumFds() uint32 {
+	if x != nil {
+		return x.NumFds
+	}
+	return 0
+}
+
+type GetVersionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetVersionRequest) Reset() {
+	*x = GetVersionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionRequest) ProtoMessage() {}
+
+func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{40}
+}
+
+type VersionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GitCommit string `protobuf:"bytes,1,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"`
+	BuildHost string `protobuf:"bytes,2,opt,name=build_host,json=buildHost,proto3" json:"build_host,omitempty"`
+}
+
+func (x *VersionResponse) Reset() {
+	*x = VersionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionResponse) ProtoMessage() {}
+
+func (x *VersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionResponse.ProtoReflect.Descriptor instead.
+func (*VersionResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *VersionResponse) GetGitCommit() string {
+	if x != nil {
+		return x.GitCommit
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetBuildHost() string {
+	if x != nil {
+		return x.BuildHost
+	}
+	return ""
+}
+
+type WhoamiRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WhoamiRequest) Reset() {
+	*x = WhoamiRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WhoamiRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhoamiRequest) ProtoMessage() {}
+
+func (x *WhoamiRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhoamiRequest.ProtoReflect.Descriptor instead.
+func (*WhoamiRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{42}
+}
+
+type WhoamiResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Role string `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	User string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *WhoamiResponse) Reset() {
+	*x = WhoamiResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WhoamiResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhoamiResponse) ProtoMessage() {}
+
+func (x *WhoamiResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhoamiResponse.ProtoReflect.Descriptor instead.
+func (*WhoamiResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *WhoamiResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *WhoamiResponse) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type AuthTestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AuthTestRequest) Reset() {
+	*x = AuthTestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthTestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthTestRequest) ProtoMessage() {}
+
+func (x *AuthTestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthTestRequest.ProtoReflect.Descriptor instead.
+func (*AuthTestRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{44}
+}
+
+type AuthTestResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AuthTestResponse) Reset() {
+	*x = AuthTestResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthTestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthTestResponse) ProtoMessage() {}
+
+func (x *AuthTestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthTestResponse.ProtoReflect.Descriptor instead.
+func (*AuthTestResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{45}
+}
+
+type TokenView struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Role string `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	// If non-empty, the token can only access the files under path_prefix.
+	PathPrefix  string `protobuf:"bytes,4,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	Description string `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedTime int64  `protobuf:"varint,6,opt,name=created_time,json=createdTime,proto3" json:"created_time,omitempty"`
+	// The token never expires if 0.
+	ExpiresTime int64 `protobuf:"varint,7,opt,name=expires_time,json=expiresTime,proto3" json:"expires_time,omitempty"`
+}
+
+func (x *TokenView) Reset() {
+	*x = TokenView{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TokenView) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenView) ProtoMessage() {}
+
+func (x *TokenView) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenView.ProtoReflect.Descriptor instead.
+func (*TokenView) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *TokenView) GetId() string {
+	if x != nil {
+		return x.Id
 	}
 	return ""
 }
 
-func (x *SystemInfoResponse) GetOs() string {
+func (x *TokenView) GetUser() string {
 	if x != nil {
-		return x.Os
+		return x.User
 	}
 	return ""
 }
 
-func (x *SystemInfoResponse) GetArch() string {
+func (x *TokenView) GetRole() string {
 	if x != nil {
-		return x.Arch
+		return x.Role
 	}
 	return ""
 }
 
-func (x *SystemInfoResponse) GetNumGoroutine() uint32 {
+func (x *TokenView) GetPathPrefix() string {
 	if x != nil {
-		return x.NumGoroutine
+		return x.PathPrefix
 	}
-	return 0
+	return ""
 }
 
-func (x *SystemInfoResponse) GetHostname() string {
+func (x *TokenView) GetDescription() string {
 	if x != nil {
-		return x.Hostname
+		return x.Description
 	}
 	return ""
 }
 
-func (x *SystemInfoResponse) GetPid() uint64 {
+func (x *TokenView) GetCreatedTime() int64 {
 	if x != nil {
-		return x.Pid
+		return x.CreatedTime
 	}
 	return 0
 }
 
-func (x *SystemInfoResponse) GetUid() uint64 {
+func (x *TokenView) GetExpiresTime() int64 {
 	if x != nil {
-		return x.Uid
+		return x.ExpiresTime
 	}
 	return 0
 }
 
-func (x *SystemInfoResponse) GetMemAlloc() uint64 {
+type CreateTokenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Defaults to "token-<id>" if empty.
+	User        string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Role        string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	PathPrefix  string `protobuf:"bytes,3,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// The token never expires if 0.
+	ExpiresTime int64 `protobuf:"varint,5,opt,name=expires_time,json=expiresTime,proto3" json:"expires_time,omitempty"`
+}
+
+func (x *CreateTokenRequest) Reset() {
+	*x = CreateTokenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTokenRequest) ProtoMessage() {}
+
+func (x *CreateTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreateTokenRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CreateTokenRequest) GetUser() string {
 	if x != nil {
-		return x.MemAlloc
+		return x.User
 	}
-	return 0
+	return ""
 }
 
-func (x *SystemInfoResponse) GetMemSys() uint64 {
+func (x *CreateTokenRequest) GetRole() string {
 	if x != nil {
-		return x.MemSys
+		return x.Role
 	}
-	return 0
+	return ""
 }
 
-func (x *SystemInfoResponse) GetNumGc() uint32 {
+func (x *CreateTokenRequest) GetPathPrefix() string {
 	if x != nil {
-		return x.NumGc
+		return x.PathPrefix
 	}
-	return 0
+	return ""
 }
 
-func (x *SystemInfoResponse) GetNumFds() uint32 {
+func (x *CreateTokenRequest) GetDescription() string {
 	if x != nil {
-		return x.NumFds
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateTokenRequest) GetExpiresTime() int64 {
+	if x != nil {
+		return x.ExpiresTime
 	}
 	return 0
 }
 
-type GetVersionRequest struct {
+type CreateTokenResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Token *TokenView `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// The bearer token. Only shown on creation.
+	Secret string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
 }
 
-func (x *GetVersionRequest) Reset() {
-	*x = GetVersionRequest{}
+func (x *CreateTokenResponse) Reset() {
+	*x = CreateTokenResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[28]
+		mi := &file_otaru_proto_msgTypes[48]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetVersionRequest) String() string {
+func (x *CreateTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetVersionRequest) ProtoMessage() {}
+func (*CreateTokenResponse) ProtoMessage() {}
 
-func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[28]
+func (x *CreateTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[48]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1685,37 +2836,88 @@ func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
-func (*GetVersionRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use CreateTokenResponse.ProtoReflect.Descriptor instead.
+func (*CreateTokenResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{48}
 }
 
-type VersionResponse struct {
+func (x *CreateTokenResponse) GetToken() *TokenView {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+func (x *CreateTokenResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListTokensRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+}
 
-	GitCommit string `protobuf:"bytes,1,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"`
-	BuildHost string `protobuf:"bytes,2,opt,name=build_host,json=buildHost,proto3" json:"build_host,omitempty"`
+func (x *ListTokensRequest) Reset() {
+	*x = ListTokensRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *VersionResponse) Reset() {
-	*x = VersionResponse{}
+func (x *ListTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokensRequest) ProtoMessage() {}
+
+func (x *ListTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListTokensRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{49}
+}
+
+type ListTokensResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token []*TokenView `protobuf:"bytes,1,rep,name=token,proto3" json:"token,omitempty"`
+}
+
+func (x *ListTokensResponse) Reset() {
+	*x = ListTokensResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[29]
+		mi := &file_otaru_proto_msgTypes[50]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *VersionResponse) String() string {
+func (x *ListTokensResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VersionResponse) ProtoMessage() {}
+func (*ListTokensResponse) ProtoMessage() {}
 
-func (x *VersionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[29]
+func (x *ListTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[50]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1726,48 +2928,88 @@ func (x *VersionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VersionResponse.ProtoReflect.Descriptor instead.
-func (*VersionResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use ListTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListTokensResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *VersionResponse) GetGitCommit() string {
+func (x *ListTokensResponse) GetToken() []*TokenView {
 	if x != nil {
-		return x.GitCommit
+		return x.Token
 	}
-	return ""
+	return nil
 }
 
-func (x *VersionResponse) GetBuildHost() string {
+type RevokeTokenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *RevokeTokenRequest) Reset() {
+	*x = RevokeTokenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_otaru_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeTokenRequest) ProtoMessage() {}
+
+func (x *RevokeTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeTokenRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *RevokeTokenRequest) GetId() string {
 	if x != nil {
-		return x.BuildHost
+		return x.Id
 	}
 	return ""
 }
 
-type WhoamiRequest struct {
+type RevokeTokenResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *WhoamiRequest) Reset() {
-	*x = WhoamiRequest{}
+func (x *RevokeTokenResponse) Reset() {
+	*x = RevokeTokenResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[30]
+		mi := &file_otaru_proto_msgTypes[52]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *WhoamiRequest) String() string {
+func (x *RevokeTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WhoamiRequest) ProtoMessage() {}
+func (*RevokeTokenResponse) ProtoMessage() {}
 
-func (x *WhoamiRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[30]
+func (x *RevokeTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[52]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1778,37 +3020,46 @@ func (x *WhoamiRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WhoamiRequest.ProtoReflect.Descriptor instead.
-func (*WhoamiRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use RevokeTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeTokenResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{52}
 }
 
-type WhoamiResponse struct {
+type AuditLogEntry struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Role string `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
-	User string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
-}
-
-func (x *WhoamiResponse) Reset() {
-	*x = WhoamiResponse{}
+	Time   int64  `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	User   string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Op     string `protobuf:"bytes,4,opt,name=op,proto3" json:"op,omitempty"`
+	Path   string `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`
+	// Destination path of "rename".
+	NewPath string `protobuf:"bytes,6,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	Id      uint64 `protobuf:"varint,7,opt,name=id,proto3" json:"id,omitempty"`
+	Txid    uint64 `protobuf:"varint,8,opt,name=txid,proto3" json:"txid,omitempty"`
+	// Non-empty if the operation failed.
+	Error string `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *AuditLogEntry) Reset() {
+	*x = AuditLogEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[31]
+		mi := &file_otaru_proto_msgTypes[53]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *WhoamiResponse) String() string {
+func (x *AuditLogEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WhoamiResponse) ProtoMessage() {}
+func (*AuditLogEntry) ProtoMessage() {}
 
-func (x *WhoamiResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[31]
+func (x *AuditLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[53]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1819,48 +3070,106 @@ func (x *WhoamiResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WhoamiResponse.ProtoReflect.Descriptor instead.
-func (*WhoamiResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use AuditLogEntry.ProtoReflect.Descriptor instead.
+func (*AuditLogEntry) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *AuditLogEntry) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *AuditLogEntry) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetNewPath() string {
+	if x != nil {
+		return x.NewPath
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
 }
 
-func (x *WhoamiResponse) GetRole() string {
+func (x *AuditLogEntry) GetTxid() uint64 {
 	if x != nil {
-		return x.Role
+		return x.Txid
 	}
-	return ""
+	return 0
 }
 
-func (x *WhoamiResponse) GetUser() string {
+func (x *AuditLogEntry) GetError() string {
 	if x != nil {
-		return x.User
+		return x.Error
 	}
 	return ""
 }
 
-type AuthTestRequest struct {
+type QueryAuditLogRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// Entries in [since_time, until_time) are returned. 0 means unbounded.
+	SinceTime  int64  `protobuf:"varint,1,opt,name=since_time,json=sinceTime,proto3" json:"since_time,omitempty"`
+	UntilTime  int64  `protobuf:"varint,2,opt,name=until_time,json=untilTime,proto3" json:"until_time,omitempty"`
+	User       string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Op         string `protobuf:"bytes,4,opt,name=op,proto3" json:"op,omitempty"`
+	PathPrefix string `protobuf:"bytes,5,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	// If > 0, only the latest limit entries are returned.
+	Limit int32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func (x *AuthTestRequest) Reset() {
-	*x = AuthTestRequest{}
+func (x *QueryAuditLogRequest) Reset() {
+	*x = QueryAuditLogRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[32]
+		mi := &file_otaru_proto_msgTypes[54]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuthTestRequest) String() string {
+func (x *QueryAuditLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthTestRequest) ProtoMessage() {}
+func (*QueryAuditLogRequest) ProtoMessage() {}
 
-func (x *AuthTestRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[32]
+func (x *QueryAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[54]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1871,34 +3180,78 @@ func (x *AuthTestRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthTestRequest.ProtoReflect.Descriptor instead.
-func (*AuthTestRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use QueryAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{54}
 }
 
-type AuthTestResponse struct {
+func (x *QueryAuditLogRequest) GetSinceTime() int64 {
+	if x != nil {
+		return x.SinceTime
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetUntilTime() int64 {
+	if x != nil {
+		return x.UntilTime
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type QueryAuditLogResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Entry []*AuditLogEntry `protobuf:"bytes,1,rep,name=entry,proto3" json:"entry,omitempty"`
 }
 
-func (x *AuthTestResponse) Reset() {
-	*x = AuthTestResponse{}
+func (x *QueryAuditLogResponse) Reset() {
+	*x = QueryAuditLogResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[33]
+		mi := &file_otaru_proto_msgTypes[55]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuthTestResponse) String() string {
+func (x *QueryAuditLogResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthTestResponse) ProtoMessage() {}
+func (*QueryAuditLogResponse) ProtoMessage() {}
 
-func (x *AuthTestResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[33]
+func (x *QueryAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_otaru_proto_msgTypes[55]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1909,9 +3262,16 @@ func (x *AuthTestResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthTestResponse.ProtoReflect.Descriptor instead.
-func (*AuthTestResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use QueryAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_otaru_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *QueryAuditLogResponse) GetEntry() []*AuditLogEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
 }
 
 type ListHostsRequest struct {
@@ -1923,7 +3283,7 @@ type ListHostsRequest struct {
 func (x *ListHostsRequest) Reset() {
 	*x = ListHostsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[34]
+		mi := &file_otaru_proto_msgTypes[56]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1936,7 +3296,7 @@ func (x *ListHostsRequest) String() string {
 func (*ListHostsRequest) ProtoMessage() {}
 
 func (x *ListHostsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[34]
+	mi := &file_otaru_proto_msgTypes[56]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1949,7 +3309,7 @@ func (x *ListHostsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListHostsRequest.ProtoReflect.Descriptor instead.
 func (*ListHostsRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{34}
+	return file_otaru_proto_rawDescGZIP(), []int{56}
 }
 
 type ListHostsResponse struct {
@@ -1963,7 +3323,7 @@ type ListHostsResponse struct {
 func (x *ListHostsResponse) Reset() {
 	*x = ListHostsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[35]
+		mi := &file_otaru_proto_msgTypes[57]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1976,7 +3336,7 @@ func (x *ListHostsResponse) String() string {
 func (*ListHostsResponse) ProtoMessage() {}
 
 func (x *ListHostsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[35]
+	mi := &file_otaru_proto_msgTypes[57]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1989,7 +3349,7 @@ func (x *ListHostsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListHostsResponse.ProtoReflect.Descriptor instead.
 func (*ListHostsResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{35}
+	return file_otaru_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *ListHostsResponse) GetHost() []string {
@@ -2014,7 +3374,7 @@ type FileInfo struct {
 func (x *FileInfo) Reset() {
 	*x = FileInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[36]
+		mi := &file_otaru_proto_msgTypes[58]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2027,7 +3387,7 @@ func (x *FileInfo) String() string {
 func (*FileInfo) ProtoMessage() {}
 
 func (x *FileInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[36]
+	mi := &file_otaru_proto_msgTypes[58]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2040,7 +3400,7 @@ func (x *FileInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
 func (*FileInfo) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{36}
+	return file_otaru_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *FileInfo) GetName() string {
@@ -2089,7 +3449,7 @@ type ListLocalDirRequest struct {
 func (x *ListLocalDirRequest) Reset() {
 	*x = ListLocalDirRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[37]
+		mi := &file_otaru_proto_msgTypes[59]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2102,7 +3462,7 @@ func (x *ListLocalDirRequest) String() string {
 func (*ListLocalDirRequest) ProtoMessage() {}
 
 func (x *ListLocalDirRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[37]
+	mi := &file_otaru_proto_msgTypes[59]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2115,7 +3475,7 @@ func (x *ListLocalDirRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListLocalDirRequest.ProtoReflect.Descriptor instead.
 func (*ListLocalDirRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{37}
+	return file_otaru_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *ListLocalDirRequest) GetPath() string {
@@ -2136,7 +3496,7 @@ type ListLocalDirResponse struct {
 func (x *ListLocalDirResponse) Reset() {
 	*x = ListLocalDirResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[38]
+		mi := &file_otaru_proto_msgTypes[60]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2149,7 +3509,7 @@ func (x *ListLocalDirResponse) String() string {
 func (*ListLocalDirResponse) ProtoMessage() {}
 
 func (x *ListLocalDirResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[38]
+	mi := &file_otaru_proto_msgTypes[60]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2162,7 +3522,7 @@ func (x *ListLocalDirResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListLocalDirResponse.ProtoReflect.Descriptor instead.
 func (*ListLocalDirResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{38}
+	return file_otaru_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *ListLocalDirResponse) GetEntry() []*FileInfo {
@@ -2183,7 +3543,7 @@ type MkdirLocalRequest struct {
 func (x *MkdirLocalRequest) Reset() {
 	*x = MkdirLocalRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[39]
+		mi := &file_otaru_proto_msgTypes[61]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2196,7 +3556,7 @@ func (x *MkdirLocalRequest) String() string {
 func (*MkdirLocalRequest) ProtoMessage() {}
 
 func (x *MkdirLocalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[39]
+	mi := &file_otaru_proto_msgTypes[61]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2209,7 +3569,7 @@ func (x *MkdirLocalRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MkdirLocalRequest.ProtoReflect.Descriptor instead.
 func (*MkdirLocalRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{39}
+	return file_otaru_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *MkdirLocalRequest) GetPath() string {
@@ -2228,7 +3588,7 @@ type MkdirLocalResponse struct {
 func (x *MkdirLocalResponse) Reset() {
 	*x = MkdirLocalResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[40]
+		mi := &file_otaru_proto_msgTypes[62]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2241,7 +3601,7 @@ func (x *MkdirLocalResponse) String() string {
 func (*MkdirLocalResponse) ProtoMessage() {}
 
 func (x *MkdirLocalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[40]
+	mi := &file_otaru_proto_msgTypes[62]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2254,7 +3614,7 @@ func (x *MkdirLocalResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MkdirLocalResponse.ProtoReflect.Descriptor instead.
 func (*MkdirLocalResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{40}
+	return file_otaru_proto_rawDescGZIP(), []int{62}
 }
 
 type CopyLocalRequest struct {
@@ -2269,7 +3629,7 @@ type CopyLocalRequest struct {
 func (x *CopyLocalRequest) Reset() {
 	*x = CopyLocalRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[41]
+		mi := &file_otaru_proto_msgTypes[63]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2282,7 +3642,7 @@ func (x *CopyLocalRequest) String() string {
 func (*CopyLocalRequest) ProtoMessage() {}
 
 func (x *CopyLocalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[41]
+	mi := &file_otaru_proto_msgTypes[63]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2295,7 +3655,7 @@ func (x *CopyLocalRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CopyLocalRequest.ProtoReflect.Descriptor instead.
 func (*CopyLocalRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{41}
+	return file_otaru_proto_rawDescGZIP(), []int{63}
 }
 
 func (x *CopyLocalRequest) GetPathSrc() string {
@@ -2321,7 +3681,7 @@ type CopyLocalResponse struct {
 func (x *CopyLocalResponse) Reset() {
 	*x = CopyLocalResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[42]
+		mi := &file_otaru_proto_msgTypes[64]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2334,7 +3694,7 @@ func (x *CopyLocalResponse) String() string {
 func (*CopyLocalResponse) ProtoMessage() {}
 
 func (x *CopyLocalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[42]
+	mi := &file_otaru_proto_msgTypes[64]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2347,7 +3707,7 @@ func (x *CopyLocalResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CopyLocalResponse.ProtoReflect.Descriptor instead.
 func (*CopyLocalResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{42}
+	return file_otaru_proto_rawDescGZIP(), []int{64}
 }
 
 type MoveLocalRequest struct {
@@ -2362,7 +3722,7 @@ type MoveLocalRequest struct {
 func (x *MoveLocalRequest) Reset() {
 	*x = MoveLocalRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[43]
+		mi := &file_otaru_proto_msgTypes[65]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2375,7 +3735,7 @@ func (x *MoveLocalRequest) String() string {
 func (*MoveLocalRequest) ProtoMessage() {}
 
 func (x *MoveLocalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[43]
+	mi := &file_otaru_proto_msgTypes[65]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2388,7 +3748,7 @@ func (x *MoveLocalRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MoveLocalRequest.ProtoReflect.Descriptor instead.
 func (*MoveLocalRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{43}
+	return file_otaru_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *MoveLocalRequest) GetPathSrc() string {
@@ -2414,7 +3774,7 @@ type MoveLocalResponse struct {
 func (x *MoveLocalResponse) Reset() {
 	*x = MoveLocalResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[44]
+		mi := &file_otaru_proto_msgTypes[66]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2427,7 +3787,7 @@ func (x *MoveLocalResponse) String() string {
 func (*MoveLocalResponse) ProtoMessage() {}
 
 func (x *MoveLocalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[44]
+	mi := &file_otaru_proto_msgTypes[66]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2440,7 +3800,7 @@ func (x *MoveLocalResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MoveLocalResponse.ProtoReflect.Descriptor instead.
 func (*MoveLocalResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{44}
+	return file_otaru_proto_rawDescGZIP(), []int{66}
 }
 
 type DownloadRequest struct {
@@ -2456,7 +3816,7 @@ type DownloadRequest struct {
 func (x *DownloadRequest) Reset() {
 	*x = DownloadRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[45]
+		mi := &file_otaru_proto_msgTypes[67]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2469,7 +3829,7 @@ func (x *DownloadRequest) String() string {
 func (*DownloadRequest) ProtoMessage() {}
 
 func (x *DownloadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[45]
+	mi := &file_otaru_proto_msgTypes[67]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2482,7 +3842,7 @@ func (x *DownloadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownloadRequest.ProtoReflect.Descriptor instead.
 func (*DownloadRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{45}
+	return file_otaru_proto_rawDescGZIP(), []int{67}
 }
 
 func (x *DownloadRequest) GetOpathSrc() string {
@@ -2515,7 +3875,7 @@ type DownloadResponse struct {
 func (x *DownloadResponse) Reset() {
 	*x = DownloadResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[46]
+		mi := &file_otaru_proto_msgTypes[68]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2528,7 +3888,7 @@ func (x *DownloadResponse) String() string {
 func (*DownloadResponse) ProtoMessage() {}
 
 func (x *DownloadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[46]
+	mi := &file_otaru_proto_msgTypes[68]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2541,7 +3901,7 @@ func (x *DownloadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownloadResponse.ProtoReflect.Descriptor instead.
 func (*DownloadResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{46}
+	return file_otaru_proto_rawDescGZIP(), []int{68}
 }
 
 type UploadRequest struct {
@@ -2557,7 +3917,7 @@ type UploadRequest struct {
 func (x *UploadRequest) Reset() {
 	*x = UploadRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[47]
+		mi := &file_otaru_proto_msgTypes[69]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2570,7 +3930,7 @@ func (x *UploadRequest) String() string {
 func (*UploadRequest) ProtoMessage() {}
 
 func (x *UploadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[47]
+	mi := &file_otaru_proto_msgTypes[69]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2583,7 +3943,7 @@ func (x *UploadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadRequest.ProtoReflect.Descriptor instead.
 func (*UploadRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{47}
+	return file_otaru_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *UploadRequest) GetPathSrc() string {
@@ -2616,7 +3976,7 @@ type UploadResponse struct {
 func (x *UploadResponse) Reset() {
 	*x = UploadResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[48]
+		mi := &file_otaru_proto_msgTypes[70]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2629,7 +3989,7 @@ func (x *UploadResponse) String() string {
 func (*UploadResponse) ProtoMessage() {}
 
 func (x *UploadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[48]
+	mi := &file_otaru_proto_msgTypes[70]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2642,7 +4002,7 @@ func (x *UploadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadResponse.ProtoReflect.Descriptor instead.
 func (*UploadResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{48}
+	return file_otaru_proto_rawDescGZIP(), []int{70}
 }
 
 type RemoteMoveRequest struct {
@@ -2657,7 +4017,7 @@ type RemoteMoveRequest struct {
 func (x *RemoteMoveRequest) Reset() {
 	*x = RemoteMoveRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[49]
+		mi := &file_otaru_proto_msgTypes[71]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2670,7 +4030,7 @@ func (x *RemoteMoveRequest) String() string {
 func (*RemoteMoveRequest) ProtoMessage() {}
 
 func (x *RemoteMoveRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[49]
+	mi := &file_otaru_proto_msgTypes[71]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2683,7 +4043,7 @@ func (x *RemoteMoveRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoteMoveRequest.ProtoReflect.Descriptor instead.
 func (*RemoteMoveRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{49}
+	return file_otaru_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *RemoteMoveRequest) GetOpathSrc() string {
@@ -2709,7 +4069,7 @@ type RemoteMoveResponse struct {
 func (x *RemoteMoveResponse) Reset() {
 	*x = RemoteMoveResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[50]
+		mi := &file_otaru_proto_msgTypes[72]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2722,7 +4082,7 @@ func (x *RemoteMoveResponse) String() string {
 func (*RemoteMoveResponse) ProtoMessage() {}
 
 func (x *RemoteMoveResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[50]
+	mi := &file_otaru_proto_msgTypes[72]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2735,7 +4095,7 @@ func (x *RemoteMoveResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoteMoveResponse.ProtoReflect.Descriptor instead.
 func (*RemoteMoveResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{50}
+	return file_otaru_proto_rawDescGZIP(), []int{72}
 }
 
 type RemoveLocalRequest struct {
@@ -2750,7 +4110,7 @@ type RemoveLocalRequest struct {
 func (x *RemoveLocalRequest) Reset() {
 	*x = RemoveLocalRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[51]
+		mi := &file_otaru_proto_msgTypes[73]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2763,7 +4123,7 @@ func (x *RemoveLocalRequest) String() string {
 func (*RemoveLocalRequest) ProtoMessage() {}
 
 func (x *RemoveLocalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[51]
+	mi := &file_otaru_proto_msgTypes[73]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2776,7 +4136,7 @@ func (x *RemoveLocalRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveLocalRequest.ProtoReflect.Descriptor instead.
 func (*RemoveLocalRequest) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{51}
+	return file_otaru_proto_rawDescGZIP(), []int{73}
 }
 
 func (x *RemoveLocalRequest) GetPath() string {
@@ -2802,7 +4162,7 @@ type RemoveLocalResponse struct {
 func (x *RemoveLocalResponse) Reset() {
 	*x = RemoveLocalResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[52]
+		mi := &file_otaru_proto_msgTypes[74]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2815,7 +4175,7 @@ func (x *RemoveLocalResponse) String() string {
 func (*RemoveLocalResponse) ProtoMessage() {}
 
 func (x *RemoveLocalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[52]
+	mi := &file_otaru_proto_msgTypes[74]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2828,7 +4188,7 @@ func (x *RemoveLocalResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveLocalResponse.ProtoReflect.Descriptor instead.
 func (*RemoveLocalResponse) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{52}
+	return file_otaru_proto_rawDescGZIP(), []int{74}
 }
 
 type ListDirResponse_Listing struct {
@@ -2843,7 +4203,7 @@ type ListDirResponse_Listing struct {
 func (x *ListDirResponse_Listing) Reset() {
 	*x = ListDirResponse_Listing{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[53]
+		mi := &file_otaru_proto_msgTypes[75]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2856,7 +4216,7 @@ func (x *ListDirResponse_Listing) String() string {
 func (*ListDirResponse_Listing) ProtoMessage() {}
 
 func (x *ListDirResponse_Listing) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[53]
+	mi := &file_otaru_proto_msgTypes[75]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2906,7 +4266,7 @@ type GetEntriesResponse_Entry struct {
 func (x *GetEntriesResponse_Entry) Reset() {
 	*x = GetEntriesResponse_Entry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_otaru_proto_msgTypes[54]
+		mi := &file_otaru_proto_msgTypes[76]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2919,7 +4279,7 @@ func (x *GetEntriesResponse_Entry) String() string {
 func (*GetEntriesResponse_Entry) ProtoMessage() {}
 
 func (x *GetEntriesResponse_Entry) ProtoReflect() protoreflect.Message {
-	mi := &file_otaru_proto_msgTypes[54]
+	mi := &file_otaru_proto_msgTypes[76]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2932,7 +4292,7 @@ func (x *GetEntriesResponse_Entry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEntriesResponse_Entry.ProtoReflect.Descriptor instead.
 func (*GetEntriesResponse_Entry) Descriptor() ([]byte, []int) {
-	return file_otaru_proto_rawDescGZIP(), []int{22, 0}
+	return file_otaru_proto_rawDescGZIP(), []int{23, 0}
 }
 
 func (x *GetEntriesResponse_Entry) GetBlobPath() string {
@@ -3010,14 +4370,11 @@ var File_otaru_proto protoreflect.FileDescriptor
 var file_otaru_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x6f, 0x74, 0x61, 0x72, 0x75, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70,
 	0x62, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e,
-	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x6f, 0x70, 0x65, 0x6e,
-	0x61, 0x70, 0x69, 0x76, 0x32, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x61, 0x6e,
 	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
 	0x34, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
 	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x02, 0x69,
 	0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0xcc, 0x01, 0x0a, 0x09, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x56,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0xb5, 0x02, 0x0a, 0x09, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x56,
 	0x69, 0x65, 0x77, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
 	0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
@@ -3030,169 +4387,317 @@ var file_otaru_proto_rawDesc = []byte{
 	0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x65, 0x72, 0x6d, 0x4d, 0x6f, 0x64, 0x65, 0x12,
 	0x23, 0x0a, 0x0d, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65,
 	0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64,
-	0x54, 0x69, 0x6d, 0x65, 0x22, 0x8f, 0x01, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x07, 0x6c, 0x69, 0x73, 0x74,
-	0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x1a,
-	0x45, 0x0a, 0x07, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x69,
-	0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x64, 0x69, 0x72, 0x49,
-	0x64, 0x12, 0x23, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x56, 0x69, 0x65, 0x77, 0x52,
-	0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x22, 0xc3, 0x01, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x69, 0x72, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x64, 0x69, 0x72, 0x49, 0x64, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x03, 0x67, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x65, 0x72, 0x6d, 0x5f,
-	0x6d, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x65, 0x72, 0x6d,
-	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64,
-	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x6f, 0x64,
-	0x69, 0x66, 0x69, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x74, 0x79, 0x70,
-	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x4e, 0x6f,
-	0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x37, 0x0a, 0x0e,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x15,
-	0x0a, 0x06, 0x69, 0x73, 0x5f, 0x6e, 0x65, 0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
-	0x69, 0x73, 0x4e, 0x65, 0x77, 0x22, 0x3a, 0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x69, 0x72, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x64, 0x69, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x22, 0x10, 0x0a, 0x0e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x47, 0x0a, 0x0d, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x73, 0x72, 0x63,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x74, 0x68, 0x53, 0x72, 0x63, 0x12,
-	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x64, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74, 0x68, 0x44, 0x65, 0x73, 0x74, 0x22, 0x10, 0x0a, 0x0e,
-	0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2d,
-	0x0a, 0x17, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x50, 0x61,
-	0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
-	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x2a, 0x0a,
-	0x18, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x50, 0x61, 0x74,
-	0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x22, 0x31, 0x0a, 0x0b, 0x41, 0x74, 0x74,
-	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x33, 0x0a, 0x0c,
-	0x41, 0x74, 0x74, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x05,
-	0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62,
-	0x2e, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x56, 0x69, 0x65, 0x77, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72,
-	0x79, 0x22, 0x51, 0x0a, 0x0f, 0x52, 0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06,
-	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6c, 0x65,
-	0x6e, 0x67, 0x74, 0x68, 0x22, 0x26, 0x0a, 0x10, 0x52, 0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x4e, 0x0a, 0x10,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64,
-	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x13, 0x0a, 0x11,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x1b, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72,
-	0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xb6,
-	0x01, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a,
-	0x11, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x69, 0x6d, 0x70, 0x6c, 0x5f, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e,
-	0x64, 0x49, 0x6d, 0x70, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x61, 0x63,
-	0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x26,
-	0x0a, 0x0f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x69, 0x6d, 0x70, 0x6c, 0x5f, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x61, 0x63, 0x68, 0x65, 0x49, 0x6d,
-	0x70, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f,
-	0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x63,
-	0x68, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x22, 0x50, 0x0a, 0x12, 0x52, 0x65, 0x64, 0x75, 0x63,
-	0x65, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a,
-	0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
-	0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x73, 0x69, 0x72, 0x65,
-	0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
-	0x73, 0x69, 0x72, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x54, 0x0a, 0x13, 0x52, 0x65, 0x64,
-	0x75, 0x63, 0x65, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72,
-	0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
-	0x13, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0x9a, 0x03, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72,
-	0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x05, 0x65,
-	0x6e, 0x74, 0x72, 0x79, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e,
-	0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x1a,
-	0xcf, 0x02, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x6c, 0x6f,
-	0x62, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x6c,
-	0x6f, 0x62, 0x50, 0x61, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08,
-	0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
-	0x62, 0x6c, 0x6f, 0x62, 0x4c, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x69, 0x64,
-	0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x76, 0x61, 0x6c, 0x69,
-	0x64, 0x4c, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x79, 0x6e, 0x63, 0x5f, 0x63, 0x6f, 0x75,
-	0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x79, 0x6e, 0x63, 0x43, 0x6f,
-	0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x64,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x73, 0x65, 0x64,
-	0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12,
-	0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x08, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x37, 0x0a, 0x18,
-	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x6f, 0x66, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72,
-	0x5f, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15,
-	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x4f, 0x66, 0x57, 0x72, 0x69, 0x74, 0x65, 0x72, 0x48, 0x61,
-	0x6e, 0x64, 0x6c, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f,
-	0x6f, 0x66, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x4f, 0x66, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65,
-	0x73, 0x22, 0x18, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53,
-	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xd4, 0x01, 0x0a, 0x17,
-	0x47, 0x65, 0x74, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f,
-	0x73, 0x79, 0x6e, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74,
-	0x53, 0x79, 0x6e, 0x63, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x74, 0x78, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x78, 0x12, 0x17, 0x0a,
-	0x07, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
-	0x6c, 0x61, 0x73, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x69, 0x63, 0x6b, 0x65,
-	0x74, 0x12, 0x2f, 0x0a, 0x14, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x6f, 0x66, 0x5f, 0x6e,
-	0x6f, 0x64, 0x65, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x11, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x4f, 0x66, 0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x6f, 0x63,
-	0x6b, 0x73, 0x22, 0x16, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
-	0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x16, 0x0a, 0x14, 0x47, 0x65,
-	0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x22, 0xa2, 0x02, 0x0a, 0x12, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66,
-	0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x6f, 0x5f,
-	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67,
-	0x6f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x63, 0x68,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x63, 0x68, 0x12, 0x23, 0x0a, 0x0d,
-	0x6e, 0x75, 0x6d, 0x5f, 0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6e, 0x75, 0x6d, 0x47, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e,
-	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a,
-	0x03, 0x70, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12,
-	0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x75, 0x69,
-	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x6d, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d, 0x65, 0x6d, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x12, 0x17,
-	0x0a, 0x07, 0x6d, 0x65, 0x6d, 0x5f, 0x73, 0x79, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x06, 0x6d, 0x65, 0x6d, 0x53, 0x79, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x5f, 0x67,
-	0x63, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6e, 0x75, 0x6d, 0x47, 0x63, 0x12, 0x17,
-	0x0a, 0x07, 0x6e, 0x75, 0x6d, 0x5f, 0x66, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x06, 0x6e, 0x75, 0x6d, 0x46, 0x64, 0x73, 0x22, 0x13, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4f, 0x0a, 0x0f,
-	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x1d, 0x0a, 0x0a, 0x67, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x1d,
-	0x0a, 0x0a, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x48, 0x6f, 0x73, 0x74, 0x22, 0x0f, 0x0a,
-	0x0d, 0x57, 0x68, 0x6f, 0x61, 0x6d, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x38,
-	0x0a, 0x0e, 0x57, 0x68, 0x6f, 0x61, 0x6d, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x72, 0x6f, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x11, 0x0a, 0x0f, 0x41, 0x75, 0x74, 0x68,
-	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x12, 0x0a, 0x10, 0x41,
-	0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0c, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x8f, 0x01,
+	0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x35, 0x0a, 0x07, 0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x52,
+	0x07, 0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x1a, 0x45, 0x0a, 0x07, 0x4c, 0x69, 0x73, 0x74,
+	0x69, 0x6e, 0x67, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x69, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x64, 0x69, 0x72, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x05, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x49,
+	0x4e, 0x6f, 0x64, 0x65, 0x56, 0x69, 0x65, 0x77, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x22,
+	0xc3, 0x01, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x69, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x64, 0x69, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x75, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x10,
+	0x0a, 0x03, 0x67, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x67, 0x69, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x70, 0x65, 0x72, 0x6d, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x65, 0x72, 0x6d, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a,
+	0x0d, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x37, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x73, 0x5f, 0x6e, 0x65,
+	0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x4e, 0x65, 0x77, 0x22, 0x3a,
+	0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x15, 0x0a, 0x06, 0x64, 0x69, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x05, 0x64, 0x69, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x10, 0x0a, 0x0e, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x47, 0x0a, 0x0d,
+	0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x73, 0x72, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x70, 0x61, 0x74, 0x68, 0x53, 0x72, 0x63, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x74, 0x68,
+	0x5f, 0x64, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74,
+	0x68, 0x44, 0x65, 0x73, 0x74, 0x22, 0x10, 0x0a, 0x0e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2d, 0x0a, 0x17, 0x46, 0x69, 0x6e, 0x64, 0x4e,
+	0x6f, 0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x2a, 0x0a, 0x18, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x6f,
+	0x64, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x31, 0x0a, 0x0b, 0x41, 0x74, 0x74, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x33, 0x0a, 0x0c, 0x41, 0x74, 0x74, 0x72, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x56,
+	0x69, 0x65, 0x77, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x22, 0x51, 0x0a, 0x0f, 0x52, 0x65,
+	0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0x26, 0x0a,
+	0x10, 0x52, 0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x4e, 0x0a, 0x10, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x13, 0x0a, 0x11, 0x57, 0x72, 0x69, 0x74, 0x65, 0x46, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x0a, 0x19, 0x47, 0x65,
+	0x74, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x80, 0x01, 0x0a, 0x14, 0x43, 0x69, 0x72, 0x63,
+	0x75, 0x69, 0x74, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x63, 0x6f, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x74, 0x69, 0x76,
+	0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x70, 0x65,
+	0x6e, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x6f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xf9, 0x01, 0x0a, 0x1a, 0x47,
+	0x65, 0x74, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x63,
+	0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x69, 0x6d, 0x70, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x49, 0x6d, 0x70,
+	0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64,
+	0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x61,
+	0x63, 0x6b, 0x65, 0x6e, 0x64, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x5f, 0x69, 0x6d, 0x70, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x61, 0x63, 0x68, 0x65, 0x49, 0x6d, 0x70, 0x6c, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x66, 0x6c, 0x61, 0x67,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x63, 0x68, 0x65, 0x46, 0x6c,
+	0x61, 0x67, 0x73, 0x12, 0x41, 0x0a, 0x0f, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x5f, 0x62,
+	0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70,
+	0x62, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0e, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x42,
+	0x72, 0x65, 0x61, 0x6b, 0x65, 0x72, 0x22, 0x50, 0x0a, 0x12, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65,
+	0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64,
+	0x72, 0x79, 0x52, 0x75, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
+	0x69, 0x72, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x54, 0x0a, 0x13, 0x52, 0x65, 0x64, 0x75,
+	0x63, 0x65, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x13,
+	0x0a, 0x11, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x9a, 0x03, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x05, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x47,
+	0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x1a, 0xcf,
+	0x02, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x6c, 0x6f, 0x62,
+	0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x6c, 0x6f,
+	0x62, 0x50, 0x61, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62,
+	0x6c, 0x6f, 0x62, 0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62,
+	0x6c, 0x6f, 0x62, 0x4c, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f,
+	0x6c, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x4c, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x79, 0x6e, 0x63, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x79, 0x6e, 0x63, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x73, 0x65, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x37, 0x0a, 0x18, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x6f, 0x66, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x72, 0x5f,
+	0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x4f, 0x66, 0x57, 0x72, 0x69, 0x74, 0x65, 0x72, 0x48, 0x61, 0x6e,
+	0x64, 0x6c, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x6f,
+	0x66, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x4f, 0x66, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73,
+	0x22, 0xaa, 0x01, 0x0a, 0x0e, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x2f, 0x0a, 0x14, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x11, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65,
+	0x72, 0x53, 0x65, 0x63, 0x12, 0x33, 0x0a, 0x16, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x12, 0x32, 0x0a, 0x15, 0x66, 0x75, 0x6c,
+	0x6c, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x77, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x66, 0x75, 0x6c, 0x6c, 0x42, 0x61,
+	0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x22, 0x1a, 0x0a,
+	0x18, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x71, 0x0a, 0x19, 0x47, 0x65, 0x74,
+	0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x61, 0x6e, 0x64, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x2a, 0x0a, 0x11, 0x69, 0x73, 0x5f, 0x66, 0x75, 0x6c, 0x6c, 0x5f, 0x62, 0x61, 0x6e, 0x64,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x69, 0x73, 0x46,
+	0x75, 0x6c, 0x6c, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x22, 0x44, 0x0a, 0x18,
+	0x53, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x61, 0x6e,
+	0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x22, 0x1b, 0x0a, 0x19, 0x53, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64,
+	0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x2d, 0x0a, 0x12, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0xae,
+	0x01, 0x0a, 0x13, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x75, 0x6d, 0x5f, 0x76, 0x65,
+	0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6e, 0x75,
+	0x6d, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x75, 0x6d,
+	0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6e, 0x75,
+	0x6d, 0x53, 0x74, 0x61, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e,
+	0x74, 0x69, 0x6e, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x71, 0x75, 0x61,
+	0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61,
+	0x5f, 0x6c, 0x6f, 0x73, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74,
+	0x61, 0x4c, 0x6f, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x22,
+	0x0e, 0x0a, 0x0c, 0x53, 0x63, 0x72, 0x75, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x62, 0x0a, 0x0c, 0x53, 0x63, 0x72, 0x75, 0x62, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x66, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x22, 0x66, 0x0a, 0x0c, 0x53, 0x63, 0x72, 0x75, 0x62, 0x42, 0x61, 0x64, 0x42,
+	0x6c, 0x6f, 0x62, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x62, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x6c, 0x6f, 0x62, 0x70, 0x61, 0x74, 0x68, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x24, 0x0a, 0x04, 0x72, 0x65, 0x66, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x72, 0x75, 0x62, 0x42, 0x6c,
+	0x6f, 0x62, 0x52, 0x65, 0x66, 0x52, 0x04, 0x72, 0x65, 0x66, 0x73, 0x22, 0x9f, 0x01, 0x0a, 0x0d,
+	0x53, 0x63, 0x72, 0x75, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x6e, 0x75, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x6e, 0x75, 0x6d, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x75, 0x6d, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x6e, 0x75, 0x6d, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2d, 0x0a,
+	0x09, 0x62, 0x61, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x72, 0x75, 0x62, 0x42, 0x61, 0x64, 0x42, 0x6c,
+	0x6f, 0x62, 0x52, 0x08, 0x62, 0x61, 0x64, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6e, 0x75, 0x6d, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x6e, 0x75, 0x6d, 0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x22, 0x18, 0x0a,
+	0x16, 0x47, 0x65, 0x74, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xd4, 0x01, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x49,
+	0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x79, 0x6e, 0x63,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x79, 0x6e, 0x63,
+	0x12, 0x17, 0x0a, 0x07, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x74, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x78, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x61, 0x73,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6c, 0x61, 0x73, 0x74,
+	0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x2f, 0x0a,
+	0x14, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x6f, 0x66, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x5f,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x4f, 0x66, 0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x16,
+	0x0a, 0x14, 0x47, 0x65, 0x74, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x16, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x53, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xa2,
+	0x02, 0x0a, 0x12, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x6f, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x6f, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x6f, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x63, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x75, 0x6d, 0x5f,
+	0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x6e, 0x75, 0x6d, 0x47, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x6d, 0x65, 0x6d, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x6d, 0x65, 0x6d, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x65,
+	0x6d, 0x5f, 0x73, 0x79, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6d, 0x65, 0x6d,
+	0x53, 0x79, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x5f, 0x67, 0x63, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x6e, 0x75, 0x6d, 0x47, 0x63, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x75,
+	0x6d, 0x5f, 0x66, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6e, 0x75, 0x6d,
+	0x46, 0x64, 0x73, 0x22, 0x13, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4f, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x67,
+	0x69, 0x74, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x67, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x48, 0x6f, 0x73, 0x74, 0x22, 0x0f, 0x0a, 0x0d, 0x57, 0x68, 0x6f,
+	0x61, 0x6d, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x38, 0x0a, 0x0e, 0x57, 0x68,
+	0x6f, 0x61, 0x6d, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x22, 0x11, 0x0a, 0x0f, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x12, 0x0a, 0x10, 0x41, 0x75, 0x74, 0x68, 0x54,
+	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xcc, 0x01, 0x0a, 0x09,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x56, 0x69, 0x65, 0x77, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xa2, 0x01, 0x0a, 0x12, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x74,
+	0x68, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x70, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x22,
+	0x52, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x56, 0x69, 0x65, 0x77, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x22, 0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x39, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23,
+	0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x70, 0x62, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x56, 0x69, 0x65, 0x77, 0x52, 0x05, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x22, 0x24, 0x0a, 0x12, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x15, 0x0a, 0x13, 0x52, 0x65, 0x76,
+	0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0xc8, 0x01, 0x0a, 0x0d, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x6f, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x78, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x04, 0x74, 0x78, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xaf, 0x01, 0x0a, 0x14,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x74,
+	0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x40, 0x0a,
+	0x15, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74,
+	0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x22,
 	0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
 	0x65, 0x73, 0x74, 0x22, 0x27, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74,
@@ -3283,9 +4788,9 @@ var file_otaru_proto_rawDesc = []byte{
 	0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65,
 	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e,
 	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x22,
-	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1c, 0x22, 0x17, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f,
-	0x66, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x3a,
-	0x01, 0x2a, 0x12, 0x56, 0x0a, 0x06, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x11, 0x2e, 0x70,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1c, 0x3a, 0x01, 0x2a, 0x22, 0x17, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x76, 0x31, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x6e, 0x6f,
+	0x64, 0x65, 0x12, 0x56, 0x0a, 0x06, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x11, 0x2e, 0x70,
 	0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
 	0x12, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
 	0x6e, 0x73, 0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x22, 0x1a, 0x2f, 0x61, 0x70,
@@ -3308,7 +4813,7 @@ var file_otaru_proto_rawDesc = []byte{
 	0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x29, 0x82, 0xd3, 0xe4,
 	0x93, 0x02, 0x23, 0x22, 0x1e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x69, 0x6c,
 	0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x6e, 0x6f, 0x64, 0x65, 0x2f, 0x72, 0x65, 0x6e,
-	0x61, 0x6d, 0x65, 0x3a, 0x01, 0x2a, 0x32, 0xcb, 0x02, 0x0a, 0x10, 0x42, 0x6c, 0x6f, 0x62, 0x73,
+	0x61, 0x6d, 0x65, 0x3a, 0x01, 0x2a, 0x32, 0x85, 0x06, 0x0a, 0x10, 0x42, 0x6c, 0x6f, 0x62, 0x73,
 	0x74, 0x6f, 0x72, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x6c, 0x0a, 0x09, 0x47,
 	0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65,
 	0x74, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
@@ -3329,115 +4834,161 @@ var file_otaru_proto_rawDesc = []byte{
 	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x29, 0x82, 0xd3, 0xe4, 0x93, 0x02,
 	0x23, 0x22, 0x1e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x62, 0x73,
 	0x74, 0x6f, 0x72, 0x65, 0x2f, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x5f, 0x63, 0x61, 0x63, 0x68,
-	0x65, 0x3a, 0x01, 0x2a, 0x32, 0x7b, 0x0a, 0x0e, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x69, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x49, 0x4e, 0x6f,
-	0x64, 0x65, 0x44, 0x42, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x47,
-	0x65, 0x74, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x4e,
-	0x6f, 0x64, 0x65, 0x44, 0x42, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x61, 0x70, 0x69,
-	0x2f, 0x76, 0x31, 0x2f, 0x69, 0x6e, 0x6f, 0x64, 0x65, 0x64, 0x62, 0x2f, 0x73, 0x74, 0x61, 0x74,
-	0x73, 0x32, 0xd4, 0x04, 0x0a, 0x11, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5e, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x53, 0x79,
-	0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65,
-	0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e,
-	0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93,
-	0x02, 0x15, 0x12, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x2f, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x58, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70,
-	0x62, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x12, 0x16, 0x2f, 0x61, 0x70, 0x69, 0x2f,
-	0x76, 0x31, 0x2f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x12, 0x4e, 0x0a, 0x06, 0x57, 0x68, 0x6f, 0x61, 0x6d, 0x69, 0x12, 0x11, 0x2e, 0x70, 0x62,
-	0x2e, 0x57, 0x68, 0x6f, 0x61, 0x6d, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12,
-	0x2e, 0x70, 0x62, 0x2e, 0x57, 0x68, 0x6f, 0x61, 0x6d, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x61, 0x70, 0x69,
-	0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x77, 0x68, 0x6f, 0x61, 0x6d,
-	0x69, 0x12, 0x69, 0x0a, 0x11, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x41, 0x6e, 0x6f,
-	0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73, 0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x68,
-	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x62,
-	0x2e, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x29, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x23, 0x12, 0x21, 0x2f, 0x61, 0x70, 0x69, 0x2f,
-	0x76, 0x31, 0x2f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x74, 0x65,
-	0x73, 0x74, 0x5f, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73, 0x12, 0x67, 0x0a, 0x10,
-	0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79,
+	0x65, 0x3a, 0x01, 0x2a, 0x12, 0x7b, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x64, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x47,
+	0x65, 0x74, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74,
+	0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x29, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x23, 0x12, 0x21,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x2f, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x7e, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x65, 0x74, 0x42,
+	0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x65, 0x74, 0x42, 0x61, 0x6e,
+	0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x2c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x26, 0x22, 0x21, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x62,
+	0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x3a, 0x01,
+	0x2a, 0x12, 0x69, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65,
+	0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x63, 0x68,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x56, 0x65,
+	0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x29, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x23, 0x3a, 0x01, 0x2a, 0x22, 0x1e, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f,
+	0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x12, 0x50, 0x0a, 0x05,
+	0x53, 0x63, 0x72, 0x75, 0x62, 0x12, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x72, 0x75, 0x62,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x72,
+	0x75, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x22, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x1c, 0x22, 0x17, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x62,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x73, 0x63, 0x72, 0x75, 0x62, 0x3a, 0x01, 0x2a, 0x32, 0x7b,
+	0x0a, 0x0e, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x69, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x4e, 0x6f, 0x64,
+	0x65, 0x44, 0x42, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x42, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x6e,
+	0x6f, 0x64, 0x65, 0x64, 0x62, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x32, 0xd4, 0x04, 0x0a, 0x11,
+	0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x5e, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70,
+	0x62, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x69, 0x6e, 0x66,
+	0x6f, 0x12, 0x58, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x15, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x18, 0x12, 0x16, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x2f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x4e, 0x0a, 0x06, 0x57,
+	0x68, 0x6f, 0x61, 0x6d, 0x69, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x57, 0x68, 0x6f, 0x61, 0x6d,
+	0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x57, 0x68,
+	0x6f, 0x61, 0x6d, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x2f, 0x77, 0x68, 0x6f, 0x61, 0x6d, 0x69, 0x12, 0x69, 0x0a, 0x11, 0x41,
+	0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x41, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73,
+	0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x54,
+	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x29, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x23, 0x12, 0x21, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x6e, 0x6f,
+	0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73, 0x12, 0x67, 0x0a, 0x10, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65,
+	0x73, 0x74, 0x52, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e,
+	0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x14, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x28, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x22, 0x12, 0x20, 0x2f,
+	0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x61, 0x75,
+	0x74, 0x68, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x6f, 0x6e, 0x6c, 0x79, 0x12,
+	0x61, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x41, 0x64, 0x6d, 0x69, 0x6e,
 	0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x54,
-	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x28, 0x82, 0xd3, 0xe4,
-	0x93, 0x02, 0x22, 0x12, 0x20, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79, 0x73,
-	0x74, 0x65, 0x6d, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x61,
-	0x64, 0x6f, 0x6e, 0x6c, 0x79, 0x12, 0x61, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73,
-	0x74, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x68,
-	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x62,
-	0x2e, 0x41, 0x75, 0x74, 0x68, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x12, 0x1d, 0x2f, 0x61, 0x70, 0x69, 0x2f,
-	0x76, 0x31, 0x2f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x74, 0x65,
-	0x73, 0x74, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x32, 0xc3, 0x06, 0x0a, 0x09, 0x46, 0x65, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x52, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x6f,
-	0x73, 0x74, 0x73, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x6f, 0x73,
-	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x12, 0x10, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76,
-	0x31, 0x2f, 0x66, 0x65, 0x2f, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x5e, 0x0a, 0x0c, 0x4c, 0x69,
-	0x73, 0x74, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x69, 0x72, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e,
-	0x4c, 0x69, 0x73, 0x74, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x6f, 0x63,
-	0x61, 0x6c, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x82,
-	0xd3, 0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66,
-	0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x6c, 0x73, 0x12, 0x5e, 0x0a, 0x0a, 0x4d, 0x6b,
-	0x64, 0x69, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x6b,
-	0x64, 0x69, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x6b, 0x64, 0x69, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x22,
-	0x16, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61,
-	0x6c, 0x2f, 0x6d, 0x6b, 0x64, 0x69, 0x72, 0x3a, 0x01, 0x2a, 0x12, 0x58, 0x0a, 0x09, 0x43, 0x6f,
-	0x70, 0x79, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x70,
-	0x79, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
-	0x70, 0x62, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x22, 0x13, 0x2f, 0x61,
-	0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x63,
-	0x70, 0x3a, 0x01, 0x2a, 0x12, 0x58, 0x0a, 0x09, 0x4d, 0x6f, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61,
-	0x6c, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x6f, 0x76,
-	0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e,
-	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x22, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f,
-	0x66, 0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x6d, 0x76, 0x3a, 0x01, 0x2a, 0x12, 0x5b,
-	0x0a, 0x08, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e,
-	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x14, 0x2e, 0x70, 0x62, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x22, 0x19, 0x2f,
-	0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f,
-	0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x3a, 0x01, 0x2a, 0x12, 0x53, 0x0a, 0x06, 0x55,
-	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x70,
-	0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x22, 0x82, 0xd3,
-	0xe4, 0x93, 0x02, 0x1c, 0x22, 0x17, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65,
-	0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x3a, 0x01, 0x2a,
-	0x12, 0x5c, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x15,
-	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74,
-	0x65, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1f, 0x82,
-	0xd3, 0xe4, 0x93, 0x02, 0x19, 0x22, 0x14, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66,
-	0x65, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6d, 0x76, 0x3a, 0x01, 0x2a, 0x12, 0x5e,
-	0x0a, 0x0b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x16, 0x2e,
-	0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76,
-	0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e,
-	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x22, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f,
-	0x66, 0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x72, 0x6d, 0x3a, 0x01, 0x2a, 0x42, 0x9c,
-	0x01, 0x5a, 0x19, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x79,
-	0x61, 0x78, 0x74, 0x2f, 0x6f, 0x74, 0x61, 0x72, 0x75, 0x2f, 0x70, 0x62, 0x92, 0x41, 0x7e, 0x12,
-	0x55, 0x0a, 0x09, 0x4f, 0x74, 0x61, 0x72, 0x75, 0x20, 0x41, 0x50, 0x49, 0x22, 0x43, 0x0a, 0x0d,
-	0x4f, 0x74, 0x61, 0x72, 0x75, 0x20, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1e, 0x68,
-	0x74, 0x74, 0x70, 0x73, 0x3a, 0x2f, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x6e, 0x79, 0x61, 0x78, 0x74, 0x2f, 0x6f, 0x74, 0x61, 0x72, 0x75, 0x1a, 0x12, 0x75,
-	0x65, 0x6e, 0x6f, 0x40, 0x6e, 0x79, 0x61, 0x78, 0x74, 0x73, 0x74, 0x65, 0x70, 0x2e, 0x63, 0x6f,
-	0x6d, 0x32, 0x03, 0x31, 0x2e, 0x30, 0x2a, 0x01, 0x02, 0x32, 0x10, 0x61, 0x70, 0x70, 0x6c, 0x69,
-	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x3a, 0x10, 0x61, 0x70, 0x70,
-	0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x6a, 0x73, 0x6f, 0x6e, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x1f, 0x12, 0x1d, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x64, 0x6d,
+	0x69, 0x6e, 0x32, 0xa9, 0x02, 0x0a, 0x0c, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x5f, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x22, 0x14, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x2f, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x3a, 0x01, 0x2a, 0x12, 0x57, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x73, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x12, 0x12, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x76, 0x31, 0x2f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x2f, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x5f, 0x0a,
+	0x0b, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x16, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1f, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x19, 0x22, 0x14, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x2f, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x3a, 0x01, 0x2a, 0x32, 0x77,
+	0x0a, 0x0f, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x64, 0x0a, 0x0d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c,
+	0x6f, 0x67, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70,
+	0x62, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x12,
+	0x16, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x75, 0x64, 0x69, 0x74, 0x6c, 0x6f,
+	0x67, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x32, 0xc3, 0x06, 0x0a, 0x09, 0x46, 0x65, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x52, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x6f, 0x73,
+	0x74, 0x73, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x6f, 0x73, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x12, 0x10, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31,
+	0x2f, 0x66, 0x65, 0x2f, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x5e, 0x0a, 0x0c, 0x4c, 0x69, 0x73,
+	0x74, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x69, 0x72, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x6f, 0x63, 0x61,
+	0x6c, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65,
+	0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x6c, 0x73, 0x12, 0x5e, 0x0a, 0x0a, 0x4d, 0x6b, 0x64,
+	0x69, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x6b, 0x64,
+	0x69, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x6b, 0x64, 0x69, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x3a, 0x01,
+	0x2a, 0x22, 0x16, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x2f, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x2f, 0x6d, 0x6b, 0x64, 0x69, 0x72, 0x12, 0x58, 0x0a, 0x09, 0x43, 0x6f, 0x70,
+	0x79, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x70, 0x79,
+	0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70,
+	0x62, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x22, 0x13, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x63, 0x70,
+	0x3a, 0x01, 0x2a, 0x12, 0x58, 0x0a, 0x09, 0x4d, 0x6f, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c,
+	0x12, 0x14, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x4d, 0x6f, 0x76, 0x65,
+	0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x18, 0x22, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66,
+	0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x6d, 0x76, 0x3a, 0x01, 0x2a, 0x12, 0x5b, 0x0a,
+	0x08, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x44,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14,
+	0x2e, 0x70, 0x62, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x22, 0x19, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x64,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x3a, 0x01, 0x2a, 0x12, 0x53, 0x0a, 0x06, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x22, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x1c, 0x22, 0x17, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65, 0x2f,
+	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x3a, 0x01, 0x2a, 0x12,
+	0x5c, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x15, 0x2e,
+	0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1f, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x19, 0x22, 0x14, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x65,
+	0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6d, 0x76, 0x3a, 0x01, 0x2a, 0x12, 0x5e, 0x0a,
+	0x0b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x16, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x18, 0x22, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x66,
+	0x65, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x2f, 0x72, 0x6d, 0x3a, 0x01, 0x2a, 0x42, 0x1b, 0x5a,
+	0x19, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x79, 0x61, 0x78,
+	0x74, 0x2f, 0x6f, 0x74, 0x61, 0x72, 0x75, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -3453,7 +5004,7 @@ func file_otaru_proto_rawDescGZIP() []byte {
 }
 
 var file_otaru_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_otaru_proto_msgTypes = make([]protoimpl.MessageInfo, 55)
+var file_otaru_proto_msgTypes = make([]protoimpl.MessageInfo, 77)
 var file_otaru_proto_goTypes = []interface{}{
 	(INodeType)(0),                     // 0: pb.INodeType
 	(*ListDirRequest)(nil),             // 1: pb.ListDirRequest
@@ -3474,112 +5025,158 @@ var file_otaru_proto_goTypes = []interface{}{
 	(*WriteFileRequest)(nil),           // 16: pb.WriteFileRequest
 	(*WriteFileResponse)(nil),          // 17: pb.WriteFileResponse
 	(*GetBlobstoreConfigRequest)(nil),  // 18: pb.GetBlobstoreConfigRequest
-	(*GetBlobstoreConfigResponse)(nil), // 19: pb.GetBlobstoreConfigResponse
-	(*ReduceCacheRequest)(nil),         // 20: pb.ReduceCacheRequest
-	(*ReduceCacheResponse)(nil),        // 21: pb.ReduceCacheResponse
-	(*GetEntriesRequest)(nil),          // 22: pb.GetEntriesRequest
-	(*GetEntriesResponse)(nil),         // 23: pb.GetEntriesResponse
-	(*GetINodeDBStatsRequest)(nil),     // 24: pb.GetINodeDBStatsRequest
-	(*GetINodeDBStatsResponse)(nil),    // 25: pb.GetINodeDBStatsResponse
-	(*GetCategoriesRequest)(nil),       // 26: pb.GetCategoriesRequest
-	(*GetSystemInfoRequest)(nil),       // 27: pb.GetSystemInfoRequest
-	(*SystemInfoResponse)(nil),         // 28: pb.SystemInfoResponse
-	(*GetVersionRequest)(nil),          // 29: pb.GetVersionRequest
-	(*VersionResponse)(nil),            // 30: pb.VersionResponse
-	(*WhoamiRequest)(nil),              // 31: pb.WhoamiRequest
-	(*WhoamiResponse)(nil),             // 32: pb.WhoamiResponse
-	(*AuthTestRequest)(nil),            // 33: pb.AuthTestRequest
-	(*AuthTestResponse)(nil),           // 34: pb.AuthTestResponse
-	(*ListHostsRequest)(nil),           // 35: pb.ListHostsRequest
-	(*ListHostsResponse)(nil),          // 36: pb.ListHostsResponse
-	(*FileInfo)(nil),                   // 37: pb.FileInfo
-	(*ListLocalDirRequest)(nil),        // 38: pb.ListLocalDirRequest
-	(*ListLocalDirResponse)(nil),       // 39: pb.ListLocalDirResponse
-	(*MkdirLocalRequest)(nil),          // 40: pb.MkdirLocalRequest
-	(*MkdirLocalResponse)(nil),         // 41: pb.MkdirLocalResponse
-	(*CopyLocalRequest)(nil),           // 42: pb.CopyLocalRequest
-	(*CopyLocalResponse)(nil),          // 43: pb.CopyLocalResponse
-	(*MoveLocalRequest)(nil),           // 44: pb.MoveLocalRequest
-	(*MoveLocalResponse)(nil),          // 45: pb.MoveLocalResponse
-	(*DownloadRequest)(nil),            // 46: pb.DownloadRequest
-	(*DownloadResponse)(nil),           // 47: pb.DownloadResponse
-	(*UploadRequest)(nil),              // 48: pb.UploadRequest
-	(*UploadResponse)(nil),             // 49: pb.UploadResponse
-	(*RemoteMoveRequest)(nil),          // 50: pb.RemoteMoveRequest
-	(*RemoteMoveResponse)(nil),         // 51: pb.RemoteMoveResponse
-	(*RemoveLocalRequest)(nil),         // 52: pb.RemoveLocalRequest
-	(*RemoveLocalResponse)(nil),        // 53: pb.RemoveLocalResponse
-	(*ListDirResponse_Listing)(nil),    // 54: pb.ListDirResponse.Listing
-	(*GetEntriesResponse_Entry)(nil),   // 55: pb.GetEntriesResponse.Entry
+	(*CircuitBreakerStatus)(nil),       // 19: pb.CircuitBreakerStatus
+	(*GetBlobstoreConfigResponse)(nil), // 20: pb.GetBlobstoreConfigResponse
+	(*ReduceCacheRequest)(nil),         // 21: pb.ReduceCacheRequest
+	(*ReduceCacheResponse)(nil),        // 22: pb.ReduceCacheResponse
+	(*GetEntriesRequest)(nil),          // 23: pb.GetEntriesRequest
+	(*GetEntriesResponse)(nil),         // 24: pb.GetEntriesResponse
+	(*BandwidthLimit)(nil),             // 25: pb.BandwidthLimit
+	(*GetBandwidthLimitRequest)(nil),   // 26: pb.GetBandwidthLimitRequest
+	(*GetBandwidthLimitResponse)(nil),  // 27: pb.GetBandwidthLimitResponse
+	(*SetBandwidthLimitRequest)(nil),   // 28: pb.SetBandwidthLimitRequest
+	(*SetBandwidthLimitResponse)(nil),  // 29: pb.SetBandwidthLimitResponse
+	(*VerifyCacheRequest)(nil),         // 30: pb.VerifyCacheRequest
+	(*VerifyCacheResponse)(nil),        // 31: pb.VerifyCacheResponse
+	(*ScrubRequest)(nil),               // 32: pb.ScrubRequest
+	(*ScrubBlobRef)(nil),               // 33: pb.ScrubBlobRef
+	(*ScrubBadBlob)(nil),               // 34: pb.ScrubBadBlob
+	(*ScrubResponse)(nil),              // 35: pb.ScrubResponse
+	(*GetINodeDBStatsRequest)(nil),     // 36: pb.GetINodeDBStatsRequest
+	(*GetINodeDBStatsResponse)(nil),    // 37: pb.GetINodeDBStatsResponse
+	(*GetCategoriesRequest)(nil),       // 38: pb.GetCategoriesRequest
+	(*GetSystemInfoRequest)(nil),       // 39: pb.GetSystemInfoRequest
+	(*SystemInfoResponse)(nil),         // 40: pb.SystemInfoResponse
+	(*GetVersionRequest)(nil),          // 41: pb.GetVersionRequest
+	(*VersionResponse)(nil),            // 42: pb.VersionResponse
+	(*WhoamiRequest)(nil),              // 43: pb.WhoamiRequest
+	(*WhoamiResponse)(nil),             // 44: pb.WhoamiResponse
+	(*AuthTestRequest)(nil),            // 45: pb.AuthTestRequest
+	(*AuthTestResponse)(nil),           // 46: pb.AuthTestResponse
+	(*TokenView)(nil),                  // 47: pb.TokenView
+	(*CreateTokenRequest)(nil),         // 48: pb.CreateTokenRequest
+	(*CreateTokenResponse)(nil),        // 49: pb.CreateTokenResponse
+	(*ListTokensRequest)(nil),          // 50: pb.ListTokensRequest
+	(*ListTokensResponse)(nil),         // 51: pb.ListTokensResponse
+	(*RevokeTokenRequest)(nil),         // 52: pb.RevokeTokenRequest
+	(*RevokeTokenResponse)(nil),        // 53: pb.RevokeTokenResponse
+	(*AuditLogEntry)(nil),              // 54: pb.AuditLogEntry
+	(*QueryAuditLogRequest)(nil),       // 55: pb.QueryAuditLogRequest
+	(*QueryAuditLogResponse)(nil),      // 56: pb.QueryAuditLogResponse
+	(*ListHostsRequest)(nil),           // 57: pb.ListHostsRequest
+	(*ListHostsResponse)(nil),          // 58: pb.ListHostsResponse
+	(*FileInfo)(nil),                   // 59: pb.FileInfo
+	(*ListLocalDirRequest)(nil),        // 60: pb.ListLocalDirRequest
+	(*ListLocalDirResponse)(nil),       // 61: pb.ListLocalDirResponse
+	(*MkdirLocalRequest)(nil),          // 62: pb.MkdirLocalRequest
+	(*MkdirLocalResponse)(nil),         // 63: pb.MkdirLocalResponse
+	(*CopyLocalRequest)(nil),           // 64: pb.CopyLocalRequest
+	(*CopyLocalResponse)(nil),          // 65: pb.CopyLocalResponse
+	(*MoveLocalRequest)(nil),           // 66: pb.MoveLocalRequest
+	(*MoveLocalResponse)(nil),          // 67: pb.MoveLocalResponse
+	(*DownloadRequest)(nil),            // 68: pb.DownloadRequest
+	(*DownloadResponse)(nil),           // 69: pb.DownloadResponse
+	(*UploadRequest)(nil),              // 70: pb.UploadRequest
+	(*UploadResponse)(nil),             // 71: pb.UploadResponse
+	(*RemoteMoveRequest)(nil),          // 72: pb.RemoteMoveRequest
+	(*RemoteMoveResponse)(nil),         // 73: pb.RemoteMoveResponse
+	(*RemoveLocalRequest)(nil),         // 74: pb.RemoveLocalRequest
+	(*RemoveLocalResponse)(nil),        // 75: pb.RemoveLocalResponse
+	(*ListDirResponse_Listing)(nil),    // 76: pb.ListDirResponse.Listing
+	(*GetEntriesResponse_Entry)(nil),   // 77: pb.GetEntriesResponse.Entry
 }
 var file_otaru_proto_depIdxs = []int32{
 	0,  // 0: pb.INodeView.type:type_name -> pb.INodeType
-	54, // 1: pb.ListDirResponse.listing:type_name -> pb.ListDirResponse.Listing
+	76, // 1: pb.ListDirResponse.listing:type_name -> pb.ListDirResponse.Listing
 	0,  // 2: pb.CreateRequest.type:type_name -> pb.INodeType
 	2,  // 3: pb.AttrResponse.entry:type_name -> pb.INodeView
-	55, // 4: pb.GetEntriesResponse.entry:type_name -> pb.GetEntriesResponse.Entry
-	0,  // 5: pb.FileInfo.type:type_name -> pb.INodeType
-	37, // 6: pb.ListLocalDirResponse.entry:type_name -> pb.FileInfo
-	2,  // 7: pb.ListDirResponse.Listing.entry:type_name -> pb.INodeView
-	1,  // 8: pb.FileSystemService.ListDir:input_type -> pb.ListDirRequest
-	10, // 9: pb.FileSystemService.FindNodeFullPath:input_type -> pb.FindNodeFullPathRequest
-	12, // 10: pb.FileSystemService.Attr:input_type -> pb.AttrRequest
-	4,  // 11: pb.FileSystemService.Create:input_type -> pb.CreateRequest
-	6,  // 12: pb.FileSystemService.Remove:input_type -> pb.RemoveRequest
-	14, // 13: pb.FileSystemService.ReadFile:input_type -> pb.ReadFileRequest
-	16, // 14: pb.FileSystemService.WriteFile:input_type -> pb.WriteFileRequest
-	8,  // 15: pb.FileSystemService.Rename:input_type -> pb.RenameRequest
-	18, // 16: pb.BlobstoreService.GetConfig:input_type -> pb.GetBlobstoreConfigRequest
-	22, // 17: pb.BlobstoreService.GetEntries:input_type -> pb.GetEntriesRequest
-	20, // 18: pb.BlobstoreService.ReduceCache:input_type -> pb.ReduceCacheRequest
-	24, // 19: pb.INodeDBService.GetINodeDBStats:input_type -> pb.GetINodeDBStatsRequest
-	27, // 20: pb.SystemInfoService.GetSystemInfo:input_type -> pb.GetSystemInfoRequest
-	29, // 21: pb.SystemInfoService.GetVersion:input_type -> pb.GetVersionRequest
-	31, // 22: pb.SystemInfoService.Whoami:input_type -> pb.WhoamiRequest
-	33, // 23: pb.SystemInfoService.AuthTestAnonymous:input_type -> pb.AuthTestRequest
-	33, // 24: pb.SystemInfoService.AuthTestReadOnly:input_type -> pb.AuthTestRequest
-	33, // 25: pb.SystemInfoService.AuthTestAdmin:input_type -> pb.AuthTestRequest
-	35, // 26: pb.FeService.ListHosts:input_type -> pb.ListHostsRequest
-	38, // 27: pb.FeService.ListLocalDir:input_type -> pb.ListLocalDirRequest
-	40, // 28: pb.FeService.MkdirLocal:input_type -> pb.MkdirLocalRequest
-	42, // 29: pb.FeService.CopyLocal:input_type -> pb.CopyLocalRequest
-	44, // 30: pb.FeService.MoveLocal:input_type -> pb.MoveLocalRequest
-	46, // 31: pb.FeService.Download:input_type -> pb.DownloadRequest
-	48, // 32: pb.FeService.Upload:input_type -> pb.UploadRequest
-	50, // 33: pb.FeService.RemoteMove:input_type -> pb.RemoteMoveRequest
-	52, // 34: pb.FeService.RemoveLocal:input_type -> pb.RemoveLocalRequest
-	3,  // 35: pb.FileSystemService.ListDir:output_type -> pb.ListDirResponse
-	11, // 36: pb.FileSystemService.FindNodeFullPath:output_type -> pb.FindNodeFullPathResponse
-	13, // 37: pb.FileSystemService.Attr:output_type -> pb.AttrResponse
-	5,  // 38: pb.FileSystemService.Create:output_type -> pb.CreateResponse
-	7,  // 39: pb.FileSystemService.Remove:output_type -> pb.RemoveResponse
-	15, // 40: pb.FileSystemService.ReadFile:output_type -> pb.ReadFileResponse
-	17, // 41: pb.FileSystemService.WriteFile:output_type -> pb.WriteFileResponse
-	9,  // 42: pb.FileSystemService.Rename:output_type -> pb.RenameResponse
-	19, // 43: pb.BlobstoreService.GetConfig:output_type -> pb.GetBlobstoreConfigResponse
-	23, // 44: pb.BlobstoreService.GetEntries:output_type -> pb.GetEntriesResponse
-	21, // 45: pb.BlobstoreService.ReduceCache:output_type -> pb.ReduceCacheResponse
-	25, // 46: pb.INodeDBService.GetINodeDBStats:output_type -> pb.GetINodeDBStatsResponse
-	28, // 47: pb.SystemInfoService.GetSystemInfo:output_type -> pb.SystemInfoResponse
-	30, // 48: pb.SystemInfoService.GetVersion:output_type -> pb.VersionResponse
-	32, // 49: pb.SystemInfoService.Whoami:output_type -> pb.WhoamiResponse
-	34, // 50: pb.SystemInfoService.AuthTestAnonymous:output_type -> pb.AuthTestResponse
-	34, // 51: pb.SystemInfoService.AuthTestReadOnly:output_type -> pb.AuthTestResponse
-	34, // 52: pb.SystemInfoService.AuthTestAdmin:output_type -> pb.AuthTestResponse
-	36, // 53: pb.FeService.ListHosts:output_type -> pb.ListHostsResponse
-	39, // 54: pb.FeService.ListLocalDir:output_type -> pb.ListLocalDirResponse
-	41, // 55: pb.FeService.MkdirLocal:output_type -> pb.MkdirLocalResponse
-	43, // 56: pb.FeService.CopyLocal:output_type -> pb.CopyLocalResponse
-	45, // 57: pb.FeService.MoveLocal:output_type -> pb.MoveLocalResponse
-	47, // 58: pb.FeService.Download:output_type -> pb.DownloadResponse
-	49, // 59: pb.FeService.Upload:output_type -> pb.UploadResponse
-	51, // 60: pb.FeService.RemoteMove:output_type -> pb.RemoteMoveResponse
-	53, // 61: pb.FeService.RemoveLocal:output_type -> pb.RemoveLocalResponse
-	35, // [35:62] is the sub-list for method output_type
-	8,  // [8:35] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	19, // 4: pb.GetBlobstoreConfigResponse.backend_breaker:type_name -> pb.CircuitBreakerStatus
+	77, // 5: pb.GetEntriesResponse.entry:type_name -> pb.GetEntriesResponse.Entry
+	25, // 6: pb.GetBandwidthLimitResponse.limit:type_name -> pb.BandwidthLimit
+	25, // 7: pb.SetBandwidthLimitRequest.limit:type_name -> pb.BandwidthLimit
+	33, // 8: pb.ScrubBadBlob.refs:type_name -> pb.ScrubBlobRef
+	34, // 9: pb.ScrubResponse.bad_blobs:type_name -> pb.ScrubBadBlob
+	47, // 10: pb.CreateTokenResponse.token:type_name -> pb.TokenView
+	47, // 11: pb.ListTokensResponse.token:type_name -> pb.TokenView
+	54, // 12: pb.QueryAuditLogResponse.entry:type_name -> pb.AuditLogEntry
+	0,  // 13: pb.FileInfo.type:type_name -> pb.INodeType
+	59, // 14: pb.ListLocalDirResponse.entry:type_name -> pb.FileInfo
+	2,  // 15: pb.ListDirResponse.Listing.entry:type_name -> pb.INodeView
+	1,  // 16: pb.FileSystemService.ListDir:input_type -> pb.ListDirRequest
+	10, // 17: pb.FileSystemService.FindNodeFullPath:input_type -> pb.FindNodeFullPathRequest
+	12, // 18: pb.FileSystemService.Attr:input_type -> pb.AttrRequest
+	4,  // 19: pb.FileSystemService.Create:input_type -> pb.CreateRequest
+	6,  // 20: pb.FileSystemService.Remove:input_type -> pb.RemoveRequest
+	14, // 21: pb.FileSystemService.ReadFile:input_type -> pb.ReadFileRequest
+	16, // 22: pb.FileSystemService.WriteFile:input_type -> pb.WriteFileRequest
+	8,  // 23: pb.FileSystemService.Rename:input_type -> pb.RenameRequest
+	18, // 24: pb.BlobstoreService.GetConfig:input_type -> pb.GetBlobstoreConfigRequest
+	23, // 25: pb.BlobstoreService.GetEntries:input_type -> pb.GetEntriesRequest
+	21, // 26: pb.BlobstoreService.ReduceCache:input_type -> pb.ReduceCacheRequest
+	26, // 27: pb.BlobstoreService.GetBandwidthLimit:input_type -> pb.GetBandwidthLimitRequest
+	28, // 28: pb.BlobstoreService.SetBandwidthLimit:input_type -> pb.SetBandwidthLimitRequest
+	30, // 29: pb.BlobstoreService.VerifyCache:input_type -> pb.VerifyCacheRequest
+	32, // 30: pb.BlobstoreService.Scrub:input_type -> pb.ScrubRequest
+	36, // 31: pb.INodeDBService.GetINodeDBStats:input_type -> pb.GetINodeDBStatsRequest
+	39, // 32: pb.SystemInfoService.GetSystemInfo:input_type -> pb.GetSystemInfoRequest
+	41, // 33: pb.SystemInfoService.GetVersion:input_type -> pb.GetVersionRequest
+	43, // 34: pb.SystemInfoService.Whoami:input_type -> pb.WhoamiRequest
+	45, // 35: pb.SystemInfoService.AuthTestAnonymous:input_type -> pb.AuthTestRequest
+	45, // 36: pb.SystemInfoService.AuthTestReadOnly:input_type -> pb.AuthTestRequest
+	45, // 37: pb.SystemInfoService.AuthTestAdmin:input_type -> pb.AuthTestRequest
+	48, // 38: pb.TokenService.CreateToken:input_type -> pb.CreateTokenRequest
+	50, // 39: pb.TokenService.ListTokens:input_type -> pb.ListTokensRequest
+	52, // 40: pb.TokenService.RevokeToken:input_type -> pb.RevokeTokenRequest
+	55, // 41: pb.AuditLogService.QueryAuditLog:input_type -> pb.QueryAuditLogRequest
+	57, // 42: pb.FeService.ListHosts:input_type -> pb.ListHostsRequest
+	60, // 43: pb.FeService.ListLocalDir:input_type -> pb.ListLocalDirRequest
+	62, // 44: pb.FeService.MkdirLocal:input_type -> pb.MkdirLocalRequest
+	64, // 45: pb.FeService.CopyLocal:input_type -> pb.CopyLocalRequest
+	66, // 46: pb.FeService.MoveLocal:input_type -> pb.MoveLocalRequest
+	68, // 47: pb.FeService.Download:input_type -> pb.DownloadRequest
+	70, // 48: pb.FeService.Upload:input_type -> pb.UploadRequest
+	72, // 49: pb.FeService.RemoteMove:input_type -> pb.RemoteMoveRequest
+	74, // 50: pb.FeService.RemoveLocal:input_type -> pb.RemoveLocalRequest
+	3,  // 51: pb.FileSystemService.ListDir:output_type -> pb.ListDirResponse
+	11, // 52: pb.FileSystemService.FindNodeFullPath:output_type -> pb.FindNodeFullPathResponse
+	13, // 53: pb.FileSystemService.Attr:output_type -> pb.AttrResponse
+	5,  // 54: pb.FileSystemService.Create:output_type -> pb.CreateResponse
+	7,  // 55: pb.FileSystemService.Remove:output_type -> pb.RemoveResponse
+	15, // 56: pb.FileSystemService.ReadFile:output_type -> pb.ReadFileResponse
+	17, // 57: pb.FileSystemService.WriteFile:output_type -> pb.WriteFileResponse
+	9,  // 58: pb.FileSystemService.Rename:output_type -> pb.RenameResponse
+	20, // 59: pb.BlobstoreService.GetConfig:output_type -> pb.GetBlobstoreConfigResponse
+	24, // 60: pb.BlobstoreService.GetEntries:output_type -> pb.GetEntriesResponse
+	22, // 61: pb.BlobstoreService.ReduceCache:output_type -> pb.ReduceCacheResponse
+	27, // 62: pb.BlobstoreService.GetBandwidthLimit:output_type -> pb.GetBandwidthLimitResponse
+	29, // 63: pb.BlobstoreService.SetBandwidthLimit:output_type -> pb.SetBandwidthLimitResponse
+	31, // 64: pb.BlobstoreService.VerifyCache:output_type -> pb.VerifyCacheResponse
+	35, // 65: pb.BlobstoreService.Scrub:output_type -> pb.ScrubResponse
+	37, // 66: pb.INodeDBService.GetINodeDBStats:output_type -> pb.GetINodeDBStatsResponse
+	40, // 67: pb.SystemInfoService.GetSystemInfo:output_type -> pb.SystemInfoResponse
+	42, // 68: pb.SystemInfoService.GetVersion:output_type -> pb.VersionResponse
+	44, // 69: pb.SystemInfoService.Whoami:output_type -> pb.WhoamiResponse
+	46, // 70: pb.SystemInfoService.AuthTestAnonymous:output_type -> pb.AuthTestResponse
+	46, // 71: pb.SystemInfoService.AuthTestReadOnly:output_type -> pb.AuthTestResponse
+	46, // 72: pb.SystemInfoService.AuthTestAdmin:output_type -> pb.AuthTestResponse
+	49, // 73: pb.TokenService.CreateToken:output_type -> pb.CreateTokenResponse
+	51, // 74: pb.TokenService.ListTokens:output_type -> pb.ListTokensResponse
+	53, // 75: pb.TokenService.RevokeToken:output_type -> pb.RevokeTokenResponse
+	56, // 76: pb.AuditLogService.QueryAuditLog:output_type -> pb.QueryAuditLogResponse
+	58, // 77: pb.FeService.ListHosts:output_type -> pb.ListHostsResponse
+	61, // 78: pb.FeService.ListLocalDir:output_type -> pb.ListLocalDirResponse
+	63, // 79: pb.FeService.MkdirLocal:output_type -> pb.MkdirLocalResponse
+	65, // 80: pb.FeService.CopyLocal:output_type -> pb.CopyLocalResponse
+	67, // 81: pb.FeService.MoveLocal:output_type -> pb.MoveLocalResponse
+	69, // 82: pb.FeService.Download:output_type -> pb.DownloadResponse
+	71, // 83: pb.FeService.Upload:output_type -> pb.UploadResponse
+	73, // 84: pb.FeService.RemoteMove:output_type -> pb.RemoteMoveResponse
+	75, // 85: pb.FeService.RemoveLocal:output_type -> pb.RemoveLocalResponse
+	51, // [51:86] is the sub-list for method output_type
+	16, // [16:51] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_otaru_proto_init() }
@@ -3600,8 +5197,128 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*INodeView); i {
+		file_otaru_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*INodeView); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDirResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindNodeFullPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FindNodeFullPathResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttrRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3612,8 +5329,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListDirResponse); i {
+		file_otaru_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttrResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3624,8 +5341,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateRequest); i {
+		file_otaru_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadFileRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3636,8 +5353,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateResponse); i {
+		file_otaru_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadFileResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3648,8 +5365,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveRequest); i {
+		file_otaru_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WriteFileRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3660,8 +5377,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveResponse); i {
+		file_otaru_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WriteFileResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3672,8 +5389,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RenameRequest); i {
+		file_otaru_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBlobstoreConfigRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3684,8 +5401,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RenameResponse); i {
+		file_otaru_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CircuitBreakerStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3696,8 +5413,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FindNodeFullPathRequest); i {
+		file_otaru_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBlobstoreConfigResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3708,8 +5425,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FindNodeFullPathResponse); i {
+		file_otaru_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReduceCacheRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3720,8 +5437,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AttrRequest); i {
+		file_otaru_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReduceCacheResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3732,8 +5449,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AttrResponse); i {
+		file_otaru_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetEntriesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3744,8 +5461,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadFileRequest); i {
+		file_otaru_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetEntriesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3756,8 +5473,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadFileResponse); i {
+		file_otaru_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BandwidthLimit); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3768,8 +5485,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WriteFileRequest); i {
+		file_otaru_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBandwidthLimitRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3780,8 +5497,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WriteFileResponse); i {
+		file_otaru_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBandwidthLimitResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3792,8 +5509,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetBlobstoreConfigRequest); i {
+		file_otaru_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetBandwidthLimitRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3804,8 +5521,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetBlobstoreConfigResponse); i {
+		file_otaru_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetBandwidthLimitResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3816,8 +5533,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReduceCacheRequest); i {
+		file_otaru_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyCacheRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3828,8 +5545,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReduceCacheResponse); i {
+		file_otaru_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyCacheResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3840,8 +5557,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetEntriesRequest); i {
+		file_otaru_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScrubRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3852,8 +5569,8 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetEntriesResponse); i {
+		file_otaru_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScrubBlobRef); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3864,7 +5581,31 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScrubBadBlob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScrubResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetINodeDBStatsRequest); i {
 			case 0:
 				return &v.state
@@ -3876,7 +5617,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetINodeDBStatsResponse); i {
 			case 0:
 				return &v.state
@@ -3888,7 +5629,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetCategoriesRequest); i {
 			case 0:
 				return &v.state
@@ -3900,7 +5641,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetSystemInfoRequest); i {
 			case 0:
 				return &v.state
@@ -3912,7 +5653,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SystemInfoResponse); i {
 			case 0:
 				return &v.state
@@ -3924,7 +5665,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetVersionRequest); i {
 			case 0:
 				return &v.state
@@ -3936,7 +5677,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*VersionResponse); i {
 			case 0:
 				return &v.state
@@ -3948,7 +5689,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WhoamiRequest); i {
 			case 0:
 				return &v.state
@@ -3960,7 +5701,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WhoamiResponse); i {
 			case 0:
 				return &v.state
@@ -3972,7 +5713,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AuthTestRequest); i {
 			case 0:
 				return &v.state
@@ -3984,7 +5725,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AuthTestResponse); i {
 			case 0:
 				return &v.state
@@ -3996,7 +5737,127 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TokenView); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateTokenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateTokenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTokensRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTokensResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeTokenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeTokenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditLogEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryAuditLogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryAuditLogResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_otaru_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListHostsRequest); i {
 			case 0:
 				return &v.state
@@ -4008,7 +5869,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListHostsResponse); i {
 			case 0:
 				return &v.state
@@ -4020,7 +5881,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*FileInfo); i {
 			case 0:
 				return &v.state
@@ -4032,7 +5893,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListLocalDirRequest); i {
 			case 0:
 				return &v.state
@@ -4044,7 +5905,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListLocalDirResponse); i {
 			case 0:
 				return &v.state
@@ -4056,7 +5917,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MkdirLocalRequest); i {
 			case 0:
 				return &v.state
@@ -4068,7 +5929,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MkdirLocalResponse); i {
 			case 0:
 				return &v.state
@@ -4080,7 +5941,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CopyLocalRequest); i {
 			case 0:
 				return &v.state
@@ -4092,7 +5953,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CopyLocalResponse); i {
 			case 0:
 				return &v.state
@@ -4104,7 +5965,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MoveLocalRequest); i {
 			case 0:
 				return &v.state
@@ -4116,7 +5977,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MoveLocalResponse); i {
 			case 0:
 				return &v.state
@@ -4128,7 +5989,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DownloadRequest); i {
 			case 0:
 				return &v.state
@@ -4140,7 +6001,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DownloadResponse); i {
 			case 0:
 				return &v.state
@@ -4152,7 +6013,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UploadRequest); i {
 			case 0:
 				return &v.state
@@ -4164,7 +6025,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UploadResponse); i {
 			case 0:
 				return &v.state
@@ -4176,7 +6037,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoteMoveRequest); i {
 			case 0:
 				return &v.state
@@ -4188,7 +6049,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoteMoveResponse); i {
 			case 0:
 				return &v.state
@@ -4200,7 +6061,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveLocalRequest); i {
 			case 0:
 				return &v.state
@@ -4212,7 +6073,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveLocalResponse); i {
 			case 0:
 				return &v.state
@@ -4224,7 +6085,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListDirResponse_Listing); i {
 			case 0:
 				return &v.state
@@ -4236,7 +6097,7 @@ func file_otaru_proto_init() {
 				return nil
 			}
 		}
-		file_otaru_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+		file_otaru_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetEntriesResponse_Entry); i {
 			case 0:
 				return &v.state
@@ -4255,9 +6116,9 @@ func file_otaru_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_otaru_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   55,
+			NumMessages:   77,
 			NumExtensions: 0,
-			NumServices:   5,
+			NumServices:   7,
 		},
 		GoTypes:           file_otaru_proto_goTypes,
 		DependencyIndexes: file_otaru_proto_depIdxs,
@@ -0,0 +1,124 @@
+package scrub
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/btncrypt"
+	"github.com/nyaxt/otaru/chunkstore"
+	"github.com/nyaxt/otaru/inodedb"
+)
+
+// BadBlob is a backend blob which failed verification.
+type BadBlob struct {
+	BlobPath string
+	Err      error
+	// Refs lists the file chunks referencing the blob.
+	Refs []inodedb.ChunkRef
+}
+
+// CacheState is implemented by the cache in front of the backend, such as
+// *cachedblobstore.CachedBlobStore.
+type CacheState interface {
+	// ModifiedSince returns true if the cached blob has changes not yet
+	// written back to the backend, or was written after t.
+	ModifiedSince(blobpath string, t time.Time) bool
+}
+
+type Report struct {
+	// Number of backend blobs verified.
+	NumVerified int
+	// Number of backend blobs skipped, as they were being modified in the cache.
+	NumSkipped int
+	// Total bytes of payload verified.
+	NumBytes int64
+	Bad      []BadBlob
+}
+
+func verifyBlob(bs blobstore.BlobStore, c *btncrypt.Cipher, bp string, refs []inodedb.ChunkRef) (int64, error) {
+	// Don't recall blobs in the cold tier just to verify them.
+	r, err := blobstore.OpenReaderNoRecall(bs, bp)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to open backend blob: %v", err)
+	}
+	defer r.Close()
+
+	h, err := chunkstore.VerifyChunk(r, c)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, ref := range refs {
+		if ref.Length > 0 && h.PayloadVersion == 0 {
+			return 0, fmt.Errorf("Backend blob is empty, while file \"%s\" expects chunk length %d", ref.Path, ref.Length)
+		}
+		if int64(h.PayloadLen) != ref.Length {
+			return 0, fmt.Errorf("Payload length %d (version %d) doesn't match chunk length %d of file \"%s\"", h.PayloadLen, h.PayloadVersion, ref.Length, ref.Path)
+		}
+	}
+	return int64(h.PayloadLen), nil
+}
+
+// Scrub reads every blob referenced from idb directly from the backend bs,
+// and verifies its header, authenticity of all its content frames, and its
+// payload length against the inodedb chunk length.
+// If cache is non-nil, the blobs modified in the cache are skipped, as their
+// backend copies are expected to lag behind the inodedb.
+func Scrub(ctx context.Context, bs blobstore.BlobStore, c *btncrypt.Cipher, idb inodedb.DBChunkRefLister, cache CacheState) (*Report, error) {
+	start := time.Now()
+
+	zap.S().Infof("Scrub start. Listing referenced chunks.")
+	refs, errs := idb.ListChunkRefs()
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("ListChunkRefs returned err: %v", errs)
+	}
+
+	refsmap := make(map[string][]inodedb.ChunkRef)
+	for _, ref := range refs {
+		refsmap[ref.BlobPath] = append(refsmap[ref.BlobPath], ref)
+	}
+	bps := make([]string, 0, len(refsmap))
+	for bp := range refsmap {
+		bps = append(bps, bp)
+	}
+	sort.Strings(bps)
+	zap.S().Infof("Found %d referenced blobs.", len(bps))
+
+	report := &Report{}
+	for _, bp := range bps {
+		if err := ctx.Err(); err != nil {
+			zap.S().Infof("Detected cancel. Bailing out.")
+			return report, err
+		}
+
+		if cache != nil && cache.ModifiedSince(bp, start) {
+			zap.S().Infof("Skipping blob \"%s\" modified in the cache.", bp)
+			report.NumSkipped++
+			continue
+		}
+
+		n, err := verifyBlob(bs, c, bp, refsmap[bp])
+		if err != nil && cache != nil && cache.ModifiedSince(bp, start) {
+			// The blob was written while being verified, so its refs listed above may be stale.
+			zap.S().Infof("Skipping blob \"%s\" modified in the cache while being verified: %v", bp, err)
+			report.NumSkipped++
+			continue
+		}
+		report.NumVerified++
+		report.NumBytes += n
+		if err != nil {
+			zap.S().Errorf("Scrub found bad blob \"%s\": %v", bp, err)
+			report.Bad = append(report.Bad, BadBlob{BlobPath: bp, Err: err, Refs: refsmap[bp]})
+		}
+	}
+
+	zap.S().Infof("Scrub done. Verified %d blobs (%d bytes), skipped %d blobs, found %d bad blobs. Took %v.",
+		report.NumVerified, report.NumBytes, report.NumSkipped, len(report.Bad), time.Since(start))
+	return report, nil
+}
@@ -0,0 +1,149 @@
+package scrub_test
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/chunkstore"
+	"github.com/nyaxt/otaru/filesystem"
+	"github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/scrub"
+	tu "github.com/nyaxt/otaru/testutils"
+)
+
+func init() { tu.EnsureLogger() }
+
+var _ = scheduler.Task(&scrub.Task{})
+
+func TestScrub(t *testing.T) {
+	bs := tu.TestFileBlobStore()
+	idb := tu.TestINodeDB()
+	fs := filesystem.NewFileSystem(idb, bs, tu.TestCipher(), zap.L())
+
+	for _, p := range []string{"/ok.txt", "/corrupted.txt", "/missing.txt"} {
+		if err := fs.WriteFile(p, tu.HelloWorld, 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	if err := fs.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	lister := idb.(inodedb.DBChunkRefLister)
+	report, err := scrub.Scrub(context.Background(), bs, tu.TestCipher(), lister, nil)
+	if err != nil {
+		t.Fatalf("Scrub failed: %v", err)
+	}
+	if report.NumVerified != 3 || len(report.Bad) != 0 {
+		t.Errorf("Unexpected report on healthy fs: %+v", report)
+	}
+
+	refs, _ := lister.ListChunkRefs()
+	for _, ref := range refs {
+		switch ref.Path {
+		case "/corrupted.txt":
+			h, err := bs.Open(ref.BlobPath, flags.O_RDWR)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			b := make([]byte, 1)
+			h.PRead(b, h.Size()-1)
+			b[0] ^= 0x01
+			h.PWrite(b, h.Size()-1)
+			h.Close()
+		case "/missing.txt":
+			if err := bs.RemoveBlob(ref.BlobPath); err != nil {
+				t.Fatalf("%v", err)
+			}
+		}
+	}
+
+	report, err = scrub.Scrub(context.Background(), bs, tu.TestCipher(), lister, nil)
+	if err != nil {
+		t.Fatalf("Scrub failed: %v", err)
+	}
+	if report.NumVerified != 3 || len(report.Bad) != 2 {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	badpaths := make(map[string]struct{})
+	for _, b := range report.Bad {
+		for _, ref := range b.Refs {
+			badpaths[ref.Path] = struct{}{}
+		}
+	}
+	for _, p := range []string{"/corrupted.txt", "/missing.txt"} {
+		if _, ok := badpaths[p]; !ok {
+			t.Errorf("Expected \"%s\" to be reported bad: %+v", p, report.Bad)
+		}
+	}
+}
+
+func TestScrub_UnflushedWrite(t *testing.T) {
+	cachedblobstore.DisableAutoSyncForTesting = true
+	defer func() { cachedblobstore.DisableAutoSyncForTesting = false }()
+
+	backendbs := tu.TestFileBlobStoreOfName("backend")
+	cachebs := tu.TestFileBlobStoreOfName("cache")
+	s := scheduler.NewScheduler()
+	defer s.AbortAllAndStop()
+
+	cbs, err := cachedblobstore.New(backendbs, cachebs, s, flags.O_RDWRCREATE, chunkstore.NewQueryChunkVersion(tu.TestCipher()))
+	if err != nil {
+		t.Fatalf("Failed to create CachedBlobStore: %v", err)
+	}
+	defer cbs.Quit()
+
+	idb := tu.TestINodeDB()
+	fs := filesystem.NewFileSystem(idb, cbs, tu.TestCipher(), zap.L())
+
+	if err := fs.WriteFile("/flushed.txt", tu.HelloWorld, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := fs.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if err := cbs.Sync(); err != nil {
+		t.Fatalf("CachedBlobStore Sync failed: %v", err)
+	}
+
+	// The chunk of the file is written to the cache, but not to the backend.
+	if err := fs.WriteFile("/unflushed.txt", tu.HelloWorld, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := fs.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	lister := idb.(inodedb.DBChunkRefLister)
+	report, err := scrub.Scrub(context.Background(), backendbs, tu.TestCipher(), lister, cbs)
+	if err != nil {
+		t.Fatalf("Scrub failed: %v", err)
+	}
+	if report.NumVerified != 1 || report.NumSkipped != 1 || len(report.Bad) != 0 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	// Without the cache state, the blob missing in the backend is reported.
+	report, err = scrub.Scrub(context.Background(), backendbs, tu.TestCipher(), lister, nil)
+	if err != nil {
+		t.Fatalf("Scrub failed: %v", err)
+	}
+	if len(report.Bad) != 1 {
+		t.Errorf("Unexpected report without cache: %+v", report)
+	}
+
+	if err := cbs.Sync(); err != nil {
+		t.Fatalf("CachedBlobStore Sync failed: %v", err)
+	}
+	report, err = scrub.Scrub(context.Background(), backendbs, tu.TestCipher(), lister, cbs)
+	if err != nil {
+		t.Fatalf("Scrub failed: %v", err)
+	}
+	if report.NumVerified != 2 || report.NumSkipped != 0 || len(report.Bad) != 0 {
+		t.Errorf("Unexpected report after sync: %+v", report)
+	}
+}
@@ -0,0 +1,37 @@
+package scrub
+
+import (
+	"fmt"
+
+	"context"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/btncrypt"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/scheduler"
+	"github.com/nyaxt/otaru/util"
+)
+
+type Task struct {
+	BS  blobstore.BlobStore
+	C   *btncrypt.Cipher
+	IDB inodedb.DBChunkRefLister
+	// Cache is optional.
+	Cache CacheState
+}
+
+type Result struct {
+	Report *Report
+	Error  error
+}
+
+func (r Result) Err() error { return r.Error }
+
+func (t *Task) Run(ctx context.Context) scheduler.Result {
+	report, err := Scrub(ctx, t.BS, t.C, t.IDB, t.Cache)
+	return Result{report, err}
+}
+
+func (t *Task) String() string {
+	return fmt.Sprintf("scrub.Task{%s, %s}", util.TryGetImplName(t.BS), util.TryGetImplName(t.IDB))
+}
@@ -30,7 +30,8 @@ func TestFileBlobStoreOfName(name string) *blobstore.FileBlobStore {
 
 func TestQueryVersion(r io.Reader) (version.Version, error) {
 	b := make([]byte, 1)
-	if _, err := r.Read(b); err != nil {
+	// Read may return io.EOF along with the last byte.
+	if n, err := r.Read(b); n == 0 && err != nil {
 		if err == io.EOF {
 			// no data -> ver 0.
 			return 0, nil
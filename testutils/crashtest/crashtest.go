@@ -0,0 +1,495 @@
+// Package crashtest runs filesystem workloads against fault-injected
+// storage, crashes, restarts from the surviving state, and checks invariants.
+package crashtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nyaxt/otaru/blobstore"
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	"github.com/nyaxt/otaru/btncrypt"
+	"github.com/nyaxt/otaru/chunkstore"
+	"github.com/nyaxt/otaru/filesystem"
+	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/inodedb"
+	"github.com/nyaxt/otaru/inodedb/blobstoredbstatesnapshotio"
+	"github.com/nyaxt/otaru/metadata"
+	"github.com/nyaxt/otaru/scheduler"
+	tu "github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/util"
+)
+
+type sslocEntry struct {
+	blobpath string
+	txid     int64
+}
+
+// ssLocator is an in-memory SSLocator which survives crashes.
+type ssLocator struct {
+	mu sync.Mutex
+	es []sslocEntry
+}
+
+func (loc *ssLocator) isEmpty() bool {
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+	return len(loc.es) == 0
+}
+
+// faultInjectSSLocator injects crashes into Put to ssLocator.
+type faultInjectSSLocator struct {
+	loc *ssLocator
+	// Blobstore used only to drive fault injection decision of Put.
+	stepbs tu.FaultInjectBlobStore
+}
+
+func (l faultInjectSSLocator) Locate(history int) (string, int64, error) {
+	l.loc.mu.Lock()
+	defer l.loc.mu.Unlock()
+
+	if history >= len(l.loc.es) {
+		return "", 0, util.ENOENT
+	}
+	e := l.loc.es[len(l.loc.es)-1-history]
+	return e.blobpath, e.txid, nil
+}
+
+func (faultInjectSSLocator) GenerateBlobpath() string {
+	return metadata.GenINodeDBSnapshotBlobpath()
+}
+
+func (l faultInjectSSLocator) Put(blobpath string, txid int64) error {
+	// Put is modeled as removing a non-existent blob, so that it is subject
+	// to the same faults as other writes.
+	if err := l.stepbs.RemoveBlob(blobpath); err != nil && !util.IsNotExist(err) {
+		return err
+	}
+
+	l.loc.mu.Lock()
+	defer l.loc.mu.Unlock()
+	l.loc.es = append(l.loc.es, sslocEntry{blobpath, txid})
+	return nil
+}
+
+func (faultInjectSSLocator) DeleteOld(ctx context.Context, threshold int, dryRun bool) ([]string, error) {
+	return nil, nil
+}
+
+// Env holds the durable state which survives crashes of Instances.
+type Env struct {
+	C *btncrypt.Cipher
+
+	// Simulates the remote backend.
+	BackendBS *blobstore.FileBlobStore
+	// Simulates the local cache dir.
+	CacheBS *blobstore.FileBlobStore
+	TxLog   *inodedb.SimpleDBTransactionLogIO
+
+	// If true, writes to the local cache torn by a crash persist partially.
+	TornCacheWrites bool
+
+	sslocbs *blobstore.FileBlobStore
+	ssloc   *ssLocator
+}
+
+func NewEnv() *Env {
+	return &Env{
+		C:         tu.TestCipher(),
+		BackendBS: tu.TestFileBlobStoreOfName("backend"),
+		CacheBS:   tu.TestFileBlobStoreOfName("cache"),
+		TxLog:     inodedb.NewSimpleDBTransactionLogIO(),
+		sslocbs:   tu.TestFileBlobStoreOfName("ssloc"),
+		ssloc:     &ssLocator{},
+	}
+}
+
+// Instance is an otaru instance running on top of Env with faults injected.
+type Instance struct {
+	FI   *tu.FaultInjector
+	S    *scheduler.Scheduler
+	CBS  *cachedblobstore.CachedBlobStore
+	TxIO *tu.FaultInjectTxLogIO
+	IDB  *inodedb.DB
+	FS   *filesystem.FileSystem
+}
+
+// Start starts an Instance from the current state of env. Faults are
+// injected into the backend and the txlog as decided by fi. The local cache
+// only fails on crash.
+// Writes to the local cache are atomic unless env.TornCacheWrites, as the cache
+// isn't journaled and a cache write torn by a crash corrupts the cached blob.
+func (env *Env) Start(fi *tu.FaultInjector) (*Instance, error) {
+	inst := &Instance{FI: fi}
+
+	backendbs := tu.FaultInjectBlobStore{BE: env.BackendBS, FI: fi, AtomicWrites: true}
+	cachebs := tu.FaultInjectBlobStore{BE: env.CacheBS, FI: fi, CrashOnly: true, AtomicWrites: !env.TornCacheWrites}
+
+	inst.S = scheduler.NewScheduler()
+
+	var err error
+	inst.CBS, err = cachedblobstore.New(backendbs, cachebs, inst.S, fl.O_RDWRCREATE, chunkstore.NewQueryChunkVersion(env.C))
+	if err != nil {
+		inst.S.AbortAllAndStop()
+		return nil, fmt.Errorf("Failed to init CachedBlobStore: %v", err)
+	}
+	if err := inst.CBS.RestoreState(env.C); err != nil {
+		zap.S().Infof("crashtest: CachedBlobStore state not restored: %v", err)
+	}
+
+	loc := faultInjectSSLocator{
+		loc:    env.ssloc,
+		stepbs: tu.FaultInjectBlobStore{BE: env.sslocbs, FI: fi, CrashOnly: true},
+	}
+	sio := blobstoredbstatesnapshotio.New(inst.CBS, env.C, loc)
+	inst.TxIO = &tu.FaultInjectTxLogIO{BE: env.TxLog, FI: fi}
+
+	if env.ssloc.isEmpty() {
+		inst.IDB, err = inodedb.NewEmptyDB(sio, inst.TxIO)
+	} else {
+		inst.IDB, err = inodedb.NewDB(sio, inst.TxIO, false)
+	}
+	if err != nil {
+		inst.Crash()
+		return nil, fmt.Errorf("Failed to init inodedb: %v", err)
+	}
+
+	inst.FS = filesystem.NewFileSystem(inst.IDB, inst.CBS, env.C, zap.L())
+	return inst, nil
+}
+
+// Sync makes all operations so far durable. This is the durability point
+// the invariants are checked against.
+func (inst *Instance) Sync() error {
+	if err := inst.FS.Sync(); err != nil {
+		return err
+	}
+	return inst.TxIO.Sync()
+}
+
+// Crash kills the instance. No operations on the underlying stores are made
+// by the instance after Crash returns.
+func (inst *Instance) Crash() {
+	inst.FI.Crash()
+	if inst.CBS != nil {
+		inst.CBS.Quit()
+	}
+	inst.S.AbortAllAndStop()
+}
+
+// Shutdown cleanly shuts down the instance.
+func (inst *Instance) Shutdown(c *btncrypt.Cipher) error {
+	var me error
+	if err := inst.Sync(); err != nil {
+		me = multierr.Append(me, err)
+	}
+	if err := inst.CBS.SaveState(c); err != nil {
+		me = multierr.Append(me, err)
+	}
+	if err := inst.CBS.Quit(); err != nil {
+		me = multierr.Append(me, err)
+	}
+	inst.S.AbortAllAndStop()
+	return me
+}
+
+// Model tracks the expected filesystem content.
+type Model struct {
+	// File content as of the last durability point.
+	Durable map[string][]byte
+	// File content as of now. nil content means the content is unknown, as
+	// an operation on the file failed.
+	Current map[string][]byte
+	// Files modified since the last durability point.
+	Touched map[string]struct{}
+}
+
+func NewModel() *Model {
+	return &Model{
+		Durable: make(map[string][]byte),
+		Current: make(map[string][]byte),
+		Touched: make(map[string]struct{}),
+	}
+}
+
+func (m *Model) touch(path string, content []byte, exists bool) {
+	m.Touched[path] = struct{}{}
+	if exists {
+		m.Current[path] = content
+	} else {
+		delete(m.Current, path)
+	}
+}
+
+func (m *Model) markDurable() {
+	m.Durable = make(map[string][]byte)
+	for path, content := range m.Current {
+		m.Durable[path] = content
+	}
+	m.Touched = make(map[string]struct{})
+	for path, content := range m.Current {
+		if content == nil {
+			// Still unknown.
+			m.Touched[path] = struct{}{}
+		}
+	}
+}
+
+func (m *Model) existingPaths() []string {
+	ps := make([]string, 0, len(m.Current))
+	for p := range m.Current {
+		ps = append(ps, p)
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+const (
+	numFileNames   = 8
+	maxFileSize    = 200 * 1024
+	maxAppendSize  = 16 * 1024
+	syncEveryNOps  = 5
+	writebackEvery = 7
+)
+
+func writeFile(fs *filesystem.FileSystem, path string, content []byte) error {
+	h, err := fs.OpenFileFullPath(path, fl.O_RDWRCREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	if err := h.Truncate(0); err != nil {
+		return err
+	}
+	if err := h.PWrite(content, 0); err != nil {
+		return err
+	}
+	// FileHandle.Close doesn't report write cache sync failure.
+	return h.Sync()
+}
+
+func appendFile(fs *filesystem.FileSystem, path string, content []byte) error {
+	h, err := fs.OpenFileFullPath(path, fl.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	if err := h.PWrite(content, h.Size()); err != nil {
+		return err
+	}
+	return h.Sync()
+}
+
+// ErrShortRead is returned from ReadFile if the file content is shorter than
+// its size. This happens when a write cache sync failed after the size update.
+var ErrShortRead = errors.New("Short read")
+
+// ReadFile reads the whole content of the file at path. On ErrShortRead, the
+// content read is also returned.
+func ReadFile(fs *filesystem.FileSystem, path string) ([]byte, error) {
+	h, err := fs.OpenFileFullPath(path, fl.O_RDONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer h.Close()
+
+	buf := make([]byte, h.Size())
+	n, err := h.ReadAt(buf, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(buf) {
+		return buf[:n], fmt.Errorf("%w: %d < %d", ErrShortRead, n, len(buf))
+	}
+	return buf, nil
+}
+
+// RunWorkload runs numOps random filesystem operations decided by rnd on
+// inst, tracking the expected content in m. It returns when all operations
+// are done, or the instance crashed.
+func RunWorkload(inst *Instance, rnd *rand.Rand, numOps int, m *Model) {
+	fs := inst.FS
+	for i := 0; i < numOps; i++ {
+		if inst.FI.Crashed() {
+			return
+		}
+
+		if i%syncEveryNOps == syncEveryNOps-1 {
+			if err := inst.Sync(); err == nil {
+				m.markDurable()
+			}
+			continue
+		}
+		if i%writebackEvery == writebackEvery-1 {
+			inst.CBS.Sync()
+			continue
+		}
+
+		path := fmt.Sprintf("/f%d", rnd.Intn(numFileNames))
+		existing, exists := m.Current[path]
+		switch op := rnd.Intn(4); {
+		case op == 0 || !exists:
+			content := make([]byte, rnd.Intn(maxFileSize))
+			rnd.Read(content)
+			if err := writeFile(fs, path, content); err != nil {
+				content = nil
+			}
+			m.touch(path, content, true)
+
+		case op == 1:
+			content := make([]byte, rnd.Intn(maxAppendSize))
+			rnd.Read(content)
+			var newContent []byte
+			if err := appendFile(fs, path, content); err == nil && existing != nil {
+				newContent = append(append([]byte{}, existing...), content...)
+			}
+			m.touch(path, newContent, true)
+
+		case op == 2:
+			if err := fs.Remove(inodedb.RootDirID, path[1:]); err != nil {
+				m.touch(path, nil, true)
+			} else {
+				m.touch(path, nil, false)
+			}
+
+		case op == 3:
+			dst := fmt.Sprintf("/f%d", rnd.Intn(numFileNames))
+			if _, ok := m.Current[dst]; ok {
+				continue
+			}
+			if err := fs.Rename(inodedb.RootDirID, path[1:], inodedb.RootDirID, dst[1:]); err != nil {
+				m.touch(path, nil, true)
+				m.touch(dst, nil, true)
+			} else {
+				m.touch(path, nil, false)
+				m.touch(dst, existing, true)
+			}
+		}
+	}
+}
+
+// Check restarts an instance from env without faults, and checks:
+//   - inodedb can be restored, and DB.Fsck finds no error.
+//   - all files are readable, and files untouched since the last durability
+//     point have the durable content. Touched files may be short or corrupted,
+//     as the local cache isn't crash consistent.
+//   - after a clean shutdown, all chunks referenced from inodedb are valid in the backend.
+//
+// On success, m is updated to the restored state.
+func (env *Env) Check(m *Model) error {
+	inst, err := env.Start(tu.NewFaultInjector(0))
+	if err != nil {
+		return fmt.Errorf("Failed to restart: %v", err)
+	}
+
+	var me error
+	if _, errs := inst.IDB.Fsck(); len(errs) > 0 {
+		me = multierr.Append(me, fmt.Errorf("Fsck failed: %v", multierr.Combine(errs...)))
+	}
+
+	entries, err := inst.FS.DirEntries(inodedb.RootDirID)
+	if err != nil {
+		inst.Crash()
+		return fmt.Errorf("Failed to list root dir: %v", err)
+	}
+	restored := make(map[string][]byte)
+	// Chunks of files corrupted by the crash. These are excluded from backend verification.
+	corruptedChunks := make(map[string]struct{})
+	for name, id := range entries {
+		path := "/" + name
+		content, err := ReadFile(inst.FS, path)
+		if _, touched := m.Touched[path]; touched && err != nil {
+			if errors.Is(err, ErrShortRead) {
+				restored[path] = content
+				continue
+			}
+
+			// The cache isn't crash consistent, so a crash may corrupt files
+			// written since the last durability point.
+			restored[path] = nil
+			if v, _, err := inst.IDB.QueryNode(id, false); err == nil {
+				if fv, ok := v.(*inodedb.FileNodeView); ok {
+					for _, c := range fv.Chunks {
+						corruptedChunks[c.BlobPath] = struct{}{}
+					}
+				}
+			}
+			continue
+		}
+		if err != nil {
+			me = multierr.Append(me, fmt.Errorf("Failed to read \"%s\": %v", path, err))
+			continue
+		}
+		restored[path] = content
+	}
+
+	for path, content := range restored {
+		if _, touched := m.Touched[path]; touched {
+			continue
+		}
+		expected, ok := m.Durable[path]
+		if !ok {
+			me = multierr.Append(me, fmt.Errorf("Unexpected file \"%s\" found", path))
+		} else if !bytes.Equal(content, expected) {
+			me = multierr.Append(me, fmt.Errorf("Content mismatch for \"%s\": len %d, expected len %d", path, len(content), len(expected)))
+		}
+	}
+	for path := range m.Durable {
+		if _, touched := m.Touched[path]; touched {
+			continue
+		}
+		if _, ok := restored[path]; !ok {
+			me = multierr.Append(me, fmt.Errorf("Durable file \"%s\" lost", path))
+		}
+	}
+
+	if err := inst.Shutdown(env.C); err != nil {
+		me = multierr.Append(me, fmt.Errorf("Failed to shutdown: %v", err))
+	}
+	if me != nil {
+		return me
+	}
+
+	if err := env.verifyBackend(inst.IDB, corruptedChunks); err != nil {
+		return err
+	}
+
+	m.Current = restored
+	m.markDurable()
+	return nil
+}
+
+func (env *Env) verifyBackend(idb *inodedb.DB, skip map[string]struct{}) error {
+	bps, errs := idb.Fsck()
+	if len(errs) > 0 {
+		return multierr.Combine(errs...)
+	}
+
+	verify := chunkstore.NewVerifyChunk(env.C)
+	var me error
+	for _, bp := range bps {
+		if _, ok := skip[bp]; ok {
+			continue
+		}
+		r, err := env.BackendBS.OpenReader(bp)
+		if err != nil {
+			me = multierr.Append(me, fmt.Errorf("Chunk \"%s\" missing in backend: %v", bp, err))
+			continue
+		}
+		if _, err := verify(r); err != nil {
+			me = multierr.Append(me, fmt.Errorf("Chunk \"%s\" corrupted in backend: %v", bp, err))
+		}
+		r.Close()
+	}
+	return me
+}
@@ -0,0 +1,63 @@
+package crashtest_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nyaxt/otaru/blobstore/cachedblobstore"
+	tu "github.com/nyaxt/otaru/testutils"
+	"github.com/nyaxt/otaru/testutils/crashtest"
+)
+
+func init() {
+	tu.EnsureLogger()
+	cachedblobstore.PrometheusRegisterer = nil
+	// Writeback only on explicit CachedBlobStore.Sync for determinism. This
+	// also lets CacheSyncer quit with entries which can no longer be written back.
+	cachedblobstore.DisableAutoSyncForTesting = true
+}
+
+const numOpsPerRun = 40
+
+func runCrashLoop(t *testing.T, seed int64, killSteps []int, errorRate, tornWriteRate float64) {
+	env := crashtest.NewEnv()
+	m := crashtest.NewModel()
+	rnd := rand.New(rand.NewSource(seed))
+
+	for i, killAt := range killSteps {
+		fi := tu.NewFaultInjector(seed + int64(i))
+		fi.ErrorRate = errorRate
+		fi.TornWriteRate = tornWriteRate
+		fi.KillAtStep = killAt
+
+		inst, err := env.Start(fi)
+		if err != nil {
+			if !fi.Crashed() {
+				t.Fatalf("seed %d run %d: Start failed: %v", seed, i, err)
+			}
+		} else {
+			crashtest.RunWorkload(inst, rnd, numOpsPerRun, m)
+			inst.Crash()
+		}
+
+		if err := env.Check(m); err != nil {
+			t.Fatalf("seed %d run %d (kill at step %d, %d steps): %v", seed, i, killAt, fi.Steps(), err)
+		}
+	}
+}
+
+func TestCrash_NoFault(t *testing.T) {
+	runCrashLoop(t, 1, []int{0, 0}, 0, 0)
+}
+
+func TestCrash_KillAtStep(t *testing.T) {
+	for seed := int64(1); seed <= 4; seed++ {
+		runCrashLoop(t, seed, []int{5, 20, 50, 100, 200}, 0, 0)
+	}
+}
+
+func TestCrash_ErrorsAndTornWrites(t *testing.T) {
+	for seed := int64(1); seed <= 4; seed++ {
+		runCrashLoop(t, seed, []int{30, 80, 150, 0}, 0.05, 0.05)
+	}
+}
@@ -0,0 +1,504 @@
+// Package fakegcs implements an in-process fake of the Google Cloud Storage
+// JSON API, sufficient for cloud.google.com/go/storage clients used by
+// otaru: object upload (multipart and resumable), download, attrs, list and
+// delete. Buckets are created implicitly on first use.
+package fakegcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type object struct {
+	data        []byte
+	contentType string
+	generation  int64
+	updated     time.Time
+}
+
+type upload struct {
+	bucket      string
+	name        string
+	contentType string
+	buf         bytes.Buffer
+}
+
+type Server struct {
+	srv *httptest.Server
+
+	mu         sync.Mutex
+	buckets    map[string]map[string]*object
+	uploads    map[string]*upload
+	generation int64
+	uploadID   int
+}
+
+// NewServer starts a fake GCS server listening on a local port.
+// The caller should Close the server when done.
+func NewServer() *Server {
+	s := &Server{
+		buckets: make(map[string]map[string]*object),
+		uploads: make(map[string]*upload),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the endpoint of the server, to be given to gcs.NewGCSBlobStore.
+func (s *Server) URL() string { return s.srv.URL }
+
+func (s *Server) Close() { s.srv.Close() }
+
+// ObjectNames returns the names of objects in the bucket in lexicographical order.
+func (s *Server) ObjectNames(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.listLocked(bucket, "")
+}
+
+func (s *Server) listLocked(bucket, prefix string) []string {
+	names := make([]string, 0, len(s.buckets[bucket]))
+	for name := range s.buckets[bucket] {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Server) putLocked(bucket, name, contentType string, data []byte) *object {
+	b, ok := s.buckets[bucket]
+	if !ok {
+		b = make(map[string]*object)
+		s.buckets[bucket] = b
+	}
+	s.generation++
+	o := &object{
+		data:        data,
+		contentType: contentType,
+		generation:  s.generation,
+		updated:     time.Now().UTC(),
+	}
+	b[name] = o
+	return o
+}
+
+type objectResource struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Bucket      string `json:"bucket"`
+	Size        string `json:"size"`
+	ContentType string `json:"contentType,omitempty"`
+	Generation  string `json:"generation"`
+	Updated     string `json:"updated"`
+}
+
+func toResource(bucket, name string, o *object) objectResource {
+	return objectResource{
+		Kind:        "storage#object",
+		Name:        name,
+		Bucket:      bucket,
+		Size:        strconv.Itoa(len(o.data)),
+		ContentType: o.contentType,
+		Generation:  strconv.FormatInt(o.generation, 10),
+		Updated:     o.updated.Format(time.RFC3339Nano),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": msg,
+		},
+	})
+}
+
+// splitEscapedPath splits the escaped path p into unescaped segments.
+func splitEscapedPath(p string) ([]string, error) {
+	segs := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i, seg := range segs {
+		var err error
+		if segs[i], err = url.PathUnescape(seg); err != nil {
+			return nil, err
+		}
+	}
+	return segs, nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	segs, err := splitEscapedPath(r.URL.EscapedPath())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch {
+	case len(segs) == 6 && segs[0] == "upload" && segs[1] == "storage" && segs[2] == "v1" && segs[3] == "b" && segs[5] == "o":
+		s.serveUpload(w, r, segs[4])
+	case len(segs) >= 5 && segs[0] == "storage" && segs[1] == "v1" && segs[2] == "b" && segs[4] == "o":
+		if len(segs) == 5 {
+			s.serveList(w, r, segs[3])
+		} else {
+			s.serveObject(w, r, segs[3], strings.Join(segs[5:], "/"))
+		}
+	case len(segs) >= 2 && segs[0] != "storage" && segs[0] != "upload":
+		s.serveDownload(w, r, segs[0], strings.Join(segs[1:], "/"))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown path %q", r.URL.Path))
+	}
+}
+
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request, bucket string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	maxResults := 1000
+	if mr := q.Get("maxResults"); mr != "" {
+		if n, err := strconv.Atoi(mr); err == nil && n > 0 && n < maxResults {
+			maxResults = n
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := s.listLocked(bucket, q.Get("prefix"))
+	if pt := q.Get("pageToken"); pt != "" {
+		i := sort.SearchStrings(names, pt)
+		names = names[i:]
+	}
+	var nextPageToken string
+	if len(names) > maxResults {
+		nextPageToken = names[maxResults]
+		names = names[:maxResults]
+	}
+
+	items := make([]objectResource, 0, len(names))
+	for _, name := range names {
+		items = append(items, toResource(bucket, name, s.buckets[bucket][name]))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"kind":          "storage#objects",
+		"items":         items,
+		"nextPageToken": nextPageToken,
+	})
+}
+
+func (s *Server) serveObject(w http.ResponseWriter, r *http.Request, bucket, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.buckets[bucket][name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such object")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if r.URL.Query().Get("alt") == "media" {
+			w.Header().Set("Content-Type", o.contentType)
+			w.Write(o.data)
+			return
+		}
+		writeJSON(w, http.StatusOK, toResource(bucket, name, o))
+	case http.MethodDelete:
+		delete(s.buckets[bucket], name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// parseRange parses the Range header value h for an object of size. Returns
+// the [start, end) byte range, or ok = false if the range isn't satisfiable.
+func parseRange(h string, size int) (start, end int, ok bool) {
+	if h == "" {
+		return 0, size, true
+	}
+	spec := strings.TrimPrefix(h, "bytes=")
+	if spec == h || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	if strings.HasPrefix(spec, "-") {
+		n, err := strconv.Atoi(spec[1:])
+		if err != nil {
+			return 0, 0, false
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, size, true
+	}
+	ss := strings.SplitN(spec, "-", 2)
+	start, err := strconv.Atoi(ss[0])
+	if err != nil || start >= size {
+		return 0, 0, false
+	}
+	end = size
+	if len(ss) == 2 && ss[1] != "" {
+		last, err := strconv.Atoi(ss[1])
+		if err != nil || last < start {
+			return 0, 0, false
+		}
+		if last+1 < end {
+			end = last + 1
+		}
+	}
+	return start, end, true
+}
+
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request, bucket, name string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	s.mu.Lock()
+	o, ok := s.buckets[bucket][name]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such object")
+		return
+	}
+	if g := r.URL.Query().Get("generation"); g != "" && g != strconv.FormatInt(o.generation, 10) {
+		writeError(w, http.StatusNotFound, "No such object generation")
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", o.contentType)
+	h.Set("Last-Modified", o.updated.Format(http.TimeFormat))
+	h.Set("X-Goog-Generation", strconv.FormatInt(o.generation, 10))
+	h.Set("X-Goog-Metageneration", "1")
+
+	size := len(o.data)
+	start, end, ok := parseRange(r.Header.Get("Range"), size)
+	if !ok {
+		h.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+		writeError(w, http.StatusRequestedRangeNotSatisfiable, "Requested range not satisfiable")
+		return
+	}
+	h.Set("Content-Length", strconv.Itoa(end-start))
+	if r.Header.Get("Range") != "" {
+		h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, size))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	if r.Method == http.MethodGet {
+		w.Write(o.data[start:end])
+	}
+}
+
+type uploadMetadata struct {
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+}
+
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request, bucket string) {
+	q := r.URL.Query()
+	switch {
+	case (r.Method == http.MethodPut || r.Method == http.MethodPost) && q.Get("upload_id") != "":
+		s.serveResumableUploadChunk(w, r, q.Get("upload_id"))
+	case r.Method == http.MethodPost && q.Get("uploadType") == "multipart":
+		s.serveMultipartUpload(w, r, bucket)
+	case r.Method == http.MethodPost && q.Get("uploadType") == "resumable":
+		s.serveStartResumableUpload(w, r, bucket)
+	case r.Method == http.MethodPost && q.Get("uploadType") == "media":
+		name := q.Get("name")
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.commitUpload(w, bucket, name, r.Header.Get("Content-Type"), data)
+	default:
+		writeError(w, http.StatusBadRequest, "Unsupported upload request")
+	}
+}
+
+func (s *Server) commitUpload(w http.ResponseWriter, bucket, name, contentType string, data []byte) {
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Object name must be given")
+		return
+	}
+
+	s.mu.Lock()
+	o := s.putLocked(bucket, name, contentType, data)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, toResource(bucket, name, o))
+}
+
+func (s *Server) serveMultipartUpload(w http.ResponseWriter, r *http.Request, bucket string) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to parse Content-Type: %v", err))
+		return
+	}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+
+	metapart, err := mr.NextPart()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to read metadata part: %v", err))
+		return
+	}
+	var md uploadMetadata
+	if err := json.NewDecoder(metapart).Decode(&md); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to decode metadata: %v", err))
+		return
+	}
+
+	mediapart, err := mr.NextPart()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to read media part: %v", err))
+		return
+	}
+	data, err := ioutil.ReadAll(mediapart)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to read media: %v", err))
+		return
+	}
+	if md.ContentType == "" {
+		md.ContentType = mediapart.Header.Get("Content-Type")
+	}
+
+	s.commitUpload(w, bucket, md.Name, md.ContentType, data)
+}
+
+func (s *Server) serveStartResumableUpload(w http.ResponseWriter, r *http.Request, bucket string) {
+	var md uploadMetadata
+	if err := json.NewDecoder(r.Body).Decode(&md); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to decode metadata: %v", err))
+		return
+	}
+	if md.Name == "" {
+		md.Name = r.URL.Query().Get("name")
+	}
+	if md.ContentType == "" {
+		md.ContentType = r.Header.Get("X-Upload-Content-Type")
+	}
+
+	s.mu.Lock()
+	s.uploadID++
+	id := strconv.Itoa(s.uploadID)
+	s.uploads[id] = &upload{bucket: bucket, name: md.Name, contentType: md.ContentType}
+	s.mu.Unlock()
+
+	loc := *r.URL
+	loc.Scheme = "http"
+	loc.Host = r.Host
+	q := loc.Query()
+	q.Set("upload_id", id)
+	loc.RawQuery = q.Encode()
+	w.Header().Set("Location", loc.String())
+	w.WriteHeader(http.StatusOK)
+}
+
+// parseContentRange parses the Content-Range header of a resumable upload
+// chunk, e.g. "bytes 0-1023/*", "bytes 1024-2047/2048" or "bytes */2048".
+// Returns the offset of the chunk and the total size, or -1 if unknown.
+func parseContentRange(h string) (offset int64, total int64, err error) {
+	spec := strings.TrimPrefix(h, "bytes ")
+	if spec == h {
+		return 0, 0, fmt.Errorf("Invalid Content-Range %q", h)
+	}
+	ss := strings.SplitN(spec, "/", 2)
+	if len(ss) != 2 {
+		return 0, 0, fmt.Errorf("Invalid Content-Range %q", h)
+	}
+
+	total = -1
+	if ss[1] != "*" {
+		if total, err = strconv.ParseInt(ss[1], 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("Invalid Content-Range %q", h)
+		}
+	}
+	if ss[0] == "*" {
+		return -1, total, nil
+	}
+	rs := strings.SplitN(ss[0], "-", 2)
+	if offset, err = strconv.ParseInt(rs[0], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("Invalid Content-Range %q", h)
+	}
+	return offset, total, nil
+}
+
+func (s *Server) serveResumableUploadChunk(w http.ResponseWriter, r *http.Request, id string) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.uploads[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such upload")
+		return
+	}
+
+	total := int64(-1)
+	if h := r.Header.Get("Content-Range"); h != "" {
+		var offset int64
+		offset, total, err = parseContentRange(h)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if offset >= 0 && offset != int64(u.buf.Len()) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Chunk offset %d doesn't match uploaded size %d", offset, u.buf.Len()))
+			return
+		}
+	} else {
+		total = int64(len(data))
+	}
+	u.buf.Write(data)
+
+	if total < 0 || int64(u.buf.Len()) < total {
+		if u.buf.Len() > 0 {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", u.buf.Len()-1))
+		}
+		w.Header().Set("Content-Length", "0")
+		if r.Header.Get("X-GUploader-No-308") == "yes" {
+			// See google.golang.org/api/internal/gensupport.
+			w.Header().Set("X-HTTP-Status-Code-Override", "308")
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusPermanentRedirect)
+		}
+		return
+	}
+
+	delete(s.uploads, id)
+	o := s.putLocked(u.bucket, u.name, u.contentType, u.buf.Bytes())
+	writeJSON(w, http.StatusOK, toResource(u.bucket, u.name, o))
+}
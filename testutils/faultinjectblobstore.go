@@ -0,0 +1,283 @@
+package testutils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/nyaxt/otaru/blobstore"
+	fl "github.com/nyaxt/otaru/flags"
+	"github.com/nyaxt/otaru/util"
+)
+
+// FaultInjectBlobStore injects faults decided by FI into operations on BE.
+// Torn writes persist a prefix of the data to BE before failing.
+type FaultInjectBlobStore struct {
+	BE blobstore.BlobStore
+	FI *FaultInjector
+	// If true, only crashes are injected. Useful for local storage which is
+	// expected to fail only on crash.
+	CrashOnly bool
+	// If true, writes never tear. Blobs written via OpenWriter only become
+	// visible on successful Close, as with cloud storage backends.
+	AtomicWrites bool
+}
+
+var _ = blobstore.BlobStore(FaultInjectBlobStore{})
+var _ = blobstore.RandomAccessBlobStore(FaultInjectBlobStore{})
+
+func (bs FaultInjectBlobStore) begin(write bool, datalen int) (fault, func()) {
+	return bs.FI.begin(write, bs.CrashOnly, datalen)
+}
+
+type faultInjectWriter struct {
+	w  io.WriteCloser
+	bs FaultInjectBlobStore
+}
+
+func (w faultInjectWriter) Write(p []byte) (int, error) {
+	f, end := w.bs.begin(true, len(p))
+	defer end()
+
+	if f.err == nil {
+		return w.w.Write(p)
+	}
+	if f.torn {
+		n, _ := w.w.Write(p[:f.tornLen])
+		return n, f.err
+	}
+	return 0, f.err
+}
+
+func (w faultInjectWriter) Close() error {
+	f, end := w.bs.begin(false, 0)
+	defer end()
+
+	// Release the underlying writer regardless of the fault.
+	if err := w.w.Close(); err != nil {
+		return err
+	}
+	return f.err
+}
+
+type atomicWriter struct {
+	buf      bytes.Buffer
+	blobpath string
+	bs       FaultInjectBlobStore
+	err      error
+}
+
+func (w *atomicWriter) Write(p []byte) (int, error) {
+	f, end := w.bs.begin(true, len(p))
+	defer end()
+
+	if f.err != nil {
+		// The blob is never committed after a failed write.
+		w.err = f.err
+		return 0, f.err
+	}
+	return w.buf.Write(p)
+}
+
+func (w *atomicWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	f, end := w.bs.begin(true, 0)
+	defer end()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	bew, err := w.bs.BE.OpenWriter(w.blobpath)
+	if err != nil {
+		return err
+	}
+	if _, err := bew.Write(w.buf.Bytes()); err != nil {
+		bew.Close()
+		return err
+	}
+	return bew.Close()
+}
+
+func (bs FaultInjectBlobStore) OpenWriter(blobpath string) (io.WriteCloser, error) {
+	f, end := bs.begin(true, 0)
+	defer end()
+
+	if f.err != nil {
+		if f.torn && !bs.AtomicWrites {
+			// Opening a writer truncates the blob on some backends.
+			if w, err := bs.BE.OpenWriter(blobpath); err == nil {
+				w.Close()
+			}
+		}
+		return nil, f.err
+	}
+	if bs.AtomicWrites {
+		return &atomicWriter{blobpath: blobpath, bs: bs}, nil
+	}
+	w, err := bs.BE.OpenWriter(blobpath)
+	if err != nil {
+		return nil, err
+	}
+	return faultInjectWriter{w, bs}, nil
+}
+
+type faultInjectReader struct {
+	r  io.ReadCloser
+	bs FaultInjectBlobStore
+}
+
+func (r faultInjectReader) Read(p []byte) (int, error) {
+	f, end := r.bs.begin(false, 0)
+	defer end()
+
+	if f.err != nil {
+		return 0, f.err
+	}
+	return r.r.Read(p)
+}
+
+func (r faultInjectReader) Close() error {
+	return r.r.Close()
+}
+
+func (bs FaultInjectBlobStore) OpenReader(blobpath string) (io.ReadCloser, error) {
+	f, end := bs.begin(false, 0)
+	defer end()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	r, err := bs.BE.OpenReader(blobpath)
+	if err != nil {
+		return nil, err
+	}
+	return faultInjectReader{r, bs}, nil
+}
+
+type faultInjectBlobHandle struct {
+	bh blobstore.BlobHandle
+	bs FaultInjectBlobStore
+}
+
+func (h faultInjectBlobHandle) PRead(p []byte, offset int64) error {
+	f, end := h.bs.begin(false, 0)
+	defer end()
+
+	if f.err != nil {
+		return f.err
+	}
+	return h.bh.PRead(p, offset)
+}
+
+func (h faultInjectBlobHandle) PWrite(p []byte, offset int64) error {
+	f, end := h.bs.begin(true, len(p))
+	defer end()
+
+	if f.err == nil {
+		return h.bh.PWrite(p, offset)
+	}
+	if f.torn && f.tornLen > 0 && !h.bs.AtomicWrites {
+		h.bh.PWrite(p[:f.tornLen], offset)
+	}
+	return f.err
+}
+
+func (h faultInjectBlobHandle) Size() int64 {
+	return h.bh.Size()
+}
+
+func (h faultInjectBlobHandle) Truncate(size int64) error {
+	f, end := h.bs.begin(true, 0)
+	defer end()
+
+	if f.err != nil {
+		return f.err
+	}
+	return h.bh.Truncate(size)
+}
+
+func (h faultInjectBlobHandle) Close() error {
+	return h.bh.Close()
+}
+
+func (bs FaultInjectBlobStore) Open(blobpath string, flags int) (blobstore.BlobHandle, error) {
+	rabs, ok := bs.BE.(blobstore.RandomAccessBlobStore)
+	if !ok {
+		return nil, fmt.Errorf("Backend blobstore \"%s\" don't support Open()", util.TryGetImplName(bs.BE))
+	}
+
+	f, end := bs.begin(fl.IsWriteAllowed(flags), 0)
+	defer end()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	bh, err := rabs.Open(blobpath, flags)
+	if err != nil {
+		return nil, err
+	}
+	return faultInjectBlobHandle{bh, bs}, nil
+}
+
+func (bs FaultInjectBlobStore) Flags() int {
+	if fr, ok := bs.BE.(fl.FlagsReader); ok {
+		return fr.Flags()
+	}
+	return fl.O_RDWRCREATE
+}
+
+var _ = blobstore.BlobLister(FaultInjectBlobStore{})
+
+func (bs FaultInjectBlobStore) ListBlobs() ([]string, error) {
+	f, end := bs.begin(false, 0)
+	defer end()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	return bs.BE.(blobstore.BlobLister).ListBlobs()
+}
+
+var _ = blobstore.BlobSizer(FaultInjectBlobStore{})
+
+func (bs FaultInjectBlobStore) BlobSize(blobpath string) (int64, error) {
+	f, end := bs.begin(false, 0)
+	defer end()
+
+	if f.err != nil {
+		return -1, f.err
+	}
+	return bs.BE.(blobstore.BlobSizer).BlobSize(blobpath)
+}
+
+var _ = blobstore.BlobRemover(FaultInjectBlobStore{})
+
+func (bs FaultInjectBlobStore) RemoveBlob(blobpath string) error {
+	f, end := bs.begin(true, 0)
+	defer end()
+
+	if f.err != nil {
+		return f.err
+	}
+	return bs.BE.(blobstore.BlobRemover).RemoveBlob(blobpath)
+}
+
+var _ = blobstore.TotalSizer(FaultInjectBlobStore{})
+
+func (bs FaultInjectBlobStore) TotalSize() (int64, error) {
+	f, end := bs.begin(false, 0)
+	defer end()
+
+	if f.err != nil {
+		return 0, f.err
+	}
+	return bs.BE.(blobstore.TotalSizer).TotalSize()
+}
+
+func (bs FaultInjectBlobStore) ImplName() string {
+	return fmt.Sprintf("FaultInjectBlobStore{%s}", util.TryGetImplName(bs.BE))
+}
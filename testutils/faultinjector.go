@@ -0,0 +1,107 @@
+package testutils
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+var (
+	ErrInjectedFault = errors.New("Injected fault.")
+	ErrCrashed       = errors.New("Injected crash. No further operations are allowed.")
+)
+
+// FaultInjector decides faults of operations on FaultInjectBlobStore and
+// FaultInjectTxLogIO. Faults are deterministic for a seed, given the same
+// sequence of operations.
+type FaultInjector struct {
+	// Probability an operation fails without any effect.
+	ErrorRate float64
+	// Probability a write persists only a prefix of its data before failing.
+	TornWriteRate float64
+	// Crash at the KillAtStep-th operation. The write at the step is torn,
+	// and all operations after that fail with ErrCrashed. Never crash if 0.
+	KillAtStep int
+
+	// Held for reading by in-flight operations, so that Crash() can wait for them.
+	inflight sync.RWMutex
+
+	mu      sync.Mutex
+	rnd     *rand.Rand
+	step    int
+	crashed bool
+}
+
+func NewFaultInjector(seed int64) *FaultInjector {
+	return &FaultInjector{rnd: rand.New(rand.NewSource(seed))}
+}
+
+type fault struct {
+	// If non-nil, the operation should fail with err.
+	err error
+	// If true, a write operation should persist tornLen bytes of its data before failing.
+	torn    bool
+	tornLen int
+}
+
+// begin decides the fault of an operation. If crashOnly, only the crash
+// fault is injected. The returned end func must be called after the operation.
+func (fi *FaultInjector) begin(write, crashOnly bool, datalen int) (fault, func()) {
+	fi.inflight.RLock()
+	end := fi.inflight.RUnlock
+
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	if fi.crashed {
+		return fault{err: ErrCrashed}, end
+	}
+
+	fi.step++
+	var f fault
+	if fi.KillAtStep > 0 && fi.step >= fi.KillAtStep {
+		fi.crashed = true
+		f.err = ErrCrashed
+	} else if crashOnly {
+		return fault{}, end
+	} else if fi.ErrorRate > 0 && fi.rnd.Float64() < fi.ErrorRate {
+		return fault{err: ErrInjectedFault}, end
+	} else if write && fi.TornWriteRate > 0 && fi.rnd.Float64() < fi.TornWriteRate {
+		f.err = ErrInjectedFault
+	} else {
+		return fault{}, end
+	}
+
+	if write {
+		f.torn = true
+		if datalen > 0 {
+			f.tornLen = fi.rnd.Intn(datalen)
+		}
+	}
+	return f, end
+}
+
+// Crash makes all further operations fail with ErrCrashed. Crash waits for
+// in-flight operations to finish, so that the underlying stores are no longer
+// modified by the crashed instance once Crash returns.
+func (fi *FaultInjector) Crash() {
+	fi.mu.Lock()
+	fi.crashed = true
+	fi.mu.Unlock()
+
+	fi.inflight.Lock()
+	fi.inflight.Unlock()
+}
+
+func (fi *FaultInjector) Crashed() bool {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return fi.crashed
+}
+
+// Steps returns the number of operations which went through the injector.
+func (fi *FaultInjector) Steps() int {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return fi.step
+}
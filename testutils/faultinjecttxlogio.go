@@ -0,0 +1,91 @@
+package testutils
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/nyaxt/otaru/inodedb"
+)
+
+// FaultInjectTxLogIO buffers appended transactions like a batching
+// DBTransactionLogIO, and makes them durable in BE on Sync. Faults decided by
+// FI are injected into the writes to BE, so a failed or crashed Sync may
+// persist only some of the buffered transactions.
+type FaultInjectTxLogIO struct {
+	BE inodedb.DBTransactionLogIO
+	FI *FaultInjector
+	// If true, Sync persists buffered transactions in random order, so that a
+	// crash during Sync may leave gaps in BE.
+	ReorderSyncs bool
+
+	mu      sync.Mutex
+	pending []inodedb.DBTransaction
+}
+
+var _ = inodedb.DBTransactionLogIO(&FaultInjectTxLogIO{})
+
+// AppendTransaction never fails, as the transactions are only buffered.
+// Buffered transactions are lost on crash.
+func (txio *FaultInjectTxLogIO) AppendTransaction(tx inodedb.DBTransaction) error {
+	txio.mu.Lock()
+	defer txio.mu.Unlock()
+
+	txio.pending = append(txio.pending, tx)
+	return nil
+}
+
+func (txio *FaultInjectTxLogIO) QueryTransactions(minID inodedb.TxID) ([]inodedb.DBTransaction, error) {
+	// Only crashes are injected, as inodedb can't recover from failures while rolling back.
+	f, end := txio.FI.begin(false, true, 0)
+	defer end()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	txs, err := txio.BE.QueryTransactions(minID)
+	if err != nil {
+		return nil, err
+	}
+
+	txio.mu.Lock()
+	for _, tx := range txio.pending {
+		if tx.TxID >= minID {
+			txs = append(txs, tx)
+		}
+	}
+	txio.mu.Unlock()
+
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].TxID < txs[j].TxID })
+	return txs, nil
+}
+
+// Sync persists the buffered transactions to BE one by one.
+func (txio *FaultInjectTxLogIO) Sync() error {
+	txio.mu.Lock()
+	defer txio.mu.Unlock()
+
+	if txio.ReorderSyncs {
+		txio.FI.mu.Lock()
+		txio.FI.rnd.Shuffle(len(txio.pending), func(i, j int) {
+			txio.pending[i], txio.pending[j] = txio.pending[j], txio.pending[i]
+		})
+		txio.FI.mu.Unlock()
+	}
+
+	for len(txio.pending) > 0 {
+		f, end := txio.FI.begin(true, false, 0)
+		if f.err != nil {
+			end()
+			return f.err
+		}
+		err := txio.BE.AppendTransaction(txio.pending[0])
+		end()
+		if err != nil {
+			return err
+		}
+		txio.pending = txio.pending[1:]
+	}
+	return nil
+}
+
+func (*FaultInjectTxLogIO) ImplName() string { return "FaultInjectTxLogIO" }
@@ -0,0 +1,153 @@
+package bwlimit
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket limiting throughput to a given bytes/sec.
+// Its zero value imposes no limit.
+//
+// Limiter allows a single Wait() to overdraw the bucket, so that callers
+// transferring buffers larger than the bucket size are not starved. The
+// overdrawn amount is paid back by the succeeding Wait() calls.
+type Limiter struct {
+	mu     sync.Mutex
+	limit  int64
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(bytesPerSec int64) *Limiter {
+	l := &Limiter{}
+	l.SetLimit(bytesPerSec)
+	return l
+}
+
+// SetLimit updates the throughput limit. bytesPerSec <= 0 removes the limit.
+func (l *Limiter) SetLimit(bytesPerSec int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bytesPerSec < 0 {
+		bytesPerSec = 0
+	}
+	l.limit = bytesPerSec
+	l.tokens = float64(bytesPerSec)
+	l.last = time.Now()
+}
+
+func (l *Limiter) Limit() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// refillWithLock adds the tokens accumulated since the last update.
+func (l *Limiter) refillWithLock() {
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.limit)
+	if l.tokens > float64(l.limit) {
+		l.tokens = float64(l.limit)
+	}
+	l.last = now
+}
+
+// Reserve consumes n tokens, overdrawing the bucket if needed, and returns the
+// duration the caller needs to wait before proceeding.
+func (l *Limiter) Reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit == 0 {
+		return 0
+	}
+
+	l.refillWithLock()
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / float64(l.limit) * float64(time.Second))
+}
+
+// TryReserve consumes n tokens only if the bucket has them, and returns 0.
+// Otherwise, it returns the duration until n tokens are available without
+// consuming any. TryReserve(0) tells if the bucket is overdrawn.
+func (l *Limiter) TryReserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit == 0 {
+		return 0
+	}
+
+	l.refillWithLock()
+	if l.tokens >= float64(n) {
+		l.tokens -= float64(n)
+		return 0
+	}
+	return time.Duration((float64(n) - l.tokens) / float64(l.limit) * float64(time.Second))
+}
+
+// Wait blocks until n bytes may be transferred, or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, n int) error {
+	d := l.Reserve(n)
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitFunc is the signature of Limiter.Wait, so that wrappers can be
+// constructed from a func deciding which Limiter to apply at the time of IO.
+type WaitFunc func(ctx context.Context, n int) error
+
+type reader struct {
+	ctx  context.Context
+	r    io.Reader
+	wait WaitFunc
+}
+
+func (r reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.wait(r.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+// NewReader returns io.Reader which throttles reads from r using wait.
+func NewReader(ctx context.Context, r io.Reader, wait WaitFunc) io.Reader {
+	return reader{ctx, r, wait}
+}
+
+type writer struct {
+	ctx  context.Context
+	w    io.Writer
+	wait WaitFunc
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	if err := w.wait(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+// NewWriter returns io.Writer which throttles writes to w using wait.
+func NewWriter(ctx context.Context, w io.Writer, wait WaitFunc) io.Writer {
+	return writer{ctx, w, wait}
+}
@@ -0,0 +1,129 @@
+package bwlimit_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/nyaxt/otaru/util/bwlimit"
+)
+
+func TestParseWindow(t *testing.T) {
+	w, err := bwlimit.ParseWindow("01:30-07:00")
+	if err != nil {
+		t.Fatalf("ParseWindow failed: %v", err)
+	}
+	if w.Begin != 90*time.Minute || w.End != 7*time.Hour {
+		t.Errorf("Unexpected window: %+v", w)
+	}
+	if w.String() != "01:30-07:00" {
+		t.Errorf("Unexpected String(): %s", w.String())
+	}
+
+	for _, s := range []string{"", "01:00", "01:00-25:00", "03:00-03:00", "a-b"} {
+		if _, err := bwlimit.ParseWindow(s); err == nil {
+			t.Errorf("ParseWindow(%q) should fail", s)
+		}
+	}
+}
+
+func TestWindow_Contains(t *testing.T) {
+	at := func(h, m int) time.Time {
+		return time.Date(2020, 1, 1, h, m, 0, 0, time.UTC)
+	}
+
+	w, _ := bwlimit.ParseWindow("01:00-07:00")
+	if !w.Contains(at(1, 0)) || !w.Contains(at(6, 59)) {
+		t.Errorf("should contain")
+	}
+	if w.Contains(at(0, 59)) || w.Contains(at(7, 0)) || w.Contains(at(12, 0)) {
+		t.Errorf("should not contain")
+	}
+
+	wrap, _ := bwlimit.ParseWindow("23:00-02:00")
+	if !wrap.Contains(at(23, 30)) || !wrap.Contains(at(0, 0)) || !wrap.Contains(at(1, 59)) {
+		t.Errorf("should contain")
+	}
+	if wrap.Contains(at(2, 0)) || wrap.Contains(at(22, 59)) {
+		t.Errorf("should not contain")
+	}
+}
+
+func TestLimiter_Throttles(t *testing.T) {
+	l := bwlimit.NewLimiter(100 * 1024)
+
+	var buf bytes.Buffer
+	w := bwlimit.NewWriter(context.Background(), &buf, l.Wait)
+
+	start := time.Now()
+	// The first 100KiB is consumed from the initial burst.
+	if _, err := io.Copy(w, io.LimitReader(zeroReader{}, 150*1024)); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("Transfer should have been throttled, but took only %v", elapsed)
+	}
+	if buf.Len() != 150*1024 {
+		t.Errorf("Unexpected len: %d", buf.Len())
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l := bwlimit.NewLimiter(0)
+
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		if err := l.Wait(context.Background(), 1024*1024); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Unlimited limiter should not block, but took %v", elapsed)
+	}
+}
+
+func TestLimiter_WaitCancel(t *testing.T) {
+	l := bwlimit.NewLimiter(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx, 1024); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLimiter_TryReserve(t *testing.T) {
+	l := bwlimit.NewLimiter(10)
+
+	for i := 0; i < 10; i++ {
+		if d := l.TryReserve(1); d != 0 {
+			t.Fatalf("TryReserve #%d should succeed from the initial burst, but got %v", i, d)
+		}
+	}
+	d := l.TryReserve(1)
+	if d <= 0 || d > 100*time.Millisecond {
+		t.Errorf("TryReserve on empty bucket should return the wait, but got %v", d)
+	}
+
+	// Overdraw, and TryReserve(0) tells the time until it is paid back.
+	l.Reserve(10)
+	d = l.TryReserve(0)
+	if d < 900*time.Millisecond || d > 1100*time.Millisecond {
+		t.Errorf("TryReserve(0) on overdrawn bucket: %v", d)
+	}
+
+	if d := bwlimit.NewLimiter(0).TryReserve(1024); d != 0 {
+		t.Errorf("Unlimited limiter should not limit, but got %v", d)
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,61 @@
+package bwlimit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time-of-day range, such as "01:00-07:00".
+// A Window whose End precedes its Begin wraps around midnight.
+type Window struct {
+	Begin time.Duration
+	End   time.Duration
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse time of day %q: %v", s, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ParseWindow parses a "HH:MM-HH:MM" formatted string into a Window.
+func ParseWindow(s string) (Window, error) {
+	ss := strings.Split(s, "-")
+	if len(ss) != 2 {
+		return Window{}, fmt.Errorf("Window %q is not in \"HH:MM-HH:MM\" format", s)
+	}
+
+	begin, err := parseTimeOfDay(ss[0])
+	if err != nil {
+		return Window{}, err
+	}
+	end, err := parseTimeOfDay(ss[1])
+	if err != nil {
+		return Window{}, err
+	}
+	if begin == end {
+		return Window{}, fmt.Errorf("Window %q is empty", s)
+	}
+	return Window{Begin: begin, End: end}, nil
+}
+
+// Contains returns true if the time of day of t, in t's location, is within
+// the window.
+func (w Window) Contains(t time.Time) bool {
+	h, m, s := t.Clock()
+	tod := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+
+	if w.Begin < w.End {
+		return w.Begin <= tod && tod < w.End
+	}
+	return w.Begin <= tod || tod < w.End
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d",
+		int(w.Begin.Hours()), int(w.Begin.Minutes())%60,
+		int(w.End.Hours()), int(w.End.Minutes())%60)
+}
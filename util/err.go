@@ -10,14 +10,20 @@ type Error syscall.Errno
 
 const (
 	EACCES    = Error(syscall.EACCES)
+	EAGAIN    = Error(syscall.EAGAIN)
 	EBADF     = Error(syscall.EBADF)
 	EEXIST    = Error(syscall.EEXIST)
+	EINVAL    = Error(syscall.EINVAL)
+	EIO       = Error(syscall.EIO)
 	EISDIR    = Error(syscall.EISDIR)
 	ENFILE    = Error(syscall.ENFILE)
 	ENOENT    = Error(syscall.ENOENT)
 	ENOTDIR   = Error(syscall.ENOTDIR)
 	ENOTEMPTY = Error(syscall.ENOTEMPTY)
+	ENOTSUP   = Error(syscall.ENOTSUP)
+	ENXIO     = Error(syscall.ENXIO)
 	EPERM     = Error(syscall.EPERM)
+	EROFS     = Error(syscall.EROFS)
 )
 
 func (e Error) Errno() fuse.Errno {
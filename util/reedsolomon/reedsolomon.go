@@ -0,0 +1,146 @@
+// Package reedsolomon implements systematic Reed-Solomon erasure coding over
+// GF(2^8). Data split into k data shards is extended by m parity shards, and
+// can be reconstructed from any k of the k+m shards.
+package reedsolomon
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTooFewShards  = errors.New("reedsolomon: too few shards to reconstruct")
+	ErrShardSizeDiff = errors.New("reedsolomon: shards differ in size")
+)
+
+type Encoder struct {
+	k, m int
+	// encoding matrix of (k+m) x k. The top k rows form the identity matrix.
+	enc matrix
+}
+
+func New(k, m int) (*Encoder, error) {
+	if k <= 0 || m < 0 {
+		return nil, fmt.Errorf("reedsolomon: invalid number of shards k=%d, m=%d", k, m)
+	}
+	if k+m > 256 {
+		return nil, fmt.Errorf("reedsolomon: too many shards k+m=%d > 256", k+m)
+	}
+
+	// Make the vandermonde matrix systematic. Any k rows of the result are
+	// still linearly independent.
+	v := vandermonde(k+m, k)
+	topinv, err := matrix(v[:k]).invert()
+	if err != nil {
+		return nil, err
+	}
+	return &Encoder{k: k, m: m, enc: v.mul(topinv)}, nil
+}
+
+func (e *Encoder) DataShards() int   { return e.k }
+func (e *Encoder) ParityShards() int { return e.m }
+func (e *Encoder) TotalShards() int  { return e.k + e.m }
+
+func shardSize(shards [][]byte) (int, error) {
+	size := -1
+	for _, s := range shards {
+		if s == nil {
+			continue
+		}
+		if size < 0 {
+			size = len(s)
+		} else if len(s) != size {
+			return 0, ErrShardSizeDiff
+		}
+	}
+	return size, nil
+}
+
+// Encode computes the parity shards shards[k:] from the data shards
+// shards[:k]. All shards must be allocated and of the same size.
+func (e *Encoder) Encode(shards [][]byte) error {
+	if len(shards) != e.k+e.m {
+		return fmt.Errorf("reedsolomon: expected %d shards, got %d", e.k+e.m, len(shards))
+	}
+	for _, s := range shards {
+		if s == nil {
+			return fmt.Errorf("reedsolomon: nil shard given to Encode")
+		}
+	}
+	if _, err := shardSize(shards); err != nil {
+		return err
+	}
+
+	for p := e.k; p < e.k+e.m; p++ {
+		dst := shards[p]
+		for i := range dst {
+			dst[i] = 0
+		}
+		for d := 0; d < e.k; d++ {
+			mulAddSlice(e.enc[p][d], shards[d], dst)
+		}
+	}
+	return nil
+}
+
+// Reconstruct fills in the missing (nil) shards. At least k shards must be present.
+func (e *Encoder) Reconstruct(shards [][]byte) error {
+	if len(shards) != e.k+e.m {
+		return fmt.Errorf("reedsolomon: expected %d shards, got %d", e.k+e.m, len(shards))
+	}
+	size, err := shardSize(shards)
+	if err != nil {
+		return err
+	}
+
+	present := make([]int, 0, e.k)
+	for i, s := range shards {
+		if s != nil && len(present) < e.k {
+			present = append(present, i)
+		}
+	}
+	if len(present) < e.k {
+		return ErrTooFewShards
+	}
+
+	dataMissing := false
+	for d := 0; d < e.k; d++ {
+		if shards[d] == nil {
+			dataMissing = true
+			break
+		}
+	}
+
+	if dataMissing {
+		sub := newMatrix(e.k, e.k)
+		for r, i := range present {
+			copy(sub[r], e.enc[i])
+		}
+		dec, err := sub.invert()
+		if err != nil {
+			return err
+		}
+		for d := 0; d < e.k; d++ {
+			if shards[d] != nil {
+				continue
+			}
+			out := make([]byte, size)
+			for r, i := range present {
+				mulAddSlice(dec[d][r], shards[i], out)
+			}
+			shards[d] = out
+		}
+	}
+
+	for p := e.k; p < e.k+e.m; p++ {
+		if shards[p] != nil {
+			continue
+		}
+		out := make([]byte, size)
+		for d := 0; d < e.k; d++ {
+			mulAddSlice(e.enc[p][d], shards[d], out)
+		}
+		shards[p] = out
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package reedsolomon_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/nyaxt/otaru/util/reedsolomon"
+)
+
+func encodeRandom(t *testing.T, e *reedsolomon.Encoder, size int) [][]byte {
+	shards := make([][]byte, e.TotalShards())
+	for i := range shards {
+		shards[i] = make([]byte, size)
+		if i < e.DataShards() {
+			rand.Read(shards[i])
+		}
+	}
+	if err := e.Encode(shards); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	return shards
+}
+
+func TestReconstruct_AllCombinations(t *testing.T) {
+	const k, m = 4, 2
+	e, err := reedsolomon.New(k, m)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	orig := encodeRandom(t, e, 1000)
+
+	for a := 0; a < k+m; a++ {
+		for b := a; b < k+m; b++ {
+			shards := make([][]byte, k+m)
+			for i := range orig {
+				if i != a && i != b {
+					shards[i] = append([]byte{}, orig[i]...)
+				}
+			}
+			if err := e.Reconstruct(shards); err != nil {
+				t.Fatalf("Reconstruct(missing %d, %d) failed: %v", a, b, err)
+			}
+			for i := range orig {
+				if !bytes.Equal(shards[i], orig[i]) {
+					t.Errorf("Reconstruct(missing %d, %d): shard %d mismatch", a, b, i)
+				}
+			}
+		}
+	}
+}
+
+func TestReconstruct_TooFewShards(t *testing.T) {
+	e, err := reedsolomon.New(3, 2)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	orig := encodeRandom(t, e, 100)
+
+	shards := [][]byte{orig[0], nil, nil, nil, orig[4]}
+	if err := e.Reconstruct(shards); err != reedsolomon.ErrTooFewShards {
+		t.Errorf("Expected ErrTooFewShards, got %v", err)
+	}
+}
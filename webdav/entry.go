@@ -11,6 +11,7 @@ type Entry struct {
 	Name         string
 	Size         int64
 	ModifiedTime time.Time
+	CreatedTime  time.Time
 	IsDir        bool
 }
 
@@ -20,6 +21,7 @@ func INodeViewToEntry(v *pb.INodeView) *Entry {
 		Name:         v.Name,
 		Size:         v.Size,
 		ModifiedTime: time.Unix(v.ModifiedTime, 0),
+		CreatedTime:  time.Unix(v.CreatedTime, 0),
 		IsDir:        v.Type == pb.INodeType_DIR,
 	}
 }
@@ -40,10 +40,14 @@ func entryToResp(basepath string, entry *Entry, toplevel bool) *davresp {
 	}
 
 	utc := entry.ModifiedTime.UTC()
+	ctime := utc
+	if !entry.CreatedTime.IsZero() && entry.CreatedTime.Unix() != 0 {
+		ctime = entry.CreatedTime.UTC()
+	}
 	r := &davresp{
 		Href:         href,
 		DispName:     entry.Name,
-		CreationDate: utc.Format(time.RFC3339),
+		CreationDate: ctime.Format(time.RFC3339),
 		Size:         entry.Size,
 		LastModified: utc.Format(time.RFC1123),
 		Status:       StatusOk,
@@ -123,6 +127,7 @@ func (m ContentServerMarshaler) WriteResponse(w http.ResponseWriter, basepath st
 		URL:    url,
 	}
 	req.Header.Add("Range", m.OrigReq.Header.Get("Range"))
+	m.CInfo.SetAuthHeader(req.Header)
 
 	resp, err := cli.Do(req)
 	if err != nil {